@@ -3,26 +3,60 @@ package app
 import (
 	"github.com/adamnasrudin03/go-asset-findr/app/configs"
 	"github.com/adamnasrudin03/go-asset-findr/app/controller"
+	"github.com/adamnasrudin03/go-asset-findr/app/graph"
 	"github.com/adamnasrudin03/go-asset-findr/app/repository"
 	"github.com/adamnasrudin03/go-asset-findr/app/service"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/cache"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/event"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/storage"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
-func WiringRepository(db *gorm.DB, cfg *configs.Configs, logger *logrus.Logger) *repository.Repositories {
+func WiringRepository(db *gorm.DB, c cache.Cache, cfg *configs.Configs, logger *logrus.Logger) *repository.Repositories {
+	post := repository.NewPostRepository(db, cfg, logger)
+	if c != nil {
+		post = repository.NewPostCacheRepository(post, c, cfg, logger)
+	}
+
 	return &repository.Repositories{
-		Post: repository.NewPostRepository(db, cfg, logger),
+		DB:           db,
+		Post:         post,
+		PostRevision: repository.NewPostRevisionRepository(db, cfg, logger),
+		Tag:          repository.NewTagRepository(db, cfg, logger),
+		Asset:        repository.NewAssetRepository(db, cfg, logger),
+		Blob:         repository.NewBlobRepository(db, cfg, logger),
+		Report:       repository.NewReportRepository(db, cfg, logger),
+		Outbox:       repository.NewOutboxRepository(db, cfg, logger),
+		Webhook:      repository.NewWebhookRepository(db, cfg, logger),
 	}
 }
 
-func WiringService(repo *repository.Repositories, cfg *configs.Configs, logger *logrus.Logger) *service.Services {
-	return &service.Services{
-		Post: service.NewPostService(repo.Post, cfg, logger),
+func WiringService(repo *repository.Repositories, store storage.Storage, publisher event.Broker, cfg *configs.Configs, logger *logrus.Logger) *service.Services {
+	services := &service.Services{
+		Post:       service.NewPostService(repo, cfg, logger),
+		Asset:      service.NewAssetService(repo, store, cfg, logger),
+		Tag:        service.NewTagService(repo, cfg, logger),
+		Report:     service.NewReportService(repo, cfg, logger),
+		Outbox:     service.NewOutboxService(repo, publisher, cfg, logger),
+		Webhook:    service.NewWebhookService(repo, cfg, logger),
+		PostStream: service.NewPostStreamService(repo, cfg, logger),
 	}
+
+	// webhooks and post streams take the events published
+	publisher.Subscribe(services.Webhook.Enqueue)
+	publisher.Subscribe(services.PostStream.Notify)
+
+	return services
 }
 
 func WiringController(srv *service.Services, cfg *configs.Configs, logger *logrus.Logger) *controller.Controllers {
 	return &controller.Controllers{
-		Post: controller.NewPostDelivery(srv.Post, logger),
+		Post:    controller.NewPostDelivery(srv.Post, srv.PostStream, cfg, logger),
+		Asset:   controller.NewAssetDelivery(srv.Asset, cfg, logger),
+		Tag:     controller.NewTagDelivery(srv.Tag, logger),
+		Report:  controller.NewReportDelivery(srv.Report, logger),
+		Webhook: controller.NewWebhookDelivery(srv.Webhook, logger),
+		GraphQL: controller.NewGraphQLDelivery(graph.New(srv, cfg, logger), logger),
 	}
 }
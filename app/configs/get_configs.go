@@ -2,6 +2,7 @@ package configs
 
 import (
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 )
@@ -20,6 +21,15 @@ func GetInstance() *Configs {
 			Name: getEnv("APP_NAME", "go-asset-findr"),
 			Env:  getEnv("APP_ENV", "dev"),
 			Port: getEnv("APP_PORT", "8000"),
+
+			GRPCPort: getEnv("APP_GRPC_PORT", "50051"),
+
+			CacheControl: getEnv("APP_CACHE_CONTROL", "no-cache"),
+			SecretKey:    getEnv("JWT_SECRET", ""),
+
+			PublishInterval:       getEnvInt("APP_PUBLISH_INTERVAL", 30),
+			ReportRefreshInterval: getEnvInt("APP_REPORT_REFRESH_INTERVAL", 3600),
+			StreamHeartbeat:       getEnvInt("APP_STREAM_HEARTBEAT", 15),
 		},
 		DB: DbConfig{
 			Host:        getEnv("DB_HOST", "127.0.0.1"),
@@ -28,6 +38,61 @@ func GetInstance() *Configs {
 			Username:    getEnv("DB_USER", "postgres"),
 			Password:    getEnv("DB_PASS", ""),
 			DbIsMigrate: getEnv("DB_IS_MIGRATE", "true") == "true",
+
+			ReplicaDSNs:       getEnvList("DB_REPLICA_DSNS", ""),
+			ReadYourWritesTTL: getEnvInt("DB_READ_YOUR_WRITES_TTL", 5),
+			BatchSize:         getEnvInt("DB_BATCH_SIZE", 100),
+		},
+		Cache: CacheConfig{
+			Driver:        getEnv("CACHE_DRIVER", ""),
+			TTL:           getEnvInt("CACHE_TTL", 60),
+			Size:          getEnvInt("CACHE_SIZE", 1000),
+			RedisAddr:     getEnv("REDIS_ADDR", "127.0.0.1:6379"),
+			RedisPassword: getEnv("REDIS_PASSWORD", ""),
+			RedisDB:       getEnvInt("REDIS_DB", 0),
+		},
+		Storage: StorageConfig{
+			Driver:   getEnv("STORAGE_DRIVER", "local"),
+			LocalDir: getEnv("STORAGE_LOCAL_DIR", "./storage"),
+
+			MaxUploadSize: int64(getEnvInt("STORAGE_MAX_UPLOAD_SIZE", 10<<20)),
+			AllowedTypes:  getEnvList("STORAGE_ALLOWED_TYPES", "image/jpeg,image/png,image/gif,image/webp,application/pdf,text/plain"),
+
+			ThumbnailSizes:  getEnvIntList("STORAGE_THUMBNAIL_SIZES", "160,640"),
+			Workers:         getEnvInt("ASSET_WORKERS", 4),
+			QueueSize:       getEnvInt("ASSET_QUEUE_SIZE", 100),
+			ProcessInterval: getEnvInt("ASSET_PROCESS_INTERVAL", 60),
+
+			GCInterval:  getEnvInt("STORAGE_GC_INTERVAL", 3600),
+			GCGrace:     getEnvInt("STORAGE_GC_GRACE", 24),
+			AssetExpiry: getEnvInt("STORAGE_ASSET_EXPIRY", 0),
+
+			S3Endpoint:  getEnv("S3_ENDPOINT", ""),
+			S3Region:    getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:    getEnv("S3_BUCKET", ""),
+			S3AccessKey: getEnv("S3_ACCESS_KEY", ""),
+			S3SecretKey: getEnv("S3_SECRET_KEY", ""),
+			S3Timeout:   getEnvInt("S3_TIMEOUT", 60),
+		},
+		Event: EventConfig{
+			Publisher:     getEnv("EVENT_PUBLISHER", "memory"),
+			RelayInterval: getEnvInt("EVENT_RELAY_INTERVAL", 1),
+			BatchSize:     getEnvInt("EVENT_BATCH_SIZE", 100),
+			RetryBase:     getEnvInt("EVENT_RETRY_BASE", 5),
+			RetryMax:      getEnvInt("EVENT_RETRY_MAX", 3600),
+			Retention:     getEnvInt("EVENT_RETENTION", 168),
+		},
+		Webhook: WebhookConfig{
+			DeliverInterval: getEnvInt("WEBHOOK_DELIVER_INTERVAL", 5),
+			BatchSize:       getEnvInt("WEBHOOK_BATCH_SIZE", 50),
+			Timeout:         getEnvInt("WEBHOOK_TIMEOUT", 10),
+			RetryBase:       getEnvInt("WEBHOOK_RETRY_BASE", 10),
+			RetryMax:        getEnvInt("WEBHOOK_RETRY_MAX", 3600),
+			MaxAttempts:     getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		},
+		GraphQL: GraphQLConfig{
+			MaxDepth:      getEnvInt("GRAPHQL_MAX_DEPTH", 8),
+			MaxComplexity: getEnvInt("GRAPHQL_MAX_COMPLEXITY", 2000),
 		},
 	}
 
@@ -40,3 +105,38 @@ func getEnv(key, fallback string) string {
 	}
 	return strings.TrimSpace(fallback)
 }
+
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(getEnv(key, ""))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// getEnvList splits a comma separated env value, skipping empty items.
+func getEnvList(key, fallback string) []string {
+	result := []string{}
+	for _, v := range strings.Split(getEnv(key, fallback), ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		result = append(result, v)
+	}
+	return result
+}
+
+// getEnvIntList splits a comma separated env value, skipping items that are
+// not positive numbers.
+func getEnvIntList(key, fallback string) []int {
+	result := []int{}
+	for _, v := range getEnvList(key, fallback) {
+		value, err := strconv.Atoi(v)
+		if err != nil || value <= 0 {
+			continue
+		}
+		result = append(result, value)
+	}
+	return result
+}
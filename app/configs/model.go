@@ -1,14 +1,36 @@
 package configs
 
 type Configs struct {
-	App AppConfig
-	DB  DbConfig
+	App     AppConfig
+	DB      DbConfig
+	Cache   CacheConfig
+	Storage StorageConfig
+	Event   EventConfig
+	Webhook WebhookConfig
+	GraphQL GraphQLConfig
 }
 
 type AppConfig struct {
 	Name string `json:"name"`
 	Env  string `json:"env"`
 	Port string `json:"port"`
+	// GRPCPort serves the gRPC API for internal services, empty disables it.
+	GRPCPort string `json:"grpc_port"`
+	// CacheControl is sent on cacheable GET responses, e.g. "no-cache" makes
+	// clients revalidate with the ETag on every request.
+	CacheControl string `json:"cache_control"`
+	// SecretKey signs the bearer JWT of the users, it has no default and
+	// the app does not start without it.
+	SecretKey string `json:"-"`
+	// PublishInterval is how often (seconds) due scheduled posts are
+	// published, 0 disables the scheduler on this instance.
+	PublishInterval int `json:"publish_interval"`
+	// ReportRefreshInterval is how often (seconds) the tag reports are
+	// recomputed, 0 leaves it to the refresh-reports command and endpoint.
+	ReportRefreshInterval int `json:"report_refresh_interval"`
+	// StreamHeartbeat is how often (seconds) an idle post stream gets a
+	// comment, so proxies do not close it.
+	StreamHeartbeat int `json:"stream_heartbeat"`
 }
 
 type DbConfig struct {
@@ -19,4 +41,94 @@ type DbConfig struct {
 	Password    string `json:"password"`
 	DbIsMigrate bool   `json:"db_is_migrate"`
 	DebugMode   bool   `json:"debug_mode"`
+
+	// ReplicaDSNs are read-only replicas used for plain SELECT queries,
+	// writes and transactions always go to the primary above.
+	ReplicaDSNs []string `json:"replica_dsns"`
+	// ReadYourWritesTTL is how long (seconds) a client keeps reading from
+	// the primary after a mutation, to hide replication lag.
+	ReadYourWritesTTL int `json:"read_your_writes_ttl"`
+	// BatchSize is how many rows a bulk write sends per statement.
+	BatchSize int `json:"batch_size"`
+}
+
+type StorageConfig struct {
+	Driver   string `json:"driver"`    // local or s3
+	LocalDir string `json:"local_dir"` // root directory of the local driver
+
+	// MaxUploadSize is the largest file (bytes) an upload accepts, and
+	// AllowedTypes the MIME types sniffed from its content that it accepts.
+	MaxUploadSize int64    `json:"max_upload_size"`
+	AllowedTypes  []string `json:"allowed_types"`
+
+	// ThumbnailSizes are the longest sides (pixels) of the thumbnails made
+	// for every image. Workers process uploads in the background with a
+	// queue of QueueSize, assets left pending are picked up again every
+	// ProcessInterval seconds.
+	ThumbnailSizes  []int `json:"thumbnail_sizes"`
+	Workers         int   `json:"workers"`
+	QueueSize       int   `json:"queue_size"`
+	ProcessInterval int   `json:"process_interval"`
+
+	// GCInterval is how often (seconds) unused blobs are removed, 0 turns
+	// it off. GCGrace (hours) is how long a blob used by no asset is kept
+	// first. AssetExpiry (hours) opts in to removing the assets attached to
+	// no post for that long too, 0 keeps them.
+	GCInterval  int `json:"gc_interval"`
+	GCGrace     int `json:"gc_grace"`
+	AssetExpiry int `json:"asset_expiry"`
+
+	S3Endpoint  string `json:"s3_endpoint"` // e.g. https://s3.ap-southeast-1.amazonaws.com or http://127.0.0.1:9000
+	S3Region    string `json:"s3_region"`
+	S3Bucket    string `json:"s3_bucket"`
+	S3AccessKey string `json:"-"`
+	S3SecretKey string `json:"-"`
+	// S3Timeout (seconds) bounds a request to S3, transferring the file
+	// included.
+	S3Timeout int `json:"s3_timeout"`
+}
+
+// EventConfig is how the events of the outbox are relayed to Publisher,
+// only memory (in process) for now. The relay publishes up to BatchSize
+// events every RelayInterval seconds, a failed event is retried after
+// RetryBase seconds doubled on every attempt up to RetryMax. Published events
+// are kept Retention hours.
+type EventConfig struct {
+	Publisher     string `json:"publisher"`
+	RelayInterval int    `json:"relay_interval"`
+	BatchSize     int    `json:"batch_size"`
+	RetryBase     int    `json:"retry_base"`
+	RetryMax      int    `json:"retry_max"`
+	Retention     int    `json:"retention"`
+}
+
+// WebhookConfig is how the deliveries to webhooks are sent. Up to BatchSize
+// due deliveries are sent every DeliverInterval seconds, each waiting up to
+// Timeout seconds for the receiver. A failed one is retried after RetryBase
+// seconds doubled on every attempt up to RetryMax, and is dead after
+// MaxAttempts attempts.
+type WebhookConfig struct {
+	DeliverInterval int `json:"deliver_interval"`
+	BatchSize       int `json:"batch_size"`
+	Timeout         int `json:"timeout"`
+	RetryBase       int `json:"retry_base"`
+	RetryMax        int `json:"retry_max"`
+	MaxAttempts     int `json:"max_attempts"`
+}
+
+// GraphQLConfig bounds the queries of /graphql. MaxDepth is how deep fields
+// may be nested, MaxComplexity how many fields a query may resolve, lists
+// asked with first counting that many times.
+type GraphQLConfig struct {
+	MaxDepth      int `json:"max_depth"`
+	MaxComplexity int `json:"max_complexity"`
+}
+
+type CacheConfig struct {
+	Driver        string `json:"driver"` // "", memory or redis
+	TTL           int    `json:"ttl"`    // in seconds
+	Size          int    `json:"size"`   // max items of the memory driver
+	RedisAddr     string `json:"redis_addr"`
+	RedisPassword string `json:"redis_password"`
+	RedisDB       int    `json:"redis_db"`
 }
@@ -0,0 +1,365 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/service"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type AssetController interface {
+	Upload(ctx *gin.Context)
+	GetDetail(ctx *gin.Context)
+	GetList(ctx *gin.Context)
+	Download(ctx *gin.Context)
+	DownloadThumbnail(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+	GetByPost(ctx *gin.Context)
+	Attach(ctx *gin.Context)
+	Detach(ctx *gin.Context)
+	StorageReport(ctx *gin.Context)
+	CollectGarbage(ctx *gin.Context)
+}
+
+type AssetHandler struct {
+	Service service.AssetService
+	Cfg     *configs.Configs
+	Logger  *logrus.Logger
+}
+
+func NewAssetDelivery(
+	srv service.AssetService,
+	cfg *configs.Configs,
+	logger *logrus.Logger,
+) AssetController {
+	return &AssetHandler{
+		Service: srv,
+		Cfg:     cfg,
+		Logger:  logger,
+	}
+}
+
+// Upload stores every file of the repeated multipart field "file". Each
+// file is reported on its own, like the bulk endpoints. strip_gps=true
+// removes the GPS location of JPEG photos.
+func (c *AssetHandler) Upload(ctx *gin.Context) {
+	var (
+		opName = "AssetController-Upload"
+		// room for the largest files plus the multipart framing
+		maxBody = int64(dto.AssetMaxUploadFiles)*c.Cfg.Storage.MaxUploadSize + 1<<20
+		err     error
+	)
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxBody)
+	form, err := ctx.MultipartForm()
+	if err != nil {
+		c.Logger.Errorf("%v error multipart form: %v ", opName, err)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			helpers.RenderJSON(ctx.Writer, http.StatusRequestEntityTooLarge, dto.ErrAssetTooLarge(c.Cfg.Storage.MaxUploadSize))
+			return
+		}
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrGetRequest())
+		return
+	}
+	defer form.RemoveAll()
+
+	files := form.File["file"]
+	if len(files) == 0 {
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrIsRequired("file", "file"))
+		return
+	}
+	if len(files) > dto.AssetMaxUploadFiles {
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrTooMany("file", "files"))
+		return
+	}
+
+	stripGPS, _ := strconv.ParseBool(ctx.Request.FormValue("strip_gps"))
+	res := dto.AssetUploadRes{Items: make([]dto.AssetUploadItemRes, len(files))}
+	for i, fileHeader := range files {
+		item := &res.Items[i]
+		item.Index = i
+		item.Filename = fileHeader.Filename
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.Logger.Errorf("%v error open file %d: %v ", opName, i, err)
+			item.SetError(err, helpers.ErrGetRequest())
+			continue
+		}
+
+		asset, err := c.Service.Upload(ctx, dto.AssetUploadReq{
+			Filename: fileHeader.Filename,
+			Size:     fileHeader.Size,
+			File:     file,
+			StripGPS: stripGPS,
+		})
+		file.Close()
+		if err != nil {
+			c.Logger.Errorf("%v error file %d: %v ", opName, i, err)
+			item.SetError(err, dto.ErrAssetStorage())
+			continue
+		}
+
+		item.Status = dto.BulkStatusSuccess
+		item.Asset = asset
+	}
+	res.CountResult()
+
+	switch {
+	case res.Failed == 0:
+		ctx.JSON(http.StatusCreated, res)
+	case res.Failed == res.Total:
+		ctx.JSON(http.StatusUnprocessableEntity, res)
+	default:
+		ctx.JSON(http.StatusMultiStatus, res)
+	}
+}
+
+func (c *AssetHandler) GetDetail(ctx *gin.Context) {
+	var (
+		opName = "AssetController-GetDetail"
+		err    error
+	)
+
+	id, err := c.parseID(ctx, opName, "id")
+	if err != nil {
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := c.Service.GetDetail(ctx, id)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// GetList filters with the repeated query "filter", e.g.
+// ?filter=width>=1024&filter=mime=image/*
+func (c *AssetHandler) GetList(ctx *gin.Context) {
+	var (
+		opName = "AssetController-GetList"
+		input  dto.AssetListReq
+		err    error
+	)
+
+	err = ctx.ShouldBindQuery(&input)
+	if err != nil {
+		c.Logger.Errorf("%v error bind query: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrGetRequest())
+		return
+	}
+
+	res, err := c.Service.GetList(ctx, input)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// Download streams the file. Its checksum is the ETag, the content of an
+// asset never changes.
+func (c *AssetHandler) Download(ctx *gin.Context) {
+	var (
+		opName = "AssetController-Download"
+		err    error
+	)
+
+	id, err := c.parseID(ctx, opName, "id")
+	if err != nil {
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, err)
+		return
+	}
+
+	asset, err := c.Service.GetDetail(ctx, id)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	etag := `"` + asset.Checksum + `"`
+	ctx.Header("ETag", etag)
+	ctx.Header("Cache-Control", "public, max-age=31536000, immutable")
+	SetLastModified(ctx, asset.CreatedAt)
+	if isNotModified(ctx.Request, etag, ctx.Writer.Header().Get("Last-Modified")) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	content, err := c.Service.Open(ctx, asset)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+	defer content.Close()
+
+	disposition := "attachment"
+	if strings.HasPrefix(asset.MimeType, "image/") {
+		disposition = "inline"
+	}
+	ctx.DataFromReader(http.StatusOK, asset.Size, asset.MimeType, content, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType(disposition, map[string]string{"filename": asset.Filename}),
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+func (c *AssetHandler) DownloadThumbnail(ctx *gin.Context) {
+	var (
+		opName = "AssetController-DownloadThumbnail"
+		err    error
+	)
+
+	id, err := c.parseID(ctx, opName, "id")
+	if err != nil {
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, err)
+		return
+	}
+
+	size, err := c.parseID(ctx, opName, "size")
+	if err != nil {
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, err)
+		return
+	}
+
+	thumbnail, content, err := c.Service.OpenThumbnail(ctx, id, int(size))
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+	defer content.Close()
+
+	ctx.DataFromReader(http.StatusOK, thumbnail.ByteSize, thumbnail.MimeType, content, map[string]string{
+		"Cache-Control":          "public, max-age=86400",
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+func (c *AssetHandler) Delete(ctx *gin.Context) {
+	var (
+		opName = "AssetController-Delete"
+		err    error
+	)
+
+	id, err := c.parseID(ctx, opName, "id")
+	if err != nil {
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, err)
+		return
+	}
+
+	err = c.Service.Delete(ctx, id)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ResponseMessage{Message: "Deleted data asset successfully"})
+}
+
+func (c *AssetHandler) GetByPost(ctx *gin.Context) {
+	var (
+		opName = "AssetController-GetByPost"
+		err    error
+	)
+
+	postID, err := c.parseID(ctx, opName, "id")
+	if err != nil {
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := c.Service.GetByPost(ctx, postID)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *AssetHandler) Attach(ctx *gin.Context) {
+	var (
+		opName = "AssetController-Attach"
+		input  dto.PostAssetReq
+		err    error
+	)
+
+	err = ctx.ShouldBindJSON(&input)
+	if err != nil {
+		c.Logger.Errorf("%v error bind json: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrGetRequest())
+		return
+	}
+
+	input.PostID, err = c.parseID(ctx, opName, "id")
+	if err != nil {
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, err)
+		return
+	}
+
+	err = c.Service.Attach(ctx, input)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ResponseMessage{Message: "Attached data asset successfully"})
+}
+
+func (c *AssetHandler) Detach(ctx *gin.Context) {
+	var (
+		opName = "AssetController-Detach"
+		err    error
+	)
+
+	postID, err := c.parseID(ctx, opName, "id")
+	if err != nil {
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, err)
+		return
+	}
+
+	assetID, err := c.parseID(ctx, opName, "asset_id")
+	if err != nil {
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, err)
+		return
+	}
+
+	err = c.Service.Detach(ctx, postID, assetID)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ResponseMessage{Message: "Detached data asset successfully"})
+}
+
+func (c *AssetHandler) parseID(ctx *gin.Context, opName, param string) (uint64, error) {
+	id, err := strconv.ParseUint(strings.TrimSpace(ctx.Param(param)), 10, 32)
+	if err != nil {
+		c.Logger.Errorf("%v error parse param %s: %v ", opName, param, err)
+		return 0, helpers.ErrInvalid(fmt.Sprintf("ID %s", param), param)
+	}
+	return id, nil
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/gin-gonic/gin"
+)
+
+// StorageReport tells how much storage deduplication saves and which
+// orphaned assets and blobs the next garbage collection removes.
+func (c *AssetHandler) StorageReport(ctx *gin.Context) {
+	opName := "AssetController-StorageReport"
+
+	res, err := c.Service.StorageReport(ctx)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// CollectGarbage removes the orphaned assets and blobs now, dry_run=true only
+// counts them.
+func (c *AssetHandler) CollectGarbage(ctx *gin.Context) {
+	var (
+		opName = "AssetController-CollectGarbage"
+		input  dto.StorageGCReq
+		err    error
+	)
+
+	err = ctx.ShouldBindQuery(&input)
+	if err != nil {
+		c.Logger.Errorf("%v error bind query: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrGetRequest())
+		return
+	}
+
+	res, err := c.Service.CollectGarbage(ctx, input)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestConditionalGet(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var (
+		modified = time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+		r        = gin.New()
+	)
+	r.GET("/posts/:id", ConditionalGet("no-cache"), func(ctx *gin.Context) {
+		if ctx.Param("id") == "0" {
+			ctx.JSON(http.StatusNotFound, gin.H{"message": "not found"})
+			return
+		}
+		SetLastModified(ctx, modified)
+		ctx.JSON(http.StatusOK, gin.H{"id": ctx.Param("id")})
+	})
+
+	first := httptest.NewRecorder()
+	r.ServeHTTP(first, httptest.NewRequest(http.MethodGet, "/posts/1", nil))
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" || first.Body.Len() == 0 {
+		t.Fatalf("first GET = %d etag %q body %q", first.Code, etag, first.Body.String())
+	}
+	if got := first.Header().Get("Cache-Control"); got != "no-cache" {
+		t.Errorf("Cache-Control = %q, want no-cache", got)
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		headers    map[string]string
+		wantStatus int
+	}{
+		{
+			name:       "same etag",
+			path:       "/posts/1",
+			headers:    map[string]string{"If-None-Match": etag},
+			wantStatus: http.StatusNotModified,
+		},
+		{
+			name:       "other etag",
+			path:       "/posts/2",
+			headers:    map[string]string{"If-None-Match": etag},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "not modified since",
+			path:       "/posts/1",
+			headers:    map[string]string{"If-Modified-Since": modified.Format(http.TimeFormat)},
+			wantStatus: http.StatusNotModified,
+		},
+		{
+			name:       "modified since",
+			path:       "/posts/1",
+			headers:    map[string]string{"If-Modified-Since": modified.Add(-time.Hour).Format(http.TimeFormat)},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "error is passed through",
+			path:       "/posts/0",
+			headers:    map[string]string{"If-None-Match": "*"},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if w.Code == http.StatusNotModified && w.Body.Len() > 0 {
+				t.Errorf("304 with body %q", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestConditionalGet_ModifiedThisSecond(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var (
+		version = 1
+		r       = gin.New()
+	)
+	r.GET("/posts/:id", ConditionalGet("no-cache"), func(ctx *gin.Context) {
+		SetLastModified(ctx, time.Now())
+		ctx.JSON(http.StatusOK, gin.H{"id": ctx.Param("id"), "version": version})
+	})
+
+	first := httptest.NewRecorder()
+	r.ServeHTTP(first, httptest.NewRequest(http.MethodGet, "/posts/1", nil))
+	if got := first.Header().Get("Last-Modified"); got != "" {
+		t.Errorf("Last-Modified = %q, want none within the second", got)
+	}
+	if first.Header().Get("ETag") == "" {
+		t.Error("ETag is missing")
+	}
+
+	// changed again in the same second as the client has seen
+	version = 2
+	req := httptest.NewRequest(http.MethodGet, "/posts/1", nil)
+	req.Header.Set("If-Modified-Since", time.Now().UTC().Format(http.TimeFormat))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Body.String() == first.Body.String() {
+		t.Errorf("GET = %d body %q, want %d with the new version", w.Code, w.Body.String(), http.StatusOK)
+	}
+}
@@ -2,5 +2,10 @@ package controller
 
 // Controllers all Controller object injected here
 type Controllers struct {
-	Post PostController
+	Post    PostController
+	Asset   AssetController
+	Tag     TagController
+	Report  ReportController
+	Webhook WebhookController
+	GraphQL GraphQLController
 }
@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/graph"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type GraphQLController interface {
+	Query(ctx *gin.Context)
+}
+
+type GraphQLHandler struct {
+	Schema *graph.Schema
+	Logger *logrus.Logger
+}
+
+func NewGraphQLDelivery(
+	schema *graph.Schema,
+	logger *logrus.Logger,
+) GraphQLController {
+	return &GraphQLHandler{
+		Schema: schema,
+		Logger: logger,
+	}
+}
+
+// Query runs a GraphQL query or mutation. Errors of the query itself come
+// back with status 200 in the errors of the response, as GraphQL clients
+// expect.
+func (c *GraphQLHandler) Query(ctx *gin.Context) {
+	var (
+		opName = "GraphQLController-Query"
+		input  graph.Request
+		err    error
+	)
+
+	err = ctx.ShouldBindJSON(&input)
+	if err != nil {
+		c.Logger.Errorf("%v error bind json: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrGetRequest())
+		return
+	}
+	if strings.TrimSpace(input.Query) == "" {
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrIsRequired("query", "query"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, c.Schema.Execute(ctx, input))
+}
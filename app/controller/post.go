@@ -2,9 +2,11 @@ package controller
 
 import (
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
 	"github.com/adamnasrudin03/go-asset-findr/app/dto"
 	"github.com/adamnasrudin03/go-asset-findr/app/service"
 	"github.com/adamnasrudin03/go-template/pkg/helpers"
@@ -15,38 +17,71 @@ import (
 type PostController interface {
 	GetList(ctx *gin.Context)
 	GetDetail(ctx *gin.Context)
+	GetBySlug(ctx *gin.Context)
 	Create(ctx *gin.Context)
 	Delete(ctx *gin.Context)
 	Update(ctx *gin.Context)
+	UpdateStatus(ctx *gin.Context)
+	BulkCreate(ctx *gin.Context)
+	BulkUpdate(ctx *gin.Context)
+	BulkDelete(ctx *gin.Context)
+	Export(ctx *gin.Context)
+	Import(ctx *gin.Context)
+	GetRevisions(ctx *gin.Context)
+	GetRevision(ctx *gin.Context)
+	DiffRevisions(ctx *gin.Context)
+	RestoreRevision(ctx *gin.Context)
+	GetRelated(ctx *gin.Context)
+	Stream(ctx *gin.Context)
 }
 
 type PostHandler struct {
-	Service service.PostService
-	Logger  *logrus.Logger
+	Service       service.PostService
+	StreamService service.PostStreamService
+	Cfg           *configs.Configs
+	Logger        *logrus.Logger
 }
 
 func NewPostDelivery(
 	srv service.PostService,
+	stream service.PostStreamService,
+	cfg *configs.Configs,
 	logger *logrus.Logger,
 ) PostController {
 	return &PostHandler{
-		Service: srv,
-		Logger:  logger,
+		Service:       srv,
+		StreamService: stream,
+		Cfg:           cfg,
+		Logger:        logger,
 	}
 }
+
+// GetList filters by the query tag, e.g. ?tag=go&descendants=true for the
+// posts tagged go or any tag below it.
 func (c *PostHandler) GetList(ctx *gin.Context) {
 	var (
 		opName = "PostController-GetList"
+		input  dto.PostListReq
 		resp   = []dto.PostRes{}
 		err    error
 	)
 
-	resp, err = c.Service.GetList(ctx)
+	err = ctx.ShouldBindQuery(&input)
+	if err != nil {
+		c.Logger.Errorf("%v error bind query: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrGetRequest())
+		return
+	}
+
+	resp, err = c.Service.GetList(ctx, input)
 	if err != nil {
 		c.Logger.Errorf("%v error: %v ", opName, err)
 		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
 		return
 	}
+
+	// no Last-Modified, a deleted post leaves the newest updated_at as it
+	// was, only the ETag of the body sees the list shrink
 	ctx.JSON(http.StatusOK, resp)
 }
 
@@ -65,7 +100,8 @@ func (c *PostHandler) GetDetail(ctx *gin.Context) {
 	}
 
 	res, err := c.Service.GetDetail(ctx, dto.PostGetReq{
-		ID: id,
+		ID:     id,
+		Render: ctx.Query("render"),
 	})
 
 	if err != nil {
@@ -74,6 +110,70 @@ func (c *PostHandler) GetDetail(ctx *gin.Context) {
 		return
 	}
 
+	SetLastModified(ctx, res.UpdatedAt)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// GetRelated lists the posts sharing the most tags with the post, e.g.
+// ?limit=5.
+func (c *PostHandler) GetRelated(ctx *gin.Context) {
+	var (
+		opName  = "PostController-GetRelated"
+		idParam = strings.TrimSpace(ctx.Param("id"))
+		input   dto.PostRelatedReq
+		err     error
+	)
+
+	err = ctx.ShouldBindQuery(&input)
+	if err != nil {
+		c.Logger.Errorf("%v error bind query: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrGetRequest())
+		return
+	}
+
+	input.ID, err = strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.Logger.Errorf("%v error parse param: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrInvalid("ID Post", "Post ID"))
+		return
+	}
+
+	res, err := c.Service.GetRelated(ctx, input)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// GetBySlug answers a historical slug with 301 and the current location.
+func (c *PostHandler) GetBySlug(ctx *gin.Context) {
+	var (
+		opName = "PostController-GetBySlug"
+		slug   = strings.TrimSpace(ctx.Param("slug"))
+	)
+
+	res, err := c.Service.GetBySlug(ctx, slug)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	if res.Slug != helpers.ToLower(slug) {
+		location := "/api/posts/by-slug/" + url.PathEscape(res.Slug)
+		ctx.Header("Location", location)
+		ctx.JSON(http.StatusMovedPermanently, dto.PostSlugRedirectRes{
+			ID:       res.ID,
+			Slug:     res.Slug,
+			Location: location,
+		})
+		return
+	}
+
+	SetLastModified(ctx, res.UpdatedAt)
 	ctx.JSON(http.StatusOK, res)
 }
 
@@ -157,3 +257,121 @@ func (c *PostHandler) Update(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, dto.ResponseMessage{Message: "Updated data post successfully"})
 }
+
+func (c *PostHandler) UpdateStatus(ctx *gin.Context) {
+	var (
+		opName  = "PostController-UpdateStatus"
+		idParam = strings.TrimSpace(ctx.Param("id"))
+		input   dto.PostStatusReq
+		err     error
+	)
+
+	err = ctx.ShouldBindJSON(&input)
+	if err != nil {
+		c.Logger.Errorf("%v error bind json: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrGetRequest())
+		return
+	}
+
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.Logger.Errorf("%v error parse param: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrInvalid("ID Post", "Post ID"))
+		return
+	}
+
+	input.ID = id
+	err = c.Service.UpdateStatus(ctx, input)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ResponseMessage{Message: "Updated status post successfully"})
+}
+
+func (c *PostHandler) BulkCreate(ctx *gin.Context) {
+	var (
+		opName = "PostController-BulkCreate"
+		input  dto.PostBulkCreateReq
+		err    error
+	)
+
+	err = ctx.ShouldBindJSON(&input)
+	if err != nil {
+		c.Logger.Errorf("%v error bind json: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrGetRequest())
+		return
+	}
+
+	res, err := c.Service.BulkCreate(ctx, input)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(bulkStatusCode(res, http.StatusCreated), res)
+}
+
+func (c *PostHandler) BulkUpdate(ctx *gin.Context) {
+	var (
+		opName = "PostController-BulkUpdate"
+		input  dto.PostBulkUpdateReq
+		err    error
+	)
+
+	err = ctx.ShouldBindJSON(&input)
+	if err != nil {
+		c.Logger.Errorf("%v error bind json: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrGetRequest())
+		return
+	}
+
+	res, err := c.Service.BulkUpdate(ctx, input)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(bulkStatusCode(res, http.StatusOK), res)
+}
+
+func (c *PostHandler) BulkDelete(ctx *gin.Context) {
+	var (
+		opName = "PostController-BulkDelete"
+		input  dto.PostBulkDeleteReq
+		err    error
+	)
+
+	err = ctx.ShouldBindJSON(&input)
+	if err != nil {
+		c.Logger.Errorf("%v error bind json: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrGetRequest())
+		return
+	}
+
+	res, err := c.Service.BulkDelete(ctx, input)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(bulkStatusCode(res, http.StatusOK), res)
+}
+
+// bulkStatusCode is 422 when an atomic request wrote nothing, 207 when only
+// some items of a partial request failed, otherwise the success status.
+func bulkStatusCode(res *dto.PostBulkRes, success int) int {
+	switch {
+	case res.Failed == 0:
+		return success
+	case res.Success == 0:
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusMultiStatus
+	}
+}
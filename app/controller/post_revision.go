@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/gin-gonic/gin"
+)
+
+func (c *PostHandler) GetRevisions(ctx *gin.Context) {
+	var (
+		opName  = "PostController-GetRevisions"
+		idParam = strings.TrimSpace(ctx.Param("id"))
+		err     error
+	)
+
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.Logger.Errorf("%v error parse param: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrInvalid("ID Post", "Post ID"))
+		return
+	}
+
+	res, err := c.Service.GetRevisions(ctx, id)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *PostHandler) GetRevision(ctx *gin.Context) {
+	var (
+		opName = "PostController-GetRevision"
+		err    error
+	)
+
+	req, err := c.parseRevisionParams(ctx, opName)
+	if err != nil {
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := c.Service.GetRevision(ctx, req)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *PostHandler) DiffRevisions(ctx *gin.Context) {
+	var (
+		opName  = "PostController-DiffRevisions"
+		idParam = strings.TrimSpace(ctx.Param("id"))
+		input   dto.PostRevisionDiffReq
+		err     error
+	)
+
+	err = ctx.ShouldBindQuery(&input)
+	if err != nil {
+		c.Logger.Errorf("%v error bind query: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrGetRequest())
+		return
+	}
+
+	input.PostID, err = strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.Logger.Errorf("%v error parse param: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrInvalid("ID Post", "Post ID"))
+		return
+	}
+
+	res, err := c.Service.DiffRevisions(ctx, input)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *PostHandler) RestoreRevision(ctx *gin.Context) {
+	var (
+		opName = "PostController-RestoreRevision"
+		err    error
+	)
+
+	req, err := c.parseRevisionParams(ctx, opName)
+	if err != nil {
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, err)
+		return
+	}
+
+	err = c.Service.RestoreRevision(ctx, req)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ResponseMessage{Message: "Restored data post successfully"})
+}
+
+func (c *PostHandler) parseRevisionParams(ctx *gin.Context, opName string) (dto.PostRevisionGetReq, error) {
+	var (
+		idParam  = strings.TrimSpace(ctx.Param("id"))
+		revParam = strings.TrimSpace(ctx.Param("rev"))
+		req      dto.PostRevisionGetReq
+		err      error
+	)
+
+	req.PostID, err = strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		c.Logger.Errorf("%v error parse param: %v ", opName, err)
+		return req, helpers.ErrInvalid("ID Post", "Post ID")
+	}
+
+	req.Revision, err = strconv.ParseUint(revParam, 10, 32)
+	if err != nil {
+		c.Logger.Errorf("%v error parse param rev: %v ", opName, err)
+		return req, helpers.ErrInvalid("revisi", "revision")
+	}
+
+	return req, nil
+}
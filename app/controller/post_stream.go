@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/gin-gonic/gin"
+)
+
+// Stream sends the post changes as server-sent events, the id of each is
+// the one to resume from with the Last-Event-ID header, or ?last_event_id=
+// for clients that cannot set it. ?tag= and ?descendants= filter like the
+// list.
+func (c *PostHandler) Stream(ctx *gin.Context) {
+	var (
+		opName = "PostController-Stream"
+		input  dto.PostStreamReq
+		err    error
+	)
+
+	err = ctx.ShouldBindQuery(&input)
+	if err != nil {
+		c.Logger.Errorf("%v error bind query: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrGetRequest())
+		return
+	}
+
+	if lastEventID := strings.TrimSpace(ctx.GetHeader("Last-Event-ID")); lastEventID != "" {
+		input.LastEventID, err = strconv.ParseUint(lastEventID, 10, 64)
+		if err != nil {
+			c.Logger.Errorf("%v error parse header: %v ", opName, err)
+			helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrInvalid("Last-Event-ID", "Last-Event-ID"))
+			return
+		}
+	}
+
+	events, err := c.StreamService.Subscribe(ctx.Request.Context(), input)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	heartbeat := time.Duration(c.Cfg.App.StreamHeartbeat) * time.Second
+	if heartbeat <= 0 {
+		heartbeat = 15 * time.Second
+	}
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	// nginx buffers responses unless told otherwise
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+	ctx.Writer.Flush()
+
+	for {
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case <-ticker.C:
+			_, err = fmt.Fprint(ctx.Writer, ": keep-alive\n\n")
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+
+			data, errMarshal := json.Marshal(e)
+			if errMarshal != nil {
+				c.Logger.Errorf("%v error marshal event %d: %v ", opName, e.ID, errMarshal)
+				continue
+			}
+			_, err = fmt.Fprintf(ctx.Writer, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+		}
+		if err != nil {
+			return
+		}
+		ctx.Writer.Flush()
+	}
+}
@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/driver"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/event"
+	"github.com/gin-gonic/gin"
+)
+
+// fakeStream sends events and closes the stream, as when it lags behind.
+type fakeStream struct {
+	ctx    context.Context
+	req    dto.PostStreamReq
+	events []dto.PostStreamEvent
+}
+
+func (f *fakeStream) Notify(ctx context.Context, e event.Event) error { return nil }
+func (f *fakeStream) Receive(ctx context.Context, payload string)     {}
+func (f *fakeStream) Reset()                                          {}
+
+func (f *fakeStream) Subscribe(ctx context.Context, req dto.PostStreamReq) (<-chan dto.PostStreamEvent, error) {
+	f.ctx, f.req = ctx, req
+	result := make(chan dto.PostStreamEvent, len(f.events))
+	for _, v := range f.events {
+		result <- v
+	}
+	close(result)
+	return result, nil
+}
+
+func TestPostHandler_Stream(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var (
+		cfg    = configs.GetInstance()
+		stream = &fakeStream{events: []dto.PostStreamEvent{
+			{ID: 11, Type: dto.PostEventCreated, PostID: 1, After: &dto.PostRes{ID: 1, Title: "First"}},
+			{ID: 12, Type: dto.PostEventDeleted, PostID: 1},
+		}}
+		handler = NewPostDelivery(nil, stream, cfg, driver.Logger(cfg))
+		r       = gin.New()
+	)
+	r.GET("/posts/stream", handler.Stream)
+
+	req := httptest.NewRequest(http.MethodGet, "/posts/stream?tag=go", nil)
+	req.Header.Set("Last-Event-ID", "10")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Stream() = %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	if stream.req.LastEventID != 10 || stream.req.Tag != "go" {
+		t.Errorf("Subscribe() req = %+v, want tag go from 10", stream.req)
+	}
+	// the stream ends with the request, not with the gin context
+	if _, ok := stream.ctx.(*gin.Context); ok {
+		t.Errorf("Subscribe() ctx = %T, want the request context", stream.ctx)
+	}
+
+	body := w.Body.String()
+	for _, want := range []string{
+		"id: 11\nevent: post.created\ndata: {\"id\":11,",
+		"id: 12\nevent: post.deleted\ndata: {\"id\":12,",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body %q does not contain %q", body, want)
+		}
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/posts/stream", nil)
+	req.Header.Set("Last-Event-ID", "abc")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Stream() with a bad Last-Event-ID = %d, want 400", w.Code)
+	}
+}
@@ -0,0 +1,148 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/repository"
+	"github.com/adamnasrudin03/go-asset-findr/app/repository/mocks"
+	"github.com/adamnasrudin03/go-asset-findr/app/service"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/driver"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestPostHandler() (*mocks.PostRepository, *gin.Engine) {
+	gin.SetMode(gin.TestMode)
+	var (
+		cfg      = configs.GetInstance()
+		logger   = driver.Logger(cfg)
+		postRepo = &mocks.PostRepository{}
+		handler  = NewPostDelivery(service.NewPostService(&repository.Repositories{Post: postRepo}, cfg, logger), nil, cfg, logger)
+		r        = gin.New()
+	)
+
+	r.GET("/api/posts", ConditionalGet("no-cache"), handler.GetList)
+	r.GET("/api/posts/by-slug/:slug", ConditionalGet("no-cache"), handler.GetBySlug)
+	return postRepo, r
+}
+
+func TestPostHandler_GetList_Deleted(t *testing.T) {
+	var (
+		postRepo, r = newTestPostHandler()
+		updatedAt   = time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+		posts       = []dto.PostRes{
+			{ID: 1, Title: "one", Status: dto.PostStatusPublished, UpdatedAt: updatedAt},
+			{ID: 2, Title: "two", Status: dto.PostStatusPublished, UpdatedAt: updatedAt.Add(-time.Hour)},
+		}
+	)
+	postRepo.On("GetAll", mock.Anything).Return(func(context.Context) []dto.PostRes {
+		return append([]dto.PostRes{}, posts...)
+	}, nil)
+
+	first := httptest.NewRecorder()
+	r.ServeHTTP(first, httptest.NewRequest(http.MethodGet, "/api/posts", nil))
+	if first.Code != http.StatusOK {
+		t.Fatalf("GetList() = %d", first.Code)
+	}
+
+	// post 2 is deleted, the newest updated_at stays the same
+	posts = posts[:1]
+	req := httptest.NewRequest(http.MethodGet, "/api/posts", nil)
+	req.Header.Set("If-Modified-Since", updatedAt.Format(http.TimeFormat))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("GetList() after a delete = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/posts", nil)
+	req.Header.Set("If-None-Match", first.Header().Get("ETag"))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("GetList() after a delete with the old ETag = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestPostHandler_GetBySlug(t *testing.T) {
+	var (
+		postRepo, r = newTestPostHandler()
+		post        = &dto.PostRes{ID: 7, Title: "Go Generics", Slug: "go-generics", Status: dto.PostStatusPublished}
+	)
+	postRepo.On("FindIDBySlug", mock.Anything, "go-generics").Return(uint64(7), nil)
+	postRepo.On("FindIDBySlug", mock.Anything, "generics-in-go").Return(uint64(7), nil)
+	postRepo.On("FindIDBySlug", mock.Anything, "unknown").Return(uint64(0), helpers.ErrNotFound())
+	postRepo.On("GetDetail", mock.Anything, dto.PostGetReq{ID: 7}).Return(func(context.Context, dto.PostGetReq) *dto.PostRes {
+		res := *post
+		return &res
+	}, nil)
+
+	tests := []struct {
+		name         string
+		slug         string
+		wantStatus   int
+		wantLocation string
+	}{
+		{
+			name:       "current slug",
+			slug:       "go-generics",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "current slug in upper case",
+			slug:       "Go-Generics",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:         "old slug redirects",
+			slug:         "generics-in-go",
+			wantStatus:   http.StatusMovedPermanently,
+			wantLocation: "/api/posts/by-slug/go-generics",
+		},
+		{
+			name:       "unknown slug",
+			slug:       "unknown",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/posts/by-slug/"+tt.slug, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("GetBySlug() = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("GetBySlug() Location = %q, want %q", got, tt.wantLocation)
+			}
+			if tt.wantStatus != http.StatusMovedPermanently {
+				return
+			}
+
+			res := dto.PostSlugRedirectRes{}
+			if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+				t.Fatalf("GetBySlug() body %q: %v", w.Body.String(), err)
+			}
+			want := dto.PostSlugRedirectRes{ID: 7, Slug: "go-generics", Location: tt.wantLocation}
+			if res != want {
+				t.Errorf("GetBySlug() body = %+v, want %+v", res, want)
+			}
+
+			// the redirect leads to the post
+			w = httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.wantLocation, nil))
+			if w.Code != http.StatusOK {
+				t.Errorf("GET Location = %d, want %d", w.Code, http.StatusOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/gin-gonic/gin"
+)
+
+var transferContentTypes = map[string]string{
+	dto.TransferFormatJSONL: "application/x-ndjson",
+	dto.TransferFormatCSV:   "text/csv; charset=utf-8",
+}
+
+func (c *PostHandler) Export(ctx *gin.Context) {
+	var (
+		opName = "PostController-Export"
+		input  dto.PostExportReq
+		err    error
+	)
+
+	err = ctx.ShouldBindQuery(&input)
+	if err != nil {
+		c.Logger.Errorf("%v error bind query: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrGetRequest())
+		return
+	}
+
+	err = input.Validate()
+	if err != nil {
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, err)
+		return
+	}
+
+	fileName := fmt.Sprintf("posts-%s.%s", time.Now().Format("20060102-150405"), input.Format)
+	ctx.Header("Content-Type", transferContentTypes[input.Format])
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	ctx.Status(http.StatusOK)
+
+	// the status is sent with the first row, a failure after it can only
+	// cut the stream short
+	err = c.Service.Export(ctx, input, ctx.Writer)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		ctx.Abort()
+	}
+}
+
+// Import reads the file from the "file" field of a multipart form, or from
+// the raw request body.
+func (c *PostHandler) Import(ctx *gin.Context) {
+	var (
+		opName = "PostController-Import"
+		input  dto.PostImportReq
+		body   io.Reader = ctx.Request.Body
+		err    error
+	)
+
+	err = ctx.ShouldBindQuery(&input)
+	if err != nil {
+		c.Logger.Errorf("%v error bind query: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrGetRequest())
+		return
+	}
+
+	if ctx.ContentType() == gin.MIMEMultipartPOSTForm {
+		fileHeader, err := ctx.FormFile("file")
+		if err != nil {
+			c.Logger.Errorf("%v error form file: %v ", opName, err)
+			helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrIsRequired("file", "file"))
+			return
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.Logger.Errorf("%v error open file: %v ", opName, err)
+			helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrGetRequest())
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	res, err := c.Service.Import(ctx, input, body)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(importStatusCode(res), res)
+}
+
+// importStatusCode is 200 when no row failed, 422 when every row failed and
+// 207 otherwise, like the bulk endpoints. Skipped duplicates are no failure.
+func importStatusCode(res *dto.PostImportRes) int {
+	switch {
+	case res.Failed == 0:
+		return http.StatusOK
+	case res.Failed == res.Total:
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusMultiStatus
+	}
+}
@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/service"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type ReportController interface {
+	TagCooccurrence(ctx *gin.Context)
+	TagHistogram(ctx *gin.Context)
+	TagWeekly(ctx *gin.Context)
+	Refresh(ctx *gin.Context)
+}
+
+type ReportHandler struct {
+	Service service.ReportService
+	Logger  *logrus.Logger
+}
+
+func NewReportDelivery(
+	srv service.ReportService,
+	logger *logrus.Logger,
+) ReportController {
+	return &ReportHandler{
+		Service: srv,
+		Logger:  logger,
+	}
+}
+
+// csvReport is a report that can be sent as a CSV file.
+type csvReport interface {
+	CSV() [][]string
+}
+
+// render sends res as JSON, or as the CSV file name when format is csv.
+func (c *ReportHandler) render(ctx *gin.Context, opName, format, name string, res csvReport) {
+	if format != dto.ReportFormatCSV {
+		ctx.JSON(http.StatusOK, res)
+		return
+	}
+
+	fileName := fmt.Sprintf("%s-%s.csv", name, time.Now().Format("20060102-150405"))
+	ctx.Header("Content-Type", transferContentTypes[dto.TransferFormatCSV])
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	ctx.Status(http.StatusOK)
+
+	err := csv.NewWriter(ctx.Writer).WriteAll(res.CSV())
+	if err != nil {
+		c.Logger.Errorf("%v error write csv: %v ", opName, err)
+		ctx.Abort()
+	}
+}
+
+// TagCooccurrence sends the co-occurrence matrix of the top tags, e.g.
+// ?top=20&format=csv.
+func (c *ReportHandler) TagCooccurrence(ctx *gin.Context) {
+	var (
+		opName = "ReportController-TagCooccurrence"
+		input  dto.TagCooccurrenceReq
+		err    error
+	)
+
+	err = ctx.ShouldBindQuery(&input)
+	if err != nil {
+		c.Logger.Errorf("%v error bind query: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrGetRequest())
+		return
+	}
+
+	res, err := c.Service.TagCooccurrence(ctx, input)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.render(ctx, opName, input.Format, "tag-cooccurrence", res)
+}
+
+func (c *ReportHandler) TagHistogram(ctx *gin.Context) {
+	var (
+		opName = "ReportController-TagHistogram"
+		input  dto.ReportFormatReq
+		err    error
+	)
+
+	err = ctx.ShouldBindQuery(&input)
+	if err != nil {
+		c.Logger.Errorf("%v error bind query: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrGetRequest())
+		return
+	}
+
+	err = input.Validate()
+	if err != nil {
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := c.Service.TagHistogram(ctx)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.render(ctx, opName, input.Format, "tag-histogram", res)
+}
+
+// TagWeekly sends the posts per week of a tag or of the top tags, e.g.
+// ?weeks=12&tag=go or ?weeks=26&top=5&format=csv.
+func (c *ReportHandler) TagWeekly(ctx *gin.Context) {
+	var (
+		opName = "ReportController-TagWeekly"
+		input  dto.TagWeeklyReq
+		err    error
+	)
+
+	err = ctx.ShouldBindQuery(&input)
+	if err != nil {
+		c.Logger.Errorf("%v error bind query: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrGetRequest())
+		return
+	}
+
+	res, err := c.Service.TagWeekly(ctx, input)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	c.render(ctx, opName, input.Format, "tag-weekly", res)
+}
+
+func (c *ReportHandler) Refresh(ctx *gin.Context) {
+	opName := "ReportController-Refresh"
+
+	res, err := c.Service.Refresh(ctx)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,222 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/service"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type TagController interface {
+	GetTree(ctx *gin.Context)
+	SetParent(ctx *gin.Context)
+	AddSynonym(ctx *gin.Context)
+	DeleteSynonym(ctx *gin.Context)
+	Merge(ctx *gin.Context)
+	Suggest(ctx *gin.Context)
+	Popular(ctx *gin.Context)
+}
+
+type TagHandler struct {
+	Service service.TagService
+	Logger  *logrus.Logger
+}
+
+func NewTagDelivery(
+	srv service.TagService,
+	logger *logrus.Logger,
+) TagController {
+	return &TagHandler{
+		Service: srv,
+		Logger:  logger,
+	}
+}
+
+func (c *TagHandler) GetTree(ctx *gin.Context) {
+	opName := "TagController-GetTree"
+
+	res, err := c.Service.GetTree(ctx)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// SetParent moves the tag under parent_id, null moves it to the top.
+func (c *TagHandler) SetParent(ctx *gin.Context) {
+	var (
+		opName = "TagController-SetParent"
+		input  dto.TagParentReq
+		err    error
+	)
+
+	err = ctx.ShouldBindJSON(&input)
+	if err != nil {
+		c.Logger.Errorf("%v error bind json: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrGetRequest())
+		return
+	}
+
+	input.ID, err = c.parseID(ctx, opName)
+	if err != nil {
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, err)
+		return
+	}
+
+	err = c.Service.SetParent(ctx, input)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ResponseMessage{Message: "Updated parent tag successfully"})
+}
+
+func (c *TagHandler) AddSynonym(ctx *gin.Context) {
+	var (
+		opName = "TagController-AddSynonym"
+		input  dto.TagSynonymReq
+		err    error
+	)
+
+	err = ctx.ShouldBindJSON(&input)
+	if err != nil {
+		c.Logger.Errorf("%v error bind json: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrGetRequest())
+		return
+	}
+
+	input.TagID, err = c.parseID(ctx, opName)
+	if err != nil {
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, err)
+		return
+	}
+
+	err = c.Service.AddSynonym(ctx, input)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.ResponseMessage{Message: "Created synonym tag successfully"})
+}
+
+func (c *TagHandler) DeleteSynonym(ctx *gin.Context) {
+	var (
+		opName = "TagController-DeleteSynonym"
+		err    error
+	)
+
+	tagID, err := c.parseID(ctx, opName)
+	if err != nil {
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, err)
+		return
+	}
+
+	err = c.Service.DeleteSynonym(ctx, dto.TagSynonymReq{TagID: tagID, Label: ctx.Param("label")})
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ResponseMessage{Message: "Deleted synonym tag successfully"})
+}
+
+// Merge folds the tags of source_ids into the tag of the path.
+func (c *TagHandler) Merge(ctx *gin.Context) {
+	var (
+		opName = "TagController-Merge"
+		input  dto.TagMergeReq
+		err    error
+	)
+
+	err = ctx.ShouldBindJSON(&input)
+	if err != nil {
+		c.Logger.Errorf("%v error bind json: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrGetRequest())
+		return
+	}
+
+	input.TargetID, err = c.parseID(ctx, opName)
+	if err != nil {
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, err)
+		return
+	}
+
+	err = c.Service.Merge(ctx, input)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ResponseMessage{Message: "Merged data tag successfully"})
+}
+
+func (c *TagHandler) Suggest(ctx *gin.Context) {
+	var (
+		opName = "TagController-Suggest"
+		input  dto.TagSuggestReq
+		err    error
+	)
+
+	err = ctx.ShouldBindQuery(&input)
+	if err != nil {
+		c.Logger.Errorf("%v error bind query: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrGetRequest())
+		return
+	}
+
+	res, err := c.Service.Suggest(ctx, input)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *TagHandler) Popular(ctx *gin.Context) {
+	var (
+		opName = "TagController-Popular"
+		input  dto.TagPopularReq
+		err    error
+	)
+
+	err = ctx.ShouldBindQuery(&input)
+	if err != nil {
+		c.Logger.Errorf("%v error bind query: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrGetRequest())
+		return
+	}
+
+	res, err := c.Service.Popular(ctx, input)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *TagHandler) parseID(ctx *gin.Context, opName string) (uint64, error) {
+	id, err := strconv.ParseUint(strings.TrimSpace(ctx.Param("id")), 10, 32)
+	if err != nil {
+		c.Logger.Errorf("%v error parse param: %v ", opName, err)
+		return 0, helpers.ErrInvalid("ID Tag", "Tag ID")
+	}
+	return id, nil
+}
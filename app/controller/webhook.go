@@ -0,0 +1,218 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/service"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type WebhookController interface {
+	Create(ctx *gin.Context)
+	GetList(ctx *gin.Context)
+	GetDetail(ctx *gin.Context)
+	Update(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+	GetDeliveries(ctx *gin.Context)
+	Redeliver(ctx *gin.Context)
+}
+
+type WebhookHandler struct {
+	Service service.WebhookService
+	Logger  *logrus.Logger
+}
+
+func NewWebhookDelivery(
+	srv service.WebhookService,
+	logger *logrus.Logger,
+) WebhookController {
+	return &WebhookHandler{
+		Service: srv,
+		Logger:  logger,
+	}
+}
+
+// Create adds a webhook, the response has its secret, the only time it is
+// shown.
+func (c *WebhookHandler) Create(ctx *gin.Context) {
+	var (
+		opName = "WebhookController-Create"
+		input  dto.WebhookReq
+		err    error
+	)
+
+	err = ctx.ShouldBindJSON(&input)
+	if err != nil {
+		c.Logger.Errorf("%v error bind json: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrGetRequest())
+		return
+	}
+
+	res, err := c.Service.Create(ctx, input)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, res)
+}
+
+func (c *WebhookHandler) GetList(ctx *gin.Context) {
+	opName := "WebhookController-GetList"
+
+	res, err := c.Service.GetList(ctx)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *WebhookHandler) GetDetail(ctx *gin.Context) {
+	opName := "WebhookController-GetDetail"
+
+	id, err := c.parseID(ctx, opName, "id")
+	if err != nil {
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := c.Service.GetDetail(ctx, id)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// Update replaces the webhook, an empty secret keeps the current one.
+func (c *WebhookHandler) Update(ctx *gin.Context) {
+	var (
+		opName = "WebhookController-Update"
+		input  dto.WebhookReq
+		err    error
+	)
+
+	err = ctx.ShouldBindJSON(&input)
+	if err != nil {
+		c.Logger.Errorf("%v error bind json: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrGetRequest())
+		return
+	}
+
+	input.ID, err = c.parseID(ctx, opName, "id")
+	if err != nil {
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := c.Service.Update(ctx, input)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *WebhookHandler) Delete(ctx *gin.Context) {
+	opName := "WebhookController-Delete"
+
+	id, err := c.parseID(ctx, opName, "id")
+	if err != nil {
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, err)
+		return
+	}
+
+	err = c.Service.DeleteByID(ctx, id)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ResponseMessage{Message: "Deleted data webhook successfully"})
+}
+
+// GetDeliveries lists the deliveries of the webhook, newest first,
+// filtered by ?status=.
+func (c *WebhookHandler) GetDeliveries(ctx *gin.Context) {
+	var (
+		opName = "WebhookController-GetDeliveries"
+		input  dto.WebhookDeliveryListReq
+		err    error
+	)
+
+	err = ctx.ShouldBindQuery(&input)
+	if err != nil {
+		c.Logger.Errorf("%v error bind query: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, helpers.ErrGetRequest())
+		return
+	}
+
+	input.WebhookID, err = c.parseID(ctx, opName, "id")
+	if err != nil {
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := c.Service.GetDeliveries(ctx, input)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (c *WebhookHandler) Redeliver(ctx *gin.Context) {
+	var (
+		opName = "WebhookController-Redeliver"
+		input  dto.WebhookRedeliverReq
+		err    error
+	)
+
+	input.WebhookID, err = c.parseID(ctx, opName, "id")
+	if err != nil {
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, err)
+		return
+	}
+	input.DeliveryID, err = c.parseID(ctx, opName, "delivery_id")
+	if err != nil {
+		helpers.RenderJSON(ctx.Writer, http.StatusBadRequest, err)
+		return
+	}
+
+	res, err := c.Service.Redeliver(ctx, input)
+	if err != nil {
+		c.Logger.Errorf("%v error: %v ", opName, err)
+		helpers.RenderJSON(ctx.Writer, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, res)
+}
+
+func (c *WebhookHandler) parseID(ctx *gin.Context, opName, param string) (uint64, error) {
+	id, err := strconv.ParseUint(strings.TrimSpace(ctx.Param(param)), 10, 32)
+	if err != nil {
+		c.Logger.Errorf("%v error parse param %s: %v ", opName, param, err)
+		if param == "delivery_id" {
+			return 0, helpers.ErrInvalid("ID Pengiriman", "Delivery ID")
+		}
+		return 0, helpers.ErrInvalid("ID Webhook", "Webhook ID")
+	}
+	return id, nil
+}
@@ -0,0 +1,104 @@
+package dto
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+)
+
+const (
+	AssetListDefaultLimit = 50
+	AssetListMaxLimit     = 200
+)
+
+// assetFilterColumns maps the filter fields to their columns, numeric ones
+// take any comparison, mime only = and != with an optional "type/*".
+var assetFilterColumns = map[string]string{
+	"width":  "width",
+	"height": "height",
+	"pages":  "page_count",
+	"size":   "size",
+	"mime":   "mime_type",
+	"status": "meta_status",
+}
+
+var assetFilterRe = regexp.MustCompile(`^\s*([a-z]+)\s*(>=|<=|!=|=|>|<)\s*(\S+)\s*$`)
+
+// AssetFilter is one parsed condition, Column and Op are safe to put in SQL.
+type AssetFilter struct {
+	Column string
+	Op     string
+	Value  interface{}
+	// IsPrefix makes Value, a string, match as a prefix, e.g. "image/*".
+	IsPrefix bool
+}
+
+type AssetListReq struct {
+	// Filters are conditions like "width>=1024" or "mime=image/*", all of
+	// them must match.
+	Filters []string `form:"filter" json:"filters"`
+	Limit   int      `form:"limit" json:"limit"`
+	Offset  int      `form:"offset" json:"offset"`
+
+	Parsed []AssetFilter `form:"-" json:"-"`
+}
+
+func (m *AssetListReq) Validate() error {
+	if m.Limit <= 0 {
+		m.Limit = AssetListDefaultLimit
+	}
+	if m.Limit > AssetListMaxLimit {
+		return helpers.ErrCannotBeMoreThan("limit", "limit", strconv.Itoa(AssetListMaxLimit))
+	}
+	if m.Offset < 0 {
+		return helpers.ErrInvalid("offset", "offset")
+	}
+
+	m.Parsed = make([]AssetFilter, 0, len(m.Filters))
+	for _, v := range m.Filters {
+		filter, err := parseAssetFilter(v)
+		if err != nil {
+			return err
+		}
+		m.Parsed = append(m.Parsed, filter)
+	}
+
+	return nil
+}
+
+func parseAssetFilter(s string) (AssetFilter, error) {
+	errInvalid := helpers.ErrInvalid("filter "+s, "filter "+s)
+
+	match := assetFilterRe.FindStringSubmatch(strings.ToLower(s))
+	if match == nil {
+		return AssetFilter{}, errInvalid
+	}
+
+	var (
+		field, op, value = match[1], match[2], match[3]
+		result           = AssetFilter{Column: assetFilterColumns[field], Op: op}
+	)
+	switch field {
+	case "mime", "status":
+		if op != "=" && op != "!=" {
+			return result, errInvalid
+		}
+		if field == "mime" && strings.HasSuffix(value, "/*") {
+			result.IsPrefix = true
+			value = strings.TrimSuffix(value, "*")
+		}
+		result.Value = value
+	case "width", "height", "pages", "size":
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return result, errInvalid
+		}
+		result.Value = number
+	default:
+		return result, errInvalid
+	}
+
+	return result, nil
+}
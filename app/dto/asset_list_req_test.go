@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAssetListReq_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		m          *AssetListReq
+		wantParsed []AssetFilter
+		wantErr    bool
+	}{
+		{
+			name:    "limit too big",
+			m:       &AssetListReq{Limit: AssetListMaxLimit + 1},
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			m:       &AssetListReq{Filters: []string{"owner=1"}},
+			wantErr: true,
+		},
+		{
+			name:    "mime compared",
+			m:       &AssetListReq{Filters: []string{"mime>=image/png"}},
+			wantErr: true,
+		},
+		{
+			name:    "width not a number",
+			m:       &AssetListReq{Filters: []string{"width>=big"}},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			m:    &AssetListReq{Filters: []string{"width>=1024", " MIME = image/* ", "pages<3"}},
+			wantParsed: []AssetFilter{
+				{Column: "width", Op: ">=", Value: int64(1024)},
+				{Column: "mime_type", Op: "=", Value: "image/", IsPrefix: true},
+				{Column: "page_count", Op: "<", Value: int64(3)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.m.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("AssetListReq.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.m.Limit != AssetListDefaultLimit {
+				t.Errorf("AssetListReq.Validate() limit = %v, want %v", tt.m.Limit, AssetListDefaultLimit)
+			}
+			if !reflect.DeepEqual(tt.m.Parsed, tt.wantParsed) {
+				t.Errorf("AssetListReq.Validate() parsed = %v, want %v", tt.m.Parsed, tt.wantParsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package dto
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+)
+
+const (
+	AssetMaxUploadFiles = 10  // files of one upload request
+	AssetMaxAttach      = 100 // assets of one attach request
+
+	AssetMetaPending    = "pending"
+	AssetMetaProcessing = "processing"
+	AssetMetaDone       = "done"
+	AssetMetaFailed     = "failed"
+)
+
+type AssetUploadReq struct {
+	Filename   string    `json:"filename"`
+	Size       int64     `json:"size"`
+	File       io.Reader `json:"-"`
+	UploaderID uint64    `json:"uploader_id"`
+	// StripGPS removes the GPS location from the EXIF data of a JPEG
+	// before it is stored.
+	StripGPS bool `json:"strip_gps"`
+}
+
+// Validate keeps only the base name of Filename, browsers may send a path.
+func (m *AssetUploadReq) Validate() error {
+	m.Filename = strings.TrimSpace(filepath.Base(strings.ReplaceAll(m.Filename, "\\", "/")))
+	if m.Filename == "" || m.Filename == "." || m.Filename == "/" {
+		return helpers.ErrIsRequired("nama file", "filename")
+	}
+	if len(m.Filename) > 255 {
+		return helpers.ErrInvalid("nama file", "filename")
+	}
+
+	if m.File == nil || m.Size <= 0 {
+		return helpers.ErrIsRequired("file", "file")
+	}
+
+	return nil
+}
+
+type PostAssetReq struct {
+	PostID   uint64   `json:"post_id"`
+	AssetIDs []uint64 `json:"asset_ids"`
+}
+
+func (m *PostAssetReq) Validate() error {
+	if m.PostID == 0 {
+		return helpers.ErrIsRequired("id post", "post id")
+	}
+
+	// filter duplicate value
+	isExist := map[uint64]bool{}
+	ids := []uint64{}
+	for _, v := range m.AssetIDs {
+		if v == 0 || isExist[v] {
+			continue
+		}
+
+		isExist[v] = true
+		ids = append(ids, v)
+	}
+	m.AssetIDs = ids
+
+	if len(m.AssetIDs) == 0 {
+		return helpers.ErrIsRequired("id aset", "asset_ids")
+	}
+	if len(m.AssetIDs) > AssetMaxAttach {
+		return helpers.NewError(helpers.ErrValidation, helpers.NewResponseMultiLang(
+			helpers.MultiLanguages{
+				ID: fmt.Sprintf("Maksimal %d aset per permintaan", AssetMaxAttach),
+				EN: fmt.Sprintf("At most %d assets per request", AssetMaxAttach),
+			}))
+	}
+
+	return nil
+}
+
+// ErrAssetTooLarge is reported for a file over the upload size limit.
+func ErrAssetTooLarge(maxSize int64) *helpers.ResponseError {
+	return helpers.NewError(helpers.ErrValidation, helpers.NewResponseMultiLang(
+		helpers.MultiLanguages{
+			ID: fmt.Sprintf("Ukuran file maksimal %d byte", maxSize),
+			EN: fmt.Sprintf("File size must be at most %d bytes", maxSize),
+		}))
+}
+
+// ErrAssetType is reported for a file whose content is not an allowed type.
+func ErrAssetType(mimeType string) *helpers.ResponseError {
+	return helpers.NewError(helpers.ErrValidation, helpers.NewResponseMultiLang(
+		helpers.MultiLanguages{
+			ID: "Tipe file " + mimeType + " tidak diizinkan",
+			EN: "File type " + mimeType + " is not allowed",
+		}))
+}
+
+// ErrAssetStripGPS is reported for a JPEG whose GPS location can not be
+// removed, its EXIF data being cut off.
+func ErrAssetStripGPS() *helpers.ResponseError {
+	return helpers.NewError(helpers.ErrValidation, helpers.NewResponseMultiLang(
+		helpers.MultiLanguages{
+			ID: "Lokasi GPS pada file gagal dihapus",
+			EN: "Failed to remove the GPS location of the file",
+		}))
+}
+
+// ErrAssetStorage is reported when the file can not be written to or read
+// from the storage.
+func ErrAssetStorage() *helpers.ResponseError {
+	return helpers.NewError(helpers.ErrDatabase, helpers.NewResponseMultiLang(
+		helpers.MultiLanguages{
+			ID: "Gagal mengakses penyimpanan file",
+			EN: "Failed to access the file storage",
+		}))
+}
@@ -0,0 +1,91 @@
+package dto
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAssetUploadReq_Validate(t *testing.T) {
+	tests := []struct {
+		name         string
+		m            *AssetUploadReq
+		wantFilename string
+		wantErr      bool
+	}{
+		{
+			name:    "filename required",
+			m:       &AssetUploadReq{Filename: " ", Size: 1, File: strings.NewReader("a")},
+			wantErr: true,
+		},
+		{
+			name:    "file required",
+			m:       &AssetUploadReq{Filename: "a.txt", Size: 0},
+			wantErr: true,
+		},
+		{
+			name:    "filename too long",
+			m:       &AssetUploadReq{Filename: strings.Repeat("a", 256), Size: 1, File: strings.NewReader("a")},
+			wantErr: true,
+		},
+		{
+			name:         "success path is dropped",
+			m:            &AssetUploadReq{Filename: `C:\Users\me\photo.png`, Size: 1, File: strings.NewReader("a")},
+			wantFilename: "photo.png",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.m.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("AssetUploadReq.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && tt.m.Filename != tt.wantFilename {
+				t.Errorf("AssetUploadReq.Validate() filename = %v, want %v", tt.m.Filename, tt.wantFilename)
+			}
+		})
+	}
+}
+
+func TestPostAssetReq_Validate(t *testing.T) {
+	tooMany := make([]uint64, AssetMaxAttach+1)
+	for i := range tooMany {
+		tooMany[i] = uint64(i + 1)
+	}
+
+	tests := []struct {
+		name    string
+		m       *PostAssetReq
+		wantIDs []uint64
+		wantErr bool
+	}{
+		{
+			name:    "post id required",
+			m:       &PostAssetReq{AssetIDs: []uint64{1}},
+			wantErr: true,
+		},
+		{
+			name:    "asset ids required",
+			m:       &PostAssetReq{PostID: 1, AssetIDs: []uint64{0}},
+			wantErr: true,
+		},
+		{
+			name:    "too many asset ids",
+			m:       &PostAssetReq{PostID: 1, AssetIDs: tooMany},
+			wantErr: true,
+		},
+		{
+			name:    "success duplicates are dropped",
+			m:       &PostAssetReq{PostID: 1, AssetIDs: []uint64{2, 0, 3, 2}},
+			wantIDs: []uint64{2, 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.m.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("PostAssetReq.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && len(tt.m.AssetIDs) != len(tt.wantIDs) {
+				t.Errorf("PostAssetReq.Validate() asset ids = %v, want %v", tt.m.AssetIDs, tt.wantIDs)
+			}
+		})
+	}
+}
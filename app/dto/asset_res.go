@@ -0,0 +1,129 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+)
+
+type AssetRes struct {
+	ID          uint64              `json:"id"`
+	Filename    string              `json:"filename"`
+	MimeType    string              `json:"mime_type"`
+	Size        int64               `json:"size"`
+	Checksum    string              `json:"checksum"`
+	UploaderID  uint64              `json:"uploader_id"`
+	DownloadURL string              `json:"download_url"`
+	Width       int                 `json:"width,omitempty"`
+	Height      int                 `json:"height,omitempty"`
+	PageCount   int                 `json:"page_count,omitempty"`
+	EXIF        map[string]string   `json:"exif,omitempty"`
+	MetaStatus  string              `json:"meta_status"`
+	Thumbnails  []AssetThumbnailRes `json:"thumbnails"`
+	StorageKey  string              `json:"-"`
+	CreatedAt   time.Time           `json:"created_at"`
+}
+
+func NewAssetRes(m models.Asset) AssetRes {
+	thumbnails := make([]AssetThumbnailRes, 0, len(m.Thumbnails))
+	for _, v := range m.Thumbnails {
+		thumbnails = append(thumbnails, NewAssetThumbnailRes(v))
+	}
+
+	var exif map[string]string
+	if len(m.EXIF) > 0 {
+		exif = m.EXIF
+	}
+
+	return AssetRes{
+		ID:          m.ID,
+		Filename:    m.Filename,
+		MimeType:    m.MimeType,
+		Size:        m.Size,
+		Checksum:    m.Checksum,
+		UploaderID:  m.UploaderID,
+		DownloadURL: fmt.Sprintf("/api/assets/%d/download", m.ID),
+		Width:       m.Width,
+		Height:      m.Height,
+		PageCount:   m.PageCount,
+		EXIF:        exif,
+		MetaStatus:  m.MetaStatus,
+		Thumbnails:  thumbnails,
+		StorageKey:  m.StorageKey,
+		CreatedAt:   m.CreatedAt,
+	}
+}
+
+// Thumbnail returns the thumbnail made for size, nil when there is none.
+func (m *AssetRes) Thumbnail(size int) *AssetThumbnailRes {
+	for i := range m.Thumbnails {
+		if m.Thumbnails[i].Size == size {
+			return &m.Thumbnails[i]
+		}
+	}
+	return nil
+}
+
+type AssetThumbnailRes struct {
+	Size       int    `json:"size"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	MimeType   string `json:"mime_type"`
+	ByteSize   int64  `json:"byte_size"`
+	URL        string `json:"url"`
+	StorageKey string `json:"-"`
+}
+
+func NewAssetThumbnailRes(m models.AssetThumbnail) AssetThumbnailRes {
+	return AssetThumbnailRes{
+		Size:       m.Size,
+		Width:      m.Width,
+		Height:     m.Height,
+		MimeType:   m.MimeType,
+		ByteSize:   m.ByteSize,
+		URL:        fmt.Sprintf("/api/assets/%d/thumbnails/%d", m.AssetID, m.Size),
+		StorageKey: m.StorageKey,
+	}
+}
+
+type AssetUploadItemRes struct {
+	Index    int                    `json:"index"`
+	Filename string                 `json:"filename"`
+	Status   string                 `json:"status"`
+	Asset    *AssetRes              `json:"asset,omitempty"`
+	Error    *helpers.ResponseError `json:"error,omitempty"`
+}
+
+// SetError marks the item failed, see PostBulkItemRes.SetError.
+func (m *AssetUploadItemRes) SetError(err error, fallback *helpers.ResponseError) {
+	m.Status = BulkStatusFailed
+	m.Error = fallback
+
+	var respErr *helpers.ResponseError
+	if errors.As(err, &respErr) {
+		m.Error = respErr
+	}
+}
+
+type AssetUploadRes struct {
+	Total   int                  `json:"total"`
+	Success int                  `json:"success"`
+	Failed  int                  `json:"failed"`
+	Items   []AssetUploadItemRes `json:"items"`
+}
+
+// CountResult fills the totals from the items.
+func (m *AssetUploadRes) CountResult() {
+	m.Total = len(m.Items)
+	m.Success, m.Failed = 0, 0
+	for _, v := range m.Items {
+		if v.Status == BulkStatusSuccess {
+			m.Success++
+			continue
+		}
+		m.Failed++
+	}
+}
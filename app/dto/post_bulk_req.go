@@ -0,0 +1,104 @@
+package dto
+
+import (
+	"fmt"
+
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+)
+
+const (
+	BulkModeAtomic  = "atomic"  // all items are written or none
+	BulkModePartial = "partial" // valid items are written, failures are reported
+
+	BulkMaxItems = 5000
+)
+
+type PostBulkCreateReq struct {
+	Mode  string          `json:"mode"`
+	Items []PostCreateReq `json:"items"`
+}
+
+func (m *PostBulkCreateReq) Validate() error {
+	err := validateBulkMode(&m.Mode)
+	if err != nil {
+		return err
+	}
+
+	return validateBulkLen(len(m.Items))
+}
+
+func (m *PostBulkCreateReq) IsAtomic() bool {
+	return m.Mode == BulkModeAtomic
+}
+
+type PostBulkUpdateReq struct {
+	Mode  string          `json:"mode"`
+	Items []PostUpdateReq `json:"items"`
+}
+
+func (m *PostBulkUpdateReq) Validate() error {
+	err := validateBulkMode(&m.Mode)
+	if err != nil {
+		return err
+	}
+
+	return validateBulkLen(len(m.Items))
+}
+
+func (m *PostBulkUpdateReq) IsAtomic() bool {
+	return m.Mode == BulkModeAtomic
+}
+
+type PostBulkDeleteReq struct {
+	Mode string   `json:"mode"`
+	IDs  []uint64 `json:"ids"`
+}
+
+func (m *PostBulkDeleteReq) Validate() error {
+	err := validateBulkMode(&m.Mode)
+	if err != nil {
+		return err
+	}
+
+	// filter duplicate value
+	idsNotDuplicate := map[uint64]bool{}
+	ids := []uint64{}
+	for _, v := range m.IDs {
+		if v == 0 || idsNotDuplicate[v] {
+			continue
+		}
+
+		idsNotDuplicate[v] = true
+		ids = append(ids, v)
+	}
+	m.IDs = ids
+
+	return validateBulkLen(len(m.IDs))
+}
+
+func (m *PostBulkDeleteReq) IsAtomic() bool {
+	return m.Mode == BulkModeAtomic
+}
+
+func validateBulkMode(mode *string) error {
+	*mode = helpers.ToLower(*mode)
+	if *mode == "" {
+		*mode = BulkModeAtomic
+	}
+
+	if *mode != BulkModeAtomic && *mode != BulkModePartial {
+		return helpers.ErrInvalid("mode", "mode")
+	}
+	return nil
+}
+
+func validateBulkLen(total int) error {
+	if total == 0 {
+		return helpers.ErrIsRequired("item", "items")
+	}
+
+	if total > BulkMaxItems {
+		return helpers.ErrCannotBeMoreThan("item", "items", fmt.Sprint(BulkMaxItems))
+	}
+	return nil
+}
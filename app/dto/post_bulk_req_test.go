@@ -0,0 +1,131 @@
+package dto
+
+import "testing"
+
+func TestPostBulkCreateReq_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		m        *PostBulkCreateReq
+		wantMode string
+		wantErr  bool
+	}{
+		{
+			name: "invalid mode",
+			m: &PostBulkCreateReq{
+				Mode:  "some",
+				Items: []PostCreateReq{{Title: "title", Content: "content"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "items required",
+			m: &PostBulkCreateReq{
+				Mode: BulkModeAtomic,
+			},
+			wantErr: true,
+		},
+		{
+			name: "too many items",
+			m: &PostBulkCreateReq{
+				Items: make([]PostCreateReq, BulkMaxItems+1),
+			},
+			wantErr: true,
+		},
+		{
+			name: "success default mode",
+			m: &PostBulkCreateReq{
+				Items: []PostCreateReq{{Title: "title", Content: "content"}},
+			},
+			wantMode: BulkModeAtomic,
+			wantErr:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.m.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("PostBulkCreateReq.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantMode != "" && tt.m.Mode != tt.wantMode {
+				t.Errorf("PostBulkCreateReq.Validate() mode = %v, want %v", tt.m.Mode, tt.wantMode)
+			}
+		})
+	}
+}
+
+func TestPostBulkDeleteReq_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       *PostBulkDeleteReq
+		wantIDs int
+		wantErr bool
+	}{
+		{
+			name: "ids required",
+			m: &PostBulkDeleteReq{
+				IDs: []uint64{0},
+			},
+			wantErr: true,
+		},
+		{
+			name: "success filter duplicate",
+			m: &PostBulkDeleteReq{
+				Mode: "Partial",
+				IDs:  []uint64{1, 2, 1},
+			},
+			wantIDs: 2,
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.m.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("PostBulkDeleteReq.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantIDs != 0 && len(tt.m.IDs) != tt.wantIDs {
+				t.Errorf("PostBulkDeleteReq.Validate() ids = %v, want %v items", tt.m.IDs, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestPostBulkUpdateReq_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		m        *PostBulkUpdateReq
+		wantMode string
+		wantErr  bool
+	}{
+		{
+			name: "invalid mode",
+			m: &PostBulkUpdateReq{
+				Mode:  "some",
+				Items: []PostUpdateReq{{ID: 1, Title: "title", Content: "content"}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "items required",
+			m:       &PostBulkUpdateReq{},
+			wantErr: true,
+		},
+		{
+			name: "success",
+			m: &PostBulkUpdateReq{
+				Mode:  "Partial",
+				Items: []PostUpdateReq{{ID: 1, Title: "title", Content: "content"}},
+			},
+			wantMode: BulkModePartial,
+			wantErr:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.m.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("PostBulkUpdateReq.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantMode != "" && tt.m.Mode != tt.wantMode {
+				t.Errorf("PostBulkUpdateReq.Validate() mode = %v, want %v", tt.m.Mode, tt.wantMode)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"errors"
+
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+)
+
+const (
+	BulkStatusSuccess = "success"
+	BulkStatusFailed  = "failed"
+)
+
+type PostBulkItemRes struct {
+	Index  int                    `json:"index"`
+	ID     uint64                 `json:"id,omitempty"`
+	Status string                 `json:"status"`
+	Error  *helpers.ResponseError `json:"error,omitempty"`
+}
+
+// SetError marks the item failed, errors that are not a ResponseError are
+// replaced by fallback so internal details do not leak to the client.
+func (m *PostBulkItemRes) SetError(err error, fallback *helpers.ResponseError) {
+	m.Status = BulkStatusFailed
+	m.Error = fallback
+
+	var respErr *helpers.ResponseError
+	if errors.As(err, &respErr) {
+		m.Error = respErr
+	}
+}
+
+type PostBulkRes struct {
+	Mode    string            `json:"mode"`
+	Total   int               `json:"total"`
+	Success int               `json:"success"`
+	Failed  int               `json:"failed"`
+	Items   []PostBulkItemRes `json:"items"`
+}
+
+// CountResult fills the totals from the items.
+func (m *PostBulkRes) CountResult() {
+	m.Total = len(m.Items)
+	m.Success, m.Failed = 0, 0
+	for _, v := range m.Items {
+		if v.Status == BulkStatusSuccess {
+			m.Success++
+			continue
+		}
+		m.Failed++
+	}
+}
+
+// ErrBulkCanceled is reported for items rolled back because another item of
+// an atomic bulk request failed.
+func ErrBulkCanceled() *helpers.ResponseError {
+	return helpers.NewError(helpers.ErrFromUseCase, helpers.NewResponseMultiLang(
+		helpers.MultiLanguages{
+			ID: "Dibatalkan karena item lain gagal",
+			EN: "Canceled because another item failed",
+		}))
+}
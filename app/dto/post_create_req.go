@@ -2,14 +2,22 @@ package dto
 
 import (
 	"strings"
+	"time"
 
+	"github.com/adamnasrudin03/go-asset-findr/pkg/render"
 	"github.com/adamnasrudin03/go-template/pkg/helpers"
 )
 
 type PostCreateReq struct {
-	Title   string   `json:"title"`
-	Content string   `json:"content"`
-	Tags    []string `json:"tags"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
+	// ContentFormat is plain, markdown or html, plain by default.
+	ContentFormat string   `json:"content_format"`
+	Tags          []string `json:"tags"`
+	// Status defaults to draft, a post can not be created archived and only
+	// editors may create it scheduled or published.
+	Status    string     `json:"status"`
+	PublishAt *time.Time `json:"publish_at"`
 }
 
 func (m *PostCreateReq) Validate() error {
@@ -23,6 +31,14 @@ func (m *PostCreateReq) Validate() error {
 		return helpers.ErrIsRequired("konten", "content")
 	}
 
+	err := validateContentFormat(&m.ContentFormat)
+	if err != nil {
+		return err
+	}
+	if m.ContentFormat == "" {
+		m.ContentFormat = render.FormatPlain
+	}
+
 	// filter duplicate value
 	tagsNotDuplicate := map[string]bool{}
 	tags := []string{}
@@ -38,5 +54,13 @@ func (m *PostCreateReq) Validate() error {
 
 	m.Tags = tags
 
-	return nil
+	m.Status = helpers.ToLower(m.Status)
+	if m.Status == "" {
+		m.Status = PostStatusDraft
+	}
+	if !IsValidPostStatus(m.Status) || m.Status == PostStatusArchived {
+		return helpers.ErrInvalid("status", "status")
+	}
+
+	return validatePublishAt(m.Status, m.PublishAt)
 }
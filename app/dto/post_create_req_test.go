@@ -25,6 +25,15 @@ func TestPostCreateReq_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "archived not allowed",
+			m: &PostCreateReq{
+				Title:   "title 1",
+				Content: "content 2",
+				Status:  PostStatusArchived,
+			},
+			wantErr: true,
+		},
 		{
 			name: "success",
 			m: &PostCreateReq{
@@ -0,0 +1,16 @@
+package dto
+
+const (
+	AggregatePost = "post"
+
+	PostEventCreated = "post.created"
+	PostEventUpdated = "post.updated"
+	PostEventDeleted = "post.deleted"
+)
+
+// PostEventPayload is the post before and after the change, Before is nil
+// for post.created and After for post.deleted. Tags are the stored labels.
+type PostEventPayload struct {
+	Before *PostRes `json:"before"`
+	After  *PostRes `json:"after"`
+}
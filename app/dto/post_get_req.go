@@ -7,6 +7,10 @@ import (
 type PostGetReq struct {
 	ID           uint64 `json:"id"`
 	ColumnCustom string `json:"column_custom"`
+	// Render picks what Content of the response holds, see PostRes.Represent.
+	Render string `json:"render" form:"render"`
+	// IsLock locks the post row until the end of the current transaction.
+	IsLock bool `json:"-"`
 }
 
 func (m *PostGetReq) Validate() error {
@@ -15,5 +19,22 @@ func (m *PostGetReq) Validate() error {
 		return helpers.ErrIsRequired("id", "id")
 	}
 
+	var err error
+	m.Render, err = ValidateRender(m.Render)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
+
+// ValidateRender returns render lower cased when it is a mode of
+// PostRes.Represent, empty keeps the content as written.
+func ValidateRender(render string) (string, error) {
+	render = helpers.ToLower(render)
+	if render != "" && render != RenderHTML && render != RenderMarkdown && render != RenderText {
+		return "", helpers.ErrInvalid("render", "render")
+	}
+
+	return render, nil
+}
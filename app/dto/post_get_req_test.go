@@ -15,6 +15,22 @@ func TestPostGetReq_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid render",
+			m: &PostGetReq{
+				ID:     1,
+				Render: "pdf",
+			},
+			wantErr: true,
+		},
+		{
+			name: "success render html",
+			m: &PostGetReq{
+				ID:     1,
+				Render: "HTML",
+			},
+			wantErr: false,
+		},
 		{
 			name: "success",
 			m: &PostGetReq{
@@ -31,3 +47,24 @@ func TestPostGetReq_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateRender(t *testing.T) {
+	tests := []struct {
+		name    string
+		render  string
+		want    string
+		wantErr bool
+	}{
+		{name: "as written", render: "", want: ""},
+		{name: "lower cased", render: " Text ", want: RenderText},
+		{name: "invalid", render: "pdf", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateRender(tt.render)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ValidateRender() = %q, %v, want %q, wantErr %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package dto
+
+import "github.com/adamnasrudin03/go-template/pkg/helpers"
+
+// PostListReq filters the post list by Tag, given by its label or one of its
+// synonyms. Descendants also keeps posts tagged with a tag below it.
+type PostListReq struct {
+	Tag         string `form:"tag"`
+	Descendants bool   `form:"descendants"`
+}
+
+func (m *PostListReq) Validate() error {
+	m.Tag = helpers.ToLower(m.Tag)
+	if m.Tag == "" && m.Descendants {
+		return helpers.ErrIsRequired("tag", "tag")
+	}
+
+	return nil
+}
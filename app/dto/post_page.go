@@ -0,0 +1,97 @@
+package dto
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+)
+
+const (
+	PostPageDefaultFirst = 20
+	PostPageMaxFirst     = 100
+
+	postCursorPrefix = "post:"
+)
+
+// EncodePostCursor returns the opaque cursor of the post, a page after it
+// starts with the next older post.
+func EncodePostCursor(postID uint64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(postCursorPrefix + strconv.FormatUint(postID, 10)))
+}
+
+// DecodePostCursor returns the post id of a cursor made by EncodePostCursor.
+func DecodePostCursor(cursor string) (uint64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(raw), postCursorPrefix) {
+		return 0, helpers.ErrInvalid("cursor", "cursor")
+	}
+
+	postID, err := strconv.ParseUint(strings.TrimPrefix(string(raw), postCursorPrefix), 10, 64)
+	if err != nil || postID == 0 {
+		return 0, helpers.ErrInvalid("cursor", "cursor")
+	}
+	return postID, nil
+}
+
+// PostPageReq asks the First posts after the cursor After, newest first.
+// Tag, given by its label or a synonym, and Status filter them.
+type PostPageReq struct {
+	First       int
+	After       string
+	Tag         string
+	Descendants bool
+	Status      string
+
+	// AfterID, TagIDs and PublishedAt are filled by the service, TagIDs
+	// from Tag, and PublishedAt for the public, who only sees posts
+	// published at that time.
+	AfterID     uint64
+	TagIDs      []uint64
+	PublishedAt *time.Time
+}
+
+func (m *PostPageReq) Validate() error {
+	if m.First <= 0 {
+		m.First = PostPageDefaultFirst
+	}
+	if m.First > PostPageMaxFirst {
+		return helpers.ErrCannotBeMoreThan("first", "first", strconv.Itoa(PostPageMaxFirst))
+	}
+
+	m.AfterID = 0
+	if m.After != "" {
+		postID, err := DecodePostCursor(m.After)
+		if err != nil {
+			return err
+		}
+		m.AfterID = postID
+	}
+
+	m.Tag = helpers.ToLower(m.Tag)
+	if m.Tag == "" && m.Descendants {
+		return helpers.ErrIsRequired("tag", "tag")
+	}
+
+	m.Status = helpers.ToLower(m.Status)
+	if m.Status != "" && !IsValidPostStatus(m.Status) {
+		return helpers.ErrInvalid("status", "status")
+	}
+
+	return nil
+}
+
+// PostPageRes is a page of posts, EndCursor is the cursor of its last post.
+type PostPageRes struct {
+	Posts       []PostRes `json:"posts"`
+	HasNextPage bool      `json:"has_next_page"`
+	EndCursor   string    `json:"end_cursor"`
+}
+
+// PostAuthorRes is someone who edited the post, see PostRevision.
+type PostAuthorRes struct {
+	ID       uint64 `json:"id"`
+	Username string `json:"username"`
+}
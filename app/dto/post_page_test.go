@@ -0,0 +1,79 @@
+package dto
+
+import "testing"
+
+func TestPostPageReq_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		m           *PostPageReq
+		wantErr     bool
+		wantFirst   int
+		wantAfterID uint64
+	}{
+		{
+			name:      "default first",
+			m:         &PostPageReq{},
+			wantErr:   false,
+			wantFirst: PostPageDefaultFirst,
+		},
+		{
+			name:    "first too large",
+			m:       &PostPageReq{First: PostPageMaxFirst + 1},
+			wantErr: true,
+		},
+		{
+			name:    "invalid cursor",
+			m:       &PostPageReq{After: "not a cursor"},
+			wantErr: true,
+		},
+		{
+			name:    "cursor of something else",
+			m:       &PostPageReq{After: "dGFnOjE"},
+			wantErr: true,
+		},
+		{
+			name:    "descendants without tag",
+			m:       &PostPageReq{Descendants: true},
+			wantErr: true,
+		},
+		{
+			name:    "invalid status",
+			m:       &PostPageReq{Status: "deleted"},
+			wantErr: true,
+		},
+		{
+			name:        "success",
+			m:           &PostPageReq{First: 5, After: EncodePostCursor(42), Tag: " Go ", Descendants: true, Status: "Draft"},
+			wantErr:     false,
+			wantFirst:   5,
+			wantAfterID: 42,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.m.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PostPageReq.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if tt.m.First != tt.wantFirst || tt.m.AfterID != tt.wantAfterID {
+				t.Errorf("PostPageReq.Validate() first = %d after id = %d, want %d %d", tt.m.First, tt.m.AfterID, tt.wantFirst, tt.wantAfterID)
+			}
+		})
+	}
+}
+
+func TestDecodePostCursor(t *testing.T) {
+	for _, id := range []uint64{1, 42, 1<<63 + 7} {
+		got, err := DecodePostCursor(EncodePostCursor(id))
+		if err != nil || got != id {
+			t.Errorf("DecodePostCursor(EncodePostCursor(%d)) = %d, %v", id, got, err)
+		}
+	}
+
+	if _, err := DecodePostCursor(EncodePostCursor(0)); err == nil {
+		t.Errorf("DecodePostCursor() of id 0 should fail")
+	}
+}
@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/pkg/render"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+)
+
+const (
+	PostRelatedDefaultLimit = 5
+	PostRelatedMaxLimit     = 20
+)
+
+// PostRelatedReq asks the posts sharing the most tags with the post ID.
+type PostRelatedReq struct {
+	ID    uint64 `form:"-"`
+	Limit int    `form:"limit"`
+	// IsPublishedOnly leaves out the posts the public can not see.
+	IsPublishedOnly bool `form:"-"`
+}
+
+func (m *PostRelatedReq) Validate() error {
+	if m.ID == 0 {
+		return helpers.ErrIsRequired("id", "id")
+	}
+
+	if m.Limit <= 0 {
+		m.Limit = PostRelatedDefaultLimit
+	}
+	if m.Limit > PostRelatedMaxLimit {
+		return helpers.ErrCannotBeMoreThan("limit", "limit", strconv.Itoa(PostRelatedMaxLimit))
+	}
+
+	return nil
+}
+
+// PostRelatedRes is a related post, Score is its weighted Jaccard index
+// with the post asked, from 0 to 1.
+type PostRelatedRes struct {
+	ID          uint64     `json:"id"`
+	Title       string     `json:"title"`
+	Slug        string     `json:"slug"`
+	ContentText string     `json:"-"`
+	Excerpt     string     `json:"excerpt"`
+	Score       float64    `json:"score"`
+	Status      string     `json:"status"`
+	PublishAt   *time.Time `json:"publish_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func (m *PostRelatedRes) CheckResp() {
+	m.Title = helpers.ToTitle(m.Title)
+	m.Excerpt = render.Excerpt(m.ContentText, ExcerptLength)
+}
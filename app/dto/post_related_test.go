@@ -0,0 +1,28 @@
+package dto
+
+import "testing"
+
+func TestPostRelatedReq_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		m         *PostRelatedReq
+		wantLimit int
+		wantErr   bool
+	}{
+		{name: "failed id is required", m: &PostRelatedReq{}, wantErr: true},
+		{name: "failed limit too big", m: &PostRelatedReq{ID: 1, Limit: PostRelatedMaxLimit + 1}, wantErr: true},
+		{name: "success default limit", m: &PostRelatedReq{ID: 1, Limit: -1}, wantLimit: PostRelatedDefaultLimit},
+		{name: "success", m: &PostRelatedReq{ID: 1, Limit: 3}, wantLimit: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.m.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PostRelatedReq.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && tt.m.Limit != tt.wantLimit {
+				t.Errorf("PostRelatedReq.Validate() limit = %d, want %d", tt.m.Limit, tt.wantLimit)
+			}
+		})
+	}
+}
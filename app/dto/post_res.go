@@ -1,12 +1,49 @@
 package dto
 
-import "github.com/adamnasrudin03/go-template/pkg/helpers"
+import (
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/pkg/render"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+)
+
+const (
+	RenderHTML     = "html"
+	RenderMarkdown = "markdown"
+	RenderText     = "text"
+
+	// ExcerptLength is the longest excerpt in runes.
+	ExcerptLength = 200
+)
 
 type PostRes struct {
-	ID      uint64   `json:"id"`
-	Title   string   `json:"title"`
-	Content string   `json:"content"`
-	Tags    []string `json:"tags"`
+	ID      uint64 `json:"id"`
+	Title   string `json:"title"`
+	Slug    string `json:"slug"`
+	Content string `json:"content"`
+	// ContentHTML and ContentText are only filled until Represent.
+	ContentFormat string     `json:"content_format"`
+	ContentHTML   string     `json:"content_html,omitempty"`
+	ContentText   string     `json:"content_text,omitempty"`
+	Excerpt       string     `json:"excerpt"`
+	ReadingTime   int        `json:"reading_time"` // in minutes
+	Tags          []string   `json:"tags"`
+	Status        string     `json:"status"`
+	PublishAt     *time.Time `json:"publish_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// IsPublished reports whether the post is visible to the public at now. A
+// scheduled post is visible once due, even before the scheduler flips it.
+func (m *PostRes) IsPublished(now time.Time) bool {
+	switch m.Status {
+	case PostStatusPublished:
+		return true
+	case PostStatusScheduled:
+		return m.PublishAt != nil && !m.PublishAt.After(now)
+	}
+	return false
 }
 
 func (m *PostRes) CheckResp() {
@@ -16,4 +53,34 @@ func (m *PostRes) CheckResp() {
 		m.Tags = []string{}
 	}
 
+	// posts written before rendering existed have nothing rendered yet
+	if m.ContentHTML == "" && m.Content != "" {
+		if res, err := render.Content(m.ContentFormat, m.Content); err == nil {
+			m.ContentHTML, m.ContentText = res.HTML, res.Text
+		}
+	}
+	m.Excerpt = render.Excerpt(m.ContentText, ExcerptLength)
+	m.ReadingTime = render.ReadingTime(m.ContentText)
+}
+
+// Represent sets Content to the representation asked by mode: the sanitized
+// HTML, the plain text, or the source as written for markdown and empty
+// mode. The source of html content is unsanitized, it is replaced by its
+// sanitized HTML in every mode but text. The rendered fields are cleared so
+// they are not sent twice.
+func (m *PostRes) Represent(mode string) {
+	switch {
+	case mode == RenderText:
+		m.Content = m.ContentText
+	case mode == RenderHTML || m.ContentFormat == render.FormatHTML:
+		m.Content = m.ContentHTML
+	}
+	m.ContentHTML, m.ContentText = "", ""
+}
+
+// PostSlugRedirectRes points a historical slug to the current one.
+type PostSlugRedirectRes struct {
+	ID       uint64 `json:"id"`
+	Slug     string `json:"slug"`
+	Location string `json:"location"`
 }
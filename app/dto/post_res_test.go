@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/adamnasrudin03/go-asset-findr/pkg/render"
+)
+
+func TestPostRes_Represent(t *testing.T) {
+	source := "# Hello\n\nSome *markdown* <script>alert(1)</script>"
+
+	tests := []struct {
+		name string
+		mode string
+		want string
+	}{
+		{name: "source by default", mode: "", want: source},
+		{name: "markdown is the source", mode: RenderMarkdown, want: source},
+		{name: "html", mode: RenderHTML, want: "<h1>Hello</h1>\n<p>Some <em>markdown</em> alert(1)</p>\n"},
+		{name: "text", mode: RenderText, want: "Hello\nSome markdown alert(1)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// an unrendered post is rendered on the fly
+			m := &PostRes{Content: source, ContentFormat: render.FormatMarkdown}
+			m.CheckResp()
+			m.Represent(tt.mode)
+
+			if m.Content != tt.want {
+				t.Errorf("PostRes.Represent() Content = %q, want %q", m.Content, tt.want)
+			}
+			if m.ContentHTML != "" || m.ContentText != "" {
+				t.Errorf("PostRes.Represent() kept the rendered fields")
+			}
+			if m.Excerpt != "Hello Some markdown alert(1)" || m.ReadingTime != 1 {
+				t.Errorf("PostRes.CheckResp() Excerpt = %q, ReadingTime = %d", m.Excerpt, m.ReadingTime)
+			}
+		})
+	}
+}
+
+func TestPostRes_RepresentHTML(t *testing.T) {
+	source := `<p onclick="steal()">Hi <script>alert(1)</script></p>`
+
+	for _, mode := range []string{"", RenderMarkdown, RenderHTML} {
+		m := &PostRes{Content: source, ContentFormat: render.FormatHTML}
+		m.CheckResp()
+		m.Represent(mode)
+
+		if m.Content != "<p>Hi </p>" {
+			t.Errorf("PostRes.Represent(%q) Content = %q, want the sanitized html", mode, m.Content)
+		}
+	}
+}
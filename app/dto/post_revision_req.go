@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+)
+
+type PostRevisionGetReq struct {
+	PostID   uint64 `json:"post_id"`
+	Revision uint64 `json:"revision"`
+}
+
+func (m *PostRevisionGetReq) Validate() error {
+	if m.PostID == 0 {
+		return helpers.ErrIsRequired("id post", "post id")
+	}
+
+	if m.Revision == 0 {
+		return helpers.ErrIsRequired("revisi", "revision")
+	}
+
+	return nil
+}
+
+type PostRevisionDiffReq struct {
+	PostID uint64 `json:"post_id"`
+	From   uint64 `form:"from" json:"from"`
+	To     uint64 `form:"to" json:"to"` // 0 is the current post
+}
+
+func (m *PostRevisionDiffReq) Validate() error {
+	if m.PostID == 0 {
+		return helpers.ErrIsRequired("id post", "post id")
+	}
+
+	if m.From == 0 {
+		return helpers.ErrIsRequired("revisi awal", "from revision")
+	}
+
+	return nil
+}
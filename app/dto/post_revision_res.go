@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/textdiff"
+)
+
+type PostRevisionRes struct {
+	PostID        uint64    `json:"post_id"`
+	Revision      uint64    `json:"revision"`
+	Title         string    `json:"title"`
+	Content       string    `json:"content"`
+	ContentFormat string    `json:"content_format"`
+	Tags          []string  `json:"tags"`
+	AuthorID      uint64    `json:"author_id"`
+	Author        string    `json:"author"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func NewPostRevisionRes(m models.PostRevision) PostRevisionRes {
+	tags := []string(m.Tags)
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return PostRevisionRes{
+		PostID:        m.PostID,
+		Revision:      m.Revision,
+		Title:         m.Title,
+		Content:       m.Content,
+		ContentFormat: m.ContentFormat,
+		Tags:          tags,
+		AuthorID:      m.AuthorID,
+		Author:        m.Author,
+		CreatedAt:     m.CreatedAt,
+	}
+}
+
+type PostRevisionDiffRes struct {
+	PostID      uint64          `json:"post_id"`
+	From        uint64          `json:"from"`
+	To          uint64          `json:"to"` // 0 is the current post
+	TitleFrom   string          `json:"title_from"`
+	TitleTo     string          `json:"title_to"`
+	Content     []textdiff.Line `json:"content"`
+	TagsAdded   []string        `json:"tags_added"`
+	TagsRemoved []string        `json:"tags_removed"`
+}
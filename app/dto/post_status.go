@@ -0,0 +1,86 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+)
+
+const (
+	PostStatusDraft     = "draft"
+	PostStatusInReview  = "in_review"
+	PostStatusScheduled = "scheduled"
+	PostStatusPublished = "published"
+	PostStatusArchived  = "archived"
+)
+
+// postStatusTransitions lists the statuses a post can move to from each
+// status. Scheduled posts become published by the scheduler at publish_at.
+var postStatusTransitions = map[string][]string{
+	PostStatusDraft:     {PostStatusInReview, PostStatusScheduled, PostStatusPublished, PostStatusArchived},
+	PostStatusInReview:  {PostStatusDraft, PostStatusScheduled, PostStatusPublished, PostStatusArchived},
+	PostStatusScheduled: {PostStatusDraft, PostStatusPublished, PostStatusArchived},
+	PostStatusPublished: {PostStatusDraft, PostStatusArchived},
+	PostStatusArchived:  {PostStatusDraft},
+}
+
+func IsValidPostStatus(status string) bool {
+	_, ok := postStatusTransitions[status]
+	return ok
+}
+
+// CanTransitPostStatus reports whether a post in status from may move to to.
+func CanTransitPostStatus(from, to string) bool {
+	for _, v := range postStatusTransitions[from] {
+		if v == to {
+			return true
+		}
+	}
+	return false
+}
+
+type PostStatusReq struct {
+	ID        uint64     `json:"id"`
+	Status    string     `json:"status"`
+	PublishAt *time.Time `json:"publish_at"`
+}
+
+func (m *PostStatusReq) Validate() error {
+	if m.ID == 0 {
+		return helpers.ErrIsRequired("id", "id")
+	}
+
+	m.Status = helpers.ToLower(m.Status)
+	if !IsValidPostStatus(m.Status) {
+		return helpers.ErrInvalid("status", "status")
+	}
+
+	return validatePublishAt(m.Status, m.PublishAt)
+}
+
+// validatePublishAt requires a future publish_at for scheduled posts, other
+// statuses ignore it.
+func validatePublishAt(status string, publishAt *time.Time) error {
+	if status != PostStatusScheduled {
+		return nil
+	}
+
+	if publishAt == nil || publishAt.IsZero() {
+		return helpers.ErrIsRequired("waktu terbit", "publish_at")
+	}
+
+	if !publishAt.After(time.Now()) {
+		return helpers.ErrInvalid("waktu terbit", "publish_at")
+	}
+
+	return nil
+}
+
+// ErrPostStatusTransition is returned when CanTransitPostStatus is false.
+func ErrPostStatusTransition(from, to string) *helpers.ResponseError {
+	return helpers.NewError(helpers.ErrValidation, helpers.NewResponseMultiLang(
+		helpers.MultiLanguages{
+			ID: "Status post tidak dapat diubah dari " + from + " ke " + to,
+			EN: "Post status can not change from " + from + " to " + to,
+		}))
+}
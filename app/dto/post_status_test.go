@@ -0,0 +1,85 @@
+package dto
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCanTransitPostStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want bool
+	}{
+		{name: "draft to in review", from: PostStatusDraft, to: PostStatusInReview, want: true},
+		{name: "in review to published", from: PostStatusInReview, to: PostStatusPublished, want: true},
+		{name: "scheduled to published", from: PostStatusScheduled, to: PostStatusPublished, want: true},
+		{name: "published to archived", from: PostStatusPublished, to: PostStatusArchived, want: true},
+		{name: "archived to published", from: PostStatusArchived, to: PostStatusPublished, want: false},
+		{name: "published to scheduled", from: PostStatusPublished, to: PostStatusScheduled, want: false},
+		{name: "same status", from: PostStatusDraft, to: PostStatusDraft, want: false},
+		{name: "unknown status", from: "deleted", to: PostStatusDraft, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanTransitPostStatus(tt.from, tt.to); got != tt.want {
+				t.Errorf("CanTransitPostStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostStatusReq_Validate(t *testing.T) {
+	var (
+		future = time.Now().Add(time.Hour)
+		past   = time.Now().Add(-time.Hour)
+	)
+
+	tests := []struct {
+		name    string
+		m       *PostStatusReq
+		wantErr bool
+	}{
+		{name: "id required", m: &PostStatusReq{Status: PostStatusDraft}, wantErr: true},
+		{name: "invalid status", m: &PostStatusReq{ID: 1, Status: "live"}, wantErr: true},
+		{name: "scheduled without publish_at", m: &PostStatusReq{ID: 1, Status: PostStatusScheduled}, wantErr: true},
+		{name: "scheduled in the past", m: &PostStatusReq{ID: 1, Status: PostStatusScheduled, PublishAt: &past}, wantErr: true},
+		{name: "scheduled", m: &PostStatusReq{ID: 1, Status: "Scheduled", PublishAt: &future}, wantErr: false},
+		{name: "published", m: &PostStatusReq{ID: 1, Status: PostStatusPublished}, wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.m.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("PostStatusReq.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPostRes_IsPublished(t *testing.T) {
+	var (
+		now    = time.Now()
+		past   = now.Add(-time.Minute)
+		future = now.Add(time.Minute)
+	)
+
+	tests := []struct {
+		name string
+		m    *PostRes
+		want bool
+	}{
+		{name: "published", m: &PostRes{Status: PostStatusPublished}, want: true},
+		{name: "scheduled due", m: &PostRes{Status: PostStatusScheduled, PublishAt: &past}, want: true},
+		{name: "scheduled not due", m: &PostRes{Status: PostStatusScheduled, PublishAt: &future}, want: false},
+		{name: "draft", m: &PostRes{Status: PostStatusDraft}, want: false},
+		{name: "archived", m: &PostRes{Status: PostStatusArchived}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.m.IsPublished(now); got != tt.want {
+				t.Errorf("PostRes.IsPublished() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+)
+
+const (
+	// PostStreamChannel is the Postgres channel notified with the ID of every
+	// post event relayed from the outbox.
+	PostStreamChannel = "post_events"
+	// PostStreamMaxResume is how many missed events a stream replays on
+	// resume, a client further behind reloads the list instead.
+	PostStreamMaxResume = 1000
+)
+
+// PostStreamReq follows the post changes from LastEventID, the Last-Event-ID
+// header of a reconnecting client, or from now when it is 0. Tag keeps the
+// changes of posts tagged with it, before or after the change, like the
+// post list.
+type PostStreamReq struct {
+	Tag         string `form:"tag"`
+	Descendants bool   `form:"descendants"`
+	LastEventID uint64 `form:"last_event_id"`
+}
+
+func (m *PostStreamReq) Validate() error {
+	m.Tag = helpers.ToLower(m.Tag)
+	if m.Tag == "" && m.Descendants {
+		return helpers.ErrIsRequired("tag", "tag")
+	}
+
+	return nil
+}
+
+// PostStreamEvent is a post change as sent to a stream. ID is the published
+// sequence of its outbox event, the one to resume from. Before is nil for
+// post.created and After for post.deleted.
+type PostStreamEvent struct {
+	ID         uint64    `json:"id"`
+	Type       string    `json:"type"`
+	PostID     uint64    `json:"post_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Before     *PostRes  `json:"before"`
+	After      *PostRes  `json:"after"`
+}
@@ -0,0 +1,253 @@
+package dto
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+)
+
+const (
+	TransferFormatJSONL = "jsonl"
+	TransferFormatCSV   = "csv"
+
+	ImportDedupeNone = "none" // every valid row creates a post
+	ImportDedupeID   = "id"   // rows whose id is an existing post are skipped
+	ImportDedupeSlug = "slug" // rows whose slug, or title slug, is taken are skipped
+
+	ImportStatusCreated = "created"
+	ImportStatusSkipped = "skipped"
+	ImportStatusFailed  = "failed"
+
+	// csvTagSeparator joins the tags of a post in one CSV cell, a separator
+	// or a csvTagEscape inside a tag is escaped with csvTagEscape.
+	csvTagSeparator = '|'
+	csvTagEscape    = '\\'
+)
+
+// PostCSVHeader is the header row of the CSV export, import matches the
+// columns by these names so their order does not matter.
+var PostCSVHeader = []string{"id", "slug", "title", "content", "content_format", "tags", "status", "publish_at", "created_at", "updated_at"}
+
+type PostExportReq struct {
+	Format string `form:"format" json:"format"`
+}
+
+func (m *PostExportReq) Validate() error {
+	return validateTransferFormat(&m.Format)
+}
+
+type PostImportReq struct {
+	Format string `form:"format" json:"format"`
+	Dedupe string `form:"dedupe" json:"dedupe"`
+}
+
+func (m *PostImportReq) Validate() error {
+	err := validateTransferFormat(&m.Format)
+	if err != nil {
+		return err
+	}
+
+	m.Dedupe = helpers.ToLower(m.Dedupe)
+	if m.Dedupe == "" {
+		m.Dedupe = ImportDedupeNone
+	}
+	if m.Dedupe != ImportDedupeNone && m.Dedupe != ImportDedupeID && m.Dedupe != ImportDedupeSlug {
+		return helpers.ErrInvalid("dedupe", "dedupe")
+	}
+	return nil
+}
+
+func validateTransferFormat(format *string) error {
+	*format = helpers.ToLower(*format)
+	if *format == "" {
+		*format = TransferFormatJSONL
+	}
+
+	if *format != TransferFormatJSONL && *format != TransferFormatCSV {
+		return helpers.ErrInvalid("format", "format")
+	}
+	return nil
+}
+
+// PostTransferRow is one post in an export file, and one row of an import.
+type PostTransferRow struct {
+	ID            uint64     `json:"id"`
+	Slug          string     `json:"slug"`
+	Title         string     `json:"title"`
+	Content       string     `json:"content"`
+	ContentFormat string     `json:"content_format"`
+	Tags          []string   `json:"tags"`
+	Status        string     `json:"status"`
+	PublishAt     *time.Time `json:"publish_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// CSVRecord returns the row in the column order of PostCSVHeader.
+func (m *PostTransferRow) CSVRecord() []string {
+	publishAt := ""
+	if m.PublishAt != nil {
+		publishAt = m.PublishAt.Format(time.RFC3339)
+	}
+
+	return []string{
+		strconv.FormatUint(m.ID, 10),
+		m.Slug,
+		m.Title,
+		m.Content,
+		m.ContentFormat,
+		joinCSVTags(m.Tags),
+		m.Status,
+		publishAt,
+		m.CreatedAt.Format(time.RFC3339),
+		m.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// ParseCSVRecord reads record into the row, columns maps a column name of
+// PostCSVHeader to its index in record. Missing columns are left empty.
+func (m *PostTransferRow) ParseCSVRecord(columns map[string]int, record []string) error {
+	get := func(name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var err error
+	if v := get("id"); v != "" {
+		m.ID, err = strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return helpers.ErrInvalid("id", "id")
+		}
+	}
+
+	if v := get("publish_at"); v != "" {
+		publishAt, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return helpers.ErrInvalid("waktu terbit", "publish_at")
+		}
+		m.PublishAt = &publishAt
+	}
+
+	m.Slug = get("slug")
+	m.Title = get("title")
+	m.Content = get("content")
+	m.ContentFormat = get("content_format")
+	m.Status = get("status")
+	m.Tags = []string{}
+	if v := get("tags"); v != "" {
+		m.Tags = splitCSVTags(v)
+	}
+	return nil
+}
+
+// joinCSVTags joins tags with csvTagSeparator, escaping it in the tags.
+func joinCSVTags(tags []string) string {
+	var sb strings.Builder
+	for i, tag := range tags {
+		if i > 0 {
+			sb.WriteRune(csvTagSeparator)
+		}
+		for _, r := range tag {
+			if r == csvTagSeparator || r == csvTagEscape {
+				sb.WriteRune(csvTagEscape)
+			}
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// splitCSVTags splits a cell of joinCSVTags back into its tags.
+func splitCSVTags(s string) []string {
+	var (
+		result    = []string{}
+		tag       strings.Builder
+		isEscaped = false
+	)
+	for _, r := range s {
+		switch {
+		case isEscaped:
+			tag.WriteRune(r)
+			isEscaped = false
+		case r == csvTagEscape:
+			isEscaped = true
+		case r == csvTagSeparator:
+			result = append(result, tag.String())
+			tag.Reset()
+		default:
+			tag.WriteRune(r)
+		}
+	}
+	return append(result, tag.String())
+}
+
+// CreateReq returns the row as a create request, ids and timestamps of the
+// source environment are not kept.
+func (m *PostTransferRow) CreateReq() PostCreateReq {
+	return PostCreateReq{
+		Title:   m.Title,
+		Content: m.Content,
+		Tags:    m.Tags,
+
+		ContentFormat: m.ContentFormat,
+		Status:        m.Status,
+		PublishAt:     m.PublishAt,
+	}
+}
+
+type PostImportRowRes struct {
+	Line   int                    `json:"line"`
+	ID     uint64                 `json:"id,omitempty"`
+	Status string                 `json:"status"`
+	Error  *helpers.ResponseError `json:"error,omitempty"`
+}
+
+// PostImportRes reports an import, Rows lists only the rows that were not
+// created so the report stays small for large files.
+type PostImportRes struct {
+	Format  string             `json:"format"`
+	Dedupe  string             `json:"dedupe"`
+	Total   int                `json:"total"`
+	Created int                `json:"created"`
+	Skipped int                `json:"skipped"`
+	Failed  int                `json:"failed"`
+	Rows    []PostImportRowRes `json:"rows"`
+}
+
+// Add counts row and keeps it in the report unless it was created.
+func (m *PostImportRes) Add(row PostImportRowRes) {
+	m.Total++
+	switch row.Status {
+	case ImportStatusCreated:
+		m.Created++
+		return
+	case ImportStatusSkipped:
+		m.Skipped++
+	default:
+		m.Failed++
+	}
+	m.Rows = append(m.Rows, row)
+}
+
+// ErrImportDuplicate is reported for rows skipped by the dedupe option.
+func ErrImportDuplicate(dedupe string) *helpers.ResponseError {
+	return helpers.NewError(helpers.ErrFromUseCase, helpers.NewResponseMultiLang(
+		helpers.MultiLanguages{
+			ID: "Post dengan " + dedupe + " yang sama sudah ada",
+			EN: "A post with the same " + dedupe + " already exists",
+		}))
+}
+
+// ErrImportRow is reported for rows that can not be read.
+func ErrImportRow() *helpers.ResponseError {
+	return helpers.NewError(helpers.ErrValidation, helpers.NewResponseMultiLang(
+		helpers.MultiLanguages{
+			ID: "Baris tidak dapat dibaca",
+			EN: "Row can not be read",
+		}))
+}
@@ -0,0 +1,102 @@
+package dto
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPostTransferRow_CSVRecord(t *testing.T) {
+	publishAt := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	row := PostTransferRow{
+		ID:        7,
+		Slug:      "hello",
+		Title:     "Hello",
+		Content:   "content",
+		Tags:      []string{"go", "sql", "a|b", `c\`},
+		Status:    PostStatusScheduled,
+		PublishAt: &publishAt,
+	}
+
+	columns := map[string]int{}
+	for i, v := range PostCSVHeader {
+		columns[v] = i
+	}
+
+	got := PostTransferRow{}
+	err := got.ParseCSVRecord(columns, row.CSVRecord())
+	if err != nil {
+		t.Fatalf("ParseCSVRecord() error = %v", err)
+	}
+
+	// timestamps of the source are not read back
+	got.CreatedAt, got.UpdatedAt = row.CreatedAt, row.UpdatedAt
+	if !reflect.DeepEqual(got, row) {
+		t.Errorf("ParseCSVRecord() = %+v, want %+v", got, row)
+	}
+}
+
+func TestPostTransferRow_ParseCSVRecord(t *testing.T) {
+	columns := map[string]int{"title": 0, "id": 1, "publish_at": 2}
+
+	tests := []struct {
+		name    string
+		columns map[string]int
+		record  []string
+		want    PostTransferRow
+		wantErr bool
+	}{
+		{name: "invalid id", record: []string{"title", "x", ""}, wantErr: true},
+		{name: "invalid publish_at", record: []string{"title", "1", "tomorrow"}, wantErr: true},
+		{name: "short record", record: []string{" title "}, want: PostTransferRow{Title: "title", Tags: []string{}}},
+		{
+			name:    "escaped tags",
+			columns: map[string]int{"title": 0, "tags": 1},
+			record:  []string{"title", `go|a\|b|c\\|`},
+			want:    PostTransferRow{Title: "title", Tags: []string{"go", "a|b", `c\`, ""}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.columns == nil {
+				tt.columns = columns
+			}
+
+			got := PostTransferRow{}
+			err := got.ParseCSVRecord(tt.columns, tt.record)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseCSVRecord() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCSVRecord() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostImportReq_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       *PostImportReq
+		want    PostImportReq
+		wantErr bool
+	}{
+		{name: "defaults", m: &PostImportReq{}, want: PostImportReq{Format: TransferFormatJSONL, Dedupe: ImportDedupeNone}},
+		{name: "csv by slug", m: &PostImportReq{Format: "CSV", Dedupe: "Slug"}, want: PostImportReq{Format: TransferFormatCSV, Dedupe: ImportDedupeSlug}},
+		{name: "invalid format", m: &PostImportReq{Format: "xml"}, wantErr: true},
+		{name: "invalid dedupe", m: &PostImportReq{Dedupe: "title"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.m.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PostImportReq.Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && *tt.m != tt.want {
+				t.Errorf("PostImportReq.Validate() = %+v, want %+v", *tt.m, tt.want)
+			}
+		})
+	}
+}
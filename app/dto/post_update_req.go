@@ -3,14 +3,17 @@ package dto
 import (
 	"strings"
 
+	"github.com/adamnasrudin03/go-asset-findr/pkg/render"
 	"github.com/adamnasrudin03/go-template/pkg/helpers"
 )
 
 type PostUpdateReq struct {
-	ID      uint64   `json:"id"`
-	Title   string   `json:"title"`
-	Content string   `json:"content"`
-	Tags    []string `json:"tags"`
+	ID      uint64 `json:"id"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
+	// ContentFormat is plain, markdown or html, empty keeps the current one.
+	ContentFormat string   `json:"content_format"`
+	Tags          []string `json:"tags"`
 }
 
 func (m *PostUpdateReq) Validate() error {
@@ -28,6 +31,11 @@ func (m *PostUpdateReq) Validate() error {
 		return helpers.ErrIsRequired("konten", "content")
 	}
 
+	err := validateContentFormat(&m.ContentFormat)
+	if err != nil {
+		return err
+	}
+
 	// filter duplicate value
 	tagsNotDuplicate := map[string]bool{}
 	tags := []string{}
@@ -45,3 +53,12 @@ func (m *PostUpdateReq) Validate() error {
 
 	return nil
 }
+
+// validateContentFormat lowercases format and checks it, empty is allowed.
+func validateContentFormat(format *string) error {
+	*format = helpers.ToLower(*format)
+	if *format != "" && !render.IsValidFormat(*format) {
+		return helpers.ErrInvalid("format konten", "content_format")
+	}
+	return nil
+}
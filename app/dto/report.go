@@ -0,0 +1,289 @@
+package dto
+
+import (
+	"math/bits"
+	"strconv"
+	"time"
+
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+)
+
+const (
+	ReportFormatJSON = "json"
+	ReportFormatCSV  = "csv"
+
+	TagCooccurrenceDefaultTop = 20
+	TagCooccurrenceMaxTop     = 100
+
+	TagWeeklyDefaultWeeks = 12
+	TagWeeklyMaxWeeks     = 104
+	TagWeeklyDefaultTop   = 10
+	TagWeeklyMaxTop       = 50
+
+	reportDateLayout = "2006-01-02"
+)
+
+func validateReportFormat(format *string) error {
+	*format = helpers.ToLower(*format)
+	if *format == "" {
+		*format = ReportFormatJSON
+	}
+
+	if *format != ReportFormatJSON && *format != ReportFormatCSV {
+		return helpers.ErrInvalid("format", "format")
+	}
+	return nil
+}
+
+// ReportFormatReq picks how a report is sent, json or csv.
+type ReportFormatReq struct {
+	Format string `form:"format"`
+}
+
+func (m *ReportFormatReq) Validate() error {
+	return validateReportFormat(&m.Format)
+}
+
+// TagCooccurrenceReq asks how often the Top most used tags appear together.
+type TagCooccurrenceReq struct {
+	Top    int    `form:"top"`
+	Format string `form:"format"`
+}
+
+func (m *TagCooccurrenceReq) Validate() error {
+	err := validateReportFormat(&m.Format)
+	if err != nil {
+		return err
+	}
+
+	if m.Top <= 0 {
+		m.Top = TagCooccurrenceDefaultTop
+	}
+	if m.Top > TagCooccurrenceMaxTop {
+		return helpers.ErrCannotBeMoreThan("top", "top", strconv.Itoa(TagCooccurrenceMaxTop))
+	}
+
+	return nil
+}
+
+// TagWeeklyReq asks the posts per week of the last Weeks weeks, for Tag,
+// given by its label or a synonym, or else for the Top most used tags of
+// those weeks.
+type TagWeeklyReq struct {
+	Weeks  int    `form:"weeks"`
+	Tag    string `form:"tag"`
+	Top    int    `form:"top"`
+	Format string `form:"format"`
+}
+
+func (m *TagWeeklyReq) Validate() error {
+	err := validateReportFormat(&m.Format)
+	if err != nil {
+		return err
+	}
+
+	if m.Weeks <= 0 {
+		m.Weeks = TagWeeklyDefaultWeeks
+	}
+	if m.Weeks > TagWeeklyMaxWeeks {
+		return helpers.ErrCannotBeMoreThan("minggu", "weeks", strconv.Itoa(TagWeeklyMaxWeeks))
+	}
+
+	m.Tag = helpers.ToLower(m.Tag)
+	if m.Top <= 0 {
+		m.Top = TagWeeklyDefaultTop
+	}
+	if m.Top > TagWeeklyMaxTop {
+		return helpers.ErrCannotBeMoreThan("top", "top", strconv.Itoa(TagWeeklyMaxTop))
+	}
+
+	return nil
+}
+
+// TagPairRow is how many posts have both tags, TagID is below OtherID.
+type TagPairRow struct {
+	TagID   uint64
+	OtherID uint64
+	Posts   int64
+}
+
+// TagPostCountRow is how many tags are given to Posts posts.
+type TagPostCountRow struct {
+	Posts int64
+	Tags  int64
+}
+
+// TagWeekRow is how many posts published in the week starting Week have
+// the tag.
+type TagWeekRow struct {
+	Week  time.Time
+	TagID uint64
+	Label string
+	Posts int64
+}
+
+// TagCooccurrenceRes is a symmetric matrix, Matrix[i][j] is how many posts
+// have both Tags[i] and Tags[j], the diagonal how many have Tags[i].
+type TagCooccurrenceRes struct {
+	Tags   []string  `json:"tags"`
+	Matrix [][]int64 `json:"matrix"`
+}
+
+// NewTagCooccurrence builds the matrix of tags, in their order, with their
+// usage on the diagonal.
+func NewTagCooccurrence(tags []TagUsageRes, pairs []TagPairRow) *TagCooccurrenceRes {
+	var (
+		result = &TagCooccurrenceRes{
+			Tags:   make([]string, len(tags)),
+			Matrix: make([][]int64, len(tags)),
+		}
+		index = make(map[uint64]int, len(tags))
+	)
+	for i, v := range tags {
+		result.Tags[i] = v.Label
+		result.Matrix[i] = make([]int64, len(tags))
+		result.Matrix[i][i] = v.Usage
+		index[v.ID] = i
+	}
+
+	for _, v := range pairs {
+		i, okTag := index[v.TagID]
+		j, okOther := index[v.OtherID]
+		if !okTag || !okOther {
+			continue
+		}
+		result.Matrix[i][j] = v.Posts
+		result.Matrix[j][i] = v.Posts
+	}
+	return result
+}
+
+func (m *TagCooccurrenceRes) CSV() [][]string {
+	result := [][]string{append([]string{"tag"}, m.Tags...)}
+	for i, row := range m.Matrix {
+		record := []string{m.Tags[i]}
+		for _, v := range row {
+			record = append(record, strconv.FormatInt(v, 10))
+		}
+		result = append(result, record)
+	}
+	return result
+}
+
+// TagHistogramBucket counts the tags given to between Min and Max posts.
+type TagHistogramBucket struct {
+	Min  int64 `json:"min"`
+	Max  int64 `json:"max"`
+	Tags int64 `json:"tags"`
+}
+
+type TagHistogramRes struct {
+	Buckets []TagHistogramBucket `json:"buckets"`
+}
+
+// NewTagHistogram buckets the counts by powers of two, 0, 1, 2-3, 4-7 and
+// so on, so a few very popular tags do not flatten the rest. Every bucket up
+// to the largest one is present.
+func NewTagHistogram(counts []TagPostCountRow) *TagHistogramRes {
+	result := &TagHistogramRes{Buckets: []TagHistogramBucket{}}
+	for _, v := range counts {
+		if v.Posts < 0 {
+			continue
+		}
+
+		i := bits.Len64(uint64(v.Posts))
+		for len(result.Buckets) <= i {
+			k := len(result.Buckets)
+			bucket := TagHistogramBucket{}
+			if k > 0 {
+				bucket.Min, bucket.Max = 1<<(k-1), 1<<k-1
+			}
+			result.Buckets = append(result.Buckets, bucket)
+		}
+		result.Buckets[i].Tags += v.Tags
+	}
+	return result
+}
+
+func (m *TagHistogramRes) CSV() [][]string {
+	result := [][]string{{"min_posts", "max_posts", "tags"}}
+	for _, v := range m.Buckets {
+		result = append(result, []string{
+			strconv.FormatInt(v.Min, 10),
+			strconv.FormatInt(v.Max, 10),
+			strconv.FormatInt(v.Tags, 10),
+		})
+	}
+	return result
+}
+
+// TagWeeklyRes is the posts per week of each tag, Posts of a tag follows
+// Weeks, the first day of each week.
+type TagWeeklyRes struct {
+	Weeks []string           `json:"weeks"`
+	Tags  []TagWeekSeriesRes `json:"tags"`
+}
+
+type TagWeekSeriesRes struct {
+	Label string  `json:"label"`
+	Posts []int64 `json:"posts"`
+}
+
+// NewTagWeekly lays the rows out on the weeks from since, a Monday, to the
+// week of until. Weeks without posts count 0, tags keep the rows order.
+func NewTagWeekly(since, until time.Time, rows []TagWeekRow) *TagWeeklyRes {
+	var (
+		result = &TagWeeklyRes{Weeks: []string{}, Tags: []TagWeekSeriesRes{}}
+		weeks  = map[string]int{}
+		tags   = map[uint64]int{}
+	)
+	for week := since; !week.After(until); week = week.AddDate(0, 0, 7) {
+		weeks[week.Format(reportDateLayout)] = len(result.Weeks)
+		result.Weeks = append(result.Weeks, week.Format(reportDateLayout))
+	}
+
+	for _, v := range rows {
+		w, ok := weeks[v.Week.UTC().Format(reportDateLayout)]
+		if !ok {
+			continue
+		}
+
+		t, ok := tags[v.TagID]
+		if !ok {
+			t = len(result.Tags)
+			tags[v.TagID] = t
+			result.Tags = append(result.Tags, TagWeekSeriesRes{Label: v.Label, Posts: make([]int64, len(result.Weeks))})
+		}
+		result.Tags[t].Posts[w] += v.Posts
+	}
+	return result
+}
+
+func (m *TagWeeklyRes) CSV() [][]string {
+	header := []string{"week"}
+	for _, v := range m.Tags {
+		header = append(header, v.Label)
+	}
+
+	result := [][]string{header}
+	for i, week := range m.Weeks {
+		record := []string{week}
+		for _, v := range m.Tags {
+			record = append(record, strconv.FormatInt(v.Posts[i], 10))
+		}
+		result = append(result, record)
+	}
+	return result
+}
+
+// WeekStart returns the Monday, in UTC, of the week of t.
+func WeekStart(t time.Time) time.Time {
+	t = t.UTC()
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.UTC)
+}
+
+type ReportRefreshRes struct {
+	RefreshedAt time.Time `json:"refreshed_at"`
+	DurationMs  int64     `json:"duration_ms"`
+}
@@ -0,0 +1,164 @@
+package dto
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTagCooccurrenceReq_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       *TagCooccurrenceReq
+		want    *TagCooccurrenceReq
+		wantErr bool
+	}{
+		{name: "failed format", m: &TagCooccurrenceReq{Format: "xml"}, wantErr: true},
+		{name: "failed top too big", m: &TagCooccurrenceReq{Top: TagCooccurrenceMaxTop + 1}, wantErr: true},
+		{name: "success default", m: &TagCooccurrenceReq{}, want: &TagCooccurrenceReq{Top: TagCooccurrenceDefaultTop, Format: ReportFormatJSON}},
+		{name: "success csv", m: &TagCooccurrenceReq{Top: 5, Format: " CSV "}, want: &TagCooccurrenceReq{Top: 5, Format: ReportFormatCSV}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.m.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TagCooccurrenceReq.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(tt.m, tt.want) {
+				t.Errorf("TagCooccurrenceReq.Validate() = %+v, want %+v", tt.m, tt.want)
+			}
+		})
+	}
+}
+
+func TestTagWeeklyReq_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       *TagWeeklyReq
+		want    *TagWeeklyReq
+		wantErr bool
+	}{
+		{name: "failed format", m: &TagWeeklyReq{Format: "pdf"}, wantErr: true},
+		{name: "failed weeks too many", m: &TagWeeklyReq{Weeks: TagWeeklyMaxWeeks + 1}, wantErr: true},
+		{name: "failed top too big", m: &TagWeeklyReq{Top: TagWeeklyMaxTop + 1}, wantErr: true},
+		{
+			name: "success default",
+			m:    &TagWeeklyReq{},
+			want: &TagWeeklyReq{Weeks: TagWeeklyDefaultWeeks, Top: TagWeeklyDefaultTop, Format: ReportFormatJSON},
+		},
+		{
+			name: "success tag normalized",
+			m:    &TagWeeklyReq{Weeks: 4, Tag: " GoLang ", Top: 3, Format: "csv"},
+			want: &TagWeeklyReq{Weeks: 4, Tag: "golang", Top: 3, Format: ReportFormatCSV},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.m.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TagWeeklyReq.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(tt.m, tt.want) {
+				t.Errorf("TagWeeklyReq.Validate() = %+v, want %+v", tt.m, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTagCooccurrence(t *testing.T) {
+	var (
+		tags  = []TagUsageRes{{ID: 3, Label: "go", Usage: 5}, {ID: 1, Label: "sql", Usage: 4}, {ID: 2, Label: "rust", Usage: 2}}
+		pairs = []TagPairRow{{TagID: 1, OtherID: 3, Posts: 2}, {TagID: 2, OtherID: 3, Posts: 1}, {TagID: 3, OtherID: 9, Posts: 7}}
+		want  = &TagCooccurrenceRes{
+			Tags:   []string{"go", "sql", "rust"},
+			Matrix: [][]int64{{5, 2, 1}, {2, 4, 0}, {1, 0, 2}},
+		}
+	)
+
+	got := NewTagCooccurrence(tags, pairs)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("NewTagCooccurrence() = %+v, want %+v", got, want)
+	}
+
+	wantCSV := [][]string{{"tag", "go", "sql", "rust"}, {"go", "5", "2", "1"}, {"sql", "2", "4", "0"}, {"rust", "1", "0", "2"}}
+	if !reflect.DeepEqual(got.CSV(), wantCSV) {
+		t.Errorf("TagCooccurrenceRes.CSV() = %v, want %v", got.CSV(), wantCSV)
+	}
+}
+
+func TestNewTagHistogram(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts []TagPostCountRow
+		want   []TagHistogramBucket
+	}{
+		{name: "empty", counts: nil, want: []TagHistogramBucket{}},
+		{
+			name:   "power of two buckets",
+			counts: []TagPostCountRow{{Posts: 0, Tags: 2}, {Posts: 1, Tags: 5}, {Posts: 3, Tags: 1}, {Posts: 2, Tags: 1}, {Posts: 9, Tags: 1}},
+			want: []TagHistogramBucket{
+				{Min: 0, Max: 0, Tags: 2},
+				{Min: 1, Max: 1, Tags: 5},
+				{Min: 2, Max: 3, Tags: 2},
+				{Min: 4, Max: 7, Tags: 0},
+				{Min: 8, Max: 15, Tags: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewTagHistogram(tt.counts); !reflect.DeepEqual(got.Buckets, tt.want) {
+				t.Errorf("NewTagHistogram() = %v, want %v", got.Buckets, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTagWeekly(t *testing.T) {
+	var (
+		since = time.Date(2026, 9, 28, 0, 0, 0, 0, time.UTC)
+		until = time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+		rows  = []TagWeekRow{
+			{Week: time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC), TagID: 2, Label: "go", Posts: 3},
+			{Week: time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), TagID: 2, Label: "go", Posts: 1},
+			{Week: time.Date(2026, 9, 28, 0, 0, 0, 0, time.UTC), TagID: 1, Label: "sql", Posts: 2},
+			{Week: time.Date(2026, 9, 21, 0, 0, 0, 0, time.UTC), TagID: 1, Label: "sql", Posts: 9},
+		}
+		want = &TagWeeklyRes{
+			Weeks: []string{"2026-09-28", "2026-10-05", "2026-10-12"},
+			Tags: []TagWeekSeriesRes{
+				{Label: "go", Posts: []int64{0, 3, 1}},
+				{Label: "sql", Posts: []int64{2, 0, 0}},
+			},
+		}
+	)
+
+	got := NewTagWeekly(since, until, rows)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("NewTagWeekly() = %+v, want %+v", got, want)
+	}
+
+	wantCSV := [][]string{{"week", "go", "sql"}, {"2026-09-28", "0", "2"}, {"2026-10-05", "3", "0"}, {"2026-10-12", "1", "0"}}
+	if !reflect.DeepEqual(got.CSV(), wantCSV) {
+		t.Errorf("TagWeeklyRes.CSV() = %v, want %v", got.CSV(), wantCSV)
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	tests := []struct {
+		name string
+		t    time.Time
+		want time.Time
+	}{
+		{name: "monday", t: time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC), want: time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)},
+		{name: "sunday", t: time.Date(2026, 10, 25, 23, 59, 0, 0, time.UTC), want: time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)},
+		{name: "other zone", t: time.Date(2026, 10, 19, 1, 0, 0, 0, time.FixedZone("WIB", 7*3600)), want: time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WeekStart(tt.t); !got.Equal(tt.want) {
+				t.Errorf("WeekStart() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package dto
+
+import "time"
+
+// StorageReportRes tells how much deduplication saves and what the next
+// garbage collection removes: blobs used by no other asset older than the
+// grace period and, when asset expiry is on, assets attached to no post
+// older than the expiry.
+type StorageReportRes struct {
+	Assets       int64   `json:"assets"`
+	Blobs        int64   `json:"blobs"`
+	LogicalBytes int64   `json:"logical_bytes"` // sum of the asset sizes
+	StoredBytes  int64   `json:"stored_bytes"`  // sum of the blob sizes
+	SavedBytes   int64   `json:"saved_bytes"`   // by assets sharing a blob
+	DedupRatio   float64 `json:"dedup_ratio"`   // logical over stored bytes of used blobs
+
+	GraceHours       int                `json:"grace_hours"`
+	AssetExpiryHours int                `json:"asset_expiry_hours"` // 0 when assets are kept
+	OrphanAssets     int64              `json:"orphan_assets"`
+	OrphanAssetBytes int64              `json:"orphan_asset_bytes"`
+	OrphanBlobs      int64              `json:"orphan_blobs"`
+	OrphanBlobBytes  int64              `json:"orphan_blob_bytes"`
+	Orphans          []StorageOrphanRes `json:"orphans"` // the oldest ones
+}
+
+const (
+	StorageOrphanAsset = "asset"
+	StorageOrphanBlob  = "blob"
+
+	StorageOrphanListLimit = 100
+)
+
+type StorageOrphanRes struct {
+	Kind      string    `json:"kind"`
+	AssetID   uint64    `json:"asset_id,omitempty"`
+	Checksum  string    `json:"checksum"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type StorageGCReq struct {
+	DryRun bool `form:"dry_run" json:"dry_run"`
+}
+
+type StorageGCRes struct {
+	DryRun        bool  `json:"dry_run"`
+	DeletedAssets int   `json:"deleted_assets"`
+	DeletedBlobs  int   `json:"deleted_blobs"`
+	FreedBytes    int64 `json:"freed_bytes"`
+}
@@ -0,0 +1,31 @@
+package dto
+
+const (
+	AggregateTag = "tag"
+
+	TagEventCreated = "tag.created"
+	TagEventUpdated = "tag.updated"
+	TagEventMerged  = "tag.merged"
+)
+
+// TagEventData is a tag as sent in its events.
+type TagEventData struct {
+	ID       uint64   `json:"id"`
+	Label    string   `json:"label"`
+	ParentID *uint64  `json:"parent_id"`
+	Synonyms []string `json:"synonyms"`
+}
+
+// TagEventPayload is the tag before and after its parent or synonyms
+// changed, Before is nil for a created tag.
+type TagEventPayload struct {
+	Before *TagEventData `json:"before"`
+	After  *TagEventData `json:"after"`
+}
+
+// TagMergedPayload is the target after the merge and the sources as they
+// were before it, they are gone since.
+type TagMergedPayload struct {
+	Target  *TagEventData  `json:"target"`
+	Sources []TagEventData `json:"sources"`
+}
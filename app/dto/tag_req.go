@@ -0,0 +1,106 @@
+package dto
+
+import (
+	"fmt"
+
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+)
+
+// TagMaxMerge is how many tags one merge may fold into another.
+const TagMaxMerge = 100
+
+// TagParentReq moves the tag under ParentID, or to the top when it is nil.
+type TagParentReq struct {
+	ID       uint64  `json:"-"`
+	ParentID *uint64 `json:"parent_id"`
+}
+
+func (m *TagParentReq) Validate() error {
+	if m.ID == 0 {
+		return helpers.ErrIsRequired("id tag", "tag id")
+	}
+	if m.ParentID != nil && (*m.ParentID == 0 || *m.ParentID == m.ID) {
+		return helpers.ErrInvalid("id induk", "parent_id")
+	}
+
+	return nil
+}
+
+type TagSynonymReq struct {
+	TagID uint64 `json:"-"`
+	Label string `json:"label"`
+}
+
+func (m *TagSynonymReq) Validate() error {
+	if m.TagID == 0 {
+		return helpers.ErrIsRequired("id tag", "tag id")
+	}
+
+	m.Label = helpers.ToLower(m.Label)
+	if m.Label == "" {
+		return helpers.ErrIsRequired("label", "label")
+	}
+
+	return nil
+}
+
+// TagMergeReq folds the source tags into the target: their posts, children
+// and synonyms move to it and their labels become its synonyms.
+type TagMergeReq struct {
+	TargetID  uint64   `json:"-"`
+	SourceIDs []uint64 `json:"source_ids"`
+}
+
+func (m *TagMergeReq) Validate() error {
+	if m.TargetID == 0 {
+		return helpers.ErrIsRequired("id tag", "tag id")
+	}
+
+	// filter duplicate value
+	isExist := map[uint64]bool{}
+	ids := []uint64{}
+	for _, v := range m.SourceIDs {
+		if v == 0 || isExist[v] {
+			continue
+		}
+		if v == m.TargetID {
+			return helpers.ErrInvalid("id tag sumber", "source_ids")
+		}
+
+		isExist[v] = true
+		ids = append(ids, v)
+	}
+	m.SourceIDs = ids
+
+	if len(m.SourceIDs) == 0 {
+		return helpers.ErrIsRequired("id tag sumber", "source_ids")
+	}
+	if len(m.SourceIDs) > TagMaxMerge {
+		return helpers.NewError(helpers.ErrValidation, helpers.NewResponseMultiLang(
+			helpers.MultiLanguages{
+				ID: fmt.Sprintf("Maksimal %d tag per penggabungan", TagMaxMerge),
+				EN: fmt.Sprintf("At most %d tags per merge", TagMaxMerge),
+			}))
+	}
+
+	return nil
+}
+
+// ErrTagLabelUsed is returned for a synonym whose label is a tag, the two
+// tags should be merged instead.
+func ErrTagLabelUsed(label string) *helpers.ResponseError {
+	return helpers.NewError(helpers.ErrValidation, helpers.NewResponseMultiLang(
+		helpers.MultiLanguages{
+			ID: fmt.Sprintf("Label %q sudah dipakai, gabungkan tag tersebut", label),
+			EN: fmt.Sprintf("Label %q is already used, merge that tag instead", label),
+		}))
+}
+
+// ErrTagCycle is returned when a tag would become a descendant of itself.
+func ErrTagCycle() *helpers.ResponseError {
+	return helpers.NewError(helpers.ErrValidation, helpers.NewResponseMultiLang(
+		helpers.MultiLanguages{
+			ID: "Tag tidak bisa menjadi turunan dari dirinya sendiri",
+			EN: "A tag can not be a descendant of itself",
+		}))
+}
@@ -0,0 +1,111 @@
+package dto
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTagParentReq_Validate(t *testing.T) {
+	var (
+		zero   = uint64(0)
+		one    = uint64(1)
+		parent = uint64(2)
+	)
+	tests := []struct {
+		name    string
+		m       *TagParentReq
+		wantErr bool
+	}{
+		{name: "failed id is required", m: &TagParentReq{ParentID: &parent}, wantErr: true},
+		{name: "failed parent zero", m: &TagParentReq{ID: 1, ParentID: &zero}, wantErr: true},
+		{name: "failed parent of itself", m: &TagParentReq{ID: 1, ParentID: &one}, wantErr: true},
+		{name: "success to the top", m: &TagParentReq{ID: 1}},
+		{name: "success", m: &TagParentReq{ID: 1, ParentID: &parent}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.m.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("TagParentReq.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTagSynonymReq_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		m         *TagSynonymReq
+		wantLabel string
+		wantErr   bool
+	}{
+		{name: "failed tag id is required", m: &TagSynonymReq{Label: "go"}, wantErr: true},
+		{name: "failed label is required", m: &TagSynonymReq{TagID: 1, Label: "  "}, wantErr: true},
+		{name: "success normalized", m: &TagSynonymReq{TagID: 1, Label: " GoLang "}, wantLabel: "golang"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.m.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TagSynonymReq.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && tt.m.Label != tt.wantLabel {
+				t.Errorf("TagSynonymReq.Validate() label = %q, want %q", tt.m.Label, tt.wantLabel)
+			}
+		})
+	}
+}
+
+func TestTagMergeReq_Validate(t *testing.T) {
+	tooMany := make([]uint64, TagMaxMerge+1)
+	for i := range tooMany {
+		tooMany[i] = uint64(i + 10)
+	}
+
+	tests := []struct {
+		name    string
+		m       *TagMergeReq
+		want    []uint64
+		wantErr bool
+	}{
+		{name: "failed target is required", m: &TagMergeReq{SourceIDs: []uint64{2}}, wantErr: true},
+		{name: "failed sources are required", m: &TagMergeReq{TargetID: 1, SourceIDs: []uint64{0}}, wantErr: true},
+		{name: "failed target in sources", m: &TagMergeReq{TargetID: 1, SourceIDs: []uint64{2, 1}}, wantErr: true},
+		{name: "failed too many", m: &TagMergeReq{TargetID: 1, SourceIDs: tooMany}, wantErr: true},
+		{name: "success dedupe", m: &TagMergeReq{TargetID: 1, SourceIDs: []uint64{3, 2, 3}}, want: []uint64{3, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.m.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TagMergeReq.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(tt.m.SourceIDs, tt.want) {
+				t.Errorf("TagMergeReq.Validate() source ids = %v, want %v", tt.m.SourceIDs, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostListReq_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       *PostListReq
+		wantTag string
+		wantErr bool
+	}{
+		{name: "failed descendants without tag", m: &PostListReq{Descendants: true}, wantErr: true},
+		{name: "success no filter", m: &PostListReq{}},
+		{name: "success normalized", m: &PostListReq{Tag: " GO ", Descendants: true}, wantTag: "go"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.m.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PostListReq.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && tt.m.Tag != tt.wantTag {
+				t.Errorf("PostListReq.Validate() tag = %q, want %q", tt.m.Tag, tt.wantTag)
+			}
+		})
+	}
+}
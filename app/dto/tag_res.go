@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"sort"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+)
+
+type TagRes struct {
+	ID       uint64   `json:"id"`
+	Label    string   `json:"label"`
+	ParentID *uint64  `json:"parent_id"`
+	Synonyms []string `json:"synonyms"`
+	Children []TagRes `json:"children"`
+}
+
+// NewTagTree nests tags under their parents, sorted by label. A tag whose
+// parent is not in tags is a root.
+func NewTagTree(tags []models.Tag, synonyms []models.TagSynonym) []TagRes {
+	var (
+		byID     = make(map[uint64]*models.Tag, len(tags))
+		children = map[uint64][]models.Tag{}
+		labels   = map[uint64][]string{}
+		roots    = []models.Tag{}
+	)
+	for i := range tags {
+		byID[tags[i].ID] = &tags[i]
+	}
+	for _, v := range tags {
+		if v.ParentID != nil && byID[*v.ParentID] != nil {
+			children[*v.ParentID] = append(children[*v.ParentID], v)
+			continue
+		}
+		roots = append(roots, v)
+	}
+	for _, v := range synonyms {
+		labels[v.TagID] = append(labels[v.TagID], v.Label)
+	}
+
+	var build func(tags []models.Tag) []TagRes
+	build = func(tags []models.Tag) []TagRes {
+		sort.Slice(tags, func(i, j int) bool {
+			return tags[i].Label < tags[j].Label
+		})
+
+		result := make([]TagRes, 0, len(tags))
+		for _, v := range tags {
+			synonyms := append([]string{}, labels[v.ID]...)
+			sort.Strings(synonyms)
+			result = append(result, TagRes{
+				ID:       v.ID,
+				Label:    v.Label,
+				ParentID: v.ParentID,
+				Synonyms: synonyms,
+				Children: build(children[v.ID]),
+			})
+		}
+		return result
+	}
+	return build(roots)
+}
@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+)
+
+func TestNewTagTree(t *testing.T) {
+	var (
+		lang    = uint64(1)
+		missing = uint64(99)
+		tags    = []models.Tag{
+			{ID: 3, Label: "rust", ParentID: &lang},
+			{ID: 2, Label: "golang", ParentID: &lang},
+			{ID: 1, Label: "language"},
+			{ID: 4, Label: "orphan", ParentID: &missing},
+		}
+		synonyms = []models.TagSynonym{
+			{TagID: 2, Label: "golang-lang"},
+			{TagID: 2, Label: "go"},
+		}
+	)
+
+	want := []TagRes{
+		{ID: 1, Label: "language", Synonyms: []string{}, Children: []TagRes{
+			{ID: 2, Label: "golang", ParentID: &lang, Synonyms: []string{"go", "golang-lang"}, Children: []TagRes{}},
+			{ID: 3, Label: "rust", ParentID: &lang, Synonyms: []string{}, Children: []TagRes{}},
+		}},
+		{ID: 4, Label: "orphan", ParentID: &missing, Synonyms: []string{}, Children: []TagRes{}},
+	}
+	if got := NewTagTree(tags, synonyms); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewTagTree() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,99 @@
+package dto
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+)
+
+const (
+	TagSuggestDefaultLimit = 10
+	TagSuggestMaxLimit     = 50
+	// TagPrefixMinLen keeps a prefix from matching most of the tags.
+	TagPrefixMinLen = 2
+	TagPrefixMaxLen = 100
+
+	TagPopularDefaultWindow = "30d"
+	TagPopularMaxWindow     = 365 * 24 * time.Hour
+	TagPopularDefaultLimit  = 20
+	TagPopularMaxLimit      = 100
+)
+
+var tagWindowPattern = regexp.MustCompile(`^([1-9][0-9]*)([hdw])$`)
+
+// TagSuggestReq looks up the tags starting with or close to Prefix.
+type TagSuggestReq struct {
+	Prefix string `form:"prefix"`
+	Limit  int    `form:"limit"`
+}
+
+func (m *TagSuggestReq) Validate() error {
+	m.Prefix = helpers.ToLower(m.Prefix)
+	if m.Prefix == "" {
+		return helpers.ErrIsRequired("prefix", "prefix")
+	}
+	if utf8.RuneCountInString(m.Prefix) < TagPrefixMinLen {
+		return helpers.ErrMinCharacters("prefix", "prefix", strconv.Itoa(TagPrefixMinLen))
+	}
+	if utf8.RuneCountInString(m.Prefix) > TagPrefixMaxLen {
+		return helpers.ErrCannotBeMoreThan("prefix", "prefix", strconv.Itoa(TagPrefixMaxLen))
+	}
+
+	if m.Limit <= 0 {
+		m.Limit = TagSuggestDefaultLimit
+	}
+	if m.Limit > TagSuggestMaxLimit {
+		return helpers.ErrCannotBeMoreThan("limit", "limit", strconv.Itoa(TagSuggestMaxLimit))
+	}
+
+	return nil
+}
+
+// TagPopularReq counts the tags given to published posts during the last
+// Window, e.g. 12h, 30d or 4w.
+type TagPopularReq struct {
+	Window string        `form:"window"`
+	Limit  int           `form:"limit"`
+	Since  time.Duration `form:"-"` // Window parsed
+}
+
+func (m *TagPopularReq) Validate() error {
+	m.Window = helpers.ToLower(m.Window)
+	if m.Window == "" {
+		m.Window = TagPopularDefaultWindow
+	}
+
+	match := tagWindowPattern.FindStringSubmatch(m.Window)
+	if match == nil {
+		return helpers.ErrInvalid("jendela waktu", "window")
+	}
+	total, err := strconv.Atoi(match[1])
+	if err != nil {
+		return helpers.ErrInvalid("jendela waktu", "window")
+	}
+
+	unit := map[string]time.Duration{"h": time.Hour, "d": 24 * time.Hour, "w": 7 * 24 * time.Hour}[match[2]]
+	if time.Duration(total) > TagPopularMaxWindow/unit {
+		return helpers.ErrCannotBeMoreThan("jendela waktu", "window", "365d")
+	}
+	m.Since = time.Duration(total) * unit
+
+	if m.Limit <= 0 {
+		m.Limit = TagPopularDefaultLimit
+	}
+	if m.Limit > TagPopularMaxLimit {
+		return helpers.ErrCannotBeMoreThan("limit", "limit", strconv.Itoa(TagPopularMaxLimit))
+	}
+
+	return nil
+}
+
+// TagUsageRes is a tag with how many posts have it.
+type TagUsageRes struct {
+	ID    uint64 `json:"id"`
+	Label string `json:"label"`
+	Usage int64  `json:"usage"`
+}
@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTagSuggestReq_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		m          *TagSuggestReq
+		wantPrefix string
+		wantLimit  int
+		wantErr    bool
+	}{
+		{name: "failed prefix is required", m: &TagSuggestReq{Prefix: "  "}, wantErr: true},
+		{name: "failed prefix too short", m: &TagSuggestReq{Prefix: " G "}, wantErr: true},
+		{name: "failed prefix too long", m: &TagSuggestReq{Prefix: strings.Repeat("a", TagPrefixMaxLen+1)}, wantErr: true},
+		{name: "failed limit too big", m: &TagSuggestReq{Prefix: "go", Limit: TagSuggestMaxLimit + 1}, wantErr: true},
+		{name: "success default limit", m: &TagSuggestReq{Prefix: " GO "}, wantPrefix: "go", wantLimit: TagSuggestDefaultLimit},
+		{name: "success", m: &TagSuggestReq{Prefix: "go", Limit: 5}, wantPrefix: "go", wantLimit: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.m.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TagSuggestReq.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.m.Prefix != tt.wantPrefix || tt.m.Limit != tt.wantLimit {
+				t.Errorf("TagSuggestReq.Validate() = %q %d, want %q %d", tt.m.Prefix, tt.m.Limit, tt.wantPrefix, tt.wantLimit)
+			}
+		})
+	}
+}
+
+func TestTagPopularReq_Validate(t *testing.T) {
+	day := 24 * time.Hour
+	tests := []struct {
+		name      string
+		m         *TagPopularReq
+		wantSince time.Duration
+		wantLimit int
+		wantErr   bool
+	}{
+		{name: "failed unknown unit", m: &TagPopularReq{Window: "30m"}, wantErr: true},
+		{name: "failed zero", m: &TagPopularReq{Window: "0d"}, wantErr: true},
+		{name: "failed too long", m: &TagPopularReq{Window: "53w"}, wantErr: true},
+		{name: "failed overflow", m: &TagPopularReq{Window: "99999999999999999999h"}, wantErr: true},
+		{name: "failed limit too big", m: &TagPopularReq{Limit: TagPopularMaxLimit + 1}, wantErr: true},
+		{name: "success default", m: &TagPopularReq{}, wantSince: 30 * day, wantLimit: TagPopularDefaultLimit},
+		{name: "success hours", m: &TagPopularReq{Window: "12H", Limit: 5}, wantSince: 12 * time.Hour, wantLimit: 5},
+		{name: "success max", m: &TagPopularReq{Window: "365d"}, wantSince: 365 * day, wantLimit: TagPopularDefaultLimit},
+		{name: "success weeks", m: &TagPopularReq{Window: "4w"}, wantSince: 28 * day, wantLimit: TagPopularDefaultLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.m.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TagPopularReq.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.m.Since != tt.wantSince || tt.m.Limit != tt.wantLimit {
+				t.Errorf("TagPopularReq.Validate() = %v %d, want %v %d", tt.m.Since, tt.m.Limit, tt.wantSince, tt.wantLimit)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package dto
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+)
+
+const (
+	WebhookStatusPending   = "pending"
+	WebhookStatusRetrying  = "retrying"
+	WebhookStatusSucceeded = "succeeded"
+	WebhookStatusDead      = "dead"
+
+	// WebhookMaxEvents is how many event filters a webhook may have.
+	WebhookMaxEvents = 20
+	// WebhookMinSecret is the shortest secret a webhook may be given.
+	WebhookMinSecret = 16
+
+	WebhookDeliveryDefaultLimit = 50
+	WebhookDeliveryMaxLimit     = 200
+)
+
+// WebhookEventTypes are the events a webhook can subscribe to.
+var WebhookEventTypes = []string{
+	PostEventCreated,
+	PostEventUpdated,
+	PostEventDeleted,
+	TagEventCreated,
+	TagEventUpdated,
+	TagEventMerged,
+}
+
+var webhookStatuses = []string{
+	WebhookStatusPending,
+	WebhookStatusRetrying,
+	WebhookStatusSucceeded,
+	WebhookStatusDead,
+}
+
+// WebhookMatch tells if eventType is selected by one of the filters, an
+// event type like "post.created", every event of an aggregate like "post.*"
+// or every event "*".
+func WebhookMatch(filters []string, eventType string) bool {
+	for _, v := range filters {
+		switch {
+		case v == "*", v == eventType:
+			return true
+		case strings.HasSuffix(v, ".*") && strings.HasPrefix(eventType, strings.TrimSuffix(v, "*")):
+			return true
+		}
+	}
+	return false
+}
+
+func isWebhookFilter(filter string) bool {
+	for _, v := range WebhookEventTypes {
+		if WebhookMatch([]string{filter}, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookReq creates or replaces a webhook. A webhook created without a
+// secret is given a random one, a replaced one keeps its secret.
+type WebhookReq struct {
+	ID       uint64   `json:"-"`
+	URL      string   `json:"url"`
+	Events   []string `json:"events"`
+	Secret   string   `json:"secret"`
+	IsActive *bool    `json:"is_active"`
+}
+
+func (m *WebhookReq) Validate() error {
+	m.URL = strings.TrimSpace(m.URL)
+	if m.URL == "" {
+		return helpers.ErrIsRequired("url", "url")
+	}
+	link, err := url.Parse(m.URL)
+	if err != nil || (link.Scheme != "http" && link.Scheme != "https") || link.Host == "" {
+		return helpers.ErrInvalid("url", "url")
+	}
+
+	if len(m.Events) == 0 {
+		return helpers.ErrIsRequired("event", "events")
+	}
+	if len(m.Events) > WebhookMaxEvents {
+		return helpers.ErrCannotBeMoreThan("event", "events", strconv.Itoa(WebhookMaxEvents))
+	}
+	var (
+		events = make([]string, 0, len(m.Events))
+		isSeen = map[string]bool{}
+	)
+	for _, v := range m.Events {
+		v = helpers.ToLower(v)
+		if !isWebhookFilter(v) {
+			return helpers.ErrInvalid("event "+v, "event "+v)
+		}
+		if isSeen[v] {
+			continue
+		}
+		isSeen[v] = true
+		events = append(events, v)
+	}
+	m.Events = events
+
+	m.Secret = strings.TrimSpace(m.Secret)
+	if m.Secret != "" && len(m.Secret) < WebhookMinSecret {
+		return helpers.ErrInvalid("secret", "secret")
+	}
+
+	if m.IsActive == nil {
+		isActive := true
+		m.IsActive = &isActive
+	}
+
+	return nil
+}
+
+// WebhookRes is a webhook, its secret is only sent when it is created.
+type WebhookRes struct {
+	ID        uint64    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"secret,omitempty"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewWebhookRes(m models.Webhook) WebhookRes {
+	events := []string(m.Events)
+	if events == nil {
+		events = []string{}
+	}
+
+	return WebhookRes{
+		ID:        m.ID,
+		URL:       m.URL,
+		Events:    events,
+		IsActive:  m.IsActive,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+// WebhookDeliveryListReq lists the deliveries of a webhook, newest first,
+// only those of Status when given.
+type WebhookDeliveryListReq struct {
+	WebhookID uint64 `form:"-"`
+	Status    string `form:"status"`
+	Limit     int    `form:"limit"`
+	Offset    int    `form:"offset"`
+}
+
+func (m *WebhookDeliveryListReq) Validate() error {
+	if m.WebhookID == 0 {
+		return helpers.ErrIsRequired("id webhook", "webhook id")
+	}
+
+	m.Status = helpers.ToLower(m.Status)
+	if m.Status != "" && !isWebhookStatus(m.Status) {
+		return helpers.ErrInvalid("status", "status")
+	}
+
+	if m.Limit <= 0 {
+		m.Limit = WebhookDeliveryDefaultLimit
+	}
+	if m.Limit > WebhookDeliveryMaxLimit {
+		return helpers.ErrCannotBeMoreThan("limit", "limit", strconv.Itoa(WebhookDeliveryMaxLimit))
+	}
+	if m.Offset < 0 {
+		return helpers.ErrInvalid("offset", "offset")
+	}
+
+	return nil
+}
+
+func isWebhookStatus(status string) bool {
+	for _, v := range webhookStatuses {
+		if v == status {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookRedeliverReq sends the delivery again, whatever its status.
+type WebhookRedeliverReq struct {
+	WebhookID  uint64
+	DeliveryID uint64
+}
+
+// WebhookPayload is the body posted to a webhook.
+type WebhookPayload struct {
+	ID            uint64      `json:"id"`
+	Type          string      `json:"type"`
+	AggregateType string      `json:"aggregate_type"`
+	AggregateID   uint64      `json:"aggregate_id"`
+	OccurredAt    time.Time   `json:"occurred_at"`
+	Data          models.JSON `json:"data"`
+}
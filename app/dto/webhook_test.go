@@ -0,0 +1,106 @@
+package dto
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWebhookMatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		filters   []string
+		eventType string
+		want      bool
+	}{
+		{name: "exact", filters: []string{PostEventCreated}, eventType: PostEventCreated, want: true},
+		{name: "other type", filters: []string{PostEventCreated}, eventType: PostEventDeleted},
+		{name: "aggregate", filters: []string{"tag.*"}, eventType: TagEventMerged, want: true},
+		{name: "other aggregate", filters: []string{"tag.*"}, eventType: PostEventUpdated},
+		{name: "every event", filters: []string{"post.created", "*"}, eventType: TagEventUpdated, want: true},
+		{name: "no filter", eventType: PostEventCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WebhookMatch(tt.filters, tt.eventType); got != tt.want {
+				t.Errorf("WebhookMatch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookReq_Validate(t *testing.T) {
+	var (
+		inactive = false
+		tooMany  = make([]string, WebhookMaxEvents+1)
+	)
+	for i := range tooMany {
+		tooMany[i] = "*"
+	}
+	tests := []struct {
+		name         string
+		m            *WebhookReq
+		wantEvents   []string
+		wantIsActive bool
+		wantErr      bool
+	}{
+		{name: "failed url is required", m: &WebhookReq{Events: []string{"*"}}, wantErr: true},
+		{name: "failed url scheme", m: &WebhookReq{URL: "ftp://example.com", Events: []string{"*"}}, wantErr: true},
+		{name: "failed url without host", m: &WebhookReq{URL: "https:///hook", Events: []string{"*"}}, wantErr: true},
+		{name: "failed events is required", m: &WebhookReq{URL: "https://example.com/hook"}, wantErr: true},
+		{name: "failed too many events", m: &WebhookReq{URL: "https://example.com/hook", Events: tooMany}, wantErr: true},
+		{name: "failed unknown event", m: &WebhookReq{URL: "https://example.com/hook", Events: []string{"asset.created"}}, wantErr: true},
+		{name: "failed short secret", m: &WebhookReq{URL: "https://example.com/hook", Events: []string{"*"}, Secret: "short"}, wantErr: true},
+		{
+			name:         "success normalized",
+			m:            &WebhookReq{URL: " https://example.com/hook ", Events: []string{" Post.Created", "tag.*", "post.created"}},
+			wantEvents:   []string{"post.created", "tag.*"},
+			wantIsActive: true,
+		},
+		{
+			name:       "success inactive with secret",
+			m:          &WebhookReq{URL: "http://127.0.0.1:8080", Events: []string{"*"}, Secret: strings.Repeat("s", WebhookMinSecret), IsActive: &inactive},
+			wantEvents: []string{"*"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.m.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WebhookReq.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(tt.m.Events, tt.wantEvents) || *tt.m.IsActive != tt.wantIsActive || strings.TrimSpace(tt.m.URL) != tt.m.URL {
+				t.Errorf("WebhookReq.Validate() = %+v, want events %v active %v", tt.m, tt.wantEvents, tt.wantIsActive)
+			}
+		})
+	}
+}
+
+func TestWebhookDeliveryListReq_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		m         *WebhookDeliveryListReq
+		wantLimit int
+		wantErr   bool
+	}{
+		{name: "failed webhook id is required", m: &WebhookDeliveryListReq{}, wantErr: true},
+		{name: "failed unknown status", m: &WebhookDeliveryListReq{WebhookID: 1, Status: "lost"}, wantErr: true},
+		{name: "failed limit", m: &WebhookDeliveryListReq{WebhookID: 1, Limit: WebhookDeliveryMaxLimit + 1}, wantErr: true},
+		{name: "failed offset", m: &WebhookDeliveryListReq{WebhookID: 1, Offset: -1}, wantErr: true},
+		{name: "success default limit", m: &WebhookDeliveryListReq{WebhookID: 1, Status: " DEAD "}, wantLimit: WebhookDeliveryDefaultLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.m.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WebhookDeliveryListReq.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && tt.m.Limit != tt.wantLimit {
+				t.Errorf("WebhookDeliveryListReq.Validate() limit = %d, want %d", tt.m.Limit, tt.wantLimit)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// formatError adds to err the status and messages of the ResponseError it
+// comes from, as the REST API sends them, so clients handle both alike.
+func formatError(err gqlerrors.FormattedError) gqlerrors.FormattedError {
+	var respErr *helpers.ResponseError
+	if !errors.As(originalError(err), &respErr) {
+		return err
+	}
+
+	err.Extensions = map[string]interface{}{
+		"code":    helpers.StatusErrorMapping(respErr.Code),
+		"status":  respErr.Status,
+		"message": respErr.Message,
+	}
+	return err
+}
+
+// originalError unwraps the errors graphql wraps a resolver error in, a
+// thunk error is wrapped twice.
+func originalError(err error) error {
+	for {
+		switch v := err.(type) {
+		case gqlerrors.FormattedError:
+			if v.OriginalError() == nil {
+				return v
+			}
+			err = v.OriginalError()
+		case *gqlerrors.Error:
+			if v.OriginalError == nil {
+				return v
+			}
+			err = v.OriginalError
+		default:
+			return err
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/service"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/sirupsen/logrus"
+)
+
+// Request is a GraphQL request as sent to /graphql.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Schema serves the posts, their tags, assets and authors over GraphQL on
+// top of the services, the same rules as the REST API apply.
+type Schema struct {
+	Post   service.PostService
+	Asset  service.AssetService
+	Tag    service.TagService
+	Cfg    *configs.Configs
+	Logger *logrus.Logger
+	schema graphql.Schema
+}
+
+// New builds the schema. Building only fails on a mistake in the types of
+// this package, it panics then.
+func New(
+	srv *service.Services,
+	cfg *configs.Configs,
+	logger *logrus.Logger,
+) *Schema {
+	s := &Schema{
+		Post:   srv.Post,
+		Asset:  srv.Asset,
+		Tag:    srv.Tag,
+		Cfg:    cfg,
+		Logger: logger,
+	}
+
+	types := s.newTypes()
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query:    s.newQuery(types),
+		Mutation: s.newMutation(types),
+	})
+	if err != nil {
+		panic(err)
+	}
+	s.schema = schema
+	return s
+}
+
+// Execute runs req as the actor of ctx. Queries over the depth or
+// complexity limits are refused before anything is resolved, and each
+// request gets its own loaders so the tags, assets and authors of the posts
+// are fetched once per level of the query.
+func (s *Schema) Execute(ctx context.Context, req Request) *graphql.Result {
+	opName := "GraphQL-Execute"
+
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	validation := graphql.ValidateDocument(&s.schema, doc, nil)
+	if !validation.IsValid {
+		return &graphql.Result{Errors: validation.Errors}
+	}
+
+	err = checkLimits(doc, req.OperationName, req.Variables, s.Cfg.GraphQL.MaxDepth, s.Cfg.GraphQL.MaxComplexity)
+	if err != nil {
+		s.Logger.Warnf("%s refused query: %v \n", opName, err)
+		return &graphql.Result{Errors: []gqlerrors.FormattedError{formatError(gqlerrors.FormatError(err))}}
+	}
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        s.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       s.withLoaders(ctx),
+	})
+	for i := range result.Errors {
+		result.Errors[i] = formatError(result.Errors[i])
+	}
+	return result
+}
+
+// operation returns the operation of doc run for operationName, nil when
+// there is none, validation has already refused ambiguous documents.
+func operation(doc *ast.Document, operationName string) *ast.OperationDefinition {
+	for _, v := range doc.Definitions {
+		op, ok := v.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if operationName == "" || (op.Name != nil && op.Name.Value == operationName) {
+			return op
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/app/repository"
+	"github.com/adamnasrudin03/go-asset-findr/app/repository/mocks"
+	"github.com/adamnasrudin03/go-asset-findr/app/service"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/auth"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/driver"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type GraphTestSuite struct {
+	suite.Suite
+	postRepo  *mocks.PostRepository
+	revRepo   *mocks.PostRevisionRepository
+	tagRepo   *mocks.TagRepository
+	assetRepo *mocks.AssetRepository
+	cfg       configs.Configs
+	schema    *Schema
+}
+
+func (s *GraphTestSuite) SetupTest() {
+	s.cfg = *configs.GetInstance()
+	s.cfg.GraphQL.MaxDepth = 8
+	s.cfg.GraphQL.MaxComplexity = 500
+	logger := driver.Logger(&s.cfg)
+
+	s.postRepo = &mocks.PostRepository{}
+	s.revRepo = &mocks.PostRevisionRepository{}
+	s.tagRepo = &mocks.TagRepository{}
+	s.assetRepo = &mocks.AssetRepository{}
+	repos := &repository.Repositories{Post: s.postRepo, PostRevision: s.revRepo, Tag: s.tagRepo, Asset: s.assetRepo}
+	s.schema = New(&service.Services{
+		Post:  service.NewPostService(repos, &s.cfg, logger),
+		Asset: service.NewAssetService(repos, nil, &s.cfg, logger),
+		Tag:   service.NewTagService(repos, &s.cfg, logger),
+	}, &s.cfg, logger)
+}
+
+func TestGraph(t *testing.T) {
+	suite.Run(t, new(GraphTestSuite))
+}
+
+// execute runs query and returns the response as a client decodes it.
+func (s *GraphTestSuite) execute(ctx context.Context, query string, variables map[string]interface{}) map[string]interface{} {
+	res := s.schema.Execute(ctx, Request{Query: query, Variables: variables})
+	body, err := json.Marshal(res)
+	s.Require().NoError(err)
+
+	result := map[string]interface{}{}
+	s.Require().NoError(json.Unmarshal(body, &result))
+	return result
+}
+
+func (s *GraphTestSuite) TestPosts_BatchesRelations() {
+	parentID := uint64(1)
+	s.postRepo.On("GetPage", mock.Anything, mock.MatchedBy(func(req dto.PostPageReq) bool {
+		return req.First == 2 && req.PublishedAt != nil
+	})).Return([]dto.PostRes{
+		{ID: 9, Title: "nine", Content: "# Nine", ContentFormat: "markdown", Status: dto.PostStatusPublished},
+		{ID: 7, Title: "seven", Content: "seven", Status: dto.PostStatusPublished},
+		{ID: 4, Title: "four", Content: "four", Status: dto.PostStatusPublished},
+	}, nil).Once()
+	s.postRepo.On("GetTagsByPosts", mock.Anything, []uint64{9, 7}).Return(map[uint64][]models.Tag{
+		9: {{ID: 2, Label: "go", ParentID: &parentID}},
+	}, nil).Once()
+	s.assetRepo.On("GetByPosts", mock.Anything, []uint64{9, 7}).Return(map[uint64][]models.Asset{
+		7: {{ID: 5, Filename: "a.png", MimeType: "image/png", Width: 10}},
+	}, nil).Once()
+	s.revRepo.On("GetAuthorsByPosts", mock.Anything, []uint64{9, 7}).Return(map[uint64][]dto.PostAuthorRes{
+		9: {{ID: 3, Username: "ana"}},
+	}, nil).Once()
+	s.tagRepo.On("GetAll", mock.Anything).Return([]models.Tag{{ID: 1, Label: "programming"}, {ID: 2, Label: "go", ParentID: &parentID}}, nil).Once()
+	s.tagRepo.On("GetSynonyms", mock.Anything).Return([]models.TagSynonym{{TagID: 2, Label: "golang"}}, nil).Once()
+
+	got := s.execute(context.Background(), `query {
+		posts(first: 2) {
+			nodes {
+				id title content(render: "html")
+				tags { label synonyms parent { label } }
+				assets { id filename width height }
+				authors { username }
+			}
+			pageInfo { hasNextPage endCursor }
+		}
+	}`, nil)
+
+	s.Nil(got["errors"])
+	s.Equal(map[string]interface{}{
+		"posts": map[string]interface{}{
+			"nodes": []interface{}{
+				map[string]interface{}{
+					"id": "9", "title": "Nine", "content": "<h1>Nine</h1>\n",
+					"tags":    []interface{}{map[string]interface{}{"label": "go", "synonyms": []interface{}{"golang"}, "parent": map[string]interface{}{"label": "programming"}}},
+					"assets":  []interface{}{},
+					"authors": []interface{}{map[string]interface{}{"username": "ana"}},
+				},
+				map[string]interface{}{
+					"id": "7", "title": "Seven", "content": "<p>seven</p>\n",
+					"tags":    []interface{}{},
+					"assets":  []interface{}{map[string]interface{}{"id": "5", "filename": "a.png", "width": float64(10), "height": nil}},
+					"authors": []interface{}{},
+				},
+			},
+			"pageInfo": map[string]interface{}{"hasNextPage": true, "endCursor": dto.EncodePostCursor(7)},
+		},
+	}, got["data"])
+	s.postRepo.AssertExpectations(s.T())
+	s.assetRepo.AssertExpectations(s.T())
+	s.revRepo.AssertExpectations(s.T())
+	s.tagRepo.AssertExpectations(s.T())
+}
+
+func (s *GraphTestSuite) TestPost_NotFound() {
+	s.postRepo.On("GetDetail", mock.Anything, dto.PostGetReq{ID: 5}).
+		Return(&dto.PostRes{ID: 5, Status: dto.PostStatusDraft}, nil).Once()
+
+	got := s.execute(context.Background(), `query($id: ID) { post(id: $id) { title } }`, map[string]interface{}{"id": 5})
+
+	s.Equal(map[string]interface{}{"post": nil}, got["data"])
+	errs, _ := got["errors"].([]interface{})
+	s.Require().Len(errs, 1)
+	extensions := errs[0].(map[string]interface{})["extensions"].(map[string]interface{})
+	s.Equal(float64(404), extensions["code"])
+}
+
+func (s *GraphTestSuite) TestLimits() {
+	s.cfg.GraphQL.MaxDepth = 3
+	got := s.execute(context.Background(), `{ posts { nodes { tags { parent { label } } } } }`, nil)
+	s.Nil(got["data"])
+	s.NotNil(got["errors"])
+
+	s.cfg.GraphQL.MaxDepth = 8
+	s.cfg.GraphQL.MaxComplexity = 100
+	got = s.execute(context.Background(), `{ posts(first: 50) { nodes { id title } } }`, nil)
+	s.Nil(got["data"])
+	s.NotNil(got["errors"])
+
+	// nothing was resolved
+	s.postRepo.AssertNotCalled(s.T(), "GetPage", mock.Anything, mock.Anything)
+}
+
+func (s *GraphTestSuite) TestUpdatePostStatus() {
+	var (
+		query  = `mutation { updatePostStatus(id: "5", status: PUBLISHED) { id status } }`
+		editor = auth.WithActor(context.Background(), auth.Actor{ID: 1, Role: auth.RoleEditor})
+		user   = auth.WithActor(context.Background(), auth.Actor{ID: 2, Role: auth.RoleUser})
+	)
+
+	got := s.execute(user, query, nil)
+	s.Nil(got["data"])
+	s.NotNil(got["errors"])
+
+	s.postRepo.On("GetDetail", mock.Anything, dto.PostGetReq{ID: 5, ColumnCustom: "id, status", IsLock: true}).
+		Return(&dto.PostRes{ID: 5, Status: dto.PostStatusDraft}, nil).Once()
+	s.postRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(req dto.PostStatusReq) bool {
+		return req.ID == 5 && req.Status == dto.PostStatusPublished
+	})).Return(nil).Once()
+	s.postRepo.On("GetDetail", mock.Anything, dto.PostGetReq{ID: 5}).
+		Return(&dto.PostRes{ID: 5, Status: dto.PostStatusPublished}, nil).Once()
+
+	got = s.execute(editor, query, nil)
+	s.Nil(got["errors"])
+	s.Equal(map[string]interface{}{
+		"updatePostStatus": map[string]interface{}{"id": "5", "status": "PUBLISHED"},
+	}, got["data"])
+}
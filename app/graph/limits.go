@@ -0,0 +1,123 @@
+package graph
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// pagedFields are the list fields taking a first argument, with the page
+// size used when it is not given. Their selection counts first times.
+var pagedFields = map[string]int{
+	"posts": dto.PostPageDefaultFirst,
+}
+
+// checkLimits refuses the operation of doc run for operationName when its
+// fields nest deeper than maxDepth or it resolves more than maxComplexity
+// fields, 0 turns a limit off.
+func checkLimits(doc *ast.Document, operationName string, variables map[string]interface{}, maxDepth, maxComplexity int) error {
+	depth, complexity := measure(doc, operationName, variables)
+	if maxDepth > 0 && depth > maxDepth {
+		return helpers.ErrCannotBeMoreThan("kedalaman query", "query depth", strconv.Itoa(maxDepth))
+	}
+	if maxComplexity > 0 && complexity > maxComplexity {
+		return helpers.ErrCannotBeMoreThan("kompleksitas query", "query complexity", strconv.Itoa(maxComplexity))
+	}
+	return nil
+}
+
+// measure returns how deep the fields of the operation nest and how many
+// fields it resolves. Introspection fields are free, clients need them
+// whatever the limits.
+func measure(doc *ast.Document, operationName string, variables map[string]interface{}) (depth, complexity int) {
+	op := operation(doc, operationName)
+	if op == nil {
+		return 0, 0
+	}
+
+	m := measurer{fragments: map[string]*ast.FragmentDefinition{}, variables: variables}
+	for _, v := range doc.Definitions {
+		if fragment, ok := v.(*ast.FragmentDefinition); ok && fragment.Name != nil {
+			m.fragments[fragment.Name.Value] = fragment
+		}
+	}
+	return m.selectionSet(op.SelectionSet, map[string]bool{})
+}
+
+type measurer struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+// selectionSet returns the depth and complexity of set, spreads already in
+// the path are skipped, validation refuses cycles anyway.
+func (m measurer) selectionSet(set *ast.SelectionSet, spreads map[string]bool) (depth, complexity int) {
+	if set == nil {
+		return 0, 0
+	}
+
+	for _, selection := range set.Selections {
+		var d, c int
+		switch v := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(v.Name.Value, "__") {
+				continue
+			}
+			d, c = m.selectionSet(v.SelectionSet, spreads)
+			d, c = d+1, 1+m.multiplier(v)*c
+		case *ast.InlineFragment:
+			d, c = m.selectionSet(v.SelectionSet, spreads)
+		case *ast.FragmentSpread:
+			fragment := m.fragments[v.Name.Value]
+			if fragment == nil || spreads[v.Name.Value] {
+				continue
+			}
+			spreads[v.Name.Value] = true
+			d, c = m.selectionSet(fragment.SelectionSet, spreads)
+			delete(spreads, v.Name.Value)
+		}
+
+		if d > depth {
+			depth = d
+		}
+		complexity += c
+	}
+	return depth, complexity
+}
+
+// multiplier is how many times the selection of field is resolved, the
+// first argument of a paged field or else its default page size.
+func (m measurer) multiplier(field *ast.Field) int {
+	pageSize, ok := pagedFields[field.Name.Value]
+	if !ok {
+		return 1
+	}
+
+	first := 0
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != "first" {
+			continue
+		}
+		switch v := arg.Value.(type) {
+		case *ast.IntValue:
+			if n, err := strconv.Atoi(v.Value); err == nil {
+				first = n
+			}
+		case *ast.Variable:
+			switch n := m.variables[v.Name.Value].(type) {
+			case float64:
+				first = int(n)
+			case int:
+				first = n
+			}
+		}
+	}
+
+	if first < 1 {
+		return pageSize
+	}
+	return first
+}
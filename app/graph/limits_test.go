@@ -0,0 +1,79 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+func TestMeasure(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		operationName  string
+		variables      map[string]interface{}
+		wantDepth      int
+		wantComplexity int
+	}{
+		{
+			name:           "flat",
+			query:          `{ tags { id label } }`,
+			wantDepth:      2,
+			wantComplexity: 3,
+		},
+		{
+			name:           "posts count first times",
+			query:          `{ posts(first: 10) { nodes { id tags { label } } } }`,
+			wantDepth:      4,
+			wantComplexity: 1 + 10*(1+1+2),
+		},
+		{
+			name:           "posts default page size",
+			query:          `{ posts { nodes { id } } }`,
+			wantDepth:      3,
+			wantComplexity: 1 + 20*2,
+		},
+		{
+			name:           "first from a variable",
+			query:          `query($n: Int) { posts(first: $n) { nodes { id } } }`,
+			variables:      map[string]interface{}{"n": float64(5)},
+			wantDepth:      3,
+			wantComplexity: 1 + 5*2,
+		},
+		{
+			name: "fragments count where spread",
+			query: `query { posts(first: 2) { nodes { ...postFields ... on Post { slug } } } }
+				fragment postFields on Post { id tags { ...tagFields } }
+				fragment tagFields on Tag { label parent { label } }`,
+			wantDepth:      5,
+			wantComplexity: 1 + 2*(1+1+(1+1+2)+1),
+		},
+		{
+			name:           "introspection is free",
+			query:          `{ __schema { types { name fields { name type { name ofType { name } } } } } tags { id } }`,
+			wantDepth:      2,
+			wantComplexity: 2,
+		},
+		{
+			name: "only the operation run",
+			query: `query small { tags { id } }
+				query large { posts(first: 100) { nodes { id } } }`,
+			operationName:  "small",
+			wantDepth:      2,
+			wantComplexity: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parser.Parse(parser.ParseParams{Source: tt.query})
+			if err != nil {
+				t.Fatalf("parse error = %v", err)
+			}
+
+			depth, complexity := measure(doc, tt.operationName, tt.variables)
+			if depth != tt.wantDepth || complexity != tt.wantComplexity {
+				t.Errorf("measure() = %d, %d, want %d, %d", depth, complexity, tt.wantDepth, tt.wantComplexity)
+			}
+		})
+	}
+}
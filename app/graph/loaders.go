@@ -0,0 +1,66 @@
+package graph
+
+import (
+	"context"
+	"sync"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/dataloader"
+)
+
+// loaders batch the loads of one request, see dataloader.Loader.
+type loaders struct {
+	tags    *dataloader.Loader[uint64, []dto.TagRes]
+	assets  *dataloader.Loader[uint64, []dto.AssetRes]
+	authors *dataloader.Loader[uint64, []dto.PostAuthorRes]
+
+	catalogueOnce sync.Once
+	catalogue     *tagCatalogue
+	catalogueErr  error
+	loadCatalogue func(ctx context.Context) ([]dto.TagRes, error)
+}
+
+// tagCatalogue is the tag tree by tag id, to resolve the parent, children
+// and synonyms of any tag without a query per tag.
+type tagCatalogue struct {
+	roots []dto.TagRes
+	byID  map[uint64]*dto.TagRes
+}
+
+type loadersKey struct{}
+
+func (s *Schema) withLoaders(ctx context.Context) context.Context {
+	return context.WithValue(ctx, loadersKey{}, &loaders{
+		tags:          dataloader.New(s.Post.GetTagsByPosts),
+		assets:        dataloader.New(s.Asset.GetByPosts),
+		authors:       dataloader.New(s.Post.GetAuthorsByPosts),
+		loadCatalogue: s.Tag.GetTree,
+	})
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// tagCatalogue loads the tag tree once per request.
+func (l *loaders) tagCatalogue(ctx context.Context) (*tagCatalogue, error) {
+	l.catalogueOnce.Do(func() {
+		tree, err := l.loadCatalogue(ctx)
+		if err != nil {
+			l.catalogueErr = err
+			return
+		}
+
+		catalogue := &tagCatalogue{roots: tree, byID: map[uint64]*dto.TagRes{}}
+		var index func(tags []dto.TagRes)
+		index = func(tags []dto.TagRes) {
+			for i := range tags {
+				catalogue.byID[tags[i].ID] = &tags[i]
+				index(tags[i].Children)
+			}
+		}
+		index(tree)
+		l.catalogue = catalogue
+	})
+	return l.catalogue, l.catalogueErr
+}
@@ -0,0 +1,169 @@
+package graph
+
+import (
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/auth"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/database"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/graphql-go/graphql"
+)
+
+func (s *Schema) newMutation(t *types) *graphql.Object {
+	tags := graphql.NewList(graphql.NewNonNull(graphql.String))
+
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createPost": {
+				Type: graphql.NewNonNull(t.post),
+				Args: graphql.FieldConfigArgument{
+					"input": {Type: graphql.NewNonNull(graphql.NewInputObject(graphql.InputObjectConfig{
+						Name: "CreatePostInput",
+						Fields: graphql.InputObjectConfigFieldMap{
+							"title":         {Type: graphql.NewNonNull(graphql.String)},
+							"content":       {Type: graphql.NewNonNull(graphql.String)},
+							"contentFormat": {Type: graphql.String},
+							"tags":          {Type: tags},
+							"status":        {Type: t.postStatus},
+							"publishAt":     {Type: graphql.DateTime},
+						},
+					}))},
+				},
+				Resolve: s.resolveCreatePost,
+			},
+			"updatePost": {
+				Type: graphql.NewNonNull(t.post),
+				Args: graphql.FieldConfigArgument{
+					"id": {Type: graphql.NewNonNull(graphql.ID)},
+					"input": {Type: graphql.NewNonNull(graphql.NewInputObject(graphql.InputObjectConfig{
+						Name: "UpdatePostInput",
+						Fields: graphql.InputObjectConfigFieldMap{
+							"title":         {Type: graphql.NewNonNull(graphql.String)},
+							"content":       {Type: graphql.NewNonNull(graphql.String)},
+							"contentFormat": {Type: graphql.String},
+							"tags":          {Type: tags},
+						},
+					}))},
+				},
+				Resolve: s.resolveUpdatePost,
+			},
+			"updatePostStatus": {
+				Type:        graphql.NewNonNull(t.post),
+				Description: "Moves the post along the status workflow, for editors.",
+				Args: graphql.FieldConfigArgument{
+					"id":        {Type: graphql.NewNonNull(graphql.ID)},
+					"status":    {Type: graphql.NewNonNull(t.postStatus)},
+					"publishAt": {Type: graphql.DateTime},
+				},
+				Resolve: s.resolveUpdatePostStatus,
+			},
+			"deletePost": {
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{
+					"id": {Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					postID, err := idArg(p.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					err = s.Post.DeleteByID(p.Context, postID)
+					if err != nil {
+						return nil, err
+					}
+					return true, nil
+				},
+			},
+		},
+	})
+}
+
+func (s *Schema) resolveCreatePost(p graphql.ResolveParams) (interface{}, error) {
+	input, _ := p.Args["input"].(map[string]interface{})
+
+	res, err := s.Post.Create(p.Context, dto.PostCreateReq{
+		Title:         stringArg(input, "title"),
+		Content:       stringArg(input, "content"),
+		ContentFormat: stringArg(input, "contentFormat"),
+		Tags:          stringsArg(input, "tags"),
+		Status:        stringArg(input, "status"),
+		PublishAt:     timeArg(input, "publishAt"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return *res, nil
+}
+
+func (s *Schema) resolveUpdatePost(p graphql.ResolveParams) (interface{}, error) {
+	postID, err := idArg(p.Args, "id")
+	if err != nil {
+		return nil, err
+	}
+	input, _ := p.Args["input"].(map[string]interface{})
+
+	err = s.Post.UpdateByID(p.Context, dto.PostUpdateReq{
+		ID:            postID,
+		Title:         stringArg(input, "title"),
+		Content:       stringArg(input, "content"),
+		ContentFormat: stringArg(input, "contentFormat"),
+		Tags:          stringsArg(input, "tags"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.readAfterWrite(p, postID)
+}
+
+func (s *Schema) resolveUpdatePostStatus(p graphql.ResolveParams) (interface{}, error) {
+	if !auth.FromContext(p.Context).IsEditor() {
+		return nil, helpers.ErrCannotHaveAccessResources()
+	}
+
+	postID, err := idArg(p.Args, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Post.UpdateStatus(p.Context, dto.PostStatusReq{
+		ID:        postID,
+		Status:    stringArg(p.Args, "status"),
+		PublishAt: timeArg(p.Args, "publishAt"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.readAfterWrite(p, postID)
+}
+
+// readAfterWrite returns the post as just written, from the primary so a
+// lagging replica does not hide the change.
+func (s *Schema) readAfterWrite(p graphql.ResolveParams, postID uint64) (interface{}, error) {
+	res, err := s.Post.GetDetail(database.WithReadPrimary(p.Context), dto.PostGetReq{ID: postID})
+	if err != nil {
+		return nil, err
+	}
+	return *res, nil
+}
+
+func stringsArg(args map[string]interface{}, name string) []string {
+	values, _ := args[name].([]interface{})
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func timeArg(args map[string]interface{}, name string) *time.Time {
+	v, ok := args[name].(time.Time)
+	if !ok {
+		return nil
+	}
+	return &v
+}
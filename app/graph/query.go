@@ -0,0 +1,94 @@
+package graph
+
+import (
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/graphql-go/graphql"
+)
+
+func (s *Schema) newQuery(t *types) *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"post": {
+				Type:        t.post,
+				Description: "The post of id, or having or having had slug.",
+				Args: graphql.FieldConfigArgument{
+					"id":   {Type: graphql.ID},
+					"slug": {Type: graphql.String},
+				},
+				Resolve: s.resolvePost,
+			},
+			"posts": {
+				Type: graphql.NewNonNull(t.postPage),
+				Args: graphql.FieldConfigArgument{
+					"first": {Type: graphql.Int, DefaultValue: dto.PostPageDefaultFirst},
+					"after": {Type: graphql.String},
+					"tag": {
+						Type:        graphql.String,
+						Description: "Only the posts with this tag, given by its label or a synonym.",
+					},
+					"descendants": {
+						Type:         graphql.Boolean,
+						DefaultValue: false,
+						Description:  "Also the posts with a tag below tag.",
+					},
+					"status": {Type: t.postStatus},
+				},
+				Resolve: s.resolvePosts,
+			},
+			"tags": {
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(t.tag))),
+				Description: "The tags at the top of the tree.",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					catalogue, err := loadersFrom(p.Context).tagCatalogue(p.Context)
+					if err != nil {
+						return nil, err
+					}
+					return catalogue.roots, nil
+				},
+			},
+		},
+	})
+}
+
+func (s *Schema) resolvePost(p graphql.ResolveParams) (interface{}, error) {
+	var (
+		post *dto.PostRes
+		err  error
+	)
+
+	switch {
+	case p.Args["id"] != nil:
+		postID, err := idArg(p.Args, "id")
+		if err != nil {
+			return nil, err
+		}
+		post, err = s.Post.GetDetail(p.Context, dto.PostGetReq{ID: postID})
+		if err != nil {
+			return nil, err
+		}
+	case p.Args["slug"] != nil:
+		post, err = s.Post.GetBySlug(p.Context, stringArg(p.Args, "slug"))
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, helpers.ErrIsRequired("id atau slug", "id or slug")
+	}
+
+	return *post, nil
+}
+
+func (s *Schema) resolvePosts(p graphql.ResolveParams) (interface{}, error) {
+	first, _ := p.Args["first"].(int)
+	descendants, _ := p.Args["descendants"].(bool)
+
+	return s.Post.GetPage(p.Context, dto.PostPageReq{
+		First:       first,
+		After:       stringArg(p.Args, "after"),
+		Tag:         stringArg(p.Args, "tag"),
+		Descendants: descendants,
+		Status:      stringArg(p.Args, "status"),
+	})
+}
@@ -0,0 +1,265 @@
+package graph
+
+import (
+	"strconv"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/graphql-go/graphql"
+)
+
+// types are the object types of the schema.
+type types struct {
+	postStatus *graphql.Enum
+	tag        *graphql.Object
+	asset      *graphql.Object
+	author     *graphql.Object
+	post       *graphql.Object
+	postPage   *graphql.Object
+}
+
+func (s *Schema) newTypes() *types {
+	t := &types{}
+
+	t.postStatus = graphql.NewEnum(graphql.EnumConfig{
+		Name: "PostStatus",
+		Values: graphql.EnumValueConfigMap{
+			"DRAFT":     &graphql.EnumValueConfig{Value: dto.PostStatusDraft},
+			"IN_REVIEW": &graphql.EnumValueConfig{Value: dto.PostStatusInReview},
+			"SCHEDULED": &graphql.EnumValueConfig{Value: dto.PostStatusScheduled},
+			"PUBLISHED": &graphql.EnumValueConfig{Value: dto.PostStatusPublished},
+			"ARCHIVED":  &graphql.EnumValueConfig{Value: dto.PostStatusArchived},
+		},
+	})
+
+	t.tag = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Tag",
+		Description: "A tag, placed in a tree under broader tags.",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":    {Type: graphql.NewNonNull(graphql.ID), Resolve: tagField(func(m dto.TagRes) interface{} { return m.ID })},
+				"label": {Type: graphql.NewNonNull(graphql.String), Resolve: tagField(func(m dto.TagRes) interface{} { return m.Label })},
+				"parent": {
+					Type: t.tag,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						tag := p.Source.(dto.TagRes)
+						if tag.ParentID == nil {
+							return nil, nil
+						}
+						return catalogueTag(p, *tag.ParentID, nil, func(m dto.TagRes) interface{} { return m })
+					},
+				},
+				"children": {
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(t.tag))),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return catalogueTag(p, p.Source.(dto.TagRes).ID, []dto.TagRes{}, func(m dto.TagRes) interface{} { return m.Children })
+					},
+				},
+				"synonyms": {
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return catalogueTag(p, p.Source.(dto.TagRes).ID, []string{}, func(m dto.TagRes) interface{} { return m.Synonyms })
+					},
+				},
+			}
+		}),
+	})
+
+	t.asset = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Asset",
+		Fields: graphql.Fields{
+			"id":          {Type: graphql.NewNonNull(graphql.ID), Resolve: assetField(func(m dto.AssetRes) interface{} { return m.ID })},
+			"filename":    {Type: graphql.NewNonNull(graphql.String), Resolve: assetField(func(m dto.AssetRes) interface{} { return m.Filename })},
+			"mimeType":    {Type: graphql.NewNonNull(graphql.String), Resolve: assetField(func(m dto.AssetRes) interface{} { return m.MimeType })},
+			"size":        {Type: graphql.NewNonNull(graphql.Float), Resolve: assetField(func(m dto.AssetRes) interface{} { return float64(m.Size) })},
+			"checksum":    {Type: graphql.NewNonNull(graphql.String), Resolve: assetField(func(m dto.AssetRes) interface{} { return m.Checksum })},
+			"downloadUrl": {Type: graphql.NewNonNull(graphql.String), Resolve: assetField(func(m dto.AssetRes) interface{} { return m.DownloadURL })},
+			"width":       {Type: graphql.Int, Resolve: assetField(func(m dto.AssetRes) interface{} { return nonZero(m.Width) })},
+			"height":      {Type: graphql.Int, Resolve: assetField(func(m dto.AssetRes) interface{} { return nonZero(m.Height) })},
+			"pageCount":   {Type: graphql.Int, Resolve: assetField(func(m dto.AssetRes) interface{} { return nonZero(m.PageCount) })},
+			"metaStatus":  {Type: graphql.NewNonNull(graphql.String), Resolve: assetField(func(m dto.AssetRes) interface{} { return m.MetaStatus })},
+			"createdAt":   {Type: graphql.NewNonNull(graphql.DateTime), Resolve: assetField(func(m dto.AssetRes) interface{} { return m.CreatedAt })},
+		},
+	})
+
+	t.author = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Author",
+		Description: "Someone who edited the post.",
+		Fields: graphql.Fields{
+			"id":       {Type: graphql.NewNonNull(graphql.ID), Resolve: authorField(func(m dto.PostAuthorRes) interface{} { return m.ID })},
+			"username": {Type: graphql.NewNonNull(graphql.String), Resolve: authorField(func(m dto.PostAuthorRes) interface{} { return m.Username })},
+		},
+	})
+
+	t.post = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Post",
+		Fields: graphql.Fields{
+			"id":    {Type: graphql.NewNonNull(graphql.ID), Resolve: postField(func(m dto.PostRes) interface{} { return m.ID })},
+			"title": {Type: graphql.NewNonNull(graphql.String), Resolve: postField(func(m dto.PostRes) interface{} { return m.Title })},
+			"slug":  {Type: graphql.NewNonNull(graphql.String), Resolve: postField(func(m dto.PostRes) interface{} { return m.Slug })},
+			"content": {
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "The content as written, or rendered to html or text.",
+				Args: graphql.FieldConfigArgument{
+					"render": {Type: graphql.String, DefaultValue: ""},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					mode, err := dto.ValidateRender(stringArg(p.Args, "render"))
+					if err != nil {
+						return nil, err
+					}
+
+					post := p.Source.(dto.PostRes)
+					post.CheckResp()
+					post.Represent(mode)
+					return post.Content, nil
+				},
+			},
+			"contentFormat": {Type: graphql.NewNonNull(graphql.String), Resolve: postField(func(m dto.PostRes) interface{} { return m.ContentFormat })},
+			"excerpt":       {Type: graphql.NewNonNull(graphql.String), Resolve: postField(func(m dto.PostRes) interface{} { return m.Excerpt })},
+			"readingTime":   {Type: graphql.NewNonNull(graphql.Int), Resolve: postField(func(m dto.PostRes) interface{} { return m.ReadingTime })},
+			"status":        {Type: graphql.NewNonNull(t.postStatus), Resolve: postField(func(m dto.PostRes) interface{} { return m.Status })},
+			"publishAt":     {Type: graphql.DateTime, Resolve: postField(func(m dto.PostRes) interface{} { return m.PublishAt })},
+			"createdAt":     {Type: graphql.NewNonNull(graphql.DateTime), Resolve: postField(func(m dto.PostRes) interface{} { return m.CreatedAt })},
+			"updatedAt":     {Type: graphql.NewNonNull(graphql.DateTime), Resolve: postField(func(m dto.PostRes) interface{} { return m.UpdatedAt })},
+			"tags": {
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(t.tag))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					thunk := loadersFrom(p.Context).tags.Load(p.Context, p.Source.(dto.PostRes).ID)
+					return func() (interface{}, error) {
+						tags, err := thunk()
+						return emptyIfNil(tags), err
+					}, nil
+				},
+			},
+			"assets": {
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(t.asset))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					thunk := loadersFrom(p.Context).assets.Load(p.Context, p.Source.(dto.PostRes).ID)
+					return func() (interface{}, error) {
+						assets, err := thunk()
+						return emptyIfNil(assets), err
+					}, nil
+				},
+			},
+			"authors": {
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(t.author))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					thunk := loadersFrom(p.Context).authors.Load(p.Context, p.Source.(dto.PostRes).ID)
+					return func() (interface{}, error) {
+						authors, err := thunk()
+						return emptyIfNil(authors), err
+					}, nil
+				},
+			},
+		},
+	})
+
+	t.postPage = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "PostConnection",
+		Description: "A page of posts, newest first. Pass endCursor as after to get the next one.",
+		Fields: graphql.Fields{
+			"nodes": {
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(t.post))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(*dto.PostPageRes).Posts, nil
+				},
+			},
+			"pageInfo": {
+				Type: graphql.NewNonNull(graphql.NewObject(graphql.ObjectConfig{
+					Name: "PageInfo",
+					Fields: graphql.Fields{
+						"hasNextPage": {
+							Type: graphql.NewNonNull(graphql.Boolean),
+							Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+								return p.Source.(*dto.PostPageRes).HasNextPage, nil
+							},
+						},
+						"endCursor": {
+							Type: graphql.String,
+							Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+								if cursor := p.Source.(*dto.PostPageRes).EndCursor; cursor != "" {
+									return cursor, nil
+								}
+								return nil, nil
+							},
+						},
+					},
+				})),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source, nil
+				},
+			},
+		},
+	})
+
+	return t
+}
+
+func postField(get func(m dto.PostRes) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		return get(p.Source.(dto.PostRes)), nil
+	}
+}
+
+func tagField(get func(m dto.TagRes) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		return get(p.Source.(dto.TagRes)), nil
+	}
+}
+
+func assetField(get func(m dto.AssetRes) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		return get(p.Source.(dto.AssetRes)), nil
+	}
+}
+
+func authorField(get func(m dto.PostAuthorRes) interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		return get(p.Source.(dto.PostAuthorRes)), nil
+	}
+}
+
+// catalogueTag resolves get of the tag tagID from the tag tree, or missing
+// for a tag removed since.
+func catalogueTag(p graphql.ResolveParams, tagID uint64, missing interface{}, get func(m dto.TagRes) interface{}) (interface{}, error) {
+	catalogue, err := loadersFrom(p.Context).tagCatalogue(p.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, ok := catalogue.byID[tagID]
+	if !ok {
+		return missing, nil
+	}
+	return get(*tag), nil
+}
+
+// emptyIfNil keeps the lists of the schema non null.
+func emptyIfNil[T any](v []T) []T {
+	if v == nil {
+		return []T{}
+	}
+	return v
+}
+
+func nonZero(v int) interface{} {
+	if v == 0 {
+		return nil
+	}
+	return v
+}
+
+func stringArg(args map[string]interface{}, name string) string {
+	v, _ := args[name].(string)
+	return v
+}
+
+func idArg(args map[string]interface{}, name string) (uint64, error) {
+	v, _ := args[name].(string)
+	id, err := strconv.ParseUint(v, 10, 64)
+	if err != nil || id == 0 {
+		return 0, helpers.ErrInvalid(name, name)
+	}
+	return id, nil
+}
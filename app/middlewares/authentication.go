@@ -0,0 +1,106 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/auth"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
+)
+
+// Authentication reads the actor from the bearer JWT signed with
+// JWT_SECRET. Requests without Authorization header go on as anonymous,
+// use AuthorizationMustBe on routes that need a signed in user.
+func Authentication(cfg *configs.Configs) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+
+		tokenString, err := helpers.ExtractToken(c)
+		if err != nil {
+			helpers.RenderJSON(c.Writer, http.StatusUnauthorized, err)
+			c.Abort()
+			return
+		}
+
+		actor, err := ParseToken(tokenString, cfg.App.SecretKey)
+		if err != nil {
+			helpers.RenderJSON(c.Writer, http.StatusUnauthorized, err)
+			c.Abort()
+			return
+		}
+
+		c.Request = c.Request.WithContext(auth.WithActor(c.Request.Context(), actor))
+		c.Next()
+	}
+}
+
+// AuthorizationMustBe allows only signed in actors having one of roles,
+// no roles means any signed in actor.
+func AuthorizationMustBe(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := Authorize(auth.FromContext(c.Request.Context()), roles...)
+		if err != nil {
+			helpers.RenderJSON(c.Writer, http.StatusUnauthorized, err)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Authorize returns the error of actor not being signed in, or not having
+// one of roles when any are given. It is shared by the HTTP and gRPC
+// servers so both let in the same actors.
+func Authorize(actor auth.Actor, roles ...string) error {
+	if actor.IsAnonymous() {
+		return errUnauthorized()
+	}
+
+	if len(roles) > 0 && !slices.Contains(roles, actor.Role) {
+		return helpers.ErrCannotHaveAccessResources()
+	}
+
+	return nil
+}
+
+// ParseToken reads the actor from tokenString, a JWT signed with secretKey.
+// Without a secretKey every token is invalid.
+func ParseToken(tokenString, secretKey string) (auth.Actor, error) {
+	if secretKey == "" {
+		return auth.Actor{}, errUnauthorized()
+	}
+
+	claims := &helpers.JWTClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(secretKey), nil
+	})
+	if err != nil || !token.Valid || claims.ID == 0 {
+		return auth.Actor{}, errUnauthorized()
+	}
+
+	return auth.Actor{
+		ID:       claims.ID,
+		Username: claims.Username,
+		Role:     helpers.ToLower(claims.Role),
+	}, nil
+}
+
+func errUnauthorized() error {
+	return helpers.NewError(helpers.ErrUnauthorized, helpers.NewResponseMultiLang(
+		helpers.MultiLanguages{
+			ID: "Token tidak valid",
+			EN: "Invalid token",
+		},
+	))
+}
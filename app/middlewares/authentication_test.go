@@ -0,0 +1,76 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/auth"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
+)
+
+func TestAuthentication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	claims := &helpers.JWTClaims{ID: 1, Role: "Editor"}
+	sign := func(method jwt.SigningMethod, key interface{}) string {
+		token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		if err != nil {
+			t.Fatalf("SignedString() error = %v", err)
+		}
+		return token
+	}
+
+	tests := []struct {
+		name      string
+		token     string
+		secretKey string
+		wantCode  int
+	}{
+		{
+			name:      "valid",
+			token:     sign(jwt.SigningMethodHS256, []byte("secret")),
+			secretKey: "secret",
+			wantCode:  http.StatusOK,
+		},
+		{
+			name:      "other secret",
+			token:     sign(jwt.SigningMethodHS256, []byte("other")),
+			secretKey: "secret",
+			wantCode:  http.StatusUnauthorized,
+		},
+		{
+			name:      "no secret",
+			token:     sign(jwt.SigningMethodHS256, []byte("")),
+			secretKey: "",
+			wantCode:  http.StatusUnauthorized,
+		},
+		{
+			name:      "alg none",
+			token:     sign(jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType),
+			secretKey: "secret",
+			wantCode:  http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &configs.Configs{App: configs.AppConfig{SecretKey: tt.secretKey}}
+			r := gin.New()
+			r.Use(Authentication(cfg))
+			r.GET("/", AuthorizationMustBe(auth.RoleEditor), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("Authentication() code = %v, want %v", w.Code, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Asset is an uploaded file. Its content is the Blob of Checksum, kept in
+// the storage under StorageKey which assets with the same content share.
+type Asset struct {
+	ID         uint64 `json:"id" gorm:"primaryKey"`
+	Filename   string `json:"filename" gorm:"not null;size:255"`
+	MimeType   string `json:"mime_type" gorm:"not null;size:100"`
+	Size       int64  `json:"size" gorm:"not null"`
+	Checksum   string `json:"checksum" gorm:"not null;size:64;index"` // sha256, hex encoded
+	StorageKey string `json:"storage_key" gorm:"not null;size:255;index"`
+	UploaderID uint64 `json:"uploader_id"`
+	// Width, Height, PageCount and EXIF are read from the content in the
+	// background, MetaStatus tells whether that is done.
+	Width         int        `json:"width" gorm:"not null;default:0;index"`
+	Height        int        `json:"height" gorm:"not null;default:0;index"`
+	PageCount     int        `json:"page_count" gorm:"not null;default:0"`
+	EXIF          StringMap  `json:"exif" gorm:"column:exif;not null;default:'{}'"`
+	MetaStatus    string     `json:"meta_status" gorm:"not null;default:pending;size:20;index"`
+	MetaClaimedAt *time.Time `json:"meta_claimed_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+
+	Thumbnails []AssetThumbnail `json:"thumbnails,omitempty" gorm:"foreignKey:AssetID;constraint:OnDelete:CASCADE"`
+	// a blob can not be removed while an asset uses it
+	Blob *Blob `json:"blob,omitempty" gorm:"foreignKey:Checksum;references:Checksum;constraint:OnDelete:RESTRICT"`
+}
+
+func (Asset) TableName() string {
+	return "asset"
+}
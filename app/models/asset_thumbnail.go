@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// AssetThumbnail is a scaled down copy of an image asset, Size is the
+// longest side it was made to fit.
+type AssetThumbnail struct {
+	ID         uint64    `json:"id" gorm:"primaryKey"`
+	AssetID    uint64    `json:"asset_id" gorm:"not null;uniqueIndex:idx_asset_thumbnail_asset_id_size"`
+	Size       int       `json:"size" gorm:"not null;uniqueIndex:idx_asset_thumbnail_asset_id_size"`
+	Width      int       `json:"width" gorm:"not null"`
+	Height     int       `json:"height" gorm:"not null"`
+	MimeType   string    `json:"mime_type" gorm:"not null;size:100"`
+	ByteSize   int64     `json:"byte_size" gorm:"not null"`
+	StorageKey string    `json:"storage_key" gorm:"not null;size:255"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (AssetThumbnail) TableName() string {
+	return "asset_thumbnail"
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Blob is a stored file content, shared by every asset with the same
+// checksum. It is removed by the garbage collector once no asset uses it.
+type Blob struct {
+	Checksum   string    `json:"checksum" gorm:"primaryKey;size:64"` // sha256, hex encoded
+	StorageKey string    `json:"storage_key" gorm:"not null;size:255;uniqueIndex"`
+	Size       int64     `json:"size" gorm:"not null"`
+	MimeType   string    `json:"mime_type" gorm:"not null;size:100"`
+	CreatedAt  time.Time `json:"created_at" gorm:"index"`
+}
+
+func (Blob) TableName() string {
+	return "blob"
+}
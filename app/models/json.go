@@ -0,0 +1,44 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// JSON is a raw JSON document stored as a jsonb column.
+type JSON json.RawMessage
+
+func (m JSON) Value() (driver.Value, error) {
+	if len(m) == 0 {
+		return "null", nil
+	}
+	return string(m), nil
+}
+
+func (m *JSON) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*m = nil
+	case []byte:
+		*m = append(JSON{}, v...)
+	case string:
+		*m = JSON(v)
+	default:
+		return fmt.Errorf("unsupported type %T for JSON", value)
+	}
+	return nil
+}
+
+func (m JSON) MarshalJSON() ([]byte, error) {
+	return json.RawMessage(m).MarshalJSON()
+}
+
+func (m *JSON) UnmarshalJSON(data []byte) error {
+	*m = append(JSON{}, data...)
+	return nil
+}
+
+func (JSON) GormDataType() string {
+	return "jsonb"
+}
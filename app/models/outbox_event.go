@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// OutboxEvent is a change of an aggregate, e.g. a post, written in the
+// transaction of the change and published later by the relay. Events of one
+// aggregate are published in ID order, a failed one holds back the next ones
+// until NextAttemptAt. PublishedSeq numbers the events in the order they
+// were published, which is not their ID order across aggregates.
+type OutboxEvent struct {
+	ID            uint64     `json:"id" gorm:"primaryKey;index:idx_outbox_aggregate,priority:3"`
+	AggregateType string     `json:"aggregate_type" gorm:"not null;size:50;index:idx_outbox_aggregate,priority:1"`
+	AggregateID   uint64     `json:"aggregate_id" gorm:"not null;index:idx_outbox_aggregate,priority:2"`
+	EventType     string     `json:"event_type" gorm:"not null;size:50"`
+	Payload       JSON       `json:"payload" gorm:"not null"`
+	Attempts      int        `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" gorm:"not null;default:CURRENT_TIMESTAMP"`
+	LastError     string     `json:"last_error" gorm:"not null;default:''"`
+	PublishedAt   *time.Time `json:"published_at" gorm:"index"`
+	PublishedSeq  *uint64    `json:"published_seq" gorm:"uniqueIndex"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func (OutboxEvent) TableName() string {
+	return "outbox"
+}
@@ -1,9 +1,24 @@
 package models
 
+import "time"
+
 type Post struct {
 	ID      uint64 `json:"id" gorm:"primaryKey"`
 	Title   string `json:"title" gorm:"not null"`
 	Content string `json:"content" gorm:"not null;type:text"`
+	// ContentFormat is how Content is written, ContentHTML and ContentText
+	// are rendered from it on every write.
+	ContentFormat string `json:"content_format" gorm:"not null;default:plain;size:20"`
+	ContentHTML   string `json:"content_html" gorm:"not null;default:'';type:text"`
+	ContentText   string `json:"content_text" gorm:"not null;default:'';type:text"`
+	// Slug is the current slug, see PostSlug for the older ones.
+	Slug string `json:"slug" gorm:"not null;default:'';size:100;index"`
+	// Status defaults to published in the database so posts created before
+	// the status workflow stay live, new posts always set it.
+	Status    string     `json:"status" gorm:"not null;default:published;index"`
+	PublishAt *time.Time `json:"publish_at" gorm:"index"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
 }
 
 func (Post) TableName() string {
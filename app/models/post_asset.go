@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// PostAsset links an asset to a post, an asset can be attached to many
+// posts.
+type PostAsset struct {
+	ID        uint64    `json:"id" gorm:"primaryKey"`
+	PostID    uint64    `json:"post_id" gorm:"not null;uniqueIndex:idx_post_asset_post_id_asset_id"`
+	AssetID   uint64    `json:"asset_id" gorm:"not null;uniqueIndex:idx_post_asset_post_id_asset_id;index"`
+	CreatedAt time.Time `json:"created_at"`
+	Post      *Post     `json:"post,omitempty" gorm:"foreignKey:PostID;constraint:OnDelete:CASCADE"`
+	Asset     *Asset    `json:"asset,omitempty" gorm:"foreignKey:AssetID;constraint:OnDelete:CASCADE"`
+}
+
+func (PostAsset) TableName() string {
+	return "post_asset"
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// PostRevision is a post as it was before the update made by the author at
+// CreatedAt. Revision numbers start at 1 for every post.
+type PostRevision struct {
+	ID            uint64     `json:"id" gorm:"primaryKey"`
+	PostID        uint64     `json:"post_id" gorm:"not null;uniqueIndex:idx_post_revision_post_id_revision"`
+	Revision      uint64     `json:"revision" gorm:"not null;uniqueIndex:idx_post_revision_post_id_revision"`
+	Title         string     `json:"title" gorm:"not null"`
+	Content       string     `json:"content" gorm:"not null;type:text"`
+	ContentFormat string     `json:"content_format" gorm:"not null;default:plain;size:20"`
+	Tags          StringList `json:"tags" gorm:"not null"`
+	AuthorID      uint64     `json:"author_id"`
+	Author        string     `json:"author"`
+	CreatedAt     time.Time  `json:"created_at"`
+	Post          *Post      `json:"post,omitempty" gorm:"foreignKey:PostID;constraint:OnDelete:CASCADE"`
+}
+
+func (PostRevision) TableName() string {
+	return "post_revision"
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// PostSlug is a slug a post has had. The current one is also kept on
+// Post.Slug, older ones redirect to it. A slug is never given to another
+// post while its post exists, so old links can not point to a new post.
+type PostSlug struct {
+	Slug      string    `json:"slug" gorm:"primaryKey;size:100"`
+	PostID    uint64    `json:"post_id" gorm:"not null;index"`
+	CreatedAt time.Time `json:"created_at"`
+	Post      *Post     `json:"post,omitempty" gorm:"foreignKey:PostID;constraint:OnDelete:CASCADE"`
+}
+
+func (PostSlug) TableName() string {
+	return "post_slug"
+}
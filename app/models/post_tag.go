@@ -1,11 +1,16 @@
 package models
 
+import "time"
+
 type PostTag struct {
 	ID     uint64 `json:"id" gorm:"primaryKey"`
-	PostID uint64 `json:"post_id" gorm:"not null"`
-	TagID  uint64 `json:"tag_id" gorm:"not null"`
+	PostID uint64 `json:"post_id" gorm:"not null;uniqueIndex:idx_post_tag_post_id_tag_id"`
+	TagID  uint64 `json:"tag_id" gorm:"not null;uniqueIndex:idx_post_tag_post_id_tag_id;index"`
 	Tag    *Tag   `json:"tag" gorm:"foreignKey:TagID"`
 	Post   *Post  `json:"post" gorm:"foreignKey:PostID"`
+	// CreatedAt is when the post got the tag, rows older than the column
+	// have the time their post was created.
+	CreatedAt time.Time `json:"created_at" gorm:"not null;default:CURRENT_TIMESTAMP;index"`
 }
 
 func (PostTag) TableName() string {
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// StringList is a list of strings stored as a jsonb column.
+type StringList []string
+
+func (m StringList) Value() (driver.Value, error) {
+	if m == nil {
+		return "[]", nil
+	}
+
+	data, err := json.Marshal(m)
+	return string(data), err
+}
+
+func (m *StringList) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*m = StringList{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type %T for StringList", value)
+	}
+
+	return json.Unmarshal(data, m)
+}
+
+func (StringList) GormDataType() string {
+	return "jsonb"
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// StringMap is a string to string object stored as a jsonb column.
+type StringMap map[string]string
+
+func (m StringMap) Value() (driver.Value, error) {
+	if m == nil {
+		return "{}", nil
+	}
+
+	data, err := json.Marshal(m)
+	return string(data), err
+}
+
+func (m *StringMap) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*m = StringMap{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported type %T for StringMap", value)
+	}
+
+	return json.Unmarshal(data, m)
+}
+
+func (StringMap) GormDataType() string {
+	return "jsonb"
+}
@@ -3,6 +3,11 @@ package models
 type Tag struct {
 	ID    uint64 `json:"id" gorm:"primaryKey"`
 	Label string `json:"label" gorm:"not null;unique"`
+	// ParentID places the tag under a broader one, filtering by the parent
+	// can include its descendants.
+	ParentID *uint64 `json:"parent_id" gorm:"index"`
+
+	Children []Tag `json:"children,omitempty" gorm:"foreignKey:ParentID;constraint:OnDelete:SET NULL"`
 }
 
 func (Tag) TableName() string {
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// TagSynonym is another label of a tag, e.g. "go" for "golang". Posts tagged
+// with it get the tag itself.
+type TagSynonym struct {
+	ID        uint64    `json:"id" gorm:"primaryKey"`
+	Label     string    `json:"label" gorm:"not null;unique"`
+	TagID     uint64    `json:"tag_id" gorm:"not null;index"`
+	Tag       *Tag      `json:"tag,omitempty" gorm:"foreignKey:TagID;constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (TagSynonym) TableName() string {
+	return "tag_synonym"
+}
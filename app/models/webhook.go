@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Webhook posts the events selected by Events, e.g. "post.created",
+// "post.*" or "*", to URL, signed with Secret.
+type Webhook struct {
+	ID        uint64     `json:"id" gorm:"primaryKey"`
+	URL       string     `json:"url" gorm:"not null;size:2048"`
+	Events    StringList `json:"events" gorm:"not null"`
+	Secret    string     `json:"-" gorm:"not null;size:255"`
+	IsActive  bool       `json:"is_active" gorm:"not null"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func (Webhook) TableName() string {
+	return "webhook"
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// WebhookDelivery is an event to post to a webhook, once per event. Payload
+// is the body sent on every attempt. It is pending until the first attempt,
+// retrying after a failed one until NextAttemptAt, then succeeded or, once
+// out of attempts, dead.
+type WebhookDelivery struct {
+	ID             uint64     `json:"id" gorm:"primaryKey"`
+	WebhookID      uint64     `json:"webhook_id" gorm:"not null;uniqueIndex:idx_webhook_delivery_webhook_id_event_id"`
+	EventID        uint64     `json:"event_id" gorm:"not null;uniqueIndex:idx_webhook_delivery_webhook_id_event_id"`
+	EventType      string     `json:"event_type" gorm:"not null;size:50"`
+	Payload        JSON       `json:"payload" gorm:"not null"`
+	Status         string     `json:"status" gorm:"not null;size:20;index:idx_webhook_delivery_due,priority:1"`
+	Attempts       int        `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at" gorm:"not null;index:idx_webhook_delivery_due,priority:2"`
+	LastStatusCode int        `json:"last_status_code" gorm:"not null;default:0"`
+	LastError      string     `json:"last_error" gorm:"not null;default:''"`
+	LastResponse   string     `json:"last_response" gorm:"not null;default:''"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	Webhook        *Webhook   `json:"-" gorm:"foreignKey:WebhookID;constraint:OnDelete:CASCADE"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_delivery"
+}
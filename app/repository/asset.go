@@ -0,0 +1,466 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/database"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AssetRepository interface {
+	Create(ctx context.Context, req *models.Asset) error
+	GetDetail(ctx context.Context, assetID uint64) (*models.Asset, error)
+	GetList(ctx context.Context, req dto.AssetListReq) ([]models.Asset, error)
+	GetByPost(ctx context.Context, postID uint64) ([]models.Asset, error)
+	GetByPosts(ctx context.Context, postIDs []uint64) (map[uint64][]models.Asset, error)
+	IsPublic(ctx context.Context, assetID uint64) (bool, error)
+	Delete(ctx context.Context, assetID uint64) (*models.Asset, error)
+	Attach(ctx context.Context, postID uint64, assetIDs []uint64) error
+	Detach(ctx context.Context, postID uint64, assetID uint64) error
+	ClaimMeta(ctx context.Context, assetID uint64, staleBefore time.Time) (bool, error)
+	SaveMeta(ctx context.Context, req *models.Asset) error
+	GetPendingMetaIDs(ctx context.Context, staleBefore time.Time, limit int) ([]uint64, error)
+	GetOrphanIDs(ctx context.Context, before time.Time, limit int) ([]uint64, error)
+	DeleteOrphan(ctx context.Context, assetID uint64, before time.Time) (*models.Asset, error)
+}
+
+type AssetRepo struct {
+	DB     *gorm.DB
+	Cfg    *configs.Configs
+	Logger *logrus.Logger
+}
+
+func NewAssetRepository(
+	db *gorm.DB,
+	cfg *configs.Configs,
+	logger *logrus.Logger,
+) AssetRepository {
+	return &AssetRepo{
+		DB:     db,
+		Cfg:    cfg,
+		Logger: logger,
+	}
+}
+
+func errAssetNotFound() *helpers.ResponseError {
+	return helpers.ErrDataNotFound("aset", "asset")
+}
+
+// Create stores the asset, its blob must exist. It returns ErrBlobGone when
+// the blob was removed in the meantime.
+func (r *AssetRepo) Create(ctx context.Context, req *models.Asset) error {
+	opName := "AssetRepository-Create"
+
+	err := WithTx(ctx, r.DB, func(ctx context.Context, trx *gorm.DB) error {
+		return trx.Omit("Blob").Create(req).Error
+	})
+	if isPgCode(err, pgForeignKeyViolation) {
+		return ErrBlobGone
+	}
+	if err != nil {
+		r.Logger.Errorf("%s failed create data: %v \n", opName, err)
+		return helpers.ErrCreatedDB()
+	}
+
+	return nil
+}
+
+func (r *AssetRepo) GetDetail(ctx context.Context, assetID uint64) (*models.Asset, error) {
+	var (
+		opName = "AssetRepository-GetDetail"
+		result = models.Asset{}
+	)
+
+	err := conn(ctx, r.DB).
+		Preload("Thumbnails", func(db *gorm.DB) *gorm.DB { return db.Order("size") }).
+		Where("id = ?", assetID).
+		First(&result).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errAssetNotFound()
+		}
+
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	return &result, nil
+}
+
+// GetList returns the assets matching every filter of req, newest first.
+func (r *AssetRepo) GetList(ctx context.Context, req dto.AssetListReq) ([]models.Asset, error) {
+	var (
+		opName = "AssetRepository-GetList"
+		query  = conn(ctx, r.DB)
+		result = []models.Asset{}
+	)
+
+	for _, v := range req.Parsed {
+		if v.IsPrefix {
+			like := "LIKE"
+			if v.Op == "!=" {
+				like = "NOT LIKE"
+			}
+			query = query.Where(fmt.Sprintf("%s %s ?", v.Column, like), fmt.Sprint(v.Value)+"%")
+			continue
+		}
+		query = query.Where(fmt.Sprintf("%s %s ?", v.Column, v.Op), v.Value)
+	}
+
+	err := query.
+		Preload("Thumbnails", func(db *gorm.DB) *gorm.DB { return db.Order("size") }).
+		Order("id DESC").
+		Limit(req.Limit).
+		Offset(req.Offset).
+		Find(&result).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	return result, nil
+}
+
+// GetByPost returns the assets attached to the post, oldest attachment
+// first.
+func (r *AssetRepo) GetByPost(ctx context.Context, postID uint64) ([]models.Asset, error) {
+	var (
+		opName = "AssetRepository-GetByPost"
+		result = []models.Asset{}
+	)
+
+	err := conn(ctx, r.DB).
+		Joins("JOIN post_asset pa ON pa.asset_id = asset.id").
+		Where("pa.post_id = ?", postID).
+		Order("pa.id").
+		Find(&result).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	return result, nil
+}
+
+// GetByPosts returns the assets attached to every post of postIDs, oldest
+// attachment first, with two queries whatever the number of posts. Posts
+// without assets are left out.
+func (r *AssetRepo) GetByPosts(ctx context.Context, postIDs []uint64) (map[uint64][]models.Asset, error) {
+	var (
+		opName = "AssetRepository-GetByPosts"
+		links  = []models.PostAsset{}
+		assets = []models.Asset{}
+		result = map[uint64][]models.Asset{}
+	)
+	if len(postIDs) == 0 {
+		return result, nil
+	}
+
+	err := conn(ctx, r.DB).
+		Where("post_id IN ?", postIDs).
+		Order("id").
+		Find(&links).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data links: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+	if len(links) == 0 {
+		return result, nil
+	}
+
+	assetIDs := make([]uint64, 0, len(links))
+	for _, v := range links {
+		assetIDs = append(assetIDs, v.AssetID)
+	}
+	err = conn(ctx, r.DB).Where("id IN ?", assetIDs).Find(&assets).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	byID := make(map[uint64]models.Asset, len(assets))
+	for _, v := range assets {
+		byID[v.ID] = v
+	}
+	for _, v := range links {
+		if asset, ok := byID[v.AssetID]; ok {
+			result[v.PostID] = append(result[v.PostID], asset)
+		}
+	}
+	return result, nil
+}
+
+// IsPublic reports whether the asset is attached to a post visible to the
+// public, published or scheduled and due.
+func (r *AssetRepo) IsPublic(ctx context.Context, assetID uint64) (bool, error) {
+	var (
+		opName = "AssetRepository-IsPublic"
+		result bool
+	)
+
+	err := conn(ctx, r.DB).Raw(`SELECT EXISTS (
+			SELECT 1 FROM post_asset pa
+			INNER JOIN post ON post.id = pa.post_id
+			WHERE pa.asset_id = @id
+				AND (post.status = @published OR (post.status = @scheduled AND post.publish_at <= NOW())))`,
+		map[string]interface{}{
+			"id":        assetID,
+			"published": dto.PostStatusPublished,
+			"scheduled": dto.PostStatusScheduled,
+		}).
+		Scan(&result).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return false, helpers.ErrDB()
+	}
+
+	return result, nil
+}
+
+// Delete removes the asset with its links to posts and thumbnails and
+// returns them, so the caller can remove their objects from the storage.
+func (r *AssetRepo) Delete(ctx context.Context, assetID uint64) (*models.Asset, error) {
+	var (
+		opName     = "AssetRepository-Delete"
+		deleted    = []models.Asset{}
+		thumbnails = []models.AssetThumbnail{}
+	)
+
+	err := WithTx(ctx, r.DB, func(ctx context.Context, trx *gorm.DB) error {
+		err := trx.Where("asset_id = ?", assetID).Delete(&models.PostAsset{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = trx.Clauses(clause.Returning{}).Where("asset_id = ?", assetID).Delete(&thumbnails).Error
+		if err != nil {
+			return err
+		}
+
+		err = trx.Clauses(clause.Returning{}).Where("id = ?", assetID).Delete(&deleted).Error
+		if err != nil {
+			return err
+		}
+		if len(deleted) == 0 {
+			return errAssetNotFound()
+		}
+		return nil
+	})
+	if err != nil {
+		r.Logger.Errorf("%s failed delete data: %v \n", opName, err)
+		return nil, toRespErr(err, helpers.ErrDB())
+	}
+
+	deleted[0].Thumbnails = thumbnails
+	return &deleted[0], nil
+}
+
+// Attach links the assets to the post, assets already attached are kept as
+// they are. The post and every asset must exist.
+func (r *AssetRepo) Attach(ctx context.Context, postID uint64, assetIDs []uint64) error {
+	opName := "AssetRepository-Attach"
+
+	err := WithTx(ctx, r.DB, func(ctx context.Context, trx *gorm.DB) error {
+		var total int64
+		err := trx.Model(&models.Post{}).Where("id = ?", postID).Count(&total).Error
+		if err != nil {
+			return err
+		}
+		if total == 0 {
+			return helpers.ErrNotFound()
+		}
+
+		err = trx.Model(&models.Asset{}).Where("id IN ?", assetIDs).Count(&total).Error
+		if err != nil {
+			return err
+		}
+		if int(total) != len(assetIDs) {
+			return errAssetNotFound()
+		}
+
+		postAssets := make([]models.PostAsset, 0, len(assetIDs))
+		for _, id := range assetIDs {
+			postAssets = append(postAssets, models.PostAsset{PostID: postID, AssetID: id})
+		}
+		return trx.Clauses(clause.OnConflict{DoNothing: true}).Create(&postAssets).Error
+	})
+	if err != nil {
+		r.Logger.Errorf("%s failed create data: %v \n", opName, err)
+		return toRespErr(err, helpers.ErrCreatedDB())
+	}
+
+	return nil
+}
+
+func (r *AssetRepo) Detach(ctx context.Context, postID uint64, assetID uint64) error {
+	opName := "AssetRepository-Detach"
+
+	err := WithTx(ctx, r.DB, func(ctx context.Context, trx *gorm.DB) error {
+		res := trx.Where("post_id = ? AND asset_id = ?", postID, assetID).Delete(&models.PostAsset{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errAssetNotFound()
+		}
+		return nil
+	})
+	if err != nil {
+		r.Logger.Errorf("%s failed delete data: %v \n", opName, err)
+		return toRespErr(err, helpers.ErrDB())
+	}
+
+	return nil
+}
+
+// ClaimMeta marks the asset as being processed, unless it is done or another
+// worker claimed it after staleBefore. It reports whether the caller got it.
+func (r *AssetRepo) ClaimMeta(ctx context.Context, assetID uint64, staleBefore time.Time) (bool, error) {
+	opName := "AssetRepository-ClaimMeta"
+
+	res := r.DB.WithContext(ctx).Model(&models.Asset{}).
+		Where("id = ?", assetID).
+		Where("meta_status = ? OR (meta_status = ? AND meta_claimed_at < ?)", dto.AssetMetaPending, dto.AssetMetaProcessing, staleBefore).
+		Updates(map[string]interface{}{"meta_status": dto.AssetMetaProcessing, "meta_claimed_at": time.Now()})
+	if res.Error != nil {
+		r.Logger.Errorf("%s failed update data: %v \n", opName, res.Error)
+		return false, helpers.ErrUpdatedDB()
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+// SaveMeta stores the metadata and status of req and replaces its
+// thumbnails with req.Thumbnails.
+func (r *AssetRepo) SaveMeta(ctx context.Context, req *models.Asset) error {
+	opName := "AssetRepository-SaveMeta"
+
+	err := WithTx(ctx, r.DB, func(ctx context.Context, trx *gorm.DB) error {
+		err := trx.Model(&models.Asset{}).Where("id = ?", req.ID).Updates(map[string]interface{}{
+			"width":       req.Width,
+			"height":      req.Height,
+			"page_count":  req.PageCount,
+			"exif":        req.EXIF,
+			"meta_status": req.MetaStatus,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		err = trx.Where("asset_id = ?", req.ID).Delete(&models.AssetThumbnail{}).Error
+		if err != nil || len(req.Thumbnails) == 0 {
+			return err
+		}
+
+		for i := range req.Thumbnails {
+			req.Thumbnails[i].AssetID = req.ID
+		}
+		return trx.Create(&req.Thumbnails).Error
+	})
+	if err != nil {
+		r.Logger.Errorf("%s failed update data: %v \n", opName, err)
+		return helpers.ErrUpdatedDB()
+	}
+
+	return nil
+}
+
+// GetPendingMetaIDs returns up to limit assets not processed yet, oldest
+// first, including those whose claim is older than staleBefore.
+func (r *AssetRepo) GetPendingMetaIDs(ctx context.Context, staleBefore time.Time, limit int) ([]uint64, error) {
+	var (
+		opName = "AssetRepository-GetPendingMetaIDs"
+		result = []uint64{}
+	)
+
+	// the primary, a replica may not have the latest uploads yet
+	err := conn(database.WithReadPrimary(ctx), r.DB).Model(&models.Asset{}).
+		Where("meta_status = ? OR (meta_status = ? AND meta_claimed_at < ?)", dto.AssetMetaPending, dto.AssetMetaProcessing, staleBefore).
+		Order("id").
+		Limit(limit).
+		Pluck("id", &result).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	return result, nil
+}
+
+// GetOrphanIDs returns up to limit assets created before before and attached
+// to no post, oldest first.
+func (r *AssetRepo) GetOrphanIDs(ctx context.Context, before time.Time, limit int) ([]uint64, error) {
+	var (
+		opName = "AssetRepository-GetOrphanIDs"
+		result = []uint64{}
+	)
+
+	err := conn(database.WithReadPrimary(ctx), r.DB).Model(&models.Asset{}).
+		Where("created_at < ?", before).
+		Where("NOT EXISTS (SELECT 1 FROM post_asset pa WHERE pa.asset_id = asset.id)").
+		Order("id").
+		Limit(limit).
+		Pluck("id", &result).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	return result, nil
+}
+
+// DeleteOrphan removes the asset with its thumbnails and returns them, only
+// if it is still created before before and attached to no post. It returns
+// nil when the asset was attached or removed in the meantime.
+func (r *AssetRepo) DeleteOrphan(ctx context.Context, assetID uint64, before time.Time) (*models.Asset, error) {
+	var (
+		opName     = "AssetRepository-DeleteOrphan"
+		deleted    = []models.Asset{}
+		thumbnails = []models.AssetThumbnail{}
+	)
+
+	err := WithTx(ctx, r.DB, func(ctx context.Context, trx *gorm.DB) error {
+		// the lock makes a concurrent Attach wait for us or us for it, the
+		// check below then sees the post_asset rows it committed
+		var total int64
+		err := trx.Model(&models.Asset{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", assetID).
+			Count(&total).Error
+		if err != nil || total == 0 {
+			return err
+		}
+
+		err = trx.Model(&models.Asset{}).
+			Where("id = ? AND created_at < ?", assetID, before).
+			Where("NOT EXISTS (SELECT 1 FROM post_asset pa WHERE pa.asset_id = asset.id)").
+			Count(&total).Error
+		if err != nil || total == 0 {
+			return err
+		}
+
+		err = trx.Clauses(clause.Returning{}).Where("asset_id = ?", assetID).Delete(&thumbnails).Error
+		if err != nil {
+			return err
+		}
+		return trx.Clauses(clause.Returning{}).Where("id = ?", assetID).Delete(&deleted).Error
+	})
+	if err != nil {
+		r.Logger.Errorf("%s failed delete data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+	if len(deleted) == 0 {
+		return nil, nil
+	}
+
+	deleted[0].Thumbnails = thumbnails
+	return &deleted[0], nil
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/driver"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+)
+
+func TestAssetRepo_AttachDetach(t *testing.T) {
+	var (
+		db       = openTestDB(t, "DB_TEST_DSN")
+		cfg      = testConfigs()
+		logger   = driver.Logger(cfg)
+		repo     = NewAssetRepository(db, cfg, logger)
+		postRepo = NewPostRepository(db, cfg, logger)
+		ctx      = context.Background()
+	)
+
+	createTestBlobs(t, NewBlobRepository(db, cfg, logger), "a", "b")
+	post, err := postRepo.Create(ctx, dto.PostCreateReq{Title: "with assets", Content: "content"})
+	if err != nil {
+		t.Fatalf("Create() post error = %v", err)
+	}
+
+	assets := []*models.Asset{
+		{Filename: "a.png", MimeType: "image/png", Size: 1, Checksum: "a", StorageKey: "assets/a.png"},
+		{Filename: "b.pdf", MimeType: "application/pdf", Size: 2, Checksum: "b", StorageKey: "assets/b.pdf"},
+	}
+	for _, v := range assets {
+		if err := repo.Create(ctx, v); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	err = repo.Attach(ctx, post.ID, []uint64{assets[0].ID, assets[0].ID + 100})
+	if _, ok := err.(*helpers.ResponseError); !ok {
+		t.Fatalf("Attach() unknown asset error = %v, want ResponseError", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		err = repo.Attach(ctx, post.ID, []uint64{assets[0].ID, assets[1].ID})
+		if err != nil {
+			t.Fatalf("Attach() error = %v", err)
+		}
+	}
+
+	got, err := repo.GetByPost(ctx, post.ID)
+	if err != nil || len(got) != 2 || got[0].ID != assets[0].ID {
+		t.Fatalf("GetByPost() = %v, %v, want both assets once", got, err)
+	}
+
+	err = repo.Detach(ctx, post.ID, assets[0].ID)
+	if err != nil {
+		t.Fatalf("Detach() error = %v", err)
+	}
+	if err = repo.Detach(ctx, post.ID, assets[0].ID); err == nil {
+		t.Errorf("Detach() twice error = nil")
+	}
+
+	deleted, err := repo.Delete(ctx, assets[1].ID)
+	if err != nil || deleted.StorageKey != assets[1].StorageKey {
+		t.Fatalf("Delete() = %v, %v", deleted, err)
+	}
+	got, _ = repo.GetByPost(ctx, post.ID)
+	if len(got) != 0 {
+		t.Errorf("GetByPost() after delete = %v, want none", got)
+	}
+}
+
+func TestAssetRepo_MetaAndFilters(t *testing.T) {
+	var (
+		db     = openTestDB(t, "DB_TEST_DSN")
+		cfg    = testConfigs()
+		repo   = NewAssetRepository(db, cfg, driver.Logger(cfg))
+		ctx    = context.Background()
+		assets = []*models.Asset{
+			{Filename: "big.png", MimeType: "image/png", Size: 1, Checksum: "a", StorageKey: "assets/big.png"},
+			{Filename: "small.jpg", MimeType: "image/jpeg", Size: 1, Checksum: "b", StorageKey: "assets/small.jpg"},
+			{Filename: "doc.pdf", MimeType: "application/pdf", Size: 1, Checksum: "c", StorageKey: "assets/doc.pdf"},
+		}
+	)
+	createTestBlobs(t, NewBlobRepository(db, cfg, driver.Logger(cfg)), "a", "b", "c")
+	for _, v := range assets {
+		if err := repo.Create(ctx, v); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	isClaimed, err := repo.ClaimMeta(ctx, assets[0].ID, time.Now().Add(-time.Minute))
+	if err != nil || !isClaimed {
+		t.Fatalf("ClaimMeta() = %v, %v, want claimed", isClaimed, err)
+	}
+	if isClaimed, _ = repo.ClaimMeta(ctx, assets[0].ID, time.Now().Add(-time.Minute)); isClaimed {
+		t.Errorf("ClaimMeta() twice = true, want false")
+	}
+	pending, _ := repo.GetPendingMetaIDs(ctx, time.Now().Add(-time.Minute), 10)
+	if len(pending) != 2 {
+		t.Errorf("GetPendingMetaIDs() = %v, want the 2 unclaimed", pending)
+	}
+
+	metas := []models.Asset{
+		{ID: assets[0].ID, Width: 2048, Height: 1024, MetaStatus: dto.AssetMetaDone, Thumbnails: []models.AssetThumbnail{
+			{Size: 160, Width: 160, Height: 80, MimeType: "image/png", ByteSize: 10, StorageKey: "thumbnails/1/160.png"},
+		}},
+		{ID: assets[1].ID, Width: 640, Height: 480, MetaStatus: dto.AssetMetaDone, EXIF: models.StringMap{"Make": "Test"}},
+		{ID: assets[2].ID, PageCount: 3, MetaStatus: dto.AssetMetaDone},
+	}
+	for i := range metas {
+		if err := repo.SaveMeta(ctx, &metas[i]); err != nil {
+			t.Fatalf("SaveMeta() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		filters []string
+		want    []uint64
+	}{
+		{filters: []string{"width>=1024"}, want: []uint64{assets[0].ID}},
+		{filters: []string{"mime=image/*"}, want: []uint64{assets[1].ID, assets[0].ID}},
+		{filters: []string{"mime!=image/*", "pages>1"}, want: []uint64{assets[2].ID}},
+	}
+	for _, tt := range tests {
+		req := dto.AssetListReq{Filters: tt.filters}
+		if err := req.Validate(); err != nil {
+			t.Fatalf("Validate() error = %v", err)
+		}
+
+		got, err := repo.GetList(ctx, req)
+		ids := []uint64{}
+		for _, v := range got {
+			ids = append(ids, v.ID)
+		}
+		if err != nil || !reflect.DeepEqual(ids, tt.want) {
+			t.Errorf("GetList(%v) = %v, %v, want %v", tt.filters, ids, err, tt.want)
+		}
+	}
+
+	detail, err := repo.GetDetail(ctx, assets[0].ID)
+	if err != nil || len(detail.Thumbnails) != 1 {
+		t.Errorf("GetDetail() = %+v, %v, want 1 thumbnail", detail, err)
+	}
+
+	deleted, err := repo.Delete(ctx, assets[0].ID)
+	if err != nil || len(deleted.Thumbnails) != 1 {
+		t.Errorf("Delete() = %+v, %v, want the thumbnail returned", deleted, err)
+	}
+}
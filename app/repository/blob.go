@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/database"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const pgForeignKeyViolation = "23503"
+
+// ErrBlobGone is returned when an asset refers to a blob removed by the
+// garbage collector in the meantime, the upload can store the content again.
+var ErrBlobGone = errors.New("repository: blob gone")
+
+type BlobRepository interface {
+	Find(ctx context.Context, checksum string) (*models.Blob, error)
+	Create(ctx context.Context, req *models.Blob) (bool, error)
+	GetOrphans(ctx context.Context, before time.Time, limit int) ([]models.Blob, error)
+	Delete(ctx context.Context, blob models.Blob) (bool, error)
+	Report(ctx context.Context, assetBefore, blobBefore time.Time) (*dto.StorageReportRes, error)
+	IsKeyUsed(ctx context.Context, storageKey string) (bool, error)
+}
+
+type BlobRepo struct {
+	DB     *gorm.DB
+	Cfg    *configs.Configs
+	Logger *logrus.Logger
+}
+
+func NewBlobRepository(
+	db *gorm.DB,
+	cfg *configs.Configs,
+	logger *logrus.Logger,
+) BlobRepository {
+	return &BlobRepo{
+		DB:     db,
+		Cfg:    cfg,
+		Logger: logger,
+	}
+}
+
+// Find returns the blob of checksum, or nil when there is none. It reads the
+// primary, a blob stored a moment ago must be found so its content is not
+// stored twice.
+func (r *BlobRepo) Find(ctx context.Context, checksum string) (*models.Blob, error) {
+	var (
+		opName = "BlobRepository-Find"
+		result = models.Blob{}
+	)
+
+	err := conn(database.WithReadPrimary(ctx), r.DB).Where("checksum = ?", checksum).First(&result).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	return &result, nil
+}
+
+// Create stores req unless a blob with its checksum exists, it reports
+// whether req was stored.
+func (r *BlobRepo) Create(ctx context.Context, req *models.Blob) (bool, error) {
+	opName := "BlobRepository-Create"
+
+	var total int64
+	err := WithTx(ctx, r.DB, func(ctx context.Context, trx *gorm.DB) error {
+		res := trx.Clauses(clause.OnConflict{DoNothing: true}).Create(req)
+		total = res.RowsAffected
+		return res.Error
+	})
+	if err != nil {
+		r.Logger.Errorf("%s failed create data: %v \n", opName, err)
+		return false, helpers.ErrCreatedDB()
+	}
+
+	return total > 0, nil
+}
+
+// GetOrphans returns up to limit blobs created before before and used by no
+// asset, oldest first.
+func (r *BlobRepo) GetOrphans(ctx context.Context, before time.Time, limit int) ([]models.Blob, error) {
+	var (
+		opName = "BlobRepository-GetOrphans"
+		result = []models.Blob{}
+	)
+
+	err := conn(database.WithReadPrimary(ctx), r.DB).
+		Where("created_at < ?", before).
+		Where("NOT EXISTS (SELECT 1 FROM asset a WHERE a.checksum = blob.checksum)").
+		Order("created_at").
+		Limit(limit).
+		Find(&result).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	return result, nil
+}
+
+// Delete removes blob if no asset uses it. The foreign key of asset decides,
+// so an upload reusing the blob at the same time either keeps it or fails
+// with ErrBlobGone. It reports whether the blob was removed.
+func (r *BlobRepo) Delete(ctx context.Context, blob models.Blob) (bool, error) {
+	opName := "BlobRepository-Delete"
+
+	var total int64
+	err := WithTx(ctx, r.DB, func(ctx context.Context, trx *gorm.DB) error {
+		res := trx.Where("checksum = ? AND storage_key = ?", blob.Checksum, blob.StorageKey).Delete(&models.Blob{})
+		total = res.RowsAffected
+		return res.Error
+	})
+	if isPgCode(err, pgForeignKeyViolation) {
+		return false, nil
+	}
+	if err != nil {
+		r.Logger.Errorf("%s failed delete data: %v \n", opName, err)
+		return false, helpers.ErrDB()
+	}
+
+	return total > 0, nil
+}
+
+// Report counts assets and blobs, orphans are those the garbage collector
+// removes: assets created before assetBefore, the zero time for none, and
+// blobs created before blobBefore.
+func (r *BlobRepo) Report(ctx context.Context, assetBefore, blobBefore time.Time) (*dto.StorageReportRes, error) {
+	var (
+		opName = "BlobRepository-Report"
+		query  = conn(ctx, r.DB)
+		result = dto.StorageReportRes{Orphans: []dto.StorageOrphanRes{}}
+		params = map[string]interface{}{"asset_before": assetBefore, "blob_before": blobBefore}
+		// an asset attached to no post since before it expired
+		orphanAsset = "a.created_at < @asset_before AND NOT EXISTS (SELECT 1 FROM post_asset pa WHERE pa.asset_id = a.id)"
+		// a blob with no asset left once the orphan assets are removed
+		orphanBlob = "b.created_at < @blob_before AND NOT EXISTS (SELECT 1 FROM asset a WHERE a.checksum = b.checksum AND NOT (" + orphanAsset + "))"
+		usedBytes  int64
+	)
+
+	err := query.Raw(`SELECT
+			(SELECT COUNT(*) FROM asset) AS assets,
+			(SELECT COALESCE(SUM(size), 0) FROM asset) AS logical_bytes,
+			(SELECT COUNT(*) FROM blob) AS blobs,
+			(SELECT COALESCE(SUM(size), 0) FROM blob) AS stored_bytes,
+			(SELECT COUNT(*) FROM asset a WHERE `+orphanAsset+`) AS orphan_assets,
+			(SELECT COALESCE(SUM(size), 0) FROM asset a WHERE `+orphanAsset+`) AS orphan_asset_bytes,
+			(SELECT COUNT(*) FROM blob b WHERE `+orphanBlob+`) AS orphan_blobs,
+			(SELECT COALESCE(SUM(size), 0) FROM blob b WHERE `+orphanBlob+`) AS orphan_blob_bytes`,
+		params).
+		Scan(&result).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	err = query.Raw(`SELECT COALESCE(SUM(size), 0) FROM blob b
+			WHERE EXISTS (SELECT 1 FROM asset a WHERE a.checksum = b.checksum)`).
+		Scan(&usedBytes).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get used bytes: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+	result.SavedBytes = max(result.LogicalBytes-usedBytes, 0)
+	if usedBytes > 0 {
+		result.DedupRatio = float64(result.LogicalBytes) / float64(usedBytes)
+	}
+
+	err = query.Raw(`(SELECT 'asset' AS kind, a.id AS asset_id, a.checksum, a.size, a.created_at FROM asset a WHERE `+orphanAsset+`)
+			UNION ALL
+			(SELECT 'blob' AS kind, 0 AS asset_id, b.checksum, b.size, b.created_at FROM blob b WHERE `+orphanBlob+`)
+			ORDER BY created_at LIMIT @limit`,
+		map[string]interface{}{"asset_before": assetBefore, "blob_before": blobBefore, "limit": dto.StorageOrphanListLimit}).
+		Scan(&result.Orphans).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get orphans: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	return &result, nil
+}
+
+// IsKeyUsed reports whether a blob or an asset still refers to storageKey.
+// Assets uploaded before blobs existed may have a key of their own.
+func (r *BlobRepo) IsKeyUsed(ctx context.Context, storageKey string) (bool, error) {
+	var (
+		opName = "BlobRepository-IsKeyUsed"
+		result bool
+	)
+
+	err := conn(database.WithReadPrimary(ctx), r.DB).Raw(`SELECT
+			EXISTS (SELECT 1 FROM blob WHERE storage_key = @key) OR
+			EXISTS (SELECT 1 FROM asset WHERE storage_key = @key)`,
+		map[string]interface{}{"key": storageKey}).
+		Scan(&result).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return false, helpers.ErrDB()
+	}
+
+	return result, nil
+}
+
+// isPgCode reports whether err is a postgres error with code.
+func isPgCode(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
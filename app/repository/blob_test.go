@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/driver"
+)
+
+// createTestBlobs stores a blob of size 1 for every checksum.
+func createTestBlobs(t *testing.T, repo BlobRepository, checksums ...string) []models.Blob {
+	t.Helper()
+
+	result := []models.Blob{}
+	for _, v := range checksums {
+		blob := models.Blob{Checksum: v, StorageKey: "blobs/" + v, Size: 1, MimeType: "image/png"}
+		if _, err := repo.Create(context.Background(), &blob); err != nil {
+			t.Fatalf("Create() blob error = %v", err)
+		}
+		result = append(result, blob)
+	}
+	return result
+}
+
+func TestBlobRepo_Collect(t *testing.T) {
+	var (
+		db        = openTestDB(t, "DB_TEST_DSN")
+		cfg       = testConfigs()
+		logger    = driver.Logger(cfg)
+		repo      = NewBlobRepository(db, cfg, logger)
+		assetRepo = NewAssetRepository(db, cfg, logger)
+		postRepo  = NewPostRepository(db, cfg, logger)
+		ctx       = context.Background()
+		blobs     = createTestBlobs(t, repo, "shared", "orphan")
+	)
+
+	isCreated, err := repo.Create(ctx, &models.Blob{Checksum: "shared", StorageKey: "blobs/shared-2", Size: 1, MimeType: "image/png"})
+	if err != nil || isCreated {
+		t.Fatalf("Create() same checksum = %v, %v, want not created", isCreated, err)
+	}
+	found, err := repo.Find(ctx, "shared")
+	if err != nil || found == nil || found.StorageKey != "blobs/shared" {
+		t.Fatalf("Find() = %+v, %v, want the first blob", found, err)
+	}
+	if found, err = repo.Find(ctx, "missing"); err != nil || found != nil {
+		t.Errorf("Find() missing = %+v, %v, want nil", found, err)
+	}
+
+	post, err := postRepo.Create(ctx, dto.PostCreateReq{Title: "with assets", Content: "content"})
+	if err != nil {
+		t.Fatalf("Create() post error = %v", err)
+	}
+	assets := []*models.Asset{
+		{Filename: "logo.png", MimeType: "image/png", Size: 1, Checksum: "shared", StorageKey: "blobs/shared"},
+		{Filename: "logo-copy.png", MimeType: "image/png", Size: 1, Checksum: "shared", StorageKey: "blobs/shared"},
+	}
+	for _, v := range assets {
+		if err := assetRepo.Create(ctx, v); err != nil {
+			t.Fatalf("Create() asset error = %v", err)
+		}
+	}
+	if err = assetRepo.Attach(ctx, post.ID, []uint64{assets[0].ID}); err != nil {
+		t.Fatalf("Attach() error = %v", err)
+	}
+	err = assetRepo.Create(ctx, &models.Asset{Filename: "gone.png", MimeType: "image/png", Size: 1, Checksum: "gone", StorageKey: "blobs/gone"})
+	if !errors.Is(err, ErrBlobGone) {
+		t.Errorf("Create() asset without blob error = %v, want ErrBlobGone", err)
+	}
+
+	before := time.Now().Add(time.Minute)
+	report, err := repo.Report(ctx, before, before)
+	if err != nil || report.Assets != 2 || report.Blobs != 2 || report.SavedBytes != 1 || report.OrphanAssets != 1 || report.OrphanBlobs != 1 {
+		t.Fatalf("Report() = %+v, %v", report, err)
+	}
+	// without asset expiry the copy is kept, so is its blob
+	report, err = repo.Report(ctx, time.Time{}, before)
+	if err != nil || report.OrphanAssets != 0 || report.OrphanBlobs != 1 {
+		t.Fatalf("Report() without asset expiry = %+v, %v", report, err)
+	}
+
+	ids, err := assetRepo.GetOrphanIDs(ctx, before, 10)
+	if err != nil || len(ids) != 1 || ids[0] != assets[1].ID {
+		t.Fatalf("GetOrphanIDs() = %v, %v, want the copy", ids, err)
+	}
+	if deleted, err := assetRepo.DeleteOrphan(ctx, assets[0].ID, before); err != nil || deleted != nil {
+		t.Errorf("DeleteOrphan() attached = %+v, %v, want kept", deleted, err)
+	}
+	if deleted, err := assetRepo.DeleteOrphan(ctx, assets[1].ID, before); err != nil || deleted == nil {
+		t.Errorf("DeleteOrphan() = %+v, %v, want deleted", deleted, err)
+	}
+
+	orphans, err := repo.GetOrphans(ctx, before, 10)
+	if err != nil || len(orphans) != 1 || orphans[0].Checksum != "orphan" {
+		t.Fatalf("GetOrphans() = %v, %v, want the unused blob", orphans, err)
+	}
+	if isDeleted, err := repo.Delete(ctx, blobs[0]); err != nil || isDeleted {
+		t.Errorf("Delete() used blob = %v, %v, want kept", isDeleted, err)
+	}
+	if isDeleted, err := repo.Delete(ctx, blobs[1]); err != nil || !isDeleted {
+		t.Errorf("Delete() = %v, %v, want deleted", isDeleted, err)
+	}
+
+	isUsed, err := repo.IsKeyUsed(ctx, "blobs/shared")
+	if err != nil || !isUsed {
+		t.Errorf("IsKeyUsed() = %v, %v, want used", isUsed, err)
+	}
+	if isUsed, _ = repo.IsKeyUsed(ctx, "blobs/orphan"); isUsed {
+		t.Errorf("IsKeyUsed() removed blob = true")
+	}
+}
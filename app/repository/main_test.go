@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"os"
+	"testing"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// openTestDB connects to the postgres given by env key and migrates it.
+// Tests are skipped when the env is not set, e.g.
+//
+//	DB_TEST_DSN="host=127.0.0.1 user=postgres dbname=findr_test sslmode=disable" go test ./app/repository
+func openTestDB(t *testing.T, key string) *gorm.DB {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skip database test in short mode")
+	}
+
+	dsn := os.Getenv(key)
+	if dsn == "" {
+		t.Skipf("%s is not set", key)
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatalf("failed open %s: %v", key, err)
+	}
+
+	err = Migrate(db)
+	if err != nil {
+		t.Fatalf("failed migrate %s: %v", key, err)
+	}
+
+	t.Cleanup(func() {
+		db.Exec("TRUNCATE webhook_delivery, webhook, outbox, post_asset, asset_thumbnail, asset, blob, post_slug, post_revision, post_tag, tag_synonym, tag, post RESTART IDENTITY CASCADE")
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func testConfigs() *configs.Configs {
+	return configs.GetInstance()
+}
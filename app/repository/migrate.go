@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"gorm.io/gorm"
+)
+
+// Migrate creates or updates the tables of the models, with the indexes
+// and views gorm cannot describe. Data from before a change is moved first.
+func Migrate(db *gorm.DB) error {
+	err := migrateBlobs(db)
+	if err != nil {
+		return fmt.Errorf("migrate asset blobs: %w", err)
+	}
+	err = migratePostTagCreatedAt(db)
+	if err != nil {
+		return fmt.Errorf("migrate post tag created at: %w", err)
+	}
+
+	err = db.AutoMigrate(
+		&models.Post{},
+		&models.Tag{},
+		&models.PostTag{},
+		&models.TagSynonym{},
+		&models.PostRevision{},
+		&models.PostSlug{},
+		&models.Blob{},
+		&models.Asset{},
+		&models.AssetThumbnail{},
+		&models.PostAsset{},
+		&models.OutboxEvent{},
+		&models.Webhook{},
+		&models.WebhookDelivery{},
+	)
+	if err != nil {
+		return fmt.Errorf("migrate models: %w", err)
+	}
+
+	err = migrateTagSearch(db)
+	if err != nil {
+		return fmt.Errorf("migrate tag search: %w", err)
+	}
+	err = migrateReports(db)
+	if err != nil {
+		return fmt.Errorf("migrate reports: %w", err)
+	}
+	err = migrateOutbox(db)
+	if err != nil {
+		return fmt.Errorf("migrate outbox: %w", err)
+	}
+	return nil
+}
+
+// migrateBlobs prepares assets uploaded before blobs existed, so the foreign
+// key of asset to blob can be added: every checksum gets a blob and storage
+// keys stop being unique to one asset.
+func migrateBlobs(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&models.Asset{}) || db.Migrator().HasTable(&models.Blob{}) {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.AutoMigrate(&models.Blob{})
+		if err != nil {
+			return err
+		}
+
+		err = tx.Exec("DROP INDEX IF EXISTS idx_asset_storage_key").Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Exec(`INSERT INTO blob (checksum, storage_key, size, mime_type, created_at)
+			SELECT DISTINCT ON (checksum) checksum, storage_key, size, mime_type, created_at
+			FROM asset ORDER BY checksum, id
+			ON CONFLICT DO NOTHING`).Error
+	})
+}
+
+// migratePostTagCreatedAt adds created_at to post_tag, the rows of before it
+// get the time their post was created: the closest known to when the tag was
+// given, rather than all of them now.
+func migratePostTagCreatedAt(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&models.PostTag{}) || db.Migrator().HasColumn(&models.PostTag{}, "CreatedAt") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, sql := range []string{
+			"ALTER TABLE post_tag ADD COLUMN created_at timestamptz",
+			`UPDATE post_tag SET created_at = post.created_at FROM post WHERE post.id = post_tag.post_id`,
+			"UPDATE post_tag SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL",
+			"ALTER TABLE post_tag ALTER COLUMN created_at SET DEFAULT CURRENT_TIMESTAMP",
+			"ALTER TABLE post_tag ALTER COLUMN created_at SET NOT NULL",
+		} {
+			err := tx.Exec(sql).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// migrateTagSearch adds the indexes tag suggestions need: a pattern index for
+// prefixes and a pg_trgm index for labels close to the input. The index is
+// GiST, unlike GIN it also orders by distance (<->).
+func migrateTagSearch(db *gorm.DB) error {
+	for _, sql := range []string{
+		"CREATE EXTENSION IF NOT EXISTS pg_trgm",
+		"CREATE INDEX IF NOT EXISTS idx_tag_label_pattern ON tag (label text_pattern_ops)",
+		"DROP INDEX IF EXISTS idx_tag_label_trgm",
+		"CREATE INDEX IF NOT EXISTS idx_tag_label_trgm_gist ON tag USING gist (label gist_trgm_ops)",
+		"CREATE INDEX IF NOT EXISTS idx_tag_synonym_label_pattern ON tag_synonym (label text_pattern_ops)",
+	} {
+		err := db.Exec(sql).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
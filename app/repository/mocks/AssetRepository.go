@@ -0,0 +1,401 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	dto "github.com/adamnasrudin03/go-asset-findr/app/dto"
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/adamnasrudin03/go-asset-findr/app/models"
+
+	time "time"
+)
+
+// AssetRepository is an autogenerated mock type for the AssetRepository type
+type AssetRepository struct {
+	mock.Mock
+}
+
+// Attach provides a mock function with given fields: ctx, postID, assetIDs
+func (_m *AssetRepository) Attach(ctx context.Context, postID uint64, assetIDs []uint64) error {
+	ret := _m.Called(ctx, postID, assetIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Attach")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, []uint64) error); ok {
+		r0 = rf(ctx, postID, assetIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ClaimMeta provides a mock function with given fields: ctx, assetID, staleBefore
+func (_m *AssetRepository) ClaimMeta(ctx context.Context, assetID uint64, staleBefore time.Time) (bool, error) {
+	ret := _m.Called(ctx, assetID, staleBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimMeta")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, time.Time) (bool, error)); ok {
+		return rf(ctx, assetID, staleBefore)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, time.Time) bool); ok {
+		r0 = rf(ctx, assetID, staleBefore)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, time.Time) error); ok {
+		r1 = rf(ctx, assetID, staleBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, req
+func (_m *AssetRepository) Create(ctx context.Context, req *models.Asset) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Asset) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: ctx, assetID
+func (_m *AssetRepository) Delete(ctx context.Context, assetID uint64) (*models.Asset, error) {
+	ret := _m.Called(ctx, assetID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 *models.Asset
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (*models.Asset, error)); ok {
+		return rf(ctx, assetID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *models.Asset); ok {
+		r0 = rf(ctx, assetID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Asset)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, assetID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteOrphan provides a mock function with given fields: ctx, assetID, before
+func (_m *AssetRepository) DeleteOrphan(ctx context.Context, assetID uint64, before time.Time) (*models.Asset, error) {
+	ret := _m.Called(ctx, assetID, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteOrphan")
+	}
+
+	var r0 *models.Asset
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, time.Time) (*models.Asset, error)); ok {
+		return rf(ctx, assetID, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, time.Time) *models.Asset); ok {
+		r0 = rf(ctx, assetID, before)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Asset)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, time.Time) error); ok {
+		r1 = rf(ctx, assetID, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Detach provides a mock function with given fields: ctx, postID, assetID
+func (_m *AssetRepository) Detach(ctx context.Context, postID uint64, assetID uint64) error {
+	ret := _m.Called(ctx, postID, assetID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Detach")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) error); ok {
+		r0 = rf(ctx, postID, assetID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByPost provides a mock function with given fields: ctx, postID
+func (_m *AssetRepository) GetByPost(ctx context.Context, postID uint64) ([]models.Asset, error) {
+	ret := _m.Called(ctx, postID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByPost")
+	}
+
+	var r0 []models.Asset
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) ([]models.Asset, error)); ok {
+		return rf(ctx, postID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) []models.Asset); ok {
+		r0 = rf(ctx, postID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Asset)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, postID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByPosts provides a mock function with given fields: ctx, postIDs
+func (_m *AssetRepository) GetByPosts(ctx context.Context, postIDs []uint64) (map[uint64][]models.Asset, error) {
+	ret := _m.Called(ctx, postIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByPosts")
+	}
+
+	var r0 map[uint64][]models.Asset
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uint64) (map[uint64][]models.Asset, error)); ok {
+		return rf(ctx, postIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uint64) map[uint64][]models.Asset); ok {
+		r0 = rf(ctx, postIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[uint64][]models.Asset)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []uint64) error); ok {
+		r1 = rf(ctx, postIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDetail provides a mock function with given fields: ctx, assetID
+func (_m *AssetRepository) GetDetail(ctx context.Context, assetID uint64) (*models.Asset, error) {
+	ret := _m.Called(ctx, assetID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDetail")
+	}
+
+	var r0 *models.Asset
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (*models.Asset, error)); ok {
+		return rf(ctx, assetID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *models.Asset); ok {
+		r0 = rf(ctx, assetID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Asset)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, assetID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetList provides a mock function with given fields: ctx, req
+func (_m *AssetRepository) GetList(ctx context.Context, req dto.AssetListReq) ([]models.Asset, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetList")
+	}
+
+	var r0 []models.Asset
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.AssetListReq) ([]models.Asset, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.AssetListReq) []models.Asset); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Asset)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.AssetListReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrphanIDs provides a mock function with given fields: ctx, before, limit
+func (_m *AssetRepository) GetOrphanIDs(ctx context.Context, before time.Time, limit int) ([]uint64, error) {
+	ret := _m.Called(ctx, before, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrphanIDs")
+	}
+
+	var r0 []uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]uint64, error)); ok {
+		return rf(ctx, before, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []uint64); ok {
+		r0 = rf(ctx, before, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uint64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, before, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPendingMetaIDs provides a mock function with given fields: ctx, staleBefore, limit
+func (_m *AssetRepository) GetPendingMetaIDs(ctx context.Context, staleBefore time.Time, limit int) ([]uint64, error) {
+	ret := _m.Called(ctx, staleBefore, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPendingMetaIDs")
+	}
+
+	var r0 []uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]uint64, error)); ok {
+		return rf(ctx, staleBefore, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []uint64); ok {
+		r0 = rf(ctx, staleBefore, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uint64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, staleBefore, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsPublic provides a mock function with given fields: ctx, assetID
+func (_m *AssetRepository) IsPublic(ctx context.Context, assetID uint64) (bool, error) {
+	ret := _m.Called(ctx, assetID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsPublic")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (bool, error)); ok {
+		return rf(ctx, assetID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) bool); ok {
+		r0 = rf(ctx, assetID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, assetID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveMeta provides a mock function with given fields: ctx, req
+func (_m *AssetRepository) SaveMeta(ctx context.Context, req *models.Asset) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveMeta")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Asset) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAssetRepository creates a new instance of AssetRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAssetRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AssetRepository {
+	mock := &AssetRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
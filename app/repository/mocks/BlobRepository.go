@@ -0,0 +1,207 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	dto "github.com/adamnasrudin03/go-asset-findr/app/dto"
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/adamnasrudin03/go-asset-findr/app/models"
+
+	time "time"
+)
+
+// BlobRepository is an autogenerated mock type for the BlobRepository type
+type BlobRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, req
+func (_m *BlobRepository) Create(ctx context.Context, req *models.Blob) (bool, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Blob) (bool, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Blob) bool); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Blob) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, blob
+func (_m *BlobRepository) Delete(ctx context.Context, blob models.Blob) (bool, error) {
+	ret := _m.Called(ctx, blob)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Blob) (bool, error)); ok {
+		return rf(ctx, blob)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.Blob) bool); ok {
+		r0 = rf(ctx, blob)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.Blob) error); ok {
+		r1 = rf(ctx, blob)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Find provides a mock function with given fields: ctx, checksum
+func (_m *BlobRepository) Find(ctx context.Context, checksum string) (*models.Blob, error) {
+	ret := _m.Called(ctx, checksum)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Find")
+	}
+
+	var r0 *models.Blob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*models.Blob, error)); ok {
+		return rf(ctx, checksum)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Blob); ok {
+		r0 = rf(ctx, checksum)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Blob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, checksum)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrphans provides a mock function with given fields: ctx, before, limit
+func (_m *BlobRepository) GetOrphans(ctx context.Context, before time.Time, limit int) ([]models.Blob, error) {
+	ret := _m.Called(ctx, before, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrphans")
+	}
+
+	var r0 []models.Blob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]models.Blob, error)); ok {
+		return rf(ctx, before, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []models.Blob); ok {
+		r0 = rf(ctx, before, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Blob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, before, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsKeyUsed provides a mock function with given fields: ctx, storageKey
+func (_m *BlobRepository) IsKeyUsed(ctx context.Context, storageKey string) (bool, error) {
+	ret := _m.Called(ctx, storageKey)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsKeyUsed")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, storageKey)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, storageKey)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, storageKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Report provides a mock function with given fields: ctx, assetBefore, blobBefore
+func (_m *BlobRepository) Report(ctx context.Context, assetBefore time.Time, blobBefore time.Time) (*dto.StorageReportRes, error) {
+	ret := _m.Called(ctx, assetBefore, blobBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Report")
+	}
+
+	var r0 *dto.StorageReportRes
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) (*dto.StorageReportRes, error)); ok {
+		return rf(ctx, assetBefore, blobBefore)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, time.Time) *dto.StorageReportRes); ok {
+		r0 = rf(ctx, assetBefore, blobBefore)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.StorageReportRes)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, assetBefore, blobBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewBlobRepository creates a new instance of BlobRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBlobRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BlobRepository {
+	mock := &BlobRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,203 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/adamnasrudin03/go-asset-findr/app/models"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// OutboxRepository is an autogenerated mock type for the OutboxRepository type
+type OutboxRepository struct {
+	mock.Mock
+}
+
+// DeletePublished provides a mock function with given fields: ctx, before
+func (_m *OutboxRepository) DeletePublished(ctx context.Context, before time.Time) (int64, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePublished")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByIDs provides a mock function with given fields: ctx, ids
+func (_m *OutboxRepository) GetByIDs(ctx context.Context, ids []uint64) ([]models.OutboxEvent, error) {
+	ret := _m.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByIDs")
+	}
+
+	var r0 []models.OutboxEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uint64) ([]models.OutboxEvent, error)); ok {
+		return rf(ctx, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uint64) []models.OutboxEvent); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.OutboxEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []uint64) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDue provides a mock function with given fields: ctx, now, limit
+func (_m *OutboxRepository) GetDue(ctx context.Context, now time.Time, limit int) ([]models.OutboxEvent, error) {
+	ret := _m.Called(ctx, now, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDue")
+	}
+
+	var r0 []models.OutboxEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]models.OutboxEvent, error)); ok {
+		return rf(ctx, now, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []models.OutboxEvent); ok {
+		r0 = rf(ctx, now, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.OutboxEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, now, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPublished provides a mock function with given fields: ctx, aggregateType, afterSeq, limit
+func (_m *OutboxRepository) GetPublished(ctx context.Context, aggregateType string, afterSeq uint64, limit int) ([]models.OutboxEvent, error) {
+	ret := _m.Called(ctx, aggregateType, afterSeq, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPublished")
+	}
+
+	var r0 []models.OutboxEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, int) ([]models.OutboxEvent, error)); ok {
+		return rf(ctx, aggregateType, afterSeq, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64, int) []models.OutboxEvent); ok {
+		r0 = rf(ctx, aggregateType, afterSeq, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.OutboxEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint64, int) error); ok {
+		r1 = rf(ctx, aggregateType, afterSeq, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkFailed provides a mock function with given fields: ctx, id, reason, nextAttemptAt
+func (_m *OutboxRepository) MarkFailed(ctx context.Context, id uint64, reason string, nextAttemptAt time.Time) error {
+	ret := _m.Called(ctx, id, reason, nextAttemptAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkFailed")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, string, time.Time) error); ok {
+		r0 = rf(ctx, id, reason, nextAttemptAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MarkPublished provides a mock function with given fields: ctx, ids, now
+func (_m *OutboxRepository) MarkPublished(ctx context.Context, ids []uint64, now time.Time) error {
+	ret := _m.Called(ctx, ids, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkPublished")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uint64, time.Time) error); ok {
+		r0 = rf(ctx, ids, now)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Notify provides a mock function with given fields: ctx, channel, id
+func (_m *OutboxRepository) Notify(ctx context.Context, channel string, id uint64) error {
+	ret := _m.Called(ctx, channel, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Notify")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint64) error); ok {
+		r0 = rf(ctx, channel, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewOutboxRepository creates a new instance of OutboxRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOutboxRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OutboxRepository {
+	mock := &OutboxRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
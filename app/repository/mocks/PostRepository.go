@@ -9,6 +9,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	models "github.com/adamnasrudin03/go-asset-findr/app/models"
+
+	time "time"
 )
 
 // PostRepository is an autogenerated mock type for the PostRepository type
@@ -16,6 +18,34 @@ type PostRepository struct {
 	mock.Mock
 }
 
+// BackfillSlugs provides a mock function with given fields: ctx
+func (_m *PostRepository) BackfillSlugs(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BackfillSlugs")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, req
 func (_m *PostRepository) Create(ctx context.Context, req dto.PostCreateReq) (*dto.PostRes, error) {
 	ret := _m.Called(ctx, req)
@@ -46,6 +76,66 @@ func (_m *PostRepository) Create(ctx context.Context, req dto.PostCreateReq) (*d
 	return r0, r1
 }
 
+// CreateBulk provides a mock function with given fields: ctx, req, atomic
+func (_m *PostRepository) CreateBulk(ctx context.Context, req []dto.PostCreateReq, atomic bool) ([]dto.PostBulkItemRes, error) {
+	ret := _m.Called(ctx, req, atomic)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateBulk")
+	}
+
+	var r0 []dto.PostBulkItemRes
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []dto.PostCreateReq, bool) ([]dto.PostBulkItemRes, error)); ok {
+		return rf(ctx, req, atomic)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []dto.PostCreateReq, bool) []dto.PostBulkItemRes); ok {
+		r0 = rf(ctx, req, atomic)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dto.PostBulkItemRes)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []dto.PostCreateReq, bool) error); ok {
+		r1 = rf(ctx, req, atomic)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteBulk provides a mock function with given fields: ctx, postIDs, atomic
+func (_m *PostRepository) DeleteBulk(ctx context.Context, postIDs []uint64, atomic bool) ([]dto.PostBulkItemRes, error) {
+	ret := _m.Called(ctx, postIDs, atomic)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteBulk")
+	}
+
+	var r0 []dto.PostBulkItemRes
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uint64, bool) ([]dto.PostBulkItemRes, error)); ok {
+		return rf(ctx, postIDs, atomic)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uint64, bool) []dto.PostBulkItemRes); ok {
+		r0 = rf(ctx, postIDs, atomic)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dto.PostBulkItemRes)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []uint64, bool) error); ok {
+		r1 = rf(ctx, postIDs, atomic)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteByID provides a mock function with given fields: ctx, postID
 func (_m *PostRepository) DeleteByID(ctx context.Context, postID uint64) error {
 	ret := _m.Called(ctx, postID)
@@ -64,6 +154,112 @@ func (_m *PostRepository) DeleteByID(ctx context.Context, postID uint64) error {
 	return r0
 }
 
+// Export provides a mock function with given fields: ctx, fn
+func (_m *PostRepository) Export(ctx context.Context, fn func(dto.PostTransferRow) error) error {
+	ret := _m.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Export")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(dto.PostTransferRow) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindExistingIDs provides a mock function with given fields: ctx, ids
+func (_m *PostRepository) FindExistingIDs(ctx context.Context, ids []uint64) (map[uint64]bool, error) {
+	ret := _m.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindExistingIDs")
+	}
+
+	var r0 map[uint64]bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uint64) (map[uint64]bool, error)); ok {
+		return rf(ctx, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uint64) map[uint64]bool); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[uint64]bool)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []uint64) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindIDBySlug provides a mock function with given fields: ctx, slug
+func (_m *PostRepository) FindIDBySlug(ctx context.Context, slug string) (uint64, error) {
+	ret := _m.Called(ctx, slug)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindIDBySlug")
+	}
+
+	var r0 uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (uint64, error)); ok {
+		return rf(ctx, slug)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) uint64); ok {
+		r0 = rf(ctx, slug)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, slug)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindIDsBySlugs provides a mock function with given fields: ctx, slugs
+func (_m *PostRepository) FindIDsBySlugs(ctx context.Context, slugs []string) (map[string]uint64, error) {
+	ret := _m.Called(ctx, slugs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindIDsBySlugs")
+	}
+
+	var r0 map[string]uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) (map[string]uint64, error)); ok {
+		return rf(ctx, slugs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) map[string]uint64); ok {
+		r0 = rf(ctx, slugs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]uint64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, slugs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetAll provides a mock function with given fields: ctx
 func (_m *PostRepository) GetAll(ctx context.Context) ([]dto.PostRes, error) {
 	ret := _m.Called(ctx)
@@ -124,28 +320,58 @@ func (_m *PostRepository) GetDetail(ctx context.Context, req dto.PostGetReq) (*d
 	return r0, r1
 }
 
-// GetDetailTag provides a mock function with given fields: ctx, req
-func (_m *PostRepository) GetDetailTag(ctx context.Context, req dto.TagGetReq) (*models.Tag, error) {
+// GetPage provides a mock function with given fields: ctx, req
+func (_m *PostRepository) GetPage(ctx context.Context, req dto.PostPageReq) ([]dto.PostRes, error) {
 	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetDetailTag")
+		panic("no return value specified for GetPage")
 	}
 
-	var r0 *models.Tag
+	var r0 []dto.PostRes
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, dto.TagGetReq) (*models.Tag, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, dto.PostPageReq) ([]dto.PostRes, error)); ok {
 		return rf(ctx, req)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, dto.TagGetReq) *models.Tag); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, dto.PostPageReq) []dto.PostRes); ok {
 		r0 = rf(ctx, req)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.Tag)
+			r0 = ret.Get(0).([]dto.PostRes)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.PostPageReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRelated provides a mock function with given fields: ctx, req
+func (_m *PostRepository) GetRelated(ctx context.Context, req dto.PostRelatedReq) ([]dto.PostRelatedRes, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRelated")
+	}
+
+	var r0 []dto.PostRelatedRes
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.PostRelatedReq) ([]dto.PostRelatedRes, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.PostRelatedReq) []dto.PostRelatedRes); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dto.PostRelatedRes)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, dto.TagGetReq) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, dto.PostRelatedReq) error); ok {
 		r1 = rf(ctx, req)
 	} else {
 		r1 = ret.Error(1)
@@ -154,6 +380,66 @@ func (_m *PostRepository) GetDetailTag(ctx context.Context, req dto.TagGetReq) (
 	return r0, r1
 }
 
+// GetTagsByPosts provides a mock function with given fields: ctx, postIDs
+func (_m *PostRepository) GetTagsByPosts(ctx context.Context, postIDs []uint64) (map[uint64][]models.Tag, error) {
+	ret := _m.Called(ctx, postIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTagsByPosts")
+	}
+
+	var r0 map[uint64][]models.Tag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uint64) (map[uint64][]models.Tag, error)); ok {
+		return rf(ctx, postIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uint64) map[uint64][]models.Tag); ok {
+		r0 = rf(ctx, postIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[uint64][]models.Tag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []uint64) error); ok {
+		r1 = rf(ctx, postIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PublishDue provides a mock function with given fields: ctx, now
+func (_m *PostRepository) PublishDue(ctx context.Context, now time.Time) ([]uint64, error) {
+	ret := _m.Called(ctx, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishDue")
+	}
+
+	var r0 []uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]uint64, error)); ok {
+		return rf(ctx, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []uint64); ok {
+		r0 = rf(ctx, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uint64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateByID provides a mock function with given fields: ctx, req
 func (_m *PostRepository) UpdateByID(ctx context.Context, req dto.PostUpdateReq) error {
 	ret := _m.Called(ctx, req)
@@ -172,6 +458,24 @@ func (_m *PostRepository) UpdateByID(ctx context.Context, req dto.PostUpdateReq)
 	return r0
 }
 
+// UpdateStatus provides a mock function with given fields: ctx, req
+func (_m *PostRepository) UpdateStatus(ctx context.Context, req dto.PostStatusReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.PostStatusReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewPostRepository creates a new instance of PostRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewPostRepository(t interface {
@@ -0,0 +1,139 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	dto "github.com/adamnasrudin03/go-asset-findr/app/dto"
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/adamnasrudin03/go-asset-findr/app/models"
+)
+
+// PostRevisionRepository is an autogenerated mock type for the PostRevisionRepository type
+type PostRevisionRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, req
+func (_m *PostRevisionRepository) Create(ctx context.Context, req *models.PostRevision) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PostRevision) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAll provides a mock function with given fields: ctx, postID
+func (_m *PostRevisionRepository) GetAll(ctx context.Context, postID uint64) ([]models.PostRevision, error) {
+	ret := _m.Called(ctx, postID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAll")
+	}
+
+	var r0 []models.PostRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) ([]models.PostRevision, error)); ok {
+		return rf(ctx, postID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) []models.PostRevision); ok {
+		r0 = rf(ctx, postID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.PostRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, postID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAuthorsByPosts provides a mock function with given fields: ctx, postIDs
+func (_m *PostRevisionRepository) GetAuthorsByPosts(ctx context.Context, postIDs []uint64) (map[uint64][]dto.PostAuthorRes, error) {
+	ret := _m.Called(ctx, postIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAuthorsByPosts")
+	}
+
+	var r0 map[uint64][]dto.PostAuthorRes
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uint64) (map[uint64][]dto.PostAuthorRes, error)); ok {
+		return rf(ctx, postIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uint64) map[uint64][]dto.PostAuthorRes); ok {
+		r0 = rf(ctx, postIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[uint64][]dto.PostAuthorRes)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []uint64) error); ok {
+		r1 = rf(ctx, postIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDetail provides a mock function with given fields: ctx, req
+func (_m *PostRevisionRepository) GetDetail(ctx context.Context, req dto.PostRevisionGetReq) (*models.PostRevision, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDetail")
+	}
+
+	var r0 *models.PostRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.PostRevisionGetReq) (*models.PostRevision, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.PostRevisionGetReq) *models.PostRevision); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PostRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.PostRevisionGetReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPostRevisionRepository creates a new instance of PostRevisionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPostRevisionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PostRevisionRepository {
+	mock := &PostRevisionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
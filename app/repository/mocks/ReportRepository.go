@@ -0,0 +1,169 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	dto "github.com/adamnasrudin03/go-asset-findr/app/dto"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// ReportRepository is an autogenerated mock type for the ReportRepository type
+type ReportRepository struct {
+	mock.Mock
+}
+
+// GetPostCounts provides a mock function with given fields: ctx
+func (_m *ReportRepository) GetPostCounts(ctx context.Context) ([]dto.TagPostCountRow, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPostCounts")
+	}
+
+	var r0 []dto.TagPostCountRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]dto.TagPostCountRow, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []dto.TagPostCountRow); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dto.TagPostCountRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTagPairs provides a mock function with given fields: ctx, tagIDs
+func (_m *ReportRepository) GetTagPairs(ctx context.Context, tagIDs []uint64) ([]dto.TagPairRow, error) {
+	ret := _m.Called(ctx, tagIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTagPairs")
+	}
+
+	var r0 []dto.TagPairRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uint64) ([]dto.TagPairRow, error)); ok {
+		return rf(ctx, tagIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uint64) []dto.TagPairRow); ok {
+		r0 = rf(ctx, tagIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dto.TagPairRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []uint64) error); ok {
+		r1 = rf(ctx, tagIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTagWeeks provides a mock function with given fields: ctx, since, tag, top
+func (_m *ReportRepository) GetTagWeeks(ctx context.Context, since time.Time, tag string, top int) ([]dto.TagWeekRow, error) {
+	ret := _m.Called(ctx, since, tag, top)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTagWeeks")
+	}
+
+	var r0 []dto.TagWeekRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, string, int) ([]dto.TagWeekRow, error)); ok {
+		return rf(ctx, since, tag, top)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, string, int) []dto.TagWeekRow); ok {
+		r0 = rf(ctx, since, tag, top)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dto.TagWeekRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, string, int) error); ok {
+		r1 = rf(ctx, since, tag, top)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTopTags provides a mock function with given fields: ctx, limit
+func (_m *ReportRepository) GetTopTags(ctx context.Context, limit int) ([]dto.TagUsageRes, error) {
+	ret := _m.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTopTags")
+	}
+
+	var r0 []dto.TagUsageRes
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]dto.TagUsageRes, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []dto.TagUsageRes); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dto.TagUsageRes)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Refresh provides a mock function with given fields: ctx
+func (_m *ReportRepository) Refresh(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Refresh")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewReportRepository creates a new instance of ReportRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReportRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReportRepository {
+	mock := &ReportRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
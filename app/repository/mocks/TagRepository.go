@@ -0,0 +1,327 @@
+// Code generated by mockery v2.43.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	dto "github.com/adamnasrudin03/go-asset-findr/app/dto"
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/adamnasrudin03/go-asset-findr/app/models"
+
+	time "time"
+)
+
+// TagRepository is an autogenerated mock type for the TagRepository type
+type TagRepository struct {
+	mock.Mock
+}
+
+// AddSynonym provides a mock function with given fields: ctx, req
+func (_m *TagRepository) AddSynonym(ctx context.Context, req dto.TagSynonymReq) (*models.TagSynonym, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddSynonym")
+	}
+
+	var r0 *models.TagSynonym
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.TagSynonymReq) (*models.TagSynonym, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.TagSynonymReq) *models.TagSynonym); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.TagSynonym)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.TagSynonymReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteSynonym provides a mock function with given fields: ctx, req
+func (_m *TagRepository) DeleteSynonym(ctx context.Context, req dto.TagSynonymReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteSynonym")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.TagSynonymReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAll provides a mock function with given fields: ctx
+func (_m *TagRepository) GetAll(ctx context.Context) ([]models.Tag, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAll")
+	}
+
+	var r0 []models.Tag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Tag, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.Tag); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Tag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDetail provides a mock function with given fields: ctx, req
+func (_m *TagRepository) GetDetail(ctx context.Context, req dto.TagGetReq) (*models.Tag, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDetail")
+	}
+
+	var r0 *models.Tag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.TagGetReq) (*models.Tag, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.TagGetReq) *models.Tag); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Tag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.TagGetReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSynonyms provides a mock function with given fields: ctx
+func (_m *TagRepository) GetSynonyms(ctx context.Context) ([]models.TagSynonym, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSynonyms")
+	}
+
+	var r0 []models.TagSynonym
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]models.TagSynonym, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []models.TagSynonym); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.TagSynonym)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Merge provides a mock function with given fields: ctx, req
+func (_m *TagRepository) Merge(ctx context.Context, req dto.TagMergeReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Merge")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.TagMergeReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Popular provides a mock function with given fields: ctx, since, limit
+func (_m *TagRepository) Popular(ctx context.Context, since time.Time, limit int) ([]dto.TagUsageRes, error) {
+	ret := _m.Called(ctx, since, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Popular")
+	}
+
+	var r0 []dto.TagUsageRes
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]dto.TagUsageRes, error)); ok {
+		return rf(ctx, since, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []dto.TagUsageRes); ok {
+		r0 = rf(ctx, since, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dto.TagUsageRes)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, since, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Resolve provides a mock function with given fields: ctx, label, descendants
+func (_m *TagRepository) Resolve(ctx context.Context, label string, descendants bool) ([]models.Tag, error) {
+	ret := _m.Called(ctx, label, descendants)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Resolve")
+	}
+
+	var r0 []models.Tag
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) ([]models.Tag, error)); ok {
+		return rf(ctx, label, descendants)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) []models.Tag); ok {
+		r0 = rf(ctx, label, descendants)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Tag)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, bool) error); ok {
+		r1 = rf(ctx, label, descendants)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetParent provides a mock function with given fields: ctx, req
+func (_m *TagRepository) SetParent(ctx context.Context, req dto.TagParentReq) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetParent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.TagParentReq) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Suggest provides a mock function with given fields: ctx, req
+func (_m *TagRepository) Suggest(ctx context.Context, req dto.TagSuggestReq) ([]dto.TagUsageRes, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Suggest")
+	}
+
+	var r0 []dto.TagUsageRes
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.TagSuggestReq) ([]dto.TagUsageRes, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.TagSuggestReq) []dto.TagUsageRes); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dto.TagUsageRes)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.TagSuggestReq) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Upsert provides a mock function with given fields: ctx, labels
+func (_m *TagRepository) Upsert(ctx context.Context, labels []string) (map[string]uint64, error) {
+	ret := _m.Called(ctx, labels)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Upsert")
+	}
+
+	var r0 map[string]uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) (map[string]uint64, error)); ok {
+		return rf(ctx, labels)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) map[string]uint64); ok {
+		r0 = rf(ctx, labels)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]uint64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, labels)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTagRepository creates a new instance of TagRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTagRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TagRepository {
+	mock := &TagRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
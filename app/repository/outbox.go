@@ -0,0 +1,328 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/database"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OutboxRepository reads the outbox for the relay. Events are written by the
+// repositories of the aggregates, in the transaction of their change.
+type OutboxRepository interface {
+	GetDue(ctx context.Context, now time.Time, limit int) ([]models.OutboxEvent, error)
+	MarkPublished(ctx context.Context, ids []uint64, now time.Time) error
+	MarkFailed(ctx context.Context, id uint64, reason string, nextAttemptAt time.Time) error
+	DeletePublished(ctx context.Context, before time.Time) (int64, error)
+	GetPublished(ctx context.Context, aggregateType string, afterSeq uint64, limit int) ([]models.OutboxEvent, error)
+	GetByIDs(ctx context.Context, ids []uint64) ([]models.OutboxEvent, error)
+	Notify(ctx context.Context, channel string, id uint64) error
+}
+
+// outboxPublishedSeq is the sequence numbering the outbox events as they
+// are published.
+const outboxPublishedSeq = "outbox_published_seq"
+
+// migrateOutbox creates the sequence of outboxPublishedSeq.
+func migrateOutbox(db *gorm.DB) error {
+	return db.Exec("CREATE SEQUENCE IF NOT EXISTS " + outboxPublishedSeq).Error
+}
+
+type OutboxRepo struct {
+	DB     *gorm.DB
+	Cfg    *configs.Configs
+	Logger *logrus.Logger
+}
+
+func NewOutboxRepository(
+	db *gorm.DB,
+	cfg *configs.Configs,
+	logger *logrus.Logger,
+) OutboxRepository {
+	return &OutboxRepo{
+		DB:     db,
+		Cfg:    cfg,
+		Logger: logger,
+	}
+}
+
+// GetDue returns the oldest unpublished event of each aggregate when it is
+// due, locked until the end of the transaction. A later event of the same
+// aggregate is never returned before it, so they are published in order,
+// and relays running on several instances skip each other's events.
+func (r *OutboxRepo) GetDue(ctx context.Context, now time.Time, limit int) ([]models.OutboxEvent, error) {
+	var (
+		opName = "OutboxRepository-GetDue"
+		result = []models.OutboxEvent{}
+	)
+
+	err := conn(ctx, r.DB).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("published_at IS NULL AND next_attempt_at <= ?", now).
+		Where(`NOT EXISTS (SELECT 1 FROM outbox earlier
+			WHERE earlier.aggregate_type = outbox.aggregate_type
+				AND earlier.aggregate_id = outbox.aggregate_id
+				AND earlier.published_at IS NULL
+				AND earlier.id < outbox.id)`).
+		Order("id").
+		Limit(limit).
+		Find(&result).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	return result, nil
+}
+
+// MarkPublished stamps the events of ids with the next numbers of the
+// published sequence, in ID order. It is called at the end of the relay
+// transaction: the lock it takes is held until the commit, so relays of
+// several instances commit their numbers in order and a stream resuming
+// after one never misses a smaller one committed later.
+func (r *OutboxRepo) MarkPublished(ctx context.Context, ids []uint64, now time.Time) error {
+	opName := "OutboxRepository-MarkPublished"
+	if len(ids) == 0 {
+		return nil
+	}
+
+	trx := conn(ctx, r.DB)
+	err := trx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", outboxPublishedSeq).Error
+	if err == nil {
+		err = trx.Exec(`UPDATE outbox SET published_seq = seq.value, published_at = ?, attempts = attempts + 1, last_error = ''
+			FROM (SELECT id, nextval(?) AS value FROM (SELECT id FROM outbox WHERE id IN ? ORDER BY id) ordered) seq
+			WHERE outbox.id = seq.id`, now, outboxPublishedSeq, ids).Error
+	}
+	if err != nil {
+		r.Logger.Errorf("%s failed update data: %v \n", opName, err)
+		return helpers.ErrUpdatedDB()
+	}
+
+	return nil
+}
+
+// MarkFailed counts a failed attempt and holds the event, with the next
+// events of its aggregate, until nextAttemptAt.
+func (r *OutboxRepo) MarkFailed(ctx context.Context, id uint64, reason string, nextAttemptAt time.Time) error {
+	opName := "OutboxRepository-MarkFailed"
+
+	err := conn(ctx, r.DB).Model(&models.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"last_error":      reason,
+			"next_attempt_at": nextAttemptAt,
+		}).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed update data: %v \n", opName, err)
+		return helpers.ErrUpdatedDB()
+	}
+
+	return nil
+}
+
+// DeletePublished removes the events published before before.
+func (r *OutboxRepo) DeletePublished(ctx context.Context, before time.Time) (int64, error) {
+	opName := "OutboxRepository-DeletePublished"
+
+	res := conn(ctx, r.DB).Where("published_at < ?", before).Delete(&models.OutboxEvent{})
+	if res.Error != nil {
+		r.Logger.Errorf("%s failed delete data: %v \n", opName, res.Error)
+		return 0, helpers.ErrDB()
+	}
+
+	return res.RowsAffected, nil
+}
+
+// GetPublished returns the published events of aggregateType after afterSeq
+// in the order they were published, those pruned already are gone.
+func (r *OutboxRepo) GetPublished(ctx context.Context, aggregateType string, afterSeq uint64, limit int) ([]models.OutboxEvent, error) {
+	var (
+		opName = "OutboxRepository-GetPublished"
+		result = []models.OutboxEvent{}
+	)
+
+	err := conn(ctx, r.DB).
+		Where("aggregate_type = ? AND published_seq > ?", aggregateType, afterSeq).
+		Order("published_seq").
+		Limit(limit).
+		Find(&result).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	return result, nil
+}
+
+// GetByIDs reads the events from the primary, they are read right after
+// the notification of their commit.
+func (r *OutboxRepo) GetByIDs(ctx context.Context, ids []uint64) ([]models.OutboxEvent, error) {
+	var (
+		opName = "OutboxRepository-GetByIDs"
+		result = []models.OutboxEvent{}
+	)
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	err := conn(database.WithReadPrimary(ctx), r.DB).Where("id IN ?", ids).Order("id").Find(&result).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	return result, nil
+}
+
+// Notify sends the event id to the listeners of channel, on every instance.
+// In a transaction it is only sent once committed.
+func (r *OutboxRepo) Notify(ctx context.Context, channel string, id uint64) error {
+	opName := "OutboxRepository-Notify"
+
+	err := conn(ctx, r.DB).Exec("SELECT pg_notify(?, ?)", channel, strconv.FormatUint(id, 10)).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed notify %s: %v \n", opName, channel, err)
+		return helpers.ErrDB()
+	}
+
+	return nil
+}
+
+// postSnapshots returns the posts of ids as trx sees them, with the labels
+// of their tags, for the payload of their events. isLock locks the posts so
+// the events of concurrent changes of a post are written in commit order.
+func postSnapshots(trx *gorm.DB, ids []uint64, isLock bool) (map[uint64]*dto.PostRes, error) {
+	result := make(map[uint64]*dto.PostRes, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	query := trx.Where("id IN ?", ids)
+	if isLock {
+		query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+	posts := []models.Post{}
+	err := query.Order("id").Find(&posts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range posts {
+		post := newPostRes(v)
+		post.ContentHTML, post.ContentText = "", ""
+		post.Tags = []string{}
+		result[v.ID] = post
+	}
+
+	tags := []struct {
+		PostID uint64
+		Label  string
+	}{}
+	err = trx.Raw(`SELECT post_tag.post_id, tag.label FROM post_tag
+		INNER JOIN tag ON tag.id = post_tag.tag_id
+		WHERE post_tag.post_id IN ?
+		ORDER BY tag.label`, ids).
+		Scan(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range tags {
+		if post, ok := result[v.PostID]; ok {
+			post.Tags = append(post.Tags, v.Label)
+		}
+	}
+
+	return result, nil
+}
+
+// writePostEvents adds an event of eventType to the outbox for each post of
+// ids, in order, with its snapshot before and after the change. A post
+// missing from a snapshot it needs was not changed and gets no event.
+func writePostEvents(trx *gorm.DB, eventType string, ids []uint64, before, after map[uint64]*dto.PostRes) error {
+	events := make([]models.OutboxEvent, 0, len(ids))
+	for _, id := range ids {
+		payload := dto.PostEventPayload{Before: before[id], After: after[id]}
+		switch {
+		case eventType == dto.PostEventCreated && payload.After == nil,
+			eventType == dto.PostEventUpdated && (payload.Before == nil || payload.After == nil),
+			eventType == dto.PostEventDeleted && payload.Before == nil:
+			continue
+		}
+
+		event, err := newOutboxEvent(dto.AggregatePost, id, eventType, payload)
+		if err != nil {
+			return err
+		}
+		events = append(events, event)
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	return trx.Create(&events).Error
+}
+
+// tagSnapshots returns the tags of ids as trx sees them, with their
+// synonyms, for the payload of their events.
+func tagSnapshots(trx *gorm.DB, ids []uint64) (map[uint64]*dto.TagEventData, error) {
+	result := make(map[uint64]*dto.TagEventData, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	tags := []models.Tag{}
+	err := trx.Where("id IN ?", ids).Order("id").Find(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range tags {
+		result[v.ID] = &dto.TagEventData{ID: v.ID, Label: v.Label, ParentID: v.ParentID, Synonyms: []string{}}
+	}
+
+	synonyms := []models.TagSynonym{}
+	err = trx.Where("tag_id IN ?", ids).Order("label").Find(&synonyms).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range synonyms {
+		if tag, ok := result[v.TagID]; ok {
+			tag.Synonyms = append(tag.Synonyms, v.Label)
+		}
+	}
+
+	return result, nil
+}
+
+// writeTagEvent adds an event of eventType of the tag to the outbox.
+func writeTagEvent(trx *gorm.DB, eventType string, tagID uint64, payload interface{}) error {
+	event, err := newOutboxEvent(dto.AggregateTag, tagID, eventType, payload)
+	if err != nil {
+		return err
+	}
+	return trx.Create(&event).Error
+}
+
+func newOutboxEvent(aggregateType string, aggregateID uint64, eventType string, payload interface{}) (models.OutboxEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return models.OutboxEvent{}, err
+	}
+
+	return models.OutboxEvent{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       models.JSON(data),
+		NextAttemptAt: time.Now(),
+	}, nil
+}
@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/database"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/driver"
+	"gorm.io/gorm"
+)
+
+func TestOutboxRepo(t *testing.T) {
+	var (
+		db       = openTestDB(t, "DB_TEST_DSN")
+		cfg      = testConfigs()
+		logger   = driver.Logger(cfg)
+		repo     = NewOutboxRepository(db, cfg, logger)
+		postRepo = NewPostRepository(db, cfg, logger)
+		ctx      = context.Background()
+	)
+	// tags given by a post are created with their own event
+	db.Create(&[]models.Tag{{Label: "go"}, {Label: "sql"}})
+
+	post, err := postRepo.Create(ctx, dto.PostCreateReq{Title: "first", Content: "content", Status: dto.PostStatusPublished, Tags: []string{"go"}})
+	if err != nil {
+		t.Fatalf("Create() post error = %v", err)
+	}
+	other, err := postRepo.Create(ctx, dto.PostCreateReq{Title: "other", Content: "content", Status: dto.PostStatusDraft})
+	if err != nil {
+		t.Fatalf("Create() post error = %v", err)
+	}
+	err = postRepo.UpdateByID(ctx, dto.PostUpdateReq{ID: post.ID, Title: "second", Content: "content", Tags: []string{"go", "sql"}})
+	if err != nil {
+		t.Fatalf("UpdateByID() error = %v", err)
+	}
+	if err := postRepo.DeleteByID(ctx, post.ID); err != nil {
+		t.Fatalf("DeleteByID() error = %v", err)
+	}
+	if err := postRepo.DeleteByID(ctx, post.ID); err == nil {
+		t.Errorf("DeleteByID() twice error = nil, want not found")
+	}
+
+	events := []models.OutboxEvent{}
+	db.Order("id").Find(&events)
+	types := []string{}
+	for _, v := range events {
+		types = append(types, v.EventType)
+	}
+	want := []string{dto.PostEventCreated, dto.PostEventCreated, dto.PostEventUpdated, dto.PostEventDeleted}
+	if !reflect.DeepEqual(types, want) {
+		t.Fatalf("events = %v, want %v", types, want)
+	}
+
+	payload := dto.PostEventPayload{}
+	if err := json.Unmarshal(events[2].Payload, &payload); err != nil {
+		t.Fatalf("Unmarshal() payload error = %v", err)
+	}
+	if payload.Before == nil || payload.Before.Title != "first" || payload.After == nil || !reflect.DeepEqual(payload.After.Tags, []string{"go", "sql"}) {
+		t.Errorf("updated payload = %+v %+v", payload.Before, payload.After)
+	}
+
+	// only the first event of each post is due, the other waits for it
+	withTx := func(fn func(ctx context.Context) error) {
+		err := WithTx(ctx, db, func(ctx context.Context, trx *gorm.DB) error {
+			return fn(ctx)
+		})
+		if err != nil {
+			t.Fatalf("WithTx() error = %v", err)
+		}
+	}
+	dueIDs := func() []uint64 {
+		ids := []uint64{}
+		withTx(func(ctx context.Context) error {
+			due, err := repo.GetDue(ctx, time.Now(), 10)
+			for _, v := range due {
+				ids = append(ids, v.ID)
+			}
+			return err
+		})
+		return ids
+	}
+
+	if got := dueIDs(); !reflect.DeepEqual(got, []uint64{events[0].ID, events[1].ID}) {
+		t.Fatalf("GetDue() = %v, want the first event of each post", got)
+	}
+	withTx(func(ctx context.Context) error {
+		return repo.MarkFailed(ctx, events[0].ID, "unavailable", time.Now().Add(time.Hour))
+	})
+	if got := dueIDs(); !reflect.DeepEqual(got, []uint64{events[1].ID}) {
+		t.Errorf("GetDue() after a failure = %v, want only post %d", got, other.ID)
+	}
+
+	now := time.Now()
+	withTx(func(ctx context.Context) error {
+		return repo.MarkPublished(ctx, []uint64{events[0].ID, events[1].ID}, now.Add(-2*time.Hour))
+	})
+	if got := dueIDs(); !reflect.DeepEqual(got, []uint64{events[2].ID}) {
+		t.Errorf("GetDue() after publishing = %v, want the update", got)
+	}
+	published := []models.OutboxEvent{}
+	db.Where("published_seq IS NOT NULL").Order("published_seq").Find(&published)
+	if len(published) != 2 || published[0].ID != events[0].ID || *published[1].PublishedSeq != *published[0].PublishedSeq+1 {
+		t.Errorf("published = %+v, want numbered in ID order", published)
+	}
+
+	total, err := repo.DeletePublished(ctx, now.Add(-time.Hour))
+	if err != nil || total != 2 {
+		t.Errorf("DeletePublished() = %d, %v, want 2", total, err)
+	}
+}
+
+func TestOutboxRepo_tagEvents(t *testing.T) {
+	var (
+		db       = openTestDB(t, "DB_TEST_DSN")
+		cfg      = testConfigs()
+		logger   = driver.Logger(cfg)
+		repo     = NewTagRepository(db, cfg, logger)
+		postRepo = NewPostRepository(db, cfg, logger)
+		ctx      = context.Background()
+		golang   = models.Tag{Label: "golang"}
+		gopher   = models.Tag{Label: "gopher"}
+	)
+	db.Create(&golang)
+	db.Create(&gopher)
+
+	post, err := postRepo.Create(ctx, dto.PostCreateReq{Title: "first", Content: "content", Status: dto.PostStatusPublished, Tags: []string{"gopher", "rust"}})
+	if err != nil {
+		t.Fatalf("Create() post error = %v", err)
+	}
+	if _, err := repo.AddSynonym(ctx, dto.TagSynonymReq{TagID: golang.ID, Label: "go"}); err != nil {
+		t.Fatalf("AddSynonym() error = %v", err)
+	}
+	if err := repo.Merge(ctx, dto.TagMergeReq{TargetID: golang.ID, SourceIDs: []uint64{gopher.ID}}); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+
+	events := []models.OutboxEvent{}
+	db.Where("aggregate_type = ?", dto.AggregateTag).Order("id").Find(&events)
+	if len(events) != 3 || events[0].EventType != dto.TagEventCreated ||
+		events[1].EventType != dto.TagEventUpdated || events[2].EventType != dto.TagEventMerged {
+		t.Fatalf("events = %+v, want tag.created, tag.updated then tag.merged", events)
+	}
+
+	created := dto.TagEventPayload{}
+	if err := json.Unmarshal(events[0].Payload, &created); err != nil {
+		t.Fatalf("Unmarshal() payload error = %v", err)
+	}
+	if created.Before != nil || created.After == nil || created.After.Label != "rust" || events[0].AggregateID != created.After.ID {
+		t.Errorf("created payload = %+v %+v", created.Before, created.After)
+	}
+
+	updated := dto.TagEventPayload{}
+	if err := json.Unmarshal(events[1].Payload, &updated); err != nil {
+		t.Fatalf("Unmarshal() payload error = %v", err)
+	}
+	if len(updated.Before.Synonyms) != 0 || !reflect.DeepEqual(updated.After.Synonyms, []string{"go"}) {
+		t.Errorf("updated payload = %+v %+v", updated.Before, updated.After)
+	}
+
+	merged := dto.TagMergedPayload{}
+	if err := json.Unmarshal(events[2].Payload, &merged); err != nil {
+		t.Fatalf("Unmarshal() payload error = %v", err)
+	}
+	if merged.Target.ID != golang.ID || !reflect.DeepEqual(merged.Target.Synonyms, []string{"go", "gopher"}) ||
+		len(merged.Sources) != 1 || merged.Sources[0].Label != "gopher" {
+		t.Errorf("merged payload = %+v", merged)
+	}
+
+	postEvents := []models.OutboxEvent{}
+	db.Where("aggregate_type = ? AND aggregate_id = ?", dto.AggregatePost, post.ID).Order("id").Find(&postEvents)
+	if len(postEvents) != 2 || postEvents[1].EventType != dto.PostEventUpdated {
+		t.Fatalf("post events = %+v, want post.created then post.updated", postEvents)
+	}
+	retagged := dto.PostEventPayload{}
+	if err := json.Unmarshal(postEvents[1].Payload, &retagged); err != nil {
+		t.Fatalf("Unmarshal() payload error = %v", err)
+	}
+	if !reflect.DeepEqual(retagged.Before.Tags, []string{"gopher", "rust"}) || !reflect.DeepEqual(retagged.After.Tags, []string{"golang", "rust"}) {
+		t.Errorf("retagged payload = %v %v", retagged.Before.Tags, retagged.After.Tags)
+	}
+}
+
+func TestOutboxRepo_Notify(t *testing.T) {
+	var (
+		db       = openTestDB(t, "DB_TEST_DSN")
+		cfg      = testConfigs()
+		logger   = driver.Logger(cfg)
+		repo     = NewOutboxRepository(db, cfg, logger)
+		postRepo = NewPostRepository(db, cfg, logger)
+		ctx, end = context.WithTimeout(context.Background(), 10*time.Second)
+		listened = make(chan bool, 1)
+		received = make(chan string, 1)
+	)
+	defer end()
+
+	go database.Listen(ctx, os.Getenv("DB_TEST_DSN"), dto.PostStreamChannel, logger, func() {
+		listened <- true
+	}, func(payload string) {
+		received <- payload
+	})
+	<-listened
+
+	post, err := postRepo.Create(ctx, dto.PostCreateReq{Title: "first", Content: "content", Status: dto.PostStatusPublished})
+	if err != nil {
+		t.Fatalf("Create() post error = %v", err)
+	}
+	events := []models.OutboxEvent{}
+	db.Order("id").Find(&events)
+	if len(events) != 1 {
+		t.Fatalf("events = %d, want 1", len(events))
+	}
+
+	// published and notified together, the notification comes with the commit
+	err = WithTx(ctx, db, func(ctx context.Context, trx *gorm.DB) error {
+		err := repo.MarkPublished(ctx, []uint64{events[0].ID}, time.Now())
+		if err != nil {
+			return err
+		}
+		return repo.Notify(ctx, dto.PostStreamChannel, events[0].ID)
+	})
+	if err != nil {
+		t.Fatalf("WithTx() error = %v", err)
+	}
+	if got := <-received; got != strconv.FormatUint(events[0].ID, 10) {
+		t.Errorf("notification = %q, want %d", got, events[0].ID)
+	}
+
+	byIDs, err := repo.GetByIDs(ctx, []uint64{events[0].ID})
+	if err != nil || len(byIDs) != 1 || byIDs[0].AggregateID != post.ID {
+		t.Errorf("GetByIDs() = %+v, %v", byIDs, err)
+	}
+	published, err := repo.GetPublished(ctx, dto.AggregatePost, 0, 10)
+	if err != nil || len(published) != 1 || published[0].PublishedSeq == nil {
+		t.Fatalf("GetPublished() = %+v, %v, want 1 numbered", published, err)
+	}
+	if after, err := repo.GetPublished(ctx, dto.AggregatePost, *published[0].PublishedSeq, 10); err != nil || len(after) != 0 {
+		t.Errorf("GetPublished() after the last = %d, %v, want 0", len(after), err)
+	}
+}
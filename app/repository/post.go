@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"sort"
+	"time"
 
 	"github.com/adamnasrudin03/go-asset-findr/app/configs"
 	"github.com/adamnasrudin03/go-asset-findr/app/dto"
 	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/render"
 	"github.com/adamnasrudin03/go-template/pkg/helpers"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
@@ -17,10 +19,21 @@ import (
 type PostRepository interface {
 	GetAll(ctx context.Context) (result []dto.PostRes, err error)
 	GetDetail(ctx context.Context, req dto.PostGetReq) (*dto.PostRes, error)
-	GetDetailTag(ctx context.Context, req dto.TagGetReq) (*models.Tag, error)
 	Create(ctx context.Context, req dto.PostCreateReq) (*dto.PostRes, error)
 	DeleteByID(ctx context.Context, postID uint64) error
 	UpdateByID(ctx context.Context, req dto.PostUpdateReq) error
+	UpdateStatus(ctx context.Context, req dto.PostStatusReq) error
+	PublishDue(ctx context.Context, now time.Time) ([]uint64, error)
+	FindIDBySlug(ctx context.Context, slug string) (uint64, error)
+	BackfillSlugs(ctx context.Context) (int, error)
+	Export(ctx context.Context, fn func(row dto.PostTransferRow) error) error
+	FindExistingIDs(ctx context.Context, ids []uint64) (map[uint64]bool, error)
+	FindIDsBySlugs(ctx context.Context, slugs []string) (map[string]uint64, error)
+	CreateBulk(ctx context.Context, req []dto.PostCreateReq, atomic bool) ([]dto.PostBulkItemRes, error)
+	DeleteBulk(ctx context.Context, postIDs []uint64, atomic bool) ([]dto.PostBulkItemRes, error)
+	GetRelated(ctx context.Context, req dto.PostRelatedReq) ([]dto.PostRelatedRes, error)
+	GetPage(ctx context.Context, req dto.PostPageReq) ([]dto.PostRes, error)
+	GetTagsByPosts(ctx context.Context, postIDs []uint64) (map[uint64][]models.Tag, error)
 }
 
 type PostRepo struct {
@@ -47,7 +60,7 @@ func (r *PostRepo) findTags(ctx context.Context, postID uint64) (result []string
 		tags   = []models.Tag{}
 	)
 
-	err = r.DB.WithContext(ctx).
+	err = conn(ctx, r.DB).
 		Raw("SELECT tag.id, tag.label FROM post_tag "+
 			" INNER JOIN tag ON tag.id = post_tag.tag_id "+
 			" WHERE post_tag.post_id = ?", postID).
@@ -57,6 +70,12 @@ func (r *PostRepo) findTags(ctx context.Context, postID uint64) (result []string
 		return []string{}, err
 	}
 
+	return tagTitles(tags), nil
+}
+
+// tagTitles returns the labels of tags as PostRes shows them, sorted.
+func tagTitles(tags []models.Tag) (result []string) {
+	tags = append([]models.Tag{}, tags...)
 	sort.SliceStable(tags, func(i, j int) bool {
 		return tags[i].Label < tags[j].Label
 	})
@@ -64,13 +83,13 @@ func (r *PostRepo) findTags(ctx context.Context, postID uint64) (result []string
 	for _, v := range tags {
 		result = append(result, helpers.ToTitle(v.Label))
 	}
-	return result, nil
+	return result
 }
 
 func (r *PostRepo) GetAll(ctx context.Context) (result []dto.PostRes, err error) {
 	var (
 		opName = "PostRepository-FindAll"
-		query  = r.DB.WithContext(ctx)
+		query  = conn(ctx, r.DB)
 		posts  = []models.Post{}
 	)
 	err = query.Find(&posts).Error
@@ -79,19 +98,19 @@ func (r *PostRepo) GetAll(ctx context.Context) (result []dto.PostRes, err error)
 		return result, err
 	}
 
+	postIDs := make([]uint64, 0, len(posts))
 	for _, v := range posts {
-		temp := dto.PostRes{
-			ID:      v.ID,
-			Title:   v.Title,
-			Content: v.Content,
-		}
-		resTags, err := r.findTags(ctx, v.ID)
-		if err != nil {
-			r.Logger.Errorf("%s failed get data tags: %v \n", opName, err)
-			return result, err
-		}
+		postIDs = append(postIDs, v.ID)
+	}
+	tags, err := r.GetTagsByPosts(ctx, postIDs)
+	if err != nil {
+		r.Logger.Errorf("%s failed get data tags: %v \n", opName, err)
+		return result, err
+	}
 
-		temp.Tags = resTags
+	for _, v := range posts {
+		temp := *newPostRes(v)
+		temp.Tags = tagTitles(tags[v.ID])
 		result = append(result, temp)
 	}
 
@@ -101,7 +120,7 @@ func (r *PostRepo) GetAll(ctx context.Context) (result []dto.PostRes, err error)
 func (r *PostRepo) GetDetail(ctx context.Context, req dto.PostGetReq) (*dto.PostRes, error) {
 	var (
 		opName = "PostRepository-GetDetail"
-		query  = r.DB.WithContext(ctx)
+		query  = conn(ctx, r.DB)
 		post   = models.Post{}
 		column = "*"
 	)
@@ -112,6 +131,9 @@ func (r *PostRepo) GetDetail(ctx context.Context, req dto.PostGetReq) (*dto.Post
 	if req.ID != 0 {
 		query = query.Where("id = ?", req.ID)
 	}
+	if req.IsLock {
+		query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
 
 	err := query.Select(column).First(&post).Error
 	if err != nil {
@@ -123,12 +145,7 @@ func (r *PostRepo) GetDetail(ctx context.Context, req dto.PostGetReq) (*dto.Post
 		return nil, helpers.ErrDB()
 	}
 
-	result := &dto.PostRes{
-		ID:      post.ID,
-		Title:   post.Title,
-		Content: post.Content,
-	}
-
+	result := newPostRes(post)
 	if column != "*" {
 		return result, nil
 	}
@@ -142,217 +159,306 @@ func (r *PostRepo) GetDetail(ctx context.Context, req dto.PostGetReq) (*dto.Post
 	return result, nil
 }
 
-func (r *PostRepo) GetDetailTag(ctx context.Context, req dto.TagGetReq) (*models.Tag, error) {
+func (r *PostRepo) Create(ctx context.Context, req dto.PostCreateReq) (*dto.PostRes, error) {
 	var (
-		opName = "PostRepository-GetDetailTag"
-		query  = r.DB.WithContext(ctx)
-		result = models.Tag{}
-		column = "*"
+		opName = "PostRepository-Create"
+		post   models.Post
 	)
 
-	err := req.Validate()
-	if err != nil {
-		r.Logger.Errorf("%s validate params: %v \n", opName, err)
-		return nil, err
-	}
+	err := WithTx(ctx, r.DB, func(ctx context.Context, trx *gorm.DB) error {
+		var err error
+		post, err = newPostModel(req)
+		if err != nil {
+			r.Logger.Errorf("%s failed render content: %v \n", opName, err)
+			return err
+		}
 
-	if req.ColumnCustom != "" {
-		column = req.ColumnCustom
-	}
+		err = trx.Clauses(clause.Returning{}).Create(&post).Error
+		if err != nil {
+			r.Logger.Errorf("%s failed create data: %v \n", opName, err)
+			return err
+		}
 
-	if req.ID != 0 {
-		query = query.Where("id = ?", req.ID)
-	}
-	if req.Label != "" {
-		query = query.Where("label = ?", req.Label)
-	}
+		post.Slug, err = assignSlug(trx, post.ID, post.Title)
+		if err != nil {
+			r.Logger.Errorf("%s failed assign slug: %v \n", opName, err)
+			return err
+		}
 
-	err = query.Select(column).First(&result).Error
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, nil
+		err = r.createPostTag(trx, post.ID, req.Tags)
+		if err != nil {
+			return err
 		}
 
-		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
-		return nil, helpers.ErrDB()
+		after, err := postSnapshots(trx, []uint64{post.ID}, false)
+		if err != nil {
+			r.Logger.Errorf("%s failed get snapshot: %v \n", opName, err)
+			return err
+		}
+		return writePostEvents(trx, dto.PostEventCreated, []uint64{post.ID}, nil, after)
+	})
+	if err != nil {
+		return nil, toRespErr(err, helpers.ErrCreatedDB())
 	}
 
-	return &result, nil
+	result := newPostRes(post)
+	result.Tags = req.Tags
+	return result, nil
 }
 
-func (r *PostRepo) trxEnd(trx *gorm.DB, err error) {
-	if rc := recover(); rc != nil {
-		r.Logger.Errorf(`trxEnd Panic Error %v`, r)
-		trx.Rollback()
-		return
-	}
-	if err != nil {
-		r.Logger.Errorf(`trxEnd Error %v`, err)
-		trx.Rollback()
-		return
-	}
-	if err := trx.Commit(); err != nil {
-		r.Logger.Errorf(`trxEnd err commit %v`, err)
-		trx.Rollback()
-		return
-	}
-}
+func (r *PostRepo) DeleteByID(ctx context.Context, postID uint64) error {
+	opName := "PostRepository-DeleteByID"
 
-func (r *PostRepo) Create(ctx context.Context, req dto.PostCreateReq) (*dto.PostRes, error) {
-	var (
-		opName = "PostRepository-Create"
-		err    error
-		trx    *gorm.DB
-		post   = models.Post{
-			Title:   req.Title,
-			Content: req.Content,
+	err := WithTx(ctx, r.DB, func(ctx context.Context, trx *gorm.DB) error {
+		before, err := postSnapshots(trx, []uint64{postID}, true)
+		if err != nil {
+			r.Logger.Errorf("%s failed get data post: %v \n", opName, err)
+			return err
+		}
+		if before[postID] == nil {
+			return helpers.ErrNotFound()
 		}
-	)
-
-	trx = r.DB.Begin().WithContext(ctx)
-	defer func() {
-		r.trxEnd(trx, err)
-	}()
 
-	err = trx.Clauses(clause.Returning{}).Create(&post).Error
-	if err != nil {
-		r.Logger.Errorf("%s failed create data: %v \n", opName, err)
-		return nil, err
-	}
+		err = trx.Where("post_id = ?", postID).Delete(&models.PostTag{}).Error
+		if err != nil {
+			r.Logger.Errorf("%s failed delete data post-tag: %v \n", opName, err)
+			return err
+		}
 
-	for _, val := range req.Tags {
-		_, err = r.createPostTag(ctx, trx, post.ID, models.Tag{Label: val})
+		err = trx.Where("id = ?", postID).Delete(&models.Post{}).Error
 		if err != nil {
-			r.Logger.Errorf("%s failed create post_tag: %v \n", opName, err)
-			return nil, err
+			r.Logger.Errorf("%s failed delete data post: %v \n", opName, err)
+			return err
 		}
 
-	}
+		return writePostEvents(trx, dto.PostEventDeleted, []uint64{postID}, before, nil)
+	})
 
-	result := &dto.PostRes{
-		ID:      post.ID,
-		Title:   post.Title,
-		Content: post.Content,
-		Tags:    req.Tags,
-	}
-	return result, nil
+	return toRespErr(err, helpers.ErrDB())
 }
 
-func (r *PostRepo) DeleteByID(ctx context.Context, postID uint64) error {
-	var (
-		opName = "PostRepository-DeleteByID"
-		err    error
-		trx    *gorm.DB
-	)
+func (r *PostRepo) UpdateByID(ctx context.Context, req dto.PostUpdateReq) error {
+	opName := "PostRepository-UpdateByID"
+
+	err := WithTx(ctx, r.DB, func(ctx context.Context, trx *gorm.DB) error {
+		before, err := postSnapshots(trx, []uint64{req.ID}, true)
+		if err != nil {
+			r.Logger.Errorf("%s failed get data post: %v \n", opName, err)
+			return err
+		}
+		if before[req.ID] == nil {
+			return helpers.ErrNotFound()
+		}
+
+		post := models.Post{
+			ID:            req.ID,
+			Title:         req.Title,
+			Content:       req.Content,
+			ContentFormat: req.ContentFormat,
+		}
+		err = renderContent(&post)
+		if err != nil {
+			r.Logger.Errorf("%s failed render content: %v \n", opName, err)
+			return err
+		}
+
+		// a map, updating with a struct skips its zero values and would
+		// keep the old rendering of a content now rendered empty
+		err = trx.Model(&models.Post{}).Where("id = ?", req.ID).Updates(map[string]interface{}{
+			"title":          post.Title,
+			"content":        post.Content,
+			"content_format": post.ContentFormat,
+			"content_html":   post.ContentHTML,
+			"content_text":   post.ContentText,
+			"updated_at":     time.Now(),
+		}).Error
+		if err != nil {
+			r.Logger.Errorf("%s failed update data post: %v \n", opName, err)
+			return err
+		}
+
+		_, err = assignSlug(trx, req.ID, req.Title)
+		if err != nil {
+			r.Logger.Errorf("%s failed assign slug: %v \n", opName, err)
+			return err
+		}
+
+		err = trx.Where("post_id = ?", req.ID).Delete(&models.PostTag{}).Error
+		if err != nil {
+			r.Logger.Errorf("%s failed delete data post-tag: %v \n", opName, err)
+			return err
+		}
+
+		err = r.createPostTag(trx, req.ID, req.Tags)
+		if err != nil {
+			return err
+		}
 
-	_, err = r.GetDetail(ctx, dto.PostGetReq{
-		ID:           postID,
-		ColumnCustom: "id",
+		after, err := postSnapshots(trx, []uint64{req.ID}, false)
+		if err != nil {
+			r.Logger.Errorf("%s failed get snapshot: %v \n", opName, err)
+			return err
+		}
+		return writePostEvents(trx, dto.PostEventUpdated, []uint64{req.ID}, before, after)
 	})
-	if err != nil {
-		r.Logger.Errorf("%s failed get data post: %v \n", opName, err)
-		return err
-	}
 
-	trx = r.DB.Begin().WithContext(ctx)
-	defer r.trxEnd(trx, err)
+	return toRespErr(err, helpers.ErrUpdatedDB())
+}
+
+func (r *PostRepo) UpdateStatus(ctx context.Context, req dto.PostStatusReq) error {
+	opName := "PostRepository-UpdateStatus"
 
-	err = trx.Where("post_id = ?", postID).Delete(&models.PostTag{}).Error
-	if err != nil {
-		r.Logger.Errorf("%s failed delete data post-tag: %v \n", opName, err)
-		return helpers.ErrDB()
-	}
+	err := WithTx(ctx, r.DB, func(ctx context.Context, trx *gorm.DB) error {
+		ids := []uint64{req.ID}
+		before, err := postSnapshots(trx, ids, true)
+		if err != nil {
+			return err
+		}
 
-	err = trx.Where("id = ?", postID).Delete(&models.Post{}).Error
+		err = trx.Model(&models.Post{}).Where("id = ?", req.ID).Updates(map[string]interface{}{
+			"status":     req.Status,
+			"publish_at": req.PublishAt,
+			"updated_at": time.Now(),
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		after, err := postSnapshots(trx, ids, false)
+		if err != nil {
+			return err
+		}
+		return writePostEvents(trx, dto.PostEventUpdated, ids, before, after)
+	})
 	if err != nil {
-		r.Logger.Errorf("%s failed delete data post: %v \n", opName, err)
-		return helpers.ErrDB()
+		r.Logger.Errorf("%s failed update data post: %v \n", opName, err)
+		return toRespErr(err, helpers.ErrUpdatedDB())
 	}
 
 	return nil
 }
 
-func (r *PostRepo) UpdateByID(ctx context.Context, req dto.PostUpdateReq) error {
+// PublishDue publishes every scheduled post whose publish_at is not after
+// now and returns their ids. The rows are claimed with SKIP LOCKED, so
+// schedulers running on several instances never publish a post twice.
+func (r *PostRepo) PublishDue(ctx context.Context, now time.Time) ([]uint64, error) {
 	var (
-		opName = "PostRepository-UpdateByID"
-		err    error
-		trx    *gorm.DB
+		opName = "PostRepository-PublishDue"
+		result = []uint64{}
 	)
 
-	_, err = r.GetDetail(ctx, dto.PostGetReq{
-		ID:           req.ID,
-		ColumnCustom: "id",
+	err := WithTx(ctx, r.DB, func(ctx context.Context, trx *gorm.DB) error {
+		result = []uint64{}
+		err := trx.Model(&models.Post{}).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND publish_at <= ?", dto.PostStatusScheduled, now).
+			Order("id").
+			Pluck("id", &result).Error
+		if err != nil || len(result) == 0 {
+			return err
+		}
+
+		before, err := postSnapshots(trx, result, false)
+		if err != nil {
+			return err
+		}
+
+		err = trx.Model(&models.Post{}).
+			Where("id IN ?", result).
+			Updates(map[string]interface{}{
+				"status":     dto.PostStatusPublished,
+				"updated_at": now,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		after, err := postSnapshots(trx, result, false)
+		if err != nil {
+			return err
+		}
+		return writePostEvents(trx, dto.PostEventUpdated, result, before, after)
 	})
 	if err != nil {
-		r.Logger.Errorf("%s failed get data post: %v \n", opName, err)
-		return err
+		r.Logger.Errorf("%s failed update data post: %v \n", opName, err)
+		return nil, helpers.ErrUpdatedDB()
 	}
 
-	trx = r.DB.Begin().WithContext(ctx)
-	defer r.trxEnd(trx, err)
+	return result, nil
+}
 
-	err = trx.Model(&models.Post{}).Where("id = ?", req.ID).Updates(&models.Post{
-		ID:      req.ID,
-		Title:   req.Title,
-		Content: req.Content,
-	}).Error
-	if err != nil {
-		r.Logger.Errorf("%s failed update data post: %v \n", opName, err)
-		return helpers.ErrUpdatedDB()
+// newPostModel returns the post to insert for req, with its content
+// rendered.
+func newPostModel(req dto.PostCreateReq) (models.Post, error) {
+	post := models.Post{
+		Title:         req.Title,
+		Content:       req.Content,
+		ContentFormat: req.ContentFormat,
+		Status:        req.Status,
+		PublishAt:     req.PublishAt,
 	}
 
-	err = trx.Where("post_id = ?", req.ID).Delete(&models.PostTag{}).Error
-	if err != nil {
-		r.Logger.Errorf("%s failed delete data post-tag: %v \n", opName, err)
-		return helpers.ErrUpdatedDB()
+	err := renderContent(&post)
+	return post, err
+}
+
+// renderContent fills the rendered content of post from its source.
+func renderContent(post *models.Post) error {
+	if post.ContentFormat == "" {
+		post.ContentFormat = render.FormatPlain
 	}
 
-	for _, val := range req.Tags {
-		_, err = r.createPostTag(ctx, trx, req.ID, models.Tag{Label: val})
-		if err != nil {
-			r.Logger.Errorf("%s failed create post_tag: %v \n", opName, err)
-			return err
-		}
+	res, err := render.Content(post.ContentFormat, post.Content)
+	if err != nil {
+		return err
 	}
 
+	post.ContentHTML, post.ContentText = res.HTML, res.Text
 	return nil
 }
 
-func (r *PostRepo) createPostTag(ctx context.Context, trx *gorm.DB, postID uint64, req models.Tag) (*models.Tag, error) {
+func newPostRes(post models.Post) *dto.PostRes {
+	return &dto.PostRes{
+		ID:      post.ID,
+		Title:   post.Title,
+		Slug:    post.Slug,
+		Content: post.Content,
+		Status:  post.Status,
+
+		ContentFormat: post.ContentFormat,
+		ContentHTML:   post.ContentHTML,
+		ContentText:   post.ContentText,
+		PublishAt:     post.PublishAt,
+		CreatedAt:     post.CreatedAt,
+		UpdatedAt:     post.UpdatedAt,
+	}
+}
+
+// createPostTag links the post to its tags inside trx, it returns the
+// database error as is so WithTx can retry it. Tags are resolved with an
+// upsert so concurrent posts creating the same new tag do not race on the
+// unique label, a synonym links the post to its tag.
+func (r *PostRepo) createPostTag(trx *gorm.DB, postID uint64, labels []string) error {
 	var (
 		opName = "PostRepository-createPostTag"
 		err    error
 	)
-
-	tag, err := r.GetDetailTag(ctx, dto.TagGetReq{
-		ColumnCustom: "id",
-		Label:        req.Label,
-	})
-	if err != nil {
-		r.Logger.Errorf("%s failed get data tags: %v \n", opName, err)
-		return nil, err
+	if len(labels) == 0 {
+		return nil
 	}
 
-	isExist := tag != nil && tag.ID > 0
-	if !isExist {
-		tag = &models.Tag{
-			Label: req.Label,
-		}
-		err = trx.Clauses(clause.Returning{}).Create(tag).Error
-		if err != nil {
-			r.Logger.Errorf("%s failed create data tags: %v \n", opName, err)
-			return nil, helpers.ErrDB()
-		}
+	tagIDs, err := upsertTags(trx, labels)
+	if err != nil {
+		r.Logger.Errorf("%s failed upsert data tags: %v \n", opName, err)
+		return err
 	}
 
-	err = trx.Create(&models.PostTag{
-		PostID: postID,
-		TagID:  tag.ID,
-	}).Error
+	postTags := newPostTags(postID, labels, tagIDs)
+	err = trx.Clauses(clause.OnConflict{DoNothing: true}).Create(&postTags).Error
 	if err != nil {
 		r.Logger.Errorf("%s failed create data post-tag: %v \n", opName, err)
-		return nil, helpers.ErrDB()
+		return err
 	}
 
-	return tag, nil
+	return nil
 }
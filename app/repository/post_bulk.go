@@ -0,0 +1,345 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *PostRepo) batchSize() int {
+	if r.Cfg.DB.BatchSize <= 0 {
+		return 100
+	}
+	return r.Cfg.DB.BatchSize
+}
+
+// CreateBulk inserts posts in batches. When atomic every batch shares one
+// transaction and any failure rolls back all items, otherwise each batch has
+// its own transaction and each item its own savepoint so a bad item only
+// fails itself.
+func (r *PostRepo) CreateBulk(ctx context.Context, req []dto.PostCreateReq, atomic bool) ([]dto.PostBulkItemRes, error) {
+	var (
+		opName    = "PostRepository-CreateBulk"
+		batchSize = r.batchSize()
+		result    = newBulkResult(0, len(req))
+		err       error
+	)
+
+	if atomic {
+		var attempt []dto.PostBulkItemRes
+		err = WithTx(ctx, r.DB, func(ctx context.Context, trx *gorm.DB) error {
+			attempt = newBulkResult(0, len(req))
+			for start := 0; start < len(req); start += batchSize {
+				end := min(start+batchSize, len(req))
+				err := r.createBatch(trx, req[start:end], attempt[start:end])
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			r.Logger.Errorf("%s failed create data: %v \n", opName, err)
+			for i := range result {
+				result[i].ID = 0
+				result[i].SetError(err, helpers.ErrCreatedDB())
+			}
+			return result, helpers.ErrCreatedDB()
+		}
+		return attempt, nil
+	}
+
+	for start := 0; start < len(req); start += batchSize {
+		end := min(start+batchSize, len(req))
+		var attempt []dto.PostBulkItemRes
+		err = WithTx(ctx, r.DB, func(ctx context.Context, trx *gorm.DB) error {
+			attempt = newBulkResult(start, end)
+			return r.createBatchPartial(ctx, trx, req[start:end], attempt)
+		})
+		if err != nil {
+			r.Logger.Errorf("%s failed create batch %d-%d: %v \n", opName, start, end, err)
+			for i := start; i < end; i++ {
+				result[i].SetError(err, helpers.ErrCreatedDB())
+			}
+			continue
+		}
+		copy(result[start:end], attempt)
+	}
+
+	return result, nil
+}
+
+func (r *PostRepo) createBatch(trx *gorm.DB, req []dto.PostCreateReq, result []dto.PostBulkItemRes) error {
+	tagIDs, err := upsertTags(trx, collectLabels(req))
+	if err != nil {
+		return err
+	}
+
+	posts := make([]models.Post, 0, len(req))
+	for _, v := range req {
+		post, err := newPostModel(v)
+		if err != nil {
+			return err
+		}
+		posts = append(posts, post)
+	}
+	err = trx.Clauses(clause.Returning{}).Create(&posts).Error
+	if err != nil {
+		return err
+	}
+
+	for i := range posts {
+		posts[i].Slug, err = assignSlug(trx, posts[i].ID, posts[i].Title)
+		if err != nil {
+			return err
+		}
+	}
+
+	var (
+		postTags = []models.PostTag{}
+		ids      = make([]uint64, 0, len(posts))
+	)
+	for i, v := range req {
+		postTags = append(postTags, newPostTags(posts[i].ID, v.Tags, tagIDs)...)
+		ids = append(ids, posts[i].ID)
+	}
+	if len(postTags) > 0 {
+		err = trx.Create(&postTags).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	after, err := postSnapshots(trx, ids, false)
+	if err != nil {
+		return err
+	}
+	err = writePostEvents(trx, dto.PostEventCreated, ids, nil, after)
+	if err != nil {
+		return err
+	}
+
+	for i := range req {
+		result[i].ID = posts[i].ID
+		result[i].Status = dto.BulkStatusSuccess
+	}
+	return nil
+}
+
+func (r *PostRepo) createBatchPartial(ctx context.Context, trx *gorm.DB, req []dto.PostCreateReq, result []dto.PostBulkItemRes) error {
+	opName := "PostRepository-createBatchPartial"
+
+	tagIDs, err := upsertTags(trx, collectLabels(req))
+	if err != nil {
+		return err
+	}
+
+	for i, v := range req {
+		post, err := newPostModel(v)
+		if err != nil {
+			r.Logger.Errorf("%s failed render item %d: %v \n", opName, result[i].Index, err)
+			result[i].SetError(err, helpers.ErrCreatedDB())
+			continue
+		}
+
+		// a nested WithTx is a savepoint, rolled back alone on failure
+		err = WithTx(ctx, r.DB, func(ctx context.Context, sp *gorm.DB) error {
+			err := sp.Clauses(clause.Returning{}).Create(&post).Error
+			if err != nil {
+				return err
+			}
+
+			post.Slug, err = assignSlug(sp, post.ID, post.Title)
+			if err != nil {
+				return err
+			}
+
+			postTags := newPostTags(post.ID, v.Tags, tagIDs)
+			if len(postTags) > 0 {
+				err = sp.Create(&postTags).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			after, err := postSnapshots(sp, []uint64{post.ID}, false)
+			if err != nil {
+				return err
+			}
+			return writePostEvents(sp, dto.PostEventCreated, []uint64{post.ID}, nil, after)
+		})
+		if err != nil {
+			r.Logger.Errorf("%s failed create item %d: %v \n", opName, result[i].Index, err)
+			result[i].SetError(err, helpers.ErrCreatedDB())
+			continue
+		}
+
+		result[i].ID = post.ID
+		result[i].Status = dto.BulkStatusSuccess
+	}
+
+	return nil
+}
+
+// DeleteBulk deletes posts with their post_tag rows in batches. Unknown IDs
+// are reported as not found, when atomic they also roll back the others.
+func (r *PostRepo) DeleteBulk(ctx context.Context, postIDs []uint64, atomic bool) ([]dto.PostBulkItemRes, error) {
+	var (
+		opName    = "PostRepository-DeleteBulk"
+		batchSize = r.batchSize()
+		result    = newBulkResult(0, len(postIDs))
+		err       error
+	)
+
+	if atomic {
+		var (
+			attempt   []dto.PostBulkItemRes
+			errMissed = helpers.ErrNotFound()
+		)
+		err = WithTx(ctx, r.DB, func(ctx context.Context, trx *gorm.DB) error {
+			attempt = newBulkResult(0, len(postIDs))
+			for start := 0; start < len(postIDs); start += batchSize {
+				end := min(start+batchSize, len(postIDs))
+				err := r.deleteBatch(trx, postIDs[start:end], attempt[start:end])
+				if err != nil {
+					return err
+				}
+			}
+			if hasBulkFailed(attempt) {
+				return errMissed
+			}
+			return nil
+		})
+		if err == errMissed {
+			// the run reporting the not found items is the last one
+			result = attempt
+		}
+		if err != nil {
+			r.Logger.Errorf("%s failed delete data: %v \n", opName, err)
+			for i := range result {
+				result[i].ID = postIDs[i]
+				if result[i].Status == dto.BulkStatusFailed && result[i].Error != nil {
+					continue
+				}
+				result[i].SetError(nil, dto.ErrBulkCanceled())
+			}
+			return result, err
+		}
+		return attempt, nil
+	}
+
+	for start := 0; start < len(postIDs); start += batchSize {
+		end := min(start+batchSize, len(postIDs))
+		var attempt []dto.PostBulkItemRes
+		err = WithTx(ctx, r.DB, func(ctx context.Context, trx *gorm.DB) error {
+			attempt = newBulkResult(start, end)
+			return r.deleteBatch(trx, postIDs[start:end], attempt)
+		})
+		if err != nil {
+			r.Logger.Errorf("%s failed delete batch %d-%d: %v \n", opName, start, end, err)
+			for i := start; i < end; i++ {
+				result[i].ID = postIDs[i]
+				result[i].SetError(err, helpers.ErrDB())
+			}
+			continue
+		}
+		copy(result[start:end], attempt)
+	}
+
+	return result, nil
+}
+
+func (r *PostRepo) deleteBatch(trx *gorm.DB, postIDs []uint64, result []dto.PostBulkItemRes) error {
+	before, err := postSnapshots(trx, postIDs, true)
+	if err != nil {
+		return err
+	}
+
+	err = trx.Where("post_id IN ?", postIDs).Delete(&models.PostTag{}).Error
+	if err != nil {
+		return err
+	}
+
+	deleted := []models.Post{}
+	err = trx.Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+		Where("id IN ?", postIDs).
+		Delete(&deleted).Error
+	if err != nil {
+		return err
+	}
+
+	isDeleted := map[uint64]bool{}
+	for _, v := range deleted {
+		isDeleted[v.ID] = true
+	}
+	err = writePostEvents(trx, dto.PostEventDeleted, uniqueDeleted(postIDs, isDeleted), before, nil)
+	if err != nil {
+		return err
+	}
+
+	for i, id := range postIDs {
+		result[i].ID = id
+		if !isDeleted[id] {
+			result[i].SetError(nil, helpers.ErrNotFound())
+			continue
+		}
+		result[i].Status = dto.BulkStatusSuccess
+	}
+	return nil
+}
+
+// uniqueDeleted returns the deleted ids once each, in the order asked.
+func uniqueDeleted(postIDs []uint64, isDeleted map[uint64]bool) []uint64 {
+	var (
+		result = []uint64{}
+		isSeen = map[uint64]bool{}
+	)
+	for _, id := range postIDs {
+		if isDeleted[id] && !isSeen[id] {
+			isSeen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// newBulkResult returns the items from start to end of a bulk request. A
+// transaction fills a new one on every run, so a retry does not keep the
+// items of the run it replaces.
+func newBulkResult(start, end int) []dto.PostBulkItemRes {
+	result := make([]dto.PostBulkItemRes, end-start)
+	for i := range result {
+		result[i].Index = start + i
+	}
+	return result
+}
+
+func hasBulkFailed(result []dto.PostBulkItemRes) bool {
+	for _, v := range result {
+		if v.Status == dto.BulkStatusFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// collectLabels returns the unique labels of all posts.
+func collectLabels(req []dto.PostCreateReq) []string {
+	isExist := map[string]bool{}
+	result := []string{}
+	for _, v := range req {
+		for _, label := range v.Tags {
+			if isExist[label] {
+				continue
+			}
+			isExist[label] = true
+			result = append(result, label)
+		}
+	}
+
+	return result
+}
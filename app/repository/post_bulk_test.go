@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/driver"
+)
+
+func countBulkStatus(items []dto.PostBulkItemRes) (success, failed int) {
+	for _, v := range items {
+		if v.Status == dto.BulkStatusSuccess {
+			success++
+			continue
+		}
+		failed++
+	}
+	return success, failed
+}
+
+func TestPostRepo_CreateBulk(t *testing.T) {
+	var (
+		db   = openTestDB(t, "DB_TEST_DSN")
+		cfg  = testConfigs()
+		repo = NewPostRepository(db, cfg, driver.Logger(cfg))
+		ctx  = context.Background()
+		good = dto.PostCreateReq{Title: "good", Content: "good", Tags: []string{"go"}}
+		// postgres rejects a NUL byte in text
+		bad = dto.PostCreateReq{Title: "bad", Content: "bad", Tags: []string{"bad\x00tag"}}
+	)
+
+	tests := []struct {
+		name        string
+		req         []dto.PostCreateReq
+		atomic      bool
+		wantSuccess int
+		wantFailed  int
+		wantPosts   int64
+		wantErr     bool
+	}{
+		{
+			name:        "atomic",
+			req:         []dto.PostCreateReq{good, good},
+			atomic:      true,
+			wantSuccess: 2,
+			wantPosts:   2,
+		},
+		{
+			name:       "atomic rolls back every item",
+			req:        []dto.PostCreateReq{good, bad},
+			atomic:     true,
+			wantFailed: 2,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db.Exec("TRUNCATE post_tag, tag, outbox, post_slug, post RESTART IDENTITY CASCADE")
+
+			got, err := repo.CreateBulk(ctx, tt.req, tt.atomic)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CreateBulk() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			success, failed := countBulkStatus(got)
+			if success != tt.wantSuccess || failed != tt.wantFailed {
+				t.Errorf("CreateBulk() success = %d failed = %d, want %d and %d", success, failed, tt.wantSuccess, tt.wantFailed)
+			}
+			for i, v := range got {
+				if v.Index != i || (v.Status == dto.BulkStatusSuccess) != (v.ID != 0) {
+					t.Errorf("CreateBulk() item %d = %+v", i, v)
+				}
+			}
+
+			var posts int64
+			db.Model(&models.Post{}).Count(&posts)
+			if posts != tt.wantPosts {
+				t.Errorf("posts = %d, want %d", posts, tt.wantPosts)
+			}
+		})
+	}
+}
+
+func TestPostRepo_CreateBulkPartial(t *testing.T) {
+	var (
+		db   = openTestDB(t, "DB_TEST_DSN")
+		cfg  = testConfigs()
+		repo = NewPostRepository(db, cfg, driver.Logger(cfg))
+		ctx  = context.Background()
+	)
+
+	got, err := repo.CreateBulk(ctx, []dto.PostCreateReq{
+		{Title: "good", Content: "good", Tags: []string{"go"}},
+		{Title: "bad", Content: "bad\x00content"},
+		{Title: "also good", Content: "good"},
+	}, false)
+	if err != nil {
+		t.Fatalf("CreateBulk() error = %v", err)
+	}
+
+	wantStatus := []string{dto.BulkStatusSuccess, dto.BulkStatusFailed, dto.BulkStatusSuccess}
+	for i, v := range got {
+		if v.Status != wantStatus[i] {
+			t.Errorf("CreateBulk() item %d status = %v, want %v", i, v.Status, wantStatus[i])
+		}
+	}
+	if got[1].ID != 0 || got[1].Error == nil {
+		t.Errorf("CreateBulk() failed item = %+v, want no id and an error", got[1])
+	}
+
+	var posts, events int64
+	db.Model(&models.Post{}).Count(&posts)
+	db.Model(&models.OutboxEvent{}).Where("aggregate_type = ?", dto.AggregatePost).Count(&events)
+	if posts != 2 || events != 2 {
+		t.Errorf("posts = %d, events = %d, want 2 and 2", posts, events)
+	}
+}
+
+func TestPostRepo_DeleteBulk(t *testing.T) {
+	var (
+		db   = openTestDB(t, "DB_TEST_DSN")
+		cfg  = testConfigs()
+		repo = NewPostRepository(db, cfg, driver.Logger(cfg))
+		ctx  = context.Background()
+	)
+
+	tests := []struct {
+		name        string
+		atomic      bool
+		missing     bool
+		wantSuccess int
+		wantFailed  int
+		wantPosts   int64
+		wantErr     bool
+	}{
+		{
+			name:        "atomic",
+			atomic:      true,
+			wantSuccess: 2,
+		},
+		{
+			name:       "atomic rolls back on a missing id",
+			atomic:     true,
+			missing:    true,
+			wantFailed: 3,
+			wantPosts:  2,
+			wantErr:    true,
+		},
+		{
+			name:        "partial reports the missing id",
+			missing:     true,
+			wantSuccess: 2,
+			wantFailed:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db.Exec("TRUNCATE post_tag, tag, outbox, post_slug, post RESTART IDENTITY CASCADE")
+
+			ids := []uint64{}
+			for _, title := range []string{"one", "two"} {
+				post, err := repo.Create(ctx, dto.PostCreateReq{Title: title, Content: title, Tags: []string{"go"}})
+				if err != nil {
+					t.Fatalf("Create() error = %v", err)
+				}
+				ids = append(ids, post.ID)
+			}
+			if tt.missing {
+				ids = append(ids, ids[1]+100)
+			}
+
+			got, err := repo.DeleteBulk(ctx, ids, tt.atomic)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DeleteBulk() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			success, failed := countBulkStatus(got)
+			if success != tt.wantSuccess || failed != tt.wantFailed {
+				t.Errorf("DeleteBulk() success = %d failed = %d, want %d and %d", success, failed, tt.wantSuccess, tt.wantFailed)
+			}
+
+			var posts, postTags int64
+			db.Model(&models.Post{}).Count(&posts)
+			db.Model(&models.PostTag{}).Count(&postTags)
+			if posts != tt.wantPosts || postTags != tt.wantPosts {
+				t.Errorf("posts = %d, post_tag rows = %d, want %d", posts, postTags, tt.wantPosts)
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/cache"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/database"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	postCachePrefix  = "post:"
+	postCacheListKey = postCachePrefix + "list"
+)
+
+func postCacheDetailKey(postID uint64) string {
+	return fmt.Sprintf("%sdetail:%d", postCachePrefix, postID)
+}
+
+// PostCachePurger is implemented by the post repositories that cache, such
+// as PostCacheRepo. TagSrv.Merge asserts the post repository to it, since a
+// merge retags posts it cannot list. Purge drops every cached post detail
+// and list, and bumps the cache generation so a load already in flight does
+// not store what it read before.
+type PostCachePurger interface {
+	Purge(ctx context.Context) error
+}
+
+// PostCacheRepo decorates a PostRepository, caching post list and detail
+// responses. Methods it does not override go straight to the wrapped repo.
+type PostCacheRepo struct {
+	PostRepository
+	Cache  cache.Cache
+	TTL    time.Duration
+	Logger *logrus.Logger
+	group  singleflight.Group
+	// gen counts the invalidations, a load that saw it change may hold old
+	// rows and must not stay in the cache.
+	gen atomic.Uint64
+}
+
+func NewPostCacheRepository(
+	next PostRepository,
+	c cache.Cache,
+	cfg *configs.Configs,
+	logger *logrus.Logger,
+) PostRepository {
+	return &PostCacheRepo{
+		PostRepository: next,
+		Cache:          c,
+		TTL:            time.Duration(cfg.Cache.TTL) * time.Second,
+		Logger:         logger,
+	}
+}
+
+// remember returns the cached value of key or loads it once, even when many
+// requests miss the same key at the same time. Loads always read the primary
+// so a lagging replica can not put stale data in the cache. The load outlives
+// the caller starting it, the others waiting on it must not fail when that
+// one goes away.
+func (r *PostCacheRepo) remember(ctx context.Context, key string, load func(ctx context.Context) (interface{}, error)) ([]byte, error) {
+	opName := "PostCacheRepository-remember"
+
+	value, err := r.Cache.Get(ctx, key)
+	if err == nil {
+		return value, nil
+	}
+	if !errors.Is(err, cache.ErrMiss) {
+		r.Logger.Errorf("%s failed get cache %s: %v \n", opName, key, err)
+	}
+
+	loadCtx := context.WithoutCancel(ctx)
+	ch := r.group.DoChan(key, func() (interface{}, error) {
+		gen := r.gen.Load()
+		data, err := load(database.WithReadPrimary(loadCtx))
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+
+		r.store(loadCtx, key, value, gen)
+		return value, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.([]byte), nil
+	}
+}
+
+// store caches value of key unless an invalidation came after gen, when the
+// load may have read the rows before the write. An invalidation between the
+// check and the set is caught by the check after it.
+func (r *PostCacheRepo) store(ctx context.Context, key string, value []byte, gen uint64) {
+	opName := "PostCacheRepository-store"
+	if r.gen.Load() != gen {
+		return
+	}
+
+	if err := r.Cache.Set(ctx, key, value, r.TTL); err != nil {
+		r.Logger.Errorf("%s failed set cache %s: %v \n", opName, key, err)
+		return
+	}
+
+	if r.gen.Load() != gen {
+		if err := r.Cache.Delete(ctx, key); err != nil {
+			r.Logger.Errorf("%s failed delete cache %s: %v \n", opName, key, err)
+		}
+	}
+}
+
+func (r *PostCacheRepo) GetAll(ctx context.Context) (result []dto.PostRes, err error) {
+	if isInTx(ctx) {
+		return r.PostRepository.GetAll(ctx)
+	}
+
+	value, err := r.remember(ctx, postCacheListKey, func(ctx context.Context) (interface{}, error) {
+		return r.PostRepository.GetAll(ctx)
+	})
+	if err != nil {
+		return result, err
+	}
+
+	// every caller decodes its own copy, services mutate responses in place
+	err = json.Unmarshal(value, &result)
+	return result, err
+}
+
+func (r *PostCacheRepo) GetDetail(ctx context.Context, req dto.PostGetReq) (*dto.PostRes, error) {
+	// a transaction must see its own rows and take its locks
+	if req.ColumnCustom != "" || req.IsLock || isInTx(ctx) {
+		return r.PostRepository.GetDetail(ctx, req)
+	}
+
+	value, err := r.remember(ctx, postCacheDetailKey(req.ID), func(ctx context.Context) (interface{}, error) {
+		return r.PostRepository.GetDetail(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.PostRes{}
+	err = json.Unmarshal(value, result)
+	return result, err
+}
+
+func (r *PostCacheRepo) Create(ctx context.Context, req dto.PostCreateReq) (*dto.PostRes, error) {
+	result, err := r.PostRepository.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	r.invalidate(ctx, postCacheListKey)
+	return result, nil
+}
+
+func (r *PostCacheRepo) DeleteByID(ctx context.Context, postID uint64) error {
+	err := r.PostRepository.DeleteByID(ctx, postID)
+	if err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, postCacheListKey, postCacheDetailKey(postID))
+	return nil
+}
+
+func (r *PostCacheRepo) UpdateByID(ctx context.Context, req dto.PostUpdateReq) error {
+	err := r.PostRepository.UpdateByID(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, postCacheListKey, postCacheDetailKey(req.ID))
+	return nil
+}
+
+func (r *PostCacheRepo) UpdateStatus(ctx context.Context, req dto.PostStatusReq) error {
+	err := r.PostRepository.UpdateStatus(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, postCacheListKey, postCacheDetailKey(req.ID))
+	return nil
+}
+
+func (r *PostCacheRepo) PublishDue(ctx context.Context, now time.Time) ([]uint64, error) {
+	result, err := r.PostRepository.PublishDue(ctx, now)
+	if err != nil || len(result) == 0 {
+		return result, err
+	}
+
+	keys := []string{postCacheListKey}
+	for _, id := range result {
+		keys = append(keys, postCacheDetailKey(id))
+	}
+	r.invalidate(ctx, keys...)
+	return result, nil
+}
+
+func (r *PostCacheRepo) CreateBulk(ctx context.Context, req []dto.PostCreateReq, atomic bool) ([]dto.PostBulkItemRes, error) {
+	result, err := r.PostRepository.CreateBulk(ctx, req, atomic)
+	r.invalidate(ctx, postCacheListKey)
+	return result, err
+}
+
+func (r *PostCacheRepo) DeleteBulk(ctx context.Context, postIDs []uint64, atomic bool) ([]dto.PostBulkItemRes, error) {
+	result, err := r.PostRepository.DeleteBulk(ctx, postIDs, atomic)
+
+	keys := []string{postCacheListKey}
+	for _, v := range result {
+		if v.Status == dto.BulkStatusSuccess {
+			keys = append(keys, postCacheDetailKey(v.ID))
+		}
+	}
+	r.invalidate(ctx, keys...)
+	return result, err
+}
+
+func (r *PostCacheRepo) BackfillSlugs(ctx context.Context) (int, error) {
+	total, err := r.PostRepository.BackfillSlugs(ctx)
+	if total > 0 {
+		r.Purge(ctx)
+	}
+	return total, err
+}
+
+// Purge drops every cached post, used when a change such as a tag rename
+// touches many posts at once.
+func (r *PostCacheRepo) Purge(ctx context.Context) error {
+	r.gen.Add(1)
+	err := r.Cache.DeletePrefix(ctx, postCachePrefix)
+	if err != nil {
+		r.Logger.Errorf("PostCacheRepository-Purge failed delete cache: %v \n", err)
+	}
+	return err
+}
+
+// invalidate drops keys once the write is committed, inside a unit of work
+// an earlier drop could be refilled with the old rows before the commit.
+func (r *PostCacheRepo) invalidate(ctx context.Context, keys ...string) {
+	AfterCommit(ctx, func() {
+		// forget in flight loads too, they may have read the old row
+		r.gen.Add(1)
+		for _, key := range keys {
+			r.group.Forget(key)
+		}
+
+		err := r.Cache.Delete(context.WithoutCancel(ctx), keys...)
+		if err != nil {
+			r.Logger.Errorf("PostCacheRepository-invalidate failed delete cache %v: %v \n", keys, err)
+		}
+	})
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/repository/mocks"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/cache"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/driver"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestPostCacheRepo() (*mocks.PostRepository, PostRepository) {
+	var (
+		cfg  = testConfigs()
+		next = &mocks.PostRepository{}
+	)
+	return next, NewPostCacheRepository(next, cache.NewMemory(10), cfg, driver.Logger(cfg))
+}
+
+func TestPostCacheRepo_GetDetail(t *testing.T) {
+	var (
+		ctx        = context.Background()
+		next, repo = newTestPostCacheRepo()
+		req        = dto.PostGetReq{ID: 1}
+	)
+
+	// the db is slow, so every caller misses the cache at the same time
+	next.On("GetDetail", mock.Anything, req).
+		After(50*time.Millisecond).
+		Return(&dto.PostRes{ID: 1, Title: "title"}, nil).Once()
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := repo.GetDetail(ctx, req)
+			if err != nil || got.ID != 1 {
+				t.Errorf("GetDetail() = %v, %v", got, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if _, err := repo.GetDetail(ctx, req); err != nil {
+		t.Errorf("GetDetail() from cache error = %v", err)
+	}
+	next.AssertNumberOfCalls(t, "GetDetail", 1)
+}
+
+func TestPostCacheRepo_Invalidate(t *testing.T) {
+	var (
+		ctx        = context.Background()
+		next, repo = newTestPostCacheRepo()
+		req        = dto.PostGetReq{ID: 1}
+		update     = dto.PostUpdateReq{ID: 1, Title: "new", Content: "new"}
+	)
+
+	next.On("GetDetail", mock.Anything, req).Return(&dto.PostRes{ID: 1, Title: "old"}, nil).Once()
+	next.On("UpdateByID", mock.Anything, update).Return(nil).Once()
+	next.On("GetDetail", mock.Anything, req).Return(&dto.PostRes{ID: 1, Title: "new"}, nil).Once()
+
+	repo.GetDetail(ctx, req)
+	if err := repo.UpdateByID(ctx, update); err != nil {
+		t.Fatalf("UpdateByID() error = %v", err)
+	}
+
+	got, err := repo.GetDetail(ctx, req)
+	if err != nil || got.Title != "new" {
+		t.Errorf("GetDetail() after update = %v, %v, want title new", got, err)
+	}
+}
+
+func TestPostCacheRepo_InvalidateDuringLoad(t *testing.T) {
+	var (
+		ctx        = context.Background()
+		next, repo = newTestPostCacheRepo()
+		req        = dto.PostGetReq{ID: 1}
+		update     = dto.PostUpdateReq{ID: 1, Title: "new", Content: "new"}
+	)
+
+	// the first load reads the row before the update and ends after it
+	next.On("GetDetail", mock.Anything, req).
+		After(50*time.Millisecond).
+		Return(&dto.PostRes{ID: 1, Title: "old"}, nil).Once()
+	next.On("UpdateByID", mock.Anything, update).Return(nil).Once()
+	next.On("GetDetail", mock.Anything, req).Return(&dto.PostRes{ID: 1, Title: "new"}, nil).Once()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		repo.GetDetail(ctx, req)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	if err := repo.UpdateByID(ctx, update); err != nil {
+		t.Fatalf("UpdateByID() error = %v", err)
+	}
+	<-done
+
+	got, err := repo.GetDetail(ctx, req)
+	if err != nil || got.Title != "new" {
+		t.Errorf("GetDetail() after update = %v, %v, want title new", got, err)
+	}
+}
+
+func TestPostCacheRepo_GetDetailCanceled(t *testing.T) {
+	var (
+		next, repo  = newTestPostCacheRepo()
+		req         = dto.PostGetReq{ID: 1}
+		ctx, cancel = context.WithCancel(context.Background())
+	)
+
+	next.On("GetDetail", mock.Anything, req).
+		After(50*time.Millisecond).
+		Return(func(ctx context.Context, _ dto.PostGetReq) *dto.PostRes {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return &dto.PostRes{ID: 1}
+		}, func(ctx context.Context, _ dto.PostGetReq) error {
+			return ctx.Err()
+		}).Once()
+
+	var (
+		wg    sync.WaitGroup
+		first error
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, first = repo.GetDetail(ctx, req)
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	// the second caller waits on the load of the first, which goes away
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		got, err := repo.GetDetail(context.Background(), req)
+		if err != nil || got.ID != 1 {
+			t.Errorf("GetDetail() of the waiter = %v, %v", got, err)
+		}
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	wg.Wait()
+
+	if first != context.Canceled {
+		t.Errorf("GetDetail() of the canceled caller error = %v, want %v", first, context.Canceled)
+	}
+	next.AssertNumberOfCalls(t, "GetDetail", 1)
+}
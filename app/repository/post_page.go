@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+)
+
+// postTagRow is a tag of the post PostID.
+type postTagRow struct {
+	PostID   uint64
+	ID       uint64
+	Label    string
+	ParentID *uint64
+}
+
+// GetPage returns up to req.First+1 posts older than req.AfterID, newest
+// first, so the caller can tell whether there is a next page. Their tags are
+// left out, see GetTagsByPosts.
+func (r *PostRepo) GetPage(ctx context.Context, req dto.PostPageReq) ([]dto.PostRes, error) {
+	var (
+		opName = "PostRepository-GetPage"
+		query  = conn(ctx, r.DB).Model(&models.Post{})
+		posts  = []models.Post{}
+	)
+
+	if req.AfterID != 0 {
+		query = query.Where("id < ?", req.AfterID)
+	}
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.PublishedAt != nil {
+		query = query.Where("(status = ? OR (status = ? AND publish_at <= ?))",
+			dto.PostStatusPublished, dto.PostStatusScheduled, *req.PublishedAt)
+	}
+	if req.TagIDs != nil {
+		query = query.Where("id IN (SELECT post_id FROM post_tag WHERE tag_id IN ?)", req.TagIDs)
+	}
+
+	err := query.Order("id DESC").Limit(req.First + 1).Find(&posts).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	result := make([]dto.PostRes, 0, len(posts))
+	for _, v := range posts {
+		result = append(result, *newPostRes(v))
+	}
+	return result, nil
+}
+
+// GetTagsByPosts returns the tags of every post of postIDs with one query,
+// sorted by label. Posts without tags are left out.
+func (r *PostRepo) GetTagsByPosts(ctx context.Context, postIDs []uint64) (map[uint64][]models.Tag, error) {
+	var (
+		opName = "PostRepository-GetTagsByPosts"
+		rows   = []postTagRow{}
+		result = map[uint64][]models.Tag{}
+	)
+	if len(postIDs) == 0 {
+		return result, nil
+	}
+
+	err := conn(ctx, r.DB).
+		Raw(`SELECT post_tag.post_id, tag.id, tag.label, tag.parent_id FROM post_tag
+			INNER JOIN tag ON tag.id = post_tag.tag_id
+			WHERE post_tag.post_id IN ?
+			ORDER BY tag.label`, postIDs).
+		Scan(&rows).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	for _, v := range rows {
+		result[v.PostID] = append(result[v.PostID], models.Tag{ID: v.ID, Label: v.Label, ParentID: v.ParentID})
+	}
+	return result, nil
+}
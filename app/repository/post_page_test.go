@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/driver"
+)
+
+func TestPostRepo_GetPage(t *testing.T) {
+	var (
+		db   = openTestDB(t, "DB_TEST_DSN")
+		cfg  = testConfigs()
+		repo = NewPostRepository(db, cfg, driver.Logger(cfg))
+		ctx  = context.Background()
+		ids  = []uint64{}
+	)
+
+	for i, status := range []string{dto.PostStatusPublished, dto.PostStatusDraft, dto.PostStatusPublished, dto.PostStatusPublished} {
+		res, err := repo.Create(ctx, dto.PostCreateReq{
+			Title:   fmt.Sprintf("post %d", i),
+			Content: "paged",
+			Tags:    []string{fmt.Sprintf("tag %d", i%2), "all"},
+			Status:  status,
+		})
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		ids = append(ids, res.ID)
+	}
+
+	now := time.Now()
+	page, err := repo.GetPage(ctx, dto.PostPageReq{First: 1, PublishedAt: &now})
+	if err != nil {
+		t.Fatalf("GetPage() error = %v", err)
+	}
+	if len(page) != 2 || page[0].ID != ids[3] || page[1].ID != ids[2] {
+		t.Errorf("GetPage() first page = %+v", page)
+	}
+
+	page, err = repo.GetPage(ctx, dto.PostPageReq{First: 5, AfterID: ids[3], PublishedAt: &now})
+	if err != nil {
+		t.Fatalf("GetPage() error = %v", err)
+	}
+	if len(page) != 2 || page[0].ID != ids[2] || page[1].ID != ids[0] {
+		t.Errorf("GetPage() after = %+v, drafts are hidden", page)
+	}
+
+	tags, err := repo.GetTagsByPosts(ctx, ids)
+	if err != nil {
+		t.Fatalf("GetTagsByPosts() error = %v", err)
+	}
+	for i, id := range ids {
+		if len(tags[id]) != 2 || tags[id][0].Label != "all" || tags[id][1].Label != fmt.Sprintf("tag %d", i%2) {
+			t.Errorf("GetTagsByPosts() of post %d = %+v", id, tags[id])
+		}
+	}
+
+	page, err = repo.GetPage(ctx, dto.PostPageReq{First: 5, TagIDs: []uint64{tags[ids[1]][1].ID}})
+	if err != nil {
+		t.Fatalf("GetPage() error = %v", err)
+	}
+	if len(page) != 2 || page[0].ID != ids[3] || page[1].ID != ids[1] {
+		t.Errorf("GetPage() by tag = %+v", page)
+	}
+}
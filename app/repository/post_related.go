@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+)
+
+// GetRelated ranks the posts sharing a tag with req.ID by their Jaccard index
+// over tags, each tag weighted by its inverse document frequency so a rare
+// shared tag counts more than a common one. Ties go to the most recent post.
+func (r *PostRepo) GetRelated(ctx context.Context, req dto.PostRelatedReq) ([]dto.PostRelatedRes, error) {
+	var (
+		opName = "PostRepository-GetRelated"
+		result = []dto.PostRelatedRes{}
+	)
+
+	// only the tags of the post and of its candidates are weighted, the
+	// union of a candidate is the weight of the post plus its other tags
+	err := conn(ctx, r.DB).Raw(`WITH source AS (
+			SELECT tag_id FROM post_tag WHERE post_id = @id
+		), candidate AS (
+			SELECT DISTINCT post_tag.post_id FROM post_tag
+			INNER JOIN source ON source.tag_id = post_tag.tag_id
+			INNER JOIN post ON post.id = post_tag.post_id
+			WHERE post_tag.post_id <> @id
+				AND (NOT @published_only OR post.status = @published
+					OR (post.status = @scheduled AND post.publish_at <= NOW()))
+		), usage AS (
+			SELECT post_tag.post_id, post_tag.tag_id FROM post_tag
+			WHERE post_tag.post_id IN (SELECT post_id FROM candidate)
+		), weight AS (
+			SELECT tag_id, LN(1 + (SELECT COUNT(*) FROM post)::float8 / COUNT(*)) AS value FROM post_tag
+			WHERE tag_id IN (SELECT tag_id FROM usage UNION SELECT tag_id FROM source)
+			GROUP BY tag_id
+		), score AS (
+			SELECT usage.post_id,
+				SUM(weight.value) FILTER (WHERE usage.tag_id IN (SELECT tag_id FROM source)) AS shared,
+				COALESCE(SUM(weight.value) FILTER (WHERE usage.tag_id NOT IN (SELECT tag_id FROM source)), 0) AS other
+			FROM usage INNER JOIN weight ON weight.tag_id = usage.tag_id
+			GROUP BY usage.post_id
+		)
+		SELECT post.id, post.title, post.slug, post.content_text, post.status, post.publish_at, post.created_at,
+			score.shared / ((SELECT SUM(weight.value) FROM source INNER JOIN weight ON weight.tag_id = source.tag_id) + score.other) AS score
+		FROM score INNER JOIN post ON post.id = score.post_id
+		ORDER BY score DESC, COALESCE(post.publish_at, post.created_at) DESC, post.id DESC
+		LIMIT @limit`,
+		map[string]interface{}{
+			"id":             req.ID,
+			"published_only": req.IsPublishedOnly,
+			"published":      dto.PostStatusPublished,
+			"scheduled":      dto.PostStatusScheduled,
+			"limit":          req.Limit,
+		}).
+		Scan(&result).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	return result, nil
+}
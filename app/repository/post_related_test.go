@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/driver"
+)
+
+func TestPostRepo_GetRelated(t *testing.T) {
+	var (
+		db     = openTestDB(t, "DB_TEST_DSN")
+		cfg    = testConfigs()
+		logger = driver.Logger(cfg)
+		repo   = NewPostRepository(db, cfg, logger)
+		ctx    = context.Background()
+	)
+
+	posts := []dto.PostCreateReq{
+		{Title: "source", Tags: []string{"go", "sql", "common"}},
+		{Title: "same tags", Tags: []string{"go", "sql", "common"}},
+		{Title: "rare tag", Tags: []string{"go"}},
+		{Title: "common tag", Tags: []string{"common"}},
+		{Title: "common tag newer", Tags: []string{"common"}},
+		{Title: "draft", Status: dto.PostStatusDraft, Tags: []string{"go", "sql", "common"}},
+		{Title: "unrelated", Tags: []string{"rust"}},
+		{Title: "common only 1", Tags: []string{"common"}},
+		{Title: "common only 2", Tags: []string{"common"}},
+	}
+	ids := map[string]uint64{}
+	for i, v := range posts {
+		v.Content = "content"
+		if v.Status == "" {
+			v.Status = dto.PostStatusPublished
+		}
+		post, err := repo.Create(ctx, v)
+		if err != nil {
+			t.Fatalf("Create() post error = %v", err)
+		}
+		ids[v.Title] = post.ID
+		db.Model(&models.Post{}).Where("id = ?", post.ID).Update("created_at", time.Now().Add(time.Duration(i)*time.Minute))
+	}
+
+	titles := func(res []dto.PostRelatedRes) []string {
+		result := []string{}
+		for _, v := range res {
+			result = append(result, v.Title)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name string
+		req  dto.PostRelatedReq
+		want []string
+	}{
+		{
+			name: "published only",
+			req:  dto.PostRelatedReq{ID: ids["source"], Limit: 4, IsPublishedOnly: true},
+			want: []string{"same tags", "rare tag", "common only 2", "common only 1"},
+		},
+		{
+			name: "with drafts",
+			req:  dto.PostRelatedReq{ID: ids["source"], Limit: 2},
+			want: []string{"draft", "same tags"},
+		},
+		{
+			name: "no tag shared",
+			req:  dto.PostRelatedReq{ID: ids["unrelated"], Limit: 5},
+			want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.GetRelated(ctx, tt.req)
+			if err != nil {
+				t.Fatalf("GetRelated() error = %v", err)
+			}
+			if !reflect.DeepEqual(titles(got), tt.want) {
+				t.Errorf("GetRelated() = %v, want %v", titles(got), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/database"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/driver"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// TestPostRepo_ReplicaRouting uses two independent databases as primary and
+// replica, without replication, so a row written on the primary is only
+// visible to reads routed to the primary.
+func TestPostRepo_ReplicaRouting(t *testing.T) {
+	var (
+		primary = openTestDB(t, "DB_TEST_DSN")
+		_       = openTestDB(t, "DB_TEST_REPLICA_DSN")
+		cfg     = testConfigs()
+		ctx     = context.Background()
+	)
+
+	err := primary.Use(dbresolver.Register(dbresolver.Config{
+		Replicas: []gorm.Dialector{postgres.Open(os.Getenv("DB_TEST_REPLICA_DSN"))},
+	}))
+	if err != nil {
+		t.Fatalf("failed register replica: %v", err)
+	}
+
+	repo := NewPostRepository(primary, cfg, driver.Logger(cfg))
+	post, err := repo.Create(ctx, dto.PostCreateReq{
+		Title:   "replica",
+		Content: "written on primary",
+		Tags:    []string{"go"},
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	_, err = repo.GetDetail(ctx, dto.PostGetReq{ID: post.ID})
+	if err == nil {
+		t.Errorf("GetDetail() read from primary, want replica")
+	}
+
+	got, err := repo.GetDetail(database.WithReadPrimary(ctx), dto.PostGetReq{ID: post.ID})
+	if err != nil {
+		t.Fatalf("GetDetail() with read primary error = %v", err)
+	}
+	if len(got.Tags) != 1 {
+		t.Errorf("GetDetail() tags = %v, want 1 tag", got.Tags)
+	}
+
+	err = repo.UpdateByID(ctx, dto.PostUpdateReq{
+		ID:      post.ID,
+		Title:   "replica updated",
+		Content: "still on primary",
+	})
+	if err != nil {
+		t.Errorf("UpdateByID() error = %v, want existence check on primary", err)
+	}
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type PostRevisionRepository interface {
+	Create(ctx context.Context, req *models.PostRevision) error
+	GetAll(ctx context.Context, postID uint64) ([]models.PostRevision, error)
+	GetDetail(ctx context.Context, req dto.PostRevisionGetReq) (*models.PostRevision, error)
+	GetAuthorsByPosts(ctx context.Context, postIDs []uint64) (map[uint64][]dto.PostAuthorRes, error)
+}
+
+type PostRevisionRepo struct {
+	DB     *gorm.DB
+	Cfg    *configs.Configs
+	Logger *logrus.Logger
+}
+
+func NewPostRevisionRepository(
+	db *gorm.DB,
+	cfg *configs.Configs,
+	logger *logrus.Logger,
+) PostRevisionRepository {
+	return &PostRevisionRepo{
+		DB:     db,
+		Cfg:    cfg,
+		Logger: logger,
+	}
+}
+
+// Create stores req as the next revision of its post. Callers should hold
+// the post row lock, see PostGetReq.IsLock, so revisions stay sequential.
+func (r *PostRevisionRepo) Create(ctx context.Context, req *models.PostRevision) error {
+	opName := "PostRevisionRepository-Create"
+
+	err := WithTx(ctx, r.DB, func(ctx context.Context, trx *gorm.DB) error {
+		err := trx.Model(&models.PostRevision{}).
+			Where("post_id = ?", req.PostID).
+			Select("COALESCE(MAX(revision), 0) + 1").
+			Scan(&req.Revision).Error
+		if err != nil {
+			return err
+		}
+
+		return trx.Create(req).Error
+	})
+	if err != nil {
+		r.Logger.Errorf("%s failed create data: %v \n", opName, err)
+		return toRespErr(err, helpers.ErrCreatedDB())
+	}
+
+	return nil
+}
+
+func (r *PostRevisionRepo) GetAll(ctx context.Context, postID uint64) ([]models.PostRevision, error) {
+	var (
+		opName = "PostRevisionRepository-GetAll"
+		result = []models.PostRevision{}
+	)
+
+	err := conn(ctx, r.DB).
+		Where("post_id = ?", postID).
+		Order("revision DESC").
+		Find(&result).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	return result, nil
+}
+
+func (r *PostRevisionRepo) GetDetail(ctx context.Context, req dto.PostRevisionGetReq) (*models.PostRevision, error) {
+	var (
+		opName = "PostRevisionRepository-GetDetail"
+		result = models.PostRevision{}
+	)
+
+	err := conn(ctx, r.DB).
+		Where("post_id = ? AND revision = ?", req.PostID, req.Revision).
+		First(&result).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, helpers.ErrDataNotFound("revisi", "revision")
+		}
+
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	return &result, nil
+}
+
+// GetAuthorsByPosts returns who edited every post of postIDs with one query,
+// in the order of their first revision. Edits made without a signed in user
+// are left out.
+func (r *PostRevisionRepo) GetAuthorsByPosts(ctx context.Context, postIDs []uint64) (map[uint64][]dto.PostAuthorRes, error) {
+	var (
+		opName = "PostRevisionRepository-GetAuthorsByPosts"
+		rows   = []struct {
+			PostID   uint64
+			AuthorID uint64
+			Author   string
+		}{}
+		result = map[uint64][]dto.PostAuthorRes{}
+	)
+	if len(postIDs) == 0 {
+		return result, nil
+	}
+
+	err := conn(ctx, r.DB).
+		Raw(`SELECT post_id, author_id, MAX(author) AS author FROM post_revision
+			WHERE post_id IN ? AND author_id <> 0
+			GROUP BY post_id, author_id
+			ORDER BY post_id, MIN(revision)`, postIDs).
+		Scan(&rows).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	for _, v := range rows {
+		result[v.PostID] = append(result[v.PostID], dto.PostAuthorRes{ID: v.AuthorID, Username: v.Author})
+	}
+	return result, nil
+}
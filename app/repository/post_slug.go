@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/slug"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// assignSlug makes the slug of title the current slug of the post and
+// returns it. A slug taken by another post gets the first free "-N" suffix,
+// a slug the post already had is reused, and the previous slug is kept in
+// post_slug so it keeps redirecting. It returns the database error as is.
+func assignSlug(trx *gorm.DB, postID uint64, title string) (string, error) {
+	var (
+		base  = slug.Make(title)
+		taken = []models.PostSlug{}
+	)
+
+	err := trx.Where("slug = ? OR slug LIKE ?", base, base+"-%").Find(&taken).Error
+	if err != nil {
+		return "", err
+	}
+
+	ownerOf := make(map[string]uint64, len(taken))
+	for _, v := range taken {
+		ownerOf[v.Slug] = v.PostID
+	}
+
+	for n := 1; ; n++ {
+		candidate := base
+		if n > 1 {
+			candidate = fmt.Sprintf("%s-%d", base, n)
+		}
+
+		owner, ok := ownerOf[candidate]
+		if ok && owner != postID {
+			continue
+		}
+
+		if !ok {
+			// a concurrent post may have taken it since the lookup
+			res := trx.Clauses(clause.OnConflict{DoNothing: true}).
+				Create(&models.PostSlug{Slug: candidate, PostID: postID})
+			if res.Error != nil {
+				return "", res.Error
+			}
+			if res.RowsAffected == 0 {
+				continue
+			}
+		}
+
+		err = trx.Model(&models.Post{}).
+			Where("id = ? AND slug <> ?", postID, candidate).
+			UpdateColumn("slug", candidate).Error
+		if err != nil {
+			return "", err
+		}
+		return candidate, nil
+	}
+}
+
+// FindIDBySlug returns the id of the post having or having had slug.
+func (r *PostRepo) FindIDBySlug(ctx context.Context, slug string) (uint64, error) {
+	var (
+		opName = "PostRepository-FindIDBySlug"
+		result = models.PostSlug{}
+	)
+
+	err := conn(ctx, r.DB).Where("slug = ?", slug).First(&result).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, helpers.ErrNotFound()
+		}
+
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return 0, helpers.ErrDB()
+	}
+
+	return result.PostID, nil
+}
+
+// BackfillSlugs assigns a slug to every post created before slugs existed
+// and returns how many it assigned.
+func (r *PostRepo) BackfillSlugs(ctx context.Context) (int, error) {
+	var (
+		opName = "PostRepository-BackfillSlugs"
+		total  = 0
+	)
+
+	for {
+		posts := []models.Post{}
+		err := r.DB.WithContext(ctx).Select("id, title").
+			Where("slug = ''").Order("id").Limit(r.batchSize()).
+			Find(&posts).Error
+		if err != nil {
+			r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+			return total, helpers.ErrDB()
+		}
+		if len(posts) == 0 {
+			return total, nil
+		}
+
+		err = WithTx(ctx, r.DB, func(ctx context.Context, trx *gorm.DB) error {
+			for _, v := range posts {
+				if _, err := assignSlug(trx, v.ID, v.Title); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			r.Logger.Errorf("%s failed assign slug: %v \n", opName, err)
+			return total, helpers.ErrUpdatedDB()
+		}
+		total += len(posts)
+	}
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/driver"
+)
+
+func TestPostRepo_CreateConcurrentNewTag(t *testing.T) {
+	var (
+		db    = openTestDB(t, "DB_TEST_DSN")
+		cfg   = testConfigs()
+		repo  = NewPostRepository(db, cfg, driver.Logger(cfg))
+		ctx   = context.Background()
+		total = 20
+	)
+
+	wg := sync.WaitGroup{}
+	errs := make(chan error, total)
+	for i := 0; i < total; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := repo.Create(ctx, dto.PostCreateReq{
+				Title:   fmt.Sprintf("post %d", i),
+				Content: "concurrent",
+				Tags:    []string{"go", "postgres"},
+			})
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Create() error = %v", err)
+		}
+	}
+
+	var tags, postTags int64
+	db.Model(&models.Tag{}).Where("label IN ?", []string{"go", "postgres"}).Count(&tags)
+	db.Model(&models.PostTag{}).Count(&postTags)
+	if tags != 2 {
+		t.Errorf("tags = %d, want 2", tags)
+	}
+	if postTags != int64(total*2) {
+		t.Errorf("post_tag rows = %d, want %d", postTags, total*2)
+	}
+}
+
+func TestPostRepo_PublishDue(t *testing.T) {
+	var (
+		db     = openTestDB(t, "DB_TEST_DSN")
+		cfg    = testConfigs()
+		repo   = NewPostRepository(db, cfg, driver.Logger(cfg))
+		ctx    = context.Background()
+		now    = time.Now()
+		past   = now.Add(-time.Minute)
+		future = now.Add(time.Hour)
+	)
+
+	reqs := []dto.PostCreateReq{
+		{Title: "due", Content: "due", Status: dto.PostStatusScheduled, PublishAt: &past},
+		{Title: "not due", Content: "not due", Status: dto.PostStatusScheduled, PublishAt: &future},
+		{Title: "draft", Content: "draft", Status: dto.PostStatusDraft},
+	}
+	ids := []uint64{}
+	for _, req := range reqs {
+		post, err := repo.Create(ctx, req)
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		ids = append(ids, post.ID)
+	}
+
+	got, err := repo.PublishDue(ctx, now)
+	if err != nil {
+		t.Fatalf("PublishDue() error = %v", err)
+	}
+	if len(got) != 1 || got[0] != ids[0] {
+		t.Errorf("PublishDue() = %v, want [%d]", got, ids[0])
+	}
+
+	// a second run finds nothing left to publish
+	got, err = repo.PublishDue(ctx, now)
+	if err != nil || len(got) != 0 {
+		t.Errorf("PublishDue() again = %v, %v, want none", got, err)
+	}
+}
+
+func TestPostRepo_Slugs(t *testing.T) {
+	var (
+		db   = openTestDB(t, "DB_TEST_DSN")
+		cfg  = testConfigs()
+		repo = NewPostRepository(db, cfg, driver.Logger(cfg))
+		ctx  = context.Background()
+	)
+
+	first, err := repo.Create(ctx, dto.PostCreateReq{Title: "Résumé Café", Content: "first"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	second, err := repo.Create(ctx, dto.PostCreateReq{Title: "Resume cafe!", Content: "second"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if first.Slug != "resume-cafe" || second.Slug != "resume-cafe-2" {
+		t.Fatalf("slugs = %q, %q, want resume-cafe, resume-cafe-2", first.Slug, second.Slug)
+	}
+
+	err = repo.UpdateByID(ctx, dto.PostUpdateReq{ID: first.ID, Title: "New Title", Content: "first"})
+	if err != nil {
+		t.Fatalf("UpdateByID() error = %v", err)
+	}
+
+	// the old slug still finds the post and is not given to anyone else
+	for _, slug := range []string{"resume-cafe", "new-title"} {
+		got, err := repo.FindIDBySlug(ctx, slug)
+		if err != nil || got != first.ID {
+			t.Errorf("FindIDBySlug(%q) = %v, %v, want %d", slug, got, err, first.ID)
+		}
+	}
+	third, err := repo.Create(ctx, dto.PostCreateReq{Title: "Resume Cafe", Content: "third"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if third.Slug != "resume-cafe-3" {
+		t.Errorf("slug = %q, want resume-cafe-3", third.Slug)
+	}
+
+	detail, err := repo.GetDetail(ctx, dto.PostGetReq{ID: first.ID})
+	if err != nil || detail.Slug != "new-title" {
+		t.Errorf("GetDetail() slug = %v, %v, want new-title", detail, err)
+	}
+}
+
+func TestPostRepo_UpdateByIDRenderedEmpty(t *testing.T) {
+	var (
+		db   = openTestDB(t, "DB_TEST_DSN")
+		cfg  = testConfigs()
+		repo = NewPostRepository(db, cfg, driver.Logger(cfg))
+		ctx  = context.Background()
+	)
+
+	post, err := repo.Create(ctx, dto.PostCreateReq{
+		Title:         "before",
+		Content:       "<p>old content</p>",
+		ContentFormat: "html",
+		Status:        dto.PostStatusDraft,
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// only a script, sanitized to nothing
+	err = repo.UpdateByID(ctx, dto.PostUpdateReq{
+		ID:            post.ID,
+		Title:         "after",
+		Content:       "<script>alert(1)</script>",
+		ContentFormat: "html",
+	})
+	if err != nil {
+		t.Fatalf("UpdateByID() error = %v", err)
+	}
+
+	got := models.Post{}
+	if err := db.First(&got, post.ID).Error; err != nil {
+		t.Fatalf("First() error = %v", err)
+	}
+	if got.ContentHTML != "" || got.ContentText != "" {
+		t.Errorf("content_html = %q, content_text = %q, want both empty", got.ContentHTML, got.ContentText)
+	}
+	if got.Title != "after" || !got.UpdatedAt.After(got.CreatedAt) {
+		t.Errorf("post = %+v, want the title and updated_at updated", got)
+	}
+}
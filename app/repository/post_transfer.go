@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+)
+
+type postExportRow struct {
+	ID            uint64
+	Slug          string
+	Title         string
+	Content       string
+	ContentFormat string
+	Tags          models.StringList
+	Status        string
+	PublishAt     *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// Export calls fn with every post and its tags in id order. Rows come from
+// a cursor, so memory use does not grow with the number of posts. It stops
+// at the first error of fn and returns it.
+func (r *PostRepo) Export(ctx context.Context, fn func(row dto.PostTransferRow) error) error {
+	opName := "PostRepository-Export"
+
+	rows, err := conn(ctx, r.DB).Raw("SELECT post.id, post.slug, post.title, post.content, post.content_format, " +
+		" post.status, post.publish_at, post.created_at, post.updated_at, " +
+		" COALESCE(json_agg(tag.label ORDER BY tag.label) FILTER (WHERE tag.id IS NOT NULL), '[]') AS tags " +
+		" FROM post " +
+		" LEFT JOIN post_tag ON post_tag.post_id = post.id " +
+		" LEFT JOIN tag ON tag.id = post_tag.tag_id " +
+		" GROUP BY post.id ORDER BY post.id").Rows()
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return helpers.ErrDB()
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row := postExportRow{}
+		err = r.DB.ScanRows(rows, &row)
+		if err != nil {
+			r.Logger.Errorf("%s failed scan data: %v \n", opName, err)
+			return helpers.ErrDB()
+		}
+
+		err = fn(dto.PostTransferRow{
+			ID:            row.ID,
+			Slug:          row.Slug,
+			Title:         row.Title,
+			Content:       row.Content,
+			ContentFormat: row.ContentFormat,
+			Tags:          row.Tags,
+			Status:        row.Status,
+			PublishAt:     row.PublishAt,
+			CreatedAt:     row.CreatedAt,
+			UpdatedAt:     row.UpdatedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	err = rows.Err()
+	if err != nil {
+		r.Logger.Errorf("%s failed read data: %v \n", opName, err)
+		return helpers.ErrDB()
+	}
+	return nil
+}
+
+// FindExistingIDs returns which of ids are existing posts.
+func (r *PostRepo) FindExistingIDs(ctx context.Context, ids []uint64) (map[uint64]bool, error) {
+	var (
+		opName = "PostRepository-FindExistingIDs"
+		found  = []uint64{}
+		result = make(map[uint64]bool, len(ids))
+	)
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	err := conn(ctx, r.DB).Model(&models.Post{}).Where("id IN ?", ids).Pluck("id", &found).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	for _, v := range found {
+		result[v] = true
+	}
+	return result, nil
+}
+
+// FindIDsBySlugs returns the post id of each of slugs that is, or was, the
+// slug of a post.
+func (r *PostRepo) FindIDsBySlugs(ctx context.Context, slugs []string) (map[string]uint64, error) {
+	var (
+		opName = "PostRepository-FindIDsBySlugs"
+		found  = []models.PostSlug{}
+		result = make(map[string]uint64, len(slugs))
+	)
+	if len(slugs) == 0 {
+		return result, nil
+	}
+
+	err := conn(ctx, r.DB).Where("slug IN ?", slugs).Find(&found).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	for _, v := range found {
+		result[v.Slug] = v.PostID
+	}
+	return result, nil
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// ReportRepository reads the tag reports from materialized views, they are
+// as fresh as the last Refresh. Only the posts published by then count.
+type ReportRepository interface {
+	Refresh(ctx context.Context) error
+	GetTopTags(ctx context.Context, limit int) ([]dto.TagUsageRes, error)
+	GetTagPairs(ctx context.Context, tagIDs []uint64) ([]dto.TagPairRow, error)
+	GetPostCounts(ctx context.Context) ([]dto.TagPostCountRow, error)
+	GetTagWeeks(ctx context.Context, since time.Time, tag string, top int) ([]dto.TagWeekRow, error)
+}
+
+// reportViews are the materialized views behind the reports, refreshed
+// together by ReportRepo.Refresh.
+var reportViews = []string{"report_tag_posts", "report_tag_pairs", "report_tag_weekly"}
+
+// reportPublished keeps the posts visible to the public when the reports are
+// refreshed, drafts and posts in review or archived are not counted.
+var reportPublished = fmt.Sprintf("(post.status = '%s' OR (post.status = '%s' AND post.publish_at <= NOW()))",
+	dto.PostStatusPublished, dto.PostStatusScheduled)
+
+// migrateReports creates the materialized views of the tag reports, again
+// on every migration so a changed definition is applied. Each has a unique
+// index so it can be refreshed without blocking the reads.
+func migrateReports(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, sql := range []string{
+			"DROP MATERIALIZED VIEW IF EXISTS report_tag_posts, report_tag_pairs, report_tag_weekly",
+			`CREATE MATERIALIZED VIEW report_tag_posts AS
+				SELECT tag.id AS tag_id, tag.label, COUNT(post_tag.post_id) AS posts FROM tag
+				LEFT JOIN (post_tag INNER JOIN post ON post.id = post_tag.post_id AND ` + reportPublished + `)
+					ON post_tag.tag_id = tag.id
+				GROUP BY tag.id, tag.label`,
+			"CREATE UNIQUE INDEX idx_report_tag_posts ON report_tag_posts (tag_id)",
+			`CREATE MATERIALIZED VIEW report_tag_pairs AS
+				SELECT a.tag_id, b.tag_id AS other_id, COUNT(*) AS posts FROM post_tag a
+				INNER JOIN post_tag b ON b.post_id = a.post_id AND b.tag_id > a.tag_id
+				INNER JOIN post ON post.id = a.post_id
+				WHERE ` + reportPublished + `
+				GROUP BY a.tag_id, b.tag_id`,
+			"CREATE UNIQUE INDEX idx_report_tag_pairs ON report_tag_pairs (tag_id, other_id)",
+			`CREATE MATERIALIZED VIEW report_tag_weekly AS
+				SELECT date_trunc('week', COALESCE(post.publish_at, post.created_at) AT TIME ZONE 'UTC')::date AS week,
+					post_tag.tag_id, COUNT(*) AS posts FROM post_tag
+				INNER JOIN post ON post.id = post_tag.post_id
+				WHERE ` + reportPublished + `
+				GROUP BY 1, post_tag.tag_id`,
+			"CREATE UNIQUE INDEX idx_report_tag_weekly ON report_tag_weekly (week, tag_id)",
+		} {
+			err := tx.Exec(sql).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+type ReportRepo struct {
+	DB     *gorm.DB
+	Cfg    *configs.Configs
+	Logger *logrus.Logger
+}
+
+func NewReportRepository(
+	db *gorm.DB,
+	cfg *configs.Configs,
+	logger *logrus.Logger,
+) ReportRepository {
+	return &ReportRepo{
+		DB:     db,
+		Cfg:    cfg,
+		Logger: logger,
+	}
+}
+
+// Refresh recomputes every report view. Reads keep the previous data until
+// a view is refreshed.
+func (r *ReportRepo) Refresh(ctx context.Context) error {
+	opName := "ReportRepository-Refresh"
+
+	for _, view := range reportViews {
+		err := r.DB.WithContext(ctx).Exec("REFRESH MATERIALIZED VIEW CONCURRENTLY " + view).Error
+		if err != nil {
+			r.Logger.Errorf("%s failed refresh %s: %v \n", opName, view, err)
+			return helpers.ErrDB()
+		}
+	}
+
+	return nil
+}
+
+// GetTopTags returns the limit tags given to most posts.
+func (r *ReportRepo) GetTopTags(ctx context.Context, limit int) ([]dto.TagUsageRes, error) {
+	var (
+		opName = "ReportRepository-GetTopTags"
+		result = []dto.TagUsageRes{}
+	)
+
+	err := conn(ctx, r.DB).Raw(`SELECT tag_id AS id, label, posts AS usage FROM report_tag_posts
+		WHERE posts > 0
+		ORDER BY posts DESC, label
+		LIMIT ?`, limit).
+		Scan(&result).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	return result, nil
+}
+
+// GetTagPairs returns how many posts have both tags of each pair of tagIDs
+// found together at least once.
+func (r *ReportRepo) GetTagPairs(ctx context.Context, tagIDs []uint64) ([]dto.TagPairRow, error) {
+	var (
+		opName = "ReportRepository-GetTagPairs"
+		result = []dto.TagPairRow{}
+	)
+	if len(tagIDs) == 0 {
+		return result, nil
+	}
+
+	err := conn(ctx, r.DB).Raw(`SELECT tag_id, other_id, posts FROM report_tag_pairs
+		WHERE tag_id IN @ids AND other_id IN @ids`,
+		map[string]interface{}{"ids": tagIDs}).
+		Scan(&result).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	return result, nil
+}
+
+// GetPostCounts returns how many tags are given to each number of posts.
+func (r *ReportRepo) GetPostCounts(ctx context.Context) ([]dto.TagPostCountRow, error) {
+	var (
+		opName = "ReportRepository-GetPostCounts"
+		result = []dto.TagPostCountRow{}
+	)
+
+	err := conn(ctx, r.DB).Raw(`SELECT posts, COUNT(*) AS tags FROM report_tag_posts
+		GROUP BY posts
+		ORDER BY posts`).
+		Scan(&result).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	return result, nil
+}
+
+// GetTagWeeks returns the posts per week from the week of since, of tag,
+// given by its label or a synonym, or else of the top tags of those weeks.
+// Rows are ordered by the total of their tag, then by week.
+func (r *ReportRepo) GetTagWeeks(ctx context.Context, since time.Time, tag string, top int) ([]dto.TagWeekRow, error) {
+	var (
+		opName = "ReportRepository-GetTagWeeks"
+		result = []dto.TagWeekRow{}
+	)
+
+	err := conn(ctx, r.DB).Raw(`WITH top AS (
+			SELECT report_tag_weekly.tag_id, SUM(report_tag_weekly.posts) AS total FROM report_tag_weekly
+			INNER JOIN tag ON tag.id = report_tag_weekly.tag_id
+			WHERE report_tag_weekly.week >= @since
+				AND (@tag = '' OR tag.label = @tag OR tag.id IN (SELECT tag_id FROM tag_synonym WHERE label = @tag))
+			GROUP BY report_tag_weekly.tag_id
+			ORDER BY total DESC, MIN(tag.label)
+			LIMIT @top
+		)
+		SELECT report_tag_weekly.week, report_tag_weekly.tag_id, tag.label, report_tag_weekly.posts FROM report_tag_weekly
+		INNER JOIN top ON top.tag_id = report_tag_weekly.tag_id
+		INNER JOIN tag ON tag.id = report_tag_weekly.tag_id
+		WHERE report_tag_weekly.week >= @since
+		ORDER BY top.total DESC, tag.label, report_tag_weekly.week`,
+		map[string]interface{}{"since": since.Format("2006-01-02"), "tag": tag, "top": top}).
+		Scan(&result).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	return result, nil
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/driver"
+)
+
+func TestReportRepo(t *testing.T) {
+	var (
+		db       = openTestDB(t, "DB_TEST_DSN")
+		cfg      = testConfigs()
+		logger   = driver.Logger(cfg)
+		repo     = NewReportRepository(db, cfg, logger)
+		postRepo = NewPostRepository(db, cfg, logger)
+		tagRepo  = NewTagRepository(db, cfg, logger)
+		ctx      = context.Background()
+		thisWeek = dto.WeekStart(time.Now())
+	)
+
+	for _, tags := range [][]string{{"go", "sql"}, {"go", "sql"}, {"go", "rust"}, {"go"}} {
+		_, err := postRepo.Create(ctx, dto.PostCreateReq{Title: "post", Content: "content", Status: dto.PostStatusPublished, Tags: tags})
+		if err != nil {
+			t.Fatalf("Create() post error = %v", err)
+		}
+	}
+	// drafts do not count, their tag is unused
+	_, err := postRepo.Create(ctx, dto.PostCreateReq{Title: "draft", Content: "content", Status: dto.PostStatusDraft, Tags: []string{"go", "sql", "unused"}})
+	if err != nil {
+		t.Fatalf("Create() draft error = %v", err)
+	}
+	if _, err := tagRepo.Upsert(ctx, []string{"unused"}); err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+	if err := repo.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	top, err := repo.GetTopTags(ctx, 2)
+	if err != nil {
+		t.Fatalf("GetTopTags() error = %v", err)
+	}
+	if len(top) != 2 || top[0].Label != "go" || top[0].Usage != 4 || top[1].Label != "sql" || top[1].Usage != 2 {
+		t.Fatalf("GetTopTags() = %+v, want go 4 and sql 2", top)
+	}
+
+	pairs, err := repo.GetTagPairs(ctx, []uint64{top[0].ID, top[1].ID})
+	if err != nil || len(pairs) != 1 || pairs[0].Posts != 2 {
+		t.Errorf("GetTagPairs() = %+v, %v, want one pair of 2 posts", pairs, err)
+	}
+
+	counts, err := repo.GetPostCounts(ctx)
+	want := []dto.TagPostCountRow{{Posts: 0, Tags: 1}, {Posts: 1, Tags: 1}, {Posts: 2, Tags: 1}, {Posts: 4, Tags: 1}}
+	if err != nil || !reflect.DeepEqual(counts, want) {
+		t.Errorf("GetPostCounts() = %+v, %v, want %+v", counts, err, want)
+	}
+
+	weeks, err := repo.GetTagWeeks(ctx, thisWeek, "", 2)
+	if err != nil || len(weeks) != 2 || weeks[0].Label != "go" || weeks[0].Posts != 4 || !weeks[0].Week.Equal(thisWeek) {
+		t.Errorf("GetTagWeeks() = %+v, %v, want go 4 then sql this week", weeks, err)
+	}
+	weeks, err = repo.GetTagWeeks(ctx, thisWeek, "rust", 10)
+	if err != nil || len(weeks) != 1 || weeks[0].Label != "rust" {
+		t.Errorf("GetTagWeeks() of rust = %+v, %v", weeks, err)
+	}
+}
@@ -1,6 +1,34 @@
 package repository
 
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
 // Repositories all repo object injected here
 type Repositories struct {
-	Post PostRepository
+	DB           *gorm.DB
+	Post         PostRepository
+	PostRevision PostRevisionRepository
+	Tag          TagRepository
+	Asset        AssetRepository
+	Blob         BlobRepository
+	Report       ReportRepository
+	Outbox       OutboxRepository
+	Webhook      WebhookRepository
+}
+
+// UnitOfWork runs fn in one transaction. Every repository called with the
+// ctx given to fn is bound to that transaction, so writes across several
+// repositories are committed or rolled back together. Without a DB, e.g.
+// repositories of mocks in service tests, fn is simply called.
+func (r *Repositories) UnitOfWork(ctx context.Context, fn func(ctx context.Context, repos *Repositories) error) error {
+	if r.DB == nil {
+		return fn(ctx, r)
+	}
+
+	return WithTx(ctx, r.DB, func(ctx context.Context, tx *gorm.DB) error {
+		return fn(ctx, r)
+	})
 }
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/app/repository/mocks"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/driver"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRepositories_UnitOfWorkWithoutDB(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		tag   = &mocks.TagRepository{}
+		repos = &Repositories{Tag: tag}
+	)
+	tag.On("Upsert", mock.Anything, []string{"go"}).Return(map[string]uint64{"go": 1}, nil).Once()
+
+	isCommitted := false
+	err := repos.UnitOfWork(ctx, func(ctx context.Context, repos *Repositories) error {
+		AfterCommit(ctx, func() { isCommitted = true })
+		_, err := repos.Tag.Upsert(ctx, []string{"go"})
+		return err
+	})
+	if err != nil || !isCommitted {
+		t.Errorf("UnitOfWork() error = %v, after commit called %v", err, isCommitted)
+	}
+	tag.AssertExpectations(t)
+}
+
+func TestRepositories_UnitOfWorkRollback(t *testing.T) {
+	var (
+		db    = openTestDB(t, "DB_TEST_DSN")
+		cfg   = testConfigs()
+		ctx   = context.Background()
+		repos = &Repositories{
+			DB:   db,
+			Post: NewPostRepository(db, cfg, driver.Logger(cfg)),
+			Tag:  NewTagRepository(db, cfg, driver.Logger(cfg)),
+		}
+	)
+
+	isCommitted := false
+	err := repos.UnitOfWork(ctx, func(ctx context.Context, repos *Repositories) error {
+		AfterCommit(ctx, func() { isCommitted = true })
+		_, err := repos.Tag.Upsert(ctx, []string{"rollback"})
+		if err != nil {
+			return err
+		}
+
+		_, err = repos.Post.Create(ctx, dto.PostCreateReq{Title: "rollback", Content: "rollback"})
+		if err != nil {
+			return err
+		}
+		return errors.New("audit log failed")
+	})
+	if err == nil {
+		t.Fatalf("UnitOfWork() error = nil")
+	}
+
+	var tags, posts int64
+	db.Model(&models.Tag{}).Count(&tags)
+	db.Model(&models.Post{}).Count(&posts)
+	if tags != 0 || posts != 0 || isCommitted {
+		t.Errorf("tags = %d posts = %d after commit called %v, want all rolled back", tags, posts, isCommitted)
+	}
+}
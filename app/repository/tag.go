@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TagRepository interface {
+	GetDetail(ctx context.Context, req dto.TagGetReq) (*models.Tag, error)
+	Upsert(ctx context.Context, labels []string) (map[string]uint64, error)
+	GetAll(ctx context.Context) ([]models.Tag, error)
+	GetSynonyms(ctx context.Context) ([]models.TagSynonym, error)
+	Resolve(ctx context.Context, label string, descendants bool) ([]models.Tag, error)
+	SetParent(ctx context.Context, req dto.TagParentReq) error
+	AddSynonym(ctx context.Context, req dto.TagSynonymReq) (*models.TagSynonym, error)
+	DeleteSynonym(ctx context.Context, req dto.TagSynonymReq) error
+	Merge(ctx context.Context, req dto.TagMergeReq) error
+	Suggest(ctx context.Context, req dto.TagSuggestReq) ([]dto.TagUsageRes, error)
+	Popular(ctx context.Context, since time.Time, limit int) ([]dto.TagUsageRes, error)
+}
+
+type TagRepo struct {
+	DB     *gorm.DB
+	Cfg    *configs.Configs
+	Logger *logrus.Logger
+}
+
+func NewTagRepository(
+	db *gorm.DB,
+	cfg *configs.Configs,
+	logger *logrus.Logger,
+) TagRepository {
+	return &TagRepo{
+		DB:     db,
+		Cfg:    cfg,
+		Logger: logger,
+	}
+}
+
+func (r *TagRepo) GetDetail(ctx context.Context, req dto.TagGetReq) (*models.Tag, error) {
+	var (
+		opName = "TagRepository-GetDetail"
+		query  = conn(ctx, r.DB)
+		result = models.Tag{}
+		column = "*"
+	)
+
+	err := req.Validate()
+	if err != nil {
+		r.Logger.Errorf("%s validate params: %v \n", opName, err)
+		return nil, err
+	}
+
+	if req.ColumnCustom != "" {
+		column = req.ColumnCustom
+	}
+
+	if req.ID != 0 {
+		query = query.Where("id = ?", req.ID)
+	}
+	if req.Label != "" {
+		query = query.Where("label = ?", req.Label)
+	}
+
+	err = query.Select(column).First(&result).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	return &result, nil
+}
+
+// Upsert returns the tag ID of every label, creating the missing tags.
+func (r *TagRepo) Upsert(ctx context.Context, labels []string) (map[string]uint64, error) {
+	opName := "TagRepository-Upsert"
+
+	var result map[string]uint64
+	err := WithTx(ctx, r.DB, func(ctx context.Context, trx *gorm.DB) (err error) {
+		result, err = upsertTags(trx, labels)
+		return err
+	})
+	if err != nil {
+		r.Logger.Errorf("%s failed upsert data: %v \n", opName, err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// upsertedTag is a tag returned by upsertTags, IsNew tells it was inserted
+// rather than found.
+type upsertedTag struct {
+	models.Tag
+	IsNew bool `gorm:"->;column:is_new"`
+}
+
+func (upsertedTag) TableName() string {
+	return "tag"
+}
+
+// upsertTags resolves every label to its tag ID with a single
+// INSERT ... ON CONFLICT statement, creating the missing tags with their
+// tag.created event. A synonym resolves to the ID of its tag, so labels may
+// share an ID. Labels are sorted so concurrent upserts lock the tag rows in
+// the same order.
+func upsertTags(trx *gorm.DB, labels []string) (map[string]uint64, error) {
+	result := map[string]uint64{}
+	if len(labels) == 0 {
+		return result, nil
+	}
+
+	synonyms := []models.TagSynonym{}
+	err := trx.Select("label", "tag_id").Where("label IN ?", labels).Find(&synonyms).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range synonyms {
+		result[v.Label] = v.TagID
+	}
+
+	labels = append([]string{}, labels...)
+	sort.Strings(labels)
+	tags := make([]upsertedTag, 0, len(labels))
+	for i, v := range labels {
+		if i > 0 && v == labels[i-1] || result[v] != 0 {
+			continue
+		}
+		tags = append(tags, upsertedTag{Tag: models.Tag{Label: v}})
+	}
+	if len(tags) == 0 {
+		return result, nil
+	}
+
+	// DO UPDATE instead of DO NOTHING so existing rows are returned too,
+	// xmax is 0 only for the rows this statement inserted
+	err = trx.Clauses(
+		clause.OnConflict{
+			Columns:   []clause.Column{{Name: "label"}},
+			DoUpdates: clause.AssignmentColumns([]string{"label"}),
+		},
+		clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "label"}, {Name: "xmax = 0 AS is_new", Raw: true}}},
+	).Create(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range tags {
+		result[v.Label] = v.ID
+		if !v.IsNew {
+			continue
+		}
+
+		data := &dto.TagEventData{ID: v.ID, Label: v.Label, Synonyms: []string{}}
+		err = writeTagEvent(trx, dto.TagEventCreated, v.ID, dto.TagEventPayload{After: data})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// newPostTags links the post to the tag of every label once, synonyms of
+// the same tag give a single row.
+func newPostTags(postID uint64, labels []string, tagIDs map[string]uint64) []models.PostTag {
+	var (
+		isExist = map[uint64]bool{}
+		result  = make([]models.PostTag, 0, len(labels))
+	)
+	for _, label := range labels {
+		tagID := tagIDs[label]
+		if isExist[tagID] {
+			continue
+		}
+
+		isExist[tagID] = true
+		result = append(result, models.PostTag{PostID: postID, TagID: tagID})
+	}
+	return result
+}
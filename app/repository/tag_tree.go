@@ -0,0 +1,350 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// tagMaxDepth bounds the walks down the tree, it only matters for a cycle
+// which SetParent prevents.
+const tagMaxDepth = 100
+
+func errTagNotFound() *helpers.ResponseError {
+	return helpers.ErrDataNotFound("tag", "tag")
+}
+
+func (r *TagRepo) GetAll(ctx context.Context) ([]models.Tag, error) {
+	var (
+		opName = "TagRepository-GetAll"
+		result = []models.Tag{}
+	)
+
+	err := conn(ctx, r.DB).Order("label").Find(&result).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	return result, nil
+}
+
+func (r *TagRepo) GetSynonyms(ctx context.Context) ([]models.TagSynonym, error) {
+	var (
+		opName = "TagRepository-GetSynonyms"
+		result = []models.TagSynonym{}
+	)
+
+	err := conn(ctx, r.DB).Order("label").Find(&result).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	return result, nil
+}
+
+// Resolve returns the tag of label, or of the synonym label, with all its
+// descendants when asked. An unknown label gives no tags.
+func (r *TagRepo) Resolve(ctx context.Context, label string, descendants bool) ([]models.Tag, error) {
+	var (
+		opName = "TagRepository-Resolve"
+		result = []models.Tag{}
+		depth  = 0
+	)
+	if descendants {
+		depth = tagMaxDepth
+	}
+
+	err := conn(ctx, r.DB).Raw(`WITH RECURSIVE tree AS (
+			SELECT tag.id, tag.label, tag.parent_id, 0 AS depth FROM tag
+			WHERE tag.label = @label OR tag.id IN (SELECT tag_id FROM tag_synonym WHERE label = @label)
+			UNION
+			SELECT child.id, child.label, child.parent_id, tree.depth + 1 FROM tag child
+			INNER JOIN tree ON child.parent_id = tree.id
+			WHERE tree.depth < @depth
+		)
+		SELECT DISTINCT id, label, parent_id FROM tree ORDER BY label`,
+		map[string]interface{}{"label": label, "depth": depth}).
+		Scan(&result).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	return result, nil
+}
+
+// SetParent moves the tag under req.ParentID, refusing to move it below one
+// of its own descendants.
+func (r *TagRepo) SetParent(ctx context.Context, req dto.TagParentReq) error {
+	opName := "TagRepository-SetParent"
+
+	err := WithTx(ctx, r.DB, func(ctx context.Context, trx *gorm.DB) error {
+		err := lockTags(trx, req.ID)
+		if err != nil {
+			return err
+		}
+
+		if req.ParentID != nil {
+			err = lockTags(trx, *req.ParentID)
+			if err != nil {
+				return err
+			}
+
+			var isCycle bool
+			err = trx.Raw(`WITH RECURSIVE ancestor AS (
+					SELECT id, parent_id FROM tag WHERE id = @parent
+					UNION
+					SELECT tag.id, tag.parent_id FROM tag
+					INNER JOIN ancestor ON tag.id = ancestor.parent_id
+				)
+				SELECT EXISTS (SELECT 1 FROM ancestor WHERE id = @id)`,
+				map[string]interface{}{"parent": *req.ParentID, "id": req.ID}).
+				Scan(&isCycle).Error
+			if err != nil {
+				return err
+			}
+			if isCycle {
+				return dto.ErrTagCycle()
+			}
+		}
+
+		return writeTagChange(trx, req.ID, func() error {
+			return trx.Model(&models.Tag{}).Where("id = ?", req.ID).Update("parent_id", req.ParentID).Error
+		})
+	})
+	if err != nil {
+		r.Logger.Errorf("%s failed update data: %v \n", opName, err)
+		return toRespErr(err, helpers.ErrUpdatedDB())
+	}
+
+	return nil
+}
+
+// AddSynonym adds req.Label to the tag. A label already used by a tag is
+// refused, the two tags are to be merged.
+func (r *TagRepo) AddSynonym(ctx context.Context, req dto.TagSynonymReq) (*models.TagSynonym, error) {
+	var (
+		opName = "TagRepository-AddSynonym"
+		result = models.TagSynonym{TagID: req.TagID, Label: req.Label}
+	)
+
+	err := WithTx(ctx, r.DB, func(ctx context.Context, trx *gorm.DB) error {
+		err := lockTags(trx, req.TagID)
+		if err != nil {
+			return err
+		}
+
+		var total int64
+		err = trx.Model(&models.Tag{}).Where("label = ?", req.Label).Count(&total).Error
+		if err != nil {
+			return err
+		}
+		if total > 0 {
+			return dto.ErrTagLabelUsed(req.Label)
+		}
+
+		// adding it again, to the same or another tag, moves it
+		return writeTagChange(trx, req.TagID, func() error {
+			return trx.Clauses(
+				clause.OnConflict{
+					Columns:   []clause.Column{{Name: "label"}},
+					DoUpdates: clause.AssignmentColumns([]string{"tag_id"}),
+				},
+				clause.Returning{},
+			).Create(&result).Error
+		})
+	})
+	if err != nil {
+		r.Logger.Errorf("%s failed create data: %v \n", opName, err)
+		return nil, toRespErr(err, helpers.ErrCreatedDB())
+	}
+
+	return &result, nil
+}
+
+func (r *TagRepo) DeleteSynonym(ctx context.Context, req dto.TagSynonymReq) error {
+	opName := "TagRepository-DeleteSynonym"
+
+	err := WithTx(ctx, r.DB, func(ctx context.Context, trx *gorm.DB) error {
+		return writeTagChange(trx, req.TagID, func() error {
+			res := trx.Where("tag_id = ? AND label = ?", req.TagID, req.Label).Delete(&models.TagSynonym{})
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return helpers.ErrDataNotFound("sinonim", "synonym")
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		r.Logger.Errorf("%s failed delete data: %v \n", opName, err)
+		return toRespErr(err, helpers.ErrDB())
+	}
+
+	return nil
+}
+
+// Merge folds the source tags into the target. Their posts are tagged with
+// the target instead, their children move under it, their synonyms and
+// labels become its synonyms, then they are removed. Each retagged post
+// gets a post.updated event besides the tag.merged one.
+func (r *TagRepo) Merge(ctx context.Context, req dto.TagMergeReq) error {
+	opName := "TagRepository-Merge"
+
+	err := WithTx(ctx, r.DB, func(ctx context.Context, trx *gorm.DB) error {
+		err := lockTags(trx, append([]uint64{req.TargetID}, req.SourceIDs...)...)
+		if err != nil {
+			return err
+		}
+
+		sources, err := tagSnapshots(trx, req.SourceIDs)
+		if err != nil {
+			return err
+		}
+
+		// the posts are not locked, a post update locks its post before the
+		// tags and would deadlock with the merge
+		postIDs := []uint64{}
+		err = trx.Raw(`SELECT DISTINCT post_id FROM post_tag WHERE tag_id IN ? ORDER BY post_id`, req.SourceIDs).
+			Scan(&postIDs).Error
+		if err != nil {
+			return err
+		}
+		postsBefore, err := postSnapshots(trx, postIDs, false)
+		if err != nil {
+			return err
+		}
+
+		steps := []struct {
+			sql  string
+			args []interface{}
+		}{
+			{
+				sql: `INSERT INTO post_tag (post_id, tag_id, created_at)
+					SELECT post_id, ?, MIN(created_at) FROM post_tag WHERE tag_id IN ?
+					GROUP BY post_id
+					ON CONFLICT DO NOTHING`,
+				args: []interface{}{req.TargetID, req.SourceIDs},
+			},
+			{
+				sql:  `DELETE FROM post_tag WHERE tag_id IN ?`,
+				args: []interface{}{req.SourceIDs},
+			},
+			{
+				// a target below a source would end up below itself, it
+				// moves to the top instead
+				sql: `WITH RECURSIVE ancestor AS (
+						SELECT parent_id FROM tag WHERE id = @target
+						UNION
+						SELECT tag.parent_id FROM tag INNER JOIN ancestor ON tag.id = ancestor.parent_id
+					)
+					UPDATE tag SET parent_id = NULL
+					WHERE id = @target AND EXISTS (SELECT 1 FROM ancestor WHERE parent_id IN @sources)`,
+				args: []interface{}{map[string]interface{}{"target": req.TargetID, "sources": req.SourceIDs}},
+			},
+			{
+				sql:  `UPDATE tag SET parent_id = ? WHERE parent_id IN ?`,
+				args: []interface{}{req.TargetID, req.SourceIDs},
+			},
+			{
+				sql:  `UPDATE tag_synonym SET tag_id = ? WHERE tag_id IN ?`,
+				args: []interface{}{req.TargetID, req.SourceIDs},
+			},
+			{
+				sql: `INSERT INTO tag_synonym (label, tag_id, created_at)
+					SELECT label, ?, NOW() FROM tag WHERE id IN ?
+					ON CONFLICT (label) DO UPDATE SET tag_id = EXCLUDED.tag_id`,
+				args: []interface{}{req.TargetID, req.SourceIDs},
+			},
+			{
+				sql:  `DELETE FROM tag WHERE id IN ?`,
+				args: []interface{}{req.SourceIDs},
+			},
+		}
+		for _, v := range steps {
+			err = trx.Exec(v.sql, v.args...).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		postsAfter, err := postSnapshots(trx, postIDs, false)
+		if err != nil {
+			return err
+		}
+		err = writePostEvents(trx, dto.PostEventUpdated, postIDs, postsBefore, postsAfter)
+		if err != nil {
+			return err
+		}
+
+		target, err := tagSnapshots(trx, []uint64{req.TargetID})
+		if err != nil {
+			return err
+		}
+		payload := dto.TagMergedPayload{Target: target[req.TargetID], Sources: []dto.TagEventData{}}
+		for _, id := range req.SourceIDs {
+			if v, ok := sources[id]; ok {
+				payload.Sources = append(payload.Sources, *v)
+			}
+		}
+		return writeTagEvent(trx, dto.TagEventMerged, req.TargetID, payload)
+	})
+	if err != nil {
+		r.Logger.Errorf("%s failed merge data: %v \n", opName, err)
+		return toRespErr(err, helpers.ErrUpdatedDB())
+	}
+
+	return nil
+}
+
+// writeTagChange runs change of the tag, already locked, and adds its
+// tag.updated event with the tag before and after it.
+func writeTagChange(trx *gorm.DB, tagID uint64, change func() error) error {
+	before, err := tagSnapshots(trx, []uint64{tagID})
+	if err != nil {
+		return err
+	}
+
+	err = change()
+	if err != nil {
+		return err
+	}
+
+	after, err := tagSnapshots(trx, []uint64{tagID})
+	if err != nil {
+		return err
+	}
+	return writeTagEvent(trx, dto.TagEventUpdated, tagID, dto.TagEventPayload{Before: before[tagID], After: after[tagID]})
+}
+
+// lockTags locks the tags in id order, so concurrent changes of the tree do
+// not deadlock. Every tag must exist.
+func lockTags(trx *gorm.DB, tagIDs ...uint64) error {
+	ids := []uint64{}
+	err := trx.Model(&models.Tag{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", tagIDs).
+		Order("id").
+		Pluck("id", &ids).Error
+	if err != nil {
+		return err
+	}
+
+	isExist := map[uint64]bool{}
+	for _, v := range ids {
+		isExist[v] = true
+	}
+	for _, v := range tagIDs {
+		if !isExist[v] {
+			return errTagNotFound()
+		}
+	}
+	return nil
+}
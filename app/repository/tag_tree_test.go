@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/driver"
+)
+
+func TestTagRepo_TreeAndMerge(t *testing.T) {
+	var (
+		db       = openTestDB(t, "DB_TEST_DSN")
+		cfg      = testConfigs()
+		logger   = driver.Logger(cfg)
+		repo     = NewTagRepository(db, cfg, logger)
+		postRepo = NewPostRepository(db, cfg, logger)
+		ctx      = context.Background()
+	)
+
+	ids, err := repo.Upsert(ctx, []string{"language", "golang", "go-lang", "rust"})
+	if err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+	language, golang, goLang, rust := ids["language"], ids["golang"], ids["go-lang"], ids["rust"]
+
+	for _, child := range []uint64{golang, rust} {
+		if err := repo.SetParent(ctx, dto.TagParentReq{ID: child, ParentID: &language}); err != nil {
+			t.Fatalf("SetParent() error = %v", err)
+		}
+	}
+	if err := repo.SetParent(ctx, dto.TagParentReq{ID: language, ParentID: &golang}); err == nil {
+		t.Errorf("SetParent() below its child error = nil, want cycle")
+	}
+
+	if _, err := repo.AddSynonym(ctx, dto.TagSynonymReq{TagID: golang, Label: "rust"}); err == nil {
+		t.Errorf("AddSynonym() label of a tag error = nil")
+	}
+	if _, err := repo.AddSynonym(ctx, dto.TagSynonymReq{TagID: golang, Label: "go"}); err != nil {
+		t.Fatalf("AddSynonym() error = %v", err)
+	}
+
+	// the synonym and its tag give one post_tag row
+	post, err := postRepo.Create(ctx, dto.PostCreateReq{Title: "go", Content: "content", Tags: []string{"go", "golang"}})
+	if err != nil {
+		t.Fatalf("Create() post error = %v", err)
+	}
+	other, err := postRepo.Create(ctx, dto.PostCreateReq{Title: "go-lang", Content: "content", Tags: []string{"go-lang", "rust"}})
+	if err != nil {
+		t.Fatalf("Create() post error = %v", err)
+	}
+	var total int64
+	db.Model(&models.PostTag{}).Where("post_id = ?", post.ID).Count(&total)
+	if total != 1 {
+		t.Errorf("post_tag rows of the post = %d, want 1", total)
+	}
+
+	tests := []struct {
+		label       string
+		descendants bool
+		want        []string
+	}{
+		{label: "go", want: []string{"golang"}},
+		{label: "language", want: []string{"language"}},
+		{label: "language", descendants: true, want: []string{"golang", "language", "rust"}},
+		{label: "unknown", descendants: true, want: []string{}},
+	}
+	for _, tt := range tests {
+		got, err := repo.Resolve(ctx, tt.label, tt.descendants)
+		labels := []string{}
+		for _, v := range got {
+			labels = append(labels, v.Label)
+		}
+		if err != nil || !reflect.DeepEqual(labels, tt.want) {
+			t.Errorf("Resolve(%s, %v) = %v, %v, want %v", tt.label, tt.descendants, labels, err, tt.want)
+		}
+	}
+
+	err = repo.Merge(ctx, dto.TagMergeReq{TargetID: golang, SourceIDs: []uint64{goLang}})
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	detail, err := postRepo.GetDetail(ctx, dto.PostGetReq{ID: other.ID})
+	if err != nil || !reflect.DeepEqual(detail.Tags, []string{"Golang", "Rust"}) {
+		t.Errorf("GetDetail() after merge = %v, %v", detail, err)
+	}
+	got, err := repo.Resolve(ctx, "go-lang", false)
+	if err != nil || len(got) != 1 || got[0].ID != golang {
+		t.Errorf("Resolve() merged label = %v, %v, want golang", got, err)
+	}
+
+	synonyms, _ := repo.GetSynonyms(ctx)
+	if len(synonyms) != 2 {
+		t.Errorf("GetSynonyms() = %v, want go and go-lang", synonyms)
+	}
+	if err := repo.DeleteSynonym(ctx, dto.TagSynonymReq{TagID: golang, Label: "go"}); err != nil {
+		t.Errorf("DeleteSynonym() error = %v", err)
+	}
+	if err := repo.DeleteSynonym(ctx, dto.TagSynonymReq{TagID: golang, Label: "go"}); err == nil {
+		t.Errorf("DeleteSynonym() twice error = nil")
+	}
+}
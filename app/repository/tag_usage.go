@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+)
+
+// tagSuggestCandidates bounds the labels of each kind of match, by prefix,
+// by synonym and by similarity, that are ranked by usage.
+const tagSuggestCandidates = 100
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Suggest returns the tags whose label or synonym starts with req.Prefix,
+// then those only similar to it (pg_trgm), each group by usage. Only the
+// first candidates of each kind are counted, alphabetically for a prefix
+// and closest first for a similar label, so a short prefix matching many
+// tags costs no more.
+func (r *TagRepo) Suggest(ctx context.Context, req dto.TagSuggestReq) ([]dto.TagUsageRes, error) {
+	var (
+		opName = "TagRepository-Suggest"
+		result = []dto.TagUsageRes{}
+	)
+
+	err := conn(ctx, r.DB).Raw(`WITH matched AS (
+			(SELECT id AS tag_id, 1 AS is_prefix FROM tag WHERE label LIKE @prefix ORDER BY label LIMIT @candidates)
+			UNION ALL
+			(SELECT tag_id, 1 FROM tag_synonym WHERE label LIKE @prefix ORDER BY label LIMIT @candidates)
+			UNION ALL
+			(SELECT id, 0 FROM tag WHERE label % @query ORDER BY label <-> @query LIMIT @candidates)
+		), candidate AS (
+			SELECT tag_id, MAX(is_prefix) AS is_prefix FROM matched GROUP BY tag_id
+		)
+		SELECT tag.id, tag.label, (SELECT COUNT(*) FROM post_tag WHERE post_tag.tag_id = tag.id) AS usage
+		FROM candidate INNER JOIN tag ON tag.id = candidate.tag_id
+		ORDER BY candidate.is_prefix DESC, usage DESC, similarity(tag.label, @query) DESC, tag.label
+		LIMIT @limit`,
+		map[string]interface{}{
+			"prefix":     likeEscaper.Replace(req.Prefix) + "%",
+			"query":      req.Prefix,
+			"candidates": tagSuggestCandidates,
+			"limit":      req.Limit,
+		}).
+		Scan(&result).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	return result, nil
+}
+
+// Popular returns the tags given most to published posts since since.
+func (r *TagRepo) Popular(ctx context.Context, since time.Time, limit int) ([]dto.TagUsageRes, error) {
+	var (
+		opName = "TagRepository-Popular"
+		result = []dto.TagUsageRes{}
+	)
+
+	err := conn(ctx, r.DB).Raw(`SELECT tag.id, tag.label, COUNT(*) AS usage FROM post_tag
+		INNER JOIN tag ON tag.id = post_tag.tag_id
+		INNER JOIN post ON post.id = post_tag.post_id
+		WHERE post_tag.created_at >= @since
+			AND (post.status = @published OR (post.status = @scheduled AND post.publish_at <= NOW()))
+		GROUP BY tag.id, tag.label
+		ORDER BY usage DESC, tag.label
+		LIMIT @limit`,
+		map[string]interface{}{
+			"since":     since,
+			"published": dto.PostStatusPublished,
+			"scheduled": dto.PostStatusScheduled,
+			"limit":     limit,
+		}).
+		Scan(&result).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	return result, nil
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/driver"
+)
+
+func TestTagRepo_SuggestAndPopular(t *testing.T) {
+	var (
+		db       = openTestDB(t, "DB_TEST_DSN")
+		cfg      = testConfigs()
+		logger   = driver.Logger(cfg)
+		repo     = NewTagRepository(db, cfg, logger)
+		postRepo = NewPostRepository(db, cfg, logger)
+		ctx      = context.Background()
+	)
+
+	posts := []dto.PostCreateReq{
+		{Title: "one", Content: "content", Status: dto.PostStatusPublished, Tags: []string{"golang", "gopher"}},
+		{Title: "two", Content: "content", Status: dto.PostStatusPublished, Tags: []string{"golang", "go_basics"}},
+		{Title: "three", Content: "content", Status: dto.PostStatusDraft, Tags: []string{"gopher", "gopher-con", "rust"}},
+	}
+	for _, v := range posts {
+		if _, err := postRepo.Create(ctx, v); err != nil {
+			t.Fatalf("Create() post error = %v", err)
+		}
+	}
+	ids, err := repo.Upsert(ctx, []string{"golang"})
+	if err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+	if _, err := repo.AddSynonym(ctx, dto.TagSynonymReq{TagID: ids["golang"], Label: "goroutine"}); err != nil {
+		t.Fatalf("AddSynonym() error = %v", err)
+	}
+
+	labels := func(tags []dto.TagUsageRes) []string {
+		result := []string{}
+		for _, v := range tags {
+			result = append(result, v.Label)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name string
+		req  dto.TagSuggestReq
+		want []string
+	}{
+		{name: "prefix by usage", req: dto.TagSuggestReq{Prefix: "go", Limit: 10}, want: []string{"golang", "gopher", "go_basics", "gopher-con"}},
+		{name: "limit", req: dto.TagSuggestReq{Prefix: "go", Limit: 2}, want: []string{"golang", "gopher"}},
+		{name: "underscore is no wildcard", req: dto.TagSuggestReq{Prefix: "go_", Limit: 10}, want: []string{"go_basics"}},
+		{name: "by synonym", req: dto.TagSuggestReq{Prefix: "gorout", Limit: 10}, want: []string{"golang"}},
+		{name: "typo", req: dto.TagSuggestReq{Prefix: "gophr", Limit: 10}, want: []string{"gopher"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.Suggest(ctx, tt.req)
+			if err != nil {
+				t.Fatalf("Suggest() error = %v", err)
+			}
+			if got := labels(got); len(got) < len(tt.want) || !reflect.DeepEqual(got[:len(tt.want)], tt.want) {
+				t.Errorf("Suggest() = %v, want %v first", got, tt.want)
+			}
+		})
+	}
+
+	got, err := repo.Popular(ctx, time.Now().Add(-time.Hour), 10)
+	if err != nil {
+		t.Fatalf("Popular() error = %v", err)
+	}
+	if want := []string{"golang", "go_basics", "gopher"}; !reflect.DeepEqual(labels(got), want) {
+		t.Errorf("Popular() = %v, want %v", labels(got), want)
+	}
+
+	db.Model(&models.PostTag{}).Where("true").Update("created_at", time.Now().Add(-48*time.Hour))
+	got, err = repo.Popular(ctx, time.Now().Add(-time.Hour), 10)
+	if err != nil {
+		t.Fatalf("Popular() error = %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("Popular() outside the window = %v, want none", labels(got))
+	}
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/pkg/database"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+const (
+	// TxMaxRetries is how many times WithTx reruns a transaction that failed
+	// on a serialization failure or a deadlock.
+	TxMaxRetries = 3
+
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+)
+
+type txKey struct{}
+
+type txState struct {
+	tx *gorm.DB
+	// afterCommit holds the hooks of this level, a savepoint hands its own
+	// to its parent once released and drops them on rollback.
+	afterCommit []func()
+	// savePoints counts the savepoints of the transaction, naming each one
+	// apart from its siblings and its parents.
+	savePoints *int
+}
+
+// TxFunc is the unit of work run by WithTx. ctx carries the transaction, so
+// repository calls made with it join the same transaction.
+type TxFunc func(ctx context.Context, tx *gorm.DB) error
+
+// WithTx runs fn in a transaction on db, committing when fn returns nil and
+// rolling back when it returns an error or panics (the panic is re-raised).
+//
+// Called with a ctx that already carries a transaction, fn runs inside a
+// savepoint of it instead, so only fn's own writes are rolled back on error.
+// The outermost call reruns fn on serialization failures and deadlocks, so
+// fn must return the database error as is and must be safe to rerun.
+func WithTx(ctx context.Context, db *gorm.DB, fn TxFunc, opts ...*sql.TxOptions) (err error) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return withSavePoint(ctx, state, fn)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = runTx(ctx, db, fn, opts...)
+		if err == nil || !IsRetryableTxErr(err) || attempt >= TxMaxRetries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(attempt*attempt) * 10 * time.Millisecond):
+		}
+	}
+}
+
+func runTx(ctx context.Context, db *gorm.DB, fn TxFunc, opts ...*sql.TxOptions) (err error) {
+	tx := db.WithContext(ctx).Begin(opts...)
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	panicked := true
+	defer func() {
+		if panicked || err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	state := &txState{tx: tx, savePoints: new(int)}
+	txCtx := context.WithValue(ctx, txKey{}, state)
+	err = fn(txCtx, tx.WithContext(txCtx))
+	panicked = false
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return err
+	}
+
+	for _, f := range state.afterCommit {
+		f()
+	}
+	return nil
+}
+
+func withSavePoint(ctx context.Context, parent *txState, fn TxFunc) (err error) {
+	*parent.savePoints++
+	var (
+		state = &txState{tx: parent.tx, savePoints: parent.savePoints}
+		name  = fmt.Sprintf("sp_%d", *parent.savePoints)
+	)
+
+	err = parent.tx.SavePoint(name).Error
+	if err != nil {
+		return err
+	}
+
+	panicked := true
+	defer func() {
+		isFailed := panicked || err != nil
+		if isFailed {
+			parent.tx.RollbackTo(name)
+		}
+
+		// released on rollback too, so a long loop of savepoints does not
+		// keep them all until the commit
+		releaseErr := parent.tx.Exec("RELEASE SAVEPOINT " + name).Error
+		if isFailed {
+			return
+		}
+		if releaseErr != nil {
+			err = releaseErr
+			return
+		}
+		parent.afterCommit = append(parent.afterCommit, state.afterCommit...)
+	}()
+
+	txCtx := context.WithValue(ctx, txKey{}, state)
+	err = fn(txCtx, parent.tx.WithContext(txCtx))
+	panicked = false
+	return err
+}
+
+// AfterCommit runs f once the transaction carried by ctx is committed, or
+// right away outside of a transaction. Nothing runs on rollback, of the
+// transaction or of the savepoint f was added in.
+func AfterCommit(ctx context.Context, f func()) {
+	state, ok := ctx.Value(txKey{}).(*txState)
+	if !ok {
+		f()
+		return
+	}
+	state.afterCommit = append(state.afterCommit, f)
+}
+
+// IsRetryableTxErr reports whether err is a postgres serialization failure
+// or deadlock, after which the whole transaction can be retried.
+func IsRetryableTxErr(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == pgSerializationFailure || pgErr.Code == pgDeadlockDetected
+}
+
+// toRespErr keeps a ResponseError, e.g. not found, as is and hides any other
+// database error behind fallback.
+func toRespErr(err error, fallback *helpers.ResponseError) error {
+	if err == nil {
+		return nil
+	}
+
+	var respErr *helpers.ResponseError
+	if errors.As(err, &respErr) {
+		return respErr
+	}
+	return fallback
+}
+
+// isInTx reports whether ctx carries a transaction.
+func isInTx(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*txState)
+	return ok
+}
+
+// conn returns the transaction carried by ctx, or a reader on db outside of
+// a transaction.
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.tx.WithContext(ctx)
+	}
+	return database.Reader(ctx, db)
+}
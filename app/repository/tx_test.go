@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/driver"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+func TestIsRetryableTxErr(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "serialization failure",
+			err:  fmt.Errorf("commit: %w", &pgconn.PgError{Code: "40001"}),
+			want: true,
+		},
+		{
+			name: "deadlock",
+			err:  &pgconn.PgError{Code: "40P01"},
+			want: true,
+		},
+		{
+			name: "unique violation",
+			err:  &pgconn.PgError{Code: "23505"},
+			want: false,
+		},
+		{
+			name: "other error",
+			err:  errors.New("other"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryableTxErr(tt.err); got != tt.want {
+				t.Errorf("IsRetryableTxErr() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostRepo_UpdateByIDRollback(t *testing.T) {
+	var (
+		db   = openTestDB(t, "DB_TEST_DSN")
+		cfg  = testConfigs()
+		repo = NewPostRepository(db, cfg, driver.Logger(cfg))
+		ctx  = context.Background()
+	)
+
+	post, err := repo.Create(ctx, dto.PostCreateReq{
+		Title:   "before",
+		Content: "before",
+		Tags:    []string{"go"},
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// postgres rejects a NUL byte in text, so the tag insert fails after the
+	// post row was already updated in the same transaction
+	err = repo.UpdateByID(ctx, dto.PostUpdateReq{
+		ID:      post.ID,
+		Title:   "after",
+		Content: "after",
+		Tags:    []string{"ok", "bad\x00tag"},
+	})
+	if err == nil {
+		t.Fatalf("UpdateByID() error = nil, want failed tag insert")
+	}
+
+	got, err := repo.GetDetail(ctx, dto.PostGetReq{ID: post.ID})
+	if err != nil {
+		t.Fatalf("GetDetail() error = %v", err)
+	}
+	if got.Title != "before" || len(got.Tags) != 1 {
+		t.Errorf("GetDetail() = %+v, want the update rolled back", got)
+	}
+}
+
+func TestWithTx(t *testing.T) {
+	var (
+		db  = openTestDB(t, "DB_TEST_DSN")
+		ctx = context.Background()
+	)
+
+	countPosts := func() (total int64) {
+		db.Model(&models.Post{}).Count(&total)
+		return total
+	}
+
+	t.Run("panic rolls back", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("WithTx() did not re-raise the panic")
+			}
+			if got := countPosts(); got != 0 {
+				t.Errorf("posts = %d, want 0", got)
+			}
+		}()
+
+		WithTx(ctx, db, func(ctx context.Context, tx *gorm.DB) error {
+			tx.Create(&models.Post{Title: "panic", Content: "panic"})
+			panic("boom")
+		})
+	})
+
+	t.Run("nested error rolls back the savepoint only", func(t *testing.T) {
+		err := WithTx(ctx, db, func(ctx context.Context, tx *gorm.DB) error {
+			err := tx.Create(&models.Post{Title: "outer", Content: "outer"}).Error
+			if err != nil {
+				return err
+			}
+
+			errInner := WithTx(ctx, db, func(ctx context.Context, tx *gorm.DB) error {
+				tx.Create(&models.Post{Title: "inner", Content: "inner"})
+				return errors.New("inner failed")
+			})
+			if errInner == nil {
+				t.Errorf("nested WithTx() error = nil")
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("WithTx() error = %v", err)
+		}
+
+		if got := countPosts(); got != 1 {
+			t.Errorf("posts = %d, want only the outer one", got)
+		}
+	})
+
+	t.Run("hooks of a rolled back savepoint do not run", func(t *testing.T) {
+		ran := []string{}
+		err := WithTx(ctx, db, func(ctx context.Context, tx *gorm.DB) error {
+			AfterCommit(ctx, func() { ran = append(ran, "outer") })
+
+			WithTx(ctx, db, func(ctx context.Context, tx *gorm.DB) error {
+				AfterCommit(ctx, func() { ran = append(ran, "released") })
+				return nil
+			})
+			WithTx(ctx, db, func(ctx context.Context, tx *gorm.DB) error {
+				WithTx(ctx, db, func(ctx context.Context, tx *gorm.DB) error {
+					AfterCommit(ctx, func() { ran = append(ran, "inside rolled back") })
+					return nil
+				})
+				AfterCommit(ctx, func() { ran = append(ran, "rolled back") })
+				return errors.New("inner failed")
+			})
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("WithTx() error = %v", err)
+		}
+
+		if want := []string{"outer", "released"}; !reflect.DeepEqual(ran, want) {
+			t.Errorf("hooks ran = %v, want %v", ran, want)
+		}
+	})
+
+	t.Run("savepoints are released", func(t *testing.T) {
+		errDone := errors.New("done")
+		err := WithTx(ctx, db, func(ctx context.Context, tx *gorm.DB) error {
+			for i := 0; i < 2; i++ {
+				err := WithTx(ctx, db, func(ctx context.Context, tx *gorm.DB) error {
+					return tx.Create(&models.Post{Title: "sibling", Content: "sibling"}).Error
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			if tx.Exec("ROLLBACK TO SAVEPOINT sp_1").Error == nil {
+				t.Errorf("savepoint sp_1 is not released")
+			}
+			return errDone
+		})
+		if err != errDone {
+			t.Fatalf("WithTx() error = %v, want %v", err, errDone)
+		}
+	})
+
+	t.Run("retry serialization failure", func(t *testing.T) {
+		attempt := 0
+		err := WithTx(ctx, db, func(ctx context.Context, tx *gorm.DB) error {
+			attempt++
+			if attempt == 1 {
+				return &pgconn.PgError{Code: pgSerializationFailure}
+			}
+			return nil
+		})
+		if err != nil || attempt != 2 {
+			t.Errorf("WithTx() error = %v after %d attempts, want success on the 2nd", err, attempt)
+		}
+	})
+}
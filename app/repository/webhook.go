@@ -0,0 +1,307 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WebhookRepository stores the webhooks and the deliveries of events to
+// them.
+type WebhookRepository interface {
+	Create(ctx context.Context, req dto.WebhookReq) (*models.Webhook, error)
+	GetList(ctx context.Context) ([]models.Webhook, error)
+	GetDetail(ctx context.Context, id uint64) (*models.Webhook, error)
+	UpdateByID(ctx context.Context, req dto.WebhookReq) (*models.Webhook, error)
+	DeleteByID(ctx context.Context, id uint64) error
+	GetActive(ctx context.Context) ([]models.Webhook, error)
+	CreateDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error
+	ClaimDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error)
+	SaveAttempt(ctx context.Context, delivery *models.WebhookDelivery) error
+	GetDeliveries(ctx context.Context, req dto.WebhookDeliveryListReq) ([]models.WebhookDelivery, error)
+	Redeliver(ctx context.Context, req dto.WebhookRedeliverReq) (*models.WebhookDelivery, error)
+}
+
+type WebhookRepo struct {
+	DB     *gorm.DB
+	Cfg    *configs.Configs
+	Logger *logrus.Logger
+}
+
+func NewWebhookRepository(
+	db *gorm.DB,
+	cfg *configs.Configs,
+	logger *logrus.Logger,
+) WebhookRepository {
+	return &WebhookRepo{
+		DB:     db,
+		Cfg:    cfg,
+		Logger: logger,
+	}
+}
+
+func errWebhookNotFound() *helpers.ResponseError {
+	return helpers.ErrDataNotFound("webhook", "webhook")
+}
+
+func (r *WebhookRepo) Create(ctx context.Context, req dto.WebhookReq) (*models.Webhook, error) {
+	var (
+		opName = "WebhookRepository-Create"
+		result = models.Webhook{
+			URL:      req.URL,
+			Events:   models.StringList(req.Events),
+			Secret:   req.Secret,
+			IsActive: req.IsActive != nil && *req.IsActive,
+		}
+	)
+
+	err := conn(ctx, r.DB).Create(&result).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed create data: %v \n", opName, err)
+		return nil, helpers.ErrCreatedDB()
+	}
+
+	return &result, nil
+}
+
+func (r *WebhookRepo) GetList(ctx context.Context) ([]models.Webhook, error) {
+	var (
+		opName = "WebhookRepository-GetList"
+		result = []models.Webhook{}
+	)
+
+	err := conn(ctx, r.DB).Order("id").Find(&result).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	return result, nil
+}
+
+func (r *WebhookRepo) GetDetail(ctx context.Context, id uint64) (*models.Webhook, error) {
+	var (
+		opName = "WebhookRepository-GetDetail"
+		result = models.Webhook{}
+	)
+
+	err := conn(ctx, r.DB).Where("id = ?", id).First(&result).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errWebhookNotFound()
+		}
+
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	return &result, nil
+}
+
+// UpdateByID replaces the url, events and state of the webhook, and its
+// secret when req has one.
+func (r *WebhookRepo) UpdateByID(ctx context.Context, req dto.WebhookReq) (*models.Webhook, error) {
+	var (
+		opName  = "WebhookRepository-UpdateByID"
+		updated = []models.Webhook{}
+		columns = map[string]interface{}{
+			"url":        req.URL,
+			"events":     models.StringList(req.Events),
+			"is_active":  req.IsActive != nil && *req.IsActive,
+			"updated_at": time.Now(),
+		}
+	)
+	if req.Secret != "" {
+		columns["secret"] = req.Secret
+	}
+
+	err := conn(ctx, r.DB).Model(&updated).
+		Clauses(clause.Returning{}).
+		Where("id = ?", req.ID).
+		Updates(columns).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed update data: %v \n", opName, err)
+		return nil, helpers.ErrUpdatedDB()
+	}
+	if len(updated) == 0 {
+		return nil, errWebhookNotFound()
+	}
+
+	return &updated[0], nil
+}
+
+// DeleteByID removes the webhook with its deliveries.
+func (r *WebhookRepo) DeleteByID(ctx context.Context, id uint64) error {
+	opName := "WebhookRepository-DeleteByID"
+
+	res := conn(ctx, r.DB).Where("id = ?", id).Delete(&models.Webhook{})
+	if res.Error != nil {
+		r.Logger.Errorf("%s failed delete data: %v \n", opName, res.Error)
+		return helpers.ErrDB()
+	}
+	if res.RowsAffected == 0 {
+		return errWebhookNotFound()
+	}
+
+	return nil
+}
+
+func (r *WebhookRepo) GetActive(ctx context.Context) ([]models.Webhook, error) {
+	var (
+		opName = "WebhookRepository-GetActive"
+		result = []models.Webhook{}
+	)
+
+	err := conn(ctx, r.DB).Where("is_active").Order("id").Find(&result).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	return result, nil
+}
+
+// CreateDeliveries stores the deliveries, skipping those of an event already
+// delivered to the same webhook, e.g. when the relay publishes it again.
+func (r *WebhookRepo) CreateDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	opName := "WebhookRepository-CreateDeliveries"
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	err := conn(ctx, r.DB).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Omit("Webhook").
+		Create(&deliveries).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed create data: %v \n", opName, err)
+		return helpers.ErrCreatedDB()
+	}
+
+	return nil
+}
+
+// ClaimDeliveries returns up to limit deliveries due at now of active
+// webhooks, with their webhook, oldest first. They are not due again until
+// leaseUntil, so senders running on several instances do not send them
+// twice, and one that crashed while sending is sent again after it.
+func (r *WebhookRepo) ClaimDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]models.WebhookDelivery, error) {
+	var (
+		opName = "WebhookRepository-ClaimDeliveries"
+		result = []models.WebhookDelivery{}
+	)
+
+	err := WithTx(ctx, r.DB, func(ctx context.Context, trx *gorm.DB) error {
+		ids := []uint64{}
+		err := trx.Model(&models.WebhookDelivery{}).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ? AND next_attempt_at <= ?", []string{dto.WebhookStatusPending, dto.WebhookStatusRetrying}, now).
+			Where("EXISTS (SELECT 1 FROM webhook WHERE webhook.id = webhook_delivery.webhook_id AND webhook.is_active)").
+			Order("id").
+			Limit(limit).
+			Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+
+		err = trx.Model(&models.WebhookDelivery{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", leaseUntil).Error
+		if err != nil {
+			return err
+		}
+
+		return trx.Preload("Webhook").Where("id IN ?", ids).Order("id").Find(&result).Error
+	})
+	if err != nil {
+		r.Logger.Errorf("%s failed claim data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	return result, nil
+}
+
+// SaveAttempt stores the outcome of an attempt to send the delivery.
+func (r *WebhookRepo) SaveAttempt(ctx context.Context, delivery *models.WebhookDelivery) error {
+	opName := "WebhookRepository-SaveAttempt"
+
+	err := conn(ctx, r.DB).Model(&models.WebhookDelivery{}).
+		Where("id = ?", delivery.ID).
+		Updates(map[string]interface{}{
+			"status":           delivery.Status,
+			"attempts":         delivery.Attempts,
+			"next_attempt_at":  delivery.NextAttemptAt,
+			"last_status_code": delivery.LastStatusCode,
+			"last_error":       delivery.LastError,
+			"last_response":    delivery.LastResponse,
+			"delivered_at":     delivery.DeliveredAt,
+			"updated_at":       time.Now(),
+		}).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed update data: %v \n", opName, err)
+		return helpers.ErrUpdatedDB()
+	}
+
+	return nil
+}
+
+// GetDeliveries returns the deliveries of the webhook, newest first.
+func (r *WebhookRepo) GetDeliveries(ctx context.Context, req dto.WebhookDeliveryListReq) ([]models.WebhookDelivery, error) {
+	var (
+		opName = "WebhookRepository-GetDeliveries"
+		query  = conn(ctx, r.DB).Where("webhook_id = ?", req.WebhookID)
+		result = []models.WebhookDelivery{}
+	)
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+
+	err := query.
+		Order("id DESC").
+		Limit(req.Limit).
+		Offset(req.Offset).
+		Find(&result).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, helpers.ErrDB()
+	}
+
+	return result, nil
+}
+
+// Redeliver makes the delivery pending and due now with all its attempts,
+// the outcome of its last attempt is kept until the next one.
+func (r *WebhookRepo) Redeliver(ctx context.Context, req dto.WebhookRedeliverReq) (*models.WebhookDelivery, error) {
+	var (
+		opName  = "WebhookRepository-Redeliver"
+		updated = []models.WebhookDelivery{}
+		now     = time.Now()
+	)
+
+	err := conn(ctx, r.DB).Model(&updated).
+		Clauses(clause.Returning{}).
+		Where("id = ? AND webhook_id = ?", req.DeliveryID, req.WebhookID).
+		Updates(map[string]interface{}{
+			"status":          dto.WebhookStatusPending,
+			"attempts":        0,
+			"next_attempt_at": now,
+			"updated_at":      now,
+		}).Error
+	if err != nil {
+		r.Logger.Errorf("%s failed update data: %v \n", opName, err)
+		return nil, helpers.ErrUpdatedDB()
+	}
+	if len(updated) == 0 {
+		return nil, helpers.ErrDataNotFound("pengiriman webhook", "webhook delivery")
+	}
+
+	return &updated[0], nil
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/driver"
+)
+
+func TestWebhookRepo(t *testing.T) {
+	var (
+		db       = openTestDB(t, "DB_TEST_DSN")
+		cfg      = testConfigs()
+		repo     = NewWebhookRepository(db, cfg, driver.Logger(cfg))
+		ctx      = context.Background()
+		isActive = true
+		inactive = false
+	)
+
+	hook, err := repo.Create(ctx, dto.WebhookReq{URL: "https://example.com/hook", Events: []string{"post.*"}, Secret: "first-secret-value", IsActive: &isActive})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	paused, err := repo.Create(ctx, dto.WebhookReq{URL: "https://example.com/paused", Events: []string{"*"}, Secret: "other-secret-value", IsActive: &inactive})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	updated, err := repo.UpdateByID(ctx, dto.WebhookReq{ID: hook.ID, URL: "https://example.com/v2", Events: []string{"*"}, IsActive: &isActive})
+	if err != nil || updated.URL != "https://example.com/v2" || updated.Secret != "first-secret-value" {
+		t.Fatalf("UpdateByID() = %+v, %v, want the new url with the secret kept", updated, err)
+	}
+	if _, err := repo.UpdateByID(ctx, dto.WebhookReq{ID: 999, URL: "https://example.com", IsActive: &isActive}); err == nil {
+		t.Errorf("UpdateByID() unknown error = nil, want not found")
+	}
+
+	active, err := repo.GetActive(ctx)
+	if err != nil || len(active) != 1 || active[0].ID != hook.ID {
+		t.Fatalf("GetActive() = %+v, %v, want only webhook %d", active, err, hook.ID)
+	}
+
+	now := time.Now()
+	deliveries := []models.WebhookDelivery{
+		{WebhookID: hook.ID, EventID: 1, EventType: dto.PostEventCreated, Payload: models.JSON(`{"id":1}`), Status: dto.WebhookStatusPending, NextAttemptAt: now},
+		{WebhookID: hook.ID, EventID: 2, EventType: dto.PostEventUpdated, Payload: models.JSON(`{"id":2}`), Status: dto.WebhookStatusPending, NextAttemptAt: now},
+		{WebhookID: paused.ID, EventID: 1, EventType: dto.PostEventCreated, Payload: models.JSON(`{"id":1}`), Status: dto.WebhookStatusPending, NextAttemptAt: now},
+	}
+	if err := repo.CreateDeliveries(ctx, deliveries); err != nil {
+		t.Fatalf("CreateDeliveries() error = %v", err)
+	}
+	// the relay publishing the event again adds nothing
+	if err := repo.CreateDeliveries(ctx, deliveries[:1]); err != nil {
+		t.Fatalf("CreateDeliveries() again error = %v", err)
+	}
+
+	claimed, err := repo.ClaimDeliveries(ctx, now.Add(time.Second), now.Add(time.Minute), 10)
+	if err != nil || len(claimed) != 2 || claimed[0].Webhook == nil || claimed[0].Webhook.Secret != "first-secret-value" {
+		t.Fatalf("ClaimDeliveries() = %+v, %v, want the 2 deliveries of the active webhook", claimed, err)
+	}
+	if again, err := repo.ClaimDeliveries(ctx, now.Add(time.Second), now.Add(time.Minute), 10); err != nil || len(again) != 0 {
+		t.Errorf("ClaimDeliveries() during the lease = %d, %v, want none", len(again), err)
+	}
+
+	deliveredAt := time.Now()
+	claimed[0].Status, claimed[0].Attempts, claimed[0].LastStatusCode, claimed[0].DeliveredAt = dto.WebhookStatusSucceeded, 1, 200, &deliveredAt
+	claimed[1].Status, claimed[1].Attempts, claimed[1].LastStatusCode, claimed[1].LastError = dto.WebhookStatusDead, 8, 500, "unexpected status 500"
+	for i := range claimed {
+		if err := repo.SaveAttempt(ctx, &claimed[i]); err != nil {
+			t.Fatalf("SaveAttempt() error = %v", err)
+		}
+	}
+
+	dead, err := repo.GetDeliveries(ctx, dto.WebhookDeliveryListReq{WebhookID: hook.ID, Status: dto.WebhookStatusDead, Limit: 10})
+	if err != nil || len(dead) != 1 || dead[0].ID != claimed[1].ID || dead[0].LastError == "" {
+		t.Fatalf("GetDeliveries() dead = %+v, %v", dead, err)
+	}
+
+	redelivered, err := repo.Redeliver(ctx, dto.WebhookRedeliverReq{WebhookID: hook.ID, DeliveryID: dead[0].ID})
+	if err != nil || redelivered.Status != dto.WebhookStatusPending || redelivered.Attempts != 0 || redelivered.LastStatusCode != 500 {
+		t.Fatalf("Redeliver() = %+v, %v, want pending with the last outcome kept", redelivered, err)
+	}
+	if _, err := repo.Redeliver(ctx, dto.WebhookRedeliverReq{WebhookID: paused.ID, DeliveryID: dead[0].ID}); err == nil {
+		t.Errorf("Redeliver() of another webhook error = nil, want not found")
+	}
+	if again, err := repo.ClaimDeliveries(ctx, time.Now(), time.Now().Add(time.Minute), 10); err != nil || len(again) != 1 {
+		t.Errorf("ClaimDeliveries() after Redeliver() = %d, %v, want 1", len(again), err)
+	}
+
+	if err := repo.DeleteByID(ctx, hook.ID); err != nil {
+		t.Fatalf("DeleteByID() error = %v", err)
+	}
+	var total int64
+	db.Model(&models.WebhookDelivery{}).Where("webhook_id = ?", hook.ID).Count(&total)
+	if total != 0 {
+		t.Errorf("deliveries left after DeleteByID() = %d, want 0", total)
+	}
+	if _, err := repo.GetDetail(ctx, hook.ID); err == nil {
+		t.Errorf("GetDetail() deleted error = nil, want not found")
+	}
+}
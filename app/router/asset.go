@@ -0,0 +1,36 @@
+package router
+
+import (
+	"github.com/adamnasrudin03/go-asset-findr/app/controller"
+	"github.com/adamnasrudin03/go-asset-findr/app/middlewares"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/auth"
+	"github.com/gin-gonic/gin"
+)
+
+func (r routes) assetRouter(rg *gin.RouterGroup, handler controller.AssetController) {
+	var (
+		asset      = rg.Group("/assets")
+		editorOnly = middlewares.AuthorizationMustBe(auth.RoleEditor, auth.RoleAdmin)
+	)
+	{
+		asset.POST("", editorOnly, handler.Upload)
+		asset.GET("", editorOnly, handler.GetList)
+		asset.GET("/:id", handler.GetDetail)
+		asset.GET("/:id/download", handler.Download)
+		asset.GET("/:id/thumbnails/:size", handler.DownloadThumbnail)
+		asset.DELETE("/:id", editorOnly, handler.Delete)
+	}
+
+	postAsset := rg.Group("/posts/:id/assets")
+	{
+		postAsset.GET("", handler.GetByPost)
+		postAsset.POST("", editorOnly, handler.Attach)
+		postAsset.DELETE("/:asset_id", editorOnly, handler.Detach)
+	}
+
+	storage := rg.Group("/admin/storage", middlewares.AuthorizationMustBe(auth.RoleAdmin))
+	{
+		storage.GET("/report", handler.StorageReport)
+		storage.POST("/gc", handler.CollectGarbage)
+	}
+}
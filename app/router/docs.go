@@ -0,0 +1,98 @@
+package router
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/openapi"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/gin-gonic/gin"
+)
+
+const swaggerUI = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>API Docs</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
+
+// docsRouter serves the OpenAPI document of the routes at /openapi.json and
+// Swagger UI at /docs. The document is built on the first request, once
+// every route is registered.
+func (r routes) docsRouter(rg *gin.RouterGroup) {
+	var (
+		once sync.Once
+		doc  openapi.Document
+	)
+
+	rg.GET("/openapi.json", func(c *gin.Context) {
+		once.Do(func() {
+			doc, _ = newDocument(r.cfg, r.router.Routes())
+		})
+		c.JSON(http.StatusOK, doc)
+	})
+	rg.GET("/docs", func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUI))
+	})
+}
+
+// newDocument builds the OpenAPI document of routes from operations, it
+// also returns the routes operations has nothing about.
+func newDocument(cfg *configs.Configs, routes gin.RoutesInfo) (openapi.Document, []string) {
+	var (
+		builder = openapi.NewBuilder(openapi.Info{
+			Title:       cfg.App.Name,
+			Description: "Send the bearer JWT in the Authorization header, routes without security take it too.",
+			Version:     "1.0.0",
+		}, helpers.ResponseError{})
+		missing []string
+	)
+	builder.PathParam = pathParam
+
+	for _, v := range routes {
+		key := v.Method + " " + v.Path
+		route, ok := operations[key]
+		if !ok {
+			missing = append(missing, key)
+			continue
+		}
+
+		route.ID = operationID(v.Handler)
+		builder.Add(v.Method, v.Path, route)
+	}
+
+	return builder.Document(), missing
+}
+
+// pathParam is an integer for the IDs, like :id and :asset_id, and the
+// revision and thumbnail size.
+func pathParam(name string) *openapi.Schema {
+	if name == "id" || name == "rev" || name == "size" || strings.HasSuffix(name, "_id") {
+		return &openapi.Schema{Type: "integer", Format: "int64"}
+	}
+	return &openapi.Schema{Type: "string"}
+}
+
+// operationID names the operation after its controller method, e.g.
+// Post.GetDetail for PostController.GetDetail. Routes handled in this
+// package have none.
+func operationID(handler string) string {
+	handler = strings.TrimSuffix(handler[strings.LastIndex(handler, "/")+1:], "-fm")
+	parts := strings.Split(handler, ".")
+	if len(parts) != 3 || parts[0] != "controller" {
+		return ""
+	}
+	return strings.TrimSuffix(parts[1], "Controller") + "." + parts[2]
+}
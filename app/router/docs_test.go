@@ -0,0 +1,109 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/controller"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/openapi"
+	"github.com/gin-gonic/gin"
+)
+
+func newTestRoutes() routes {
+	gin.SetMode(gin.TestMode)
+	return NewRoutes(controller.Controllers{
+		Post:    controller.NewPostDelivery(nil, nil, nil, nil),
+		Asset:   controller.NewAssetDelivery(nil, nil, nil),
+		Tag:     controller.NewTagDelivery(nil, nil),
+		Report:  controller.NewReportDelivery(nil, nil),
+		Webhook: controller.NewWebhookDelivery(nil, nil),
+		GraphQL: controller.NewGraphQLDelivery(nil, nil),
+	}, configs.GetInstance())
+}
+
+// TestOperations fails when a route is added without its entry in
+// operations, or an entry stays after its route is gone.
+func TestOperations(t *testing.T) {
+	var (
+		r          = newTestRoutes()
+		routes     = r.router.Routes()
+		registered = map[string]bool{}
+	)
+
+	_, missing := newDocument(r.cfg, routes)
+	for _, v := range missing {
+		t.Errorf("route %q has no entry in operations", v)
+	}
+
+	for _, v := range routes {
+		registered[v.Method+" "+v.Path] = true
+	}
+	for key := range operations {
+		if !registered[key] {
+			t.Errorf("operations has %q, no such route", key)
+		}
+	}
+}
+
+func TestDocsRouter(t *testing.T) {
+	r := newTestRoutes()
+
+	w := httptest.NewRecorder()
+	r.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /openapi.json status = %d", w.Code)
+	}
+
+	doc := openapi.Document{}
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("GET /openapi.json body error = %v", err)
+	}
+	for _, name := range []string{"PostCreateReq", "PostUpdateReq", "PostRes", "ResponseError"} {
+		if doc.Components.Schemas[name] == nil {
+			t.Errorf("schema %s is missing", name)
+		}
+	}
+
+	op := doc.Paths["/api/posts/{id}"]["put"]
+	if op == nil || op.OperationID != "Post.Update" || op.RequestBody.Content["application/json"].Schema.Ref != "#/components/schemas/PostUpdateReq" {
+		t.Errorf("PUT /api/posts/{id} = %+v", op)
+	}
+	if op := doc.Paths["/api/posts/{id}/status"]["put"]; op == nil || len(op.Security) != 1 {
+		t.Errorf("PUT /api/posts/{id}/status = %+v, want the bearer security", op)
+	}
+
+	// every reference resolves
+	for _, v := range regexp.MustCompile(`"\$ref":"#/components/schemas/([^"]+)"`).FindAllStringSubmatch(w.Body.String(), -1) {
+		if doc.Components.Schemas[v[1]] == nil {
+			t.Errorf("schema %s is referenced, not defined", v[1])
+		}
+	}
+
+	w = httptest.NewRecorder()
+	r.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "text/html; charset=utf-8" {
+		t.Errorf("GET /docs = %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+}
+
+func TestOperationID(t *testing.T) {
+	tests := []struct {
+		handler string
+		want    string
+	}{
+		{handler: "github.com/adamnasrudin03/go-asset-findr/app/controller.PostController.GetDetail-fm", want: "Post.GetDetail"},
+		{handler: "github.com/adamnasrudin03/go-asset-findr/app/controller.GraphQLController.Query-fm", want: "GraphQL.Query"},
+		{handler: "github.com/adamnasrudin03/go-asset-findr/app/router.NewRoutes.func1", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.handler, func(t *testing.T) {
+			if got := operationID(tt.handler); got != tt.want {
+				t.Errorf("operationID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package router
+
+import (
+	"github.com/adamnasrudin03/go-asset-findr/app/controller"
+	"github.com/gin-gonic/gin"
+)
+
+func (r routes) graphqlRouter(rg *gin.RouterGroup, handler controller.GraphQLController) {
+	rg.POST("/graphql", handler.Query)
+}
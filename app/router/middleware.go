@@ -0,0 +1,92 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/adamnasrudin03/go-asset-findr/pkg/database"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	readPrimaryHeader = "X-Read-Your-Writes"
+	readPrimaryCookie = "read_your_writes"
+)
+
+// readYourWrites keeps a client on the primary database for ttl seconds after
+// a mutation, so it never reads a stale replica. Clients can also
+// ask for it per request with the X-Read-Your-Writes: true header.
+//
+// A request is a mutation when it wrote to the database and did not fail,
+// a POST that only reads, e.g. a GraphQL query, keeps the replicas.
+func readYourWrites(ttl int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		_, errCookie := c.Cookie(readPrimaryCookie)
+		if errCookie == nil || c.GetHeader(readPrimaryHeader) == "true" {
+			ctx = database.WithReadPrimary(ctx)
+		}
+		c.Request = c.Request.WithContext(database.WithWriteTracker(ctx))
+		if ttl <= 0 {
+			c.Next()
+			return
+		}
+
+		w := &readPrimaryWriter{ResponseWriter: c.Writer, req: c.Request, ttl: ttl}
+		c.Writer = w
+		c.Next()
+		if !w.Written() {
+			w.setCookie()
+		}
+	}
+}
+
+// readPrimaryWriter sets the read primary cookie right before the headers
+// are written, once the status of the handler is known.
+type readPrimaryWriter struct {
+	gin.ResponseWriter
+	req  *http.Request
+	ttl  int
+	done bool
+}
+
+func (w *readPrimaryWriter) setCookie() {
+	if w.done {
+		return
+	}
+	w.done = true
+
+	isMutation := w.req.Method == http.MethodPost ||
+		w.req.Method == http.MethodPut ||
+		w.req.Method == http.MethodDelete
+	if !isMutation || w.Status() >= http.StatusBadRequest || !database.HasWritten(w.req.Context()) {
+		return
+	}
+
+	http.SetCookie(w.ResponseWriter, &http.Cookie{
+		Name:     readPrimaryCookie,
+		Value:    "1",
+		MaxAge:   w.ttl,
+		Path:     "/",
+		HttpOnly: true,
+	})
+}
+
+func (w *readPrimaryWriter) WriteHeaderNow() {
+	w.setCookie()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *readPrimaryWriter) Write(data []byte) (int, error) {
+	w.setCookie()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *readPrimaryWriter) WriteString(s string) (int, error) {
+	w.setCookie()
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *readPrimaryWriter) Flush() {
+	w.setCookie()
+	w.ResponseWriter.Flush()
+}
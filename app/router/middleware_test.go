@@ -0,0 +1,112 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/adamnasrudin03/go-asset-findr/pkg/database"
+	"github.com/gin-gonic/gin"
+)
+
+func TestReadYourWrites(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		method     string
+		handler    gin.HandlerFunc
+		wantCookie bool
+	}{
+		{
+			name:   "write",
+			method: http.MethodPost,
+			handler: func(c *gin.Context) {
+				database.MarkWritten(c)
+				c.JSON(http.StatusCreated, gin.H{"id": 1})
+			},
+			wantCookie: true,
+		},
+		{
+			name:   "write without body",
+			method: http.MethodDelete,
+			handler: func(c *gin.Context) {
+				database.MarkWritten(c)
+				c.Status(http.StatusNoContent)
+			},
+			wantCookie: true,
+		},
+		{
+			name:   "write flushed",
+			method: http.MethodPut,
+			handler: func(c *gin.Context) {
+				database.MarkWritten(c)
+				c.Writer.Flush()
+				c.String(http.StatusOK, "ok")
+			},
+			wantCookie: true,
+		},
+		{
+			name:   "post without write",
+			method: http.MethodPost,
+			handler: func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"data": nil})
+			},
+			wantCookie: false,
+		},
+		{
+			name:   "failed write",
+			method: http.MethodPost,
+			handler: func(c *gin.Context) {
+				database.MarkWritten(c)
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid"})
+			},
+			wantCookie: false,
+		},
+		{
+			name:   "get",
+			method: http.MethodGet,
+			handler: func(c *gin.Context) {
+				database.MarkWritten(c)
+				c.JSON(http.StatusOK, gin.H{})
+			},
+			wantCookie: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.ContextWithFallback = true
+			r.Use(readYourWrites(60))
+			r.Handle(tt.method, "/", tt.handler)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tt.method, "/", nil))
+
+			gotCookie := strings.Contains(w.Header().Get("Set-Cookie"), readPrimaryCookie+"=1")
+			if gotCookie != tt.wantCookie {
+				t.Errorf("readYourWrites() cookie = %v, want %v", gotCookie, tt.wantCookie)
+			}
+		})
+	}
+}
+
+func TestReadYourWrites_ReadPrimary(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.ContextWithFallback = true
+	r.Use(readYourWrites(60))
+
+	var got bool
+	r.GET("/", func(c *gin.Context) {
+		got = database.IsReadPrimary(c)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: readPrimaryCookie, Value: "1"})
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	if !got {
+		t.Error("readYourWrites() with cookie read primary = false, want true")
+	}
+}
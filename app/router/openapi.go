@@ -0,0 +1,251 @@
+package router
+
+import (
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/graph"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/auth"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/openapi"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+)
+
+var (
+	editors = []string{auth.RoleEditor, auth.RoleAdmin}
+	admins  = []string{auth.RoleAdmin}
+
+	binary = &openapi.Schema{Type: "string", Format: "binary"}
+)
+
+// operations documents every route by "METHOD path", as registered with gin.
+// A route missing here fails TestOperations.
+var operations = map[string]openapi.Route{
+	"GET /": {Tag: "Docs", Summary: "Welcome message", Response: helpers.ResponseDefault{}},
+	"GET /openapi.json": {
+		Tag: "Docs", Summary: "This OpenAPI document",
+		Response: &openapi.Schema{Type: "object"},
+	},
+	"GET /docs": {
+		Tag: "Docs", Summary: "Swagger UI of this document",
+		ResponseType: "text/html", Response: &openapi.Schema{Type: "string"},
+	},
+
+	// posts
+	"GET /api/posts": {
+		Tag: "Posts", Summary: "List the posts, by tag",
+		Query: dto.PostListReq{}, Response: []dto.PostRes{},
+	},
+	"GET /api/posts/:id": {
+		Tag: "Posts", Summary: "Get a post",
+		Query: dto.PostGetReq{}, Response: dto.PostRes{},
+	},
+	"GET /api/posts/by-slug/:slug": {
+		Tag: "Posts", Summary: "Get a post by its slug, an old slug redirects to the current one",
+		Response: dto.PostRes{},
+	},
+	"GET /api/posts/:id/related": {
+		Tag: "Posts", Summary: "List the posts sharing the most tags with a post",
+		Query: dto.PostRelatedReq{}, Response: []dto.PostRelatedRes{},
+	},
+	"GET /api/posts/stream": {
+		Tag: "Posts", Summary: "Stream the post changes as server-sent events",
+		Query: dto.PostStreamReq{}, ResponseType: "text/event-stream", Response: dto.PostStreamEvent{},
+	},
+	"POST /api/posts": {
+		Tag: "Posts", Summary: "Create a post, only editors may create it past draft",
+		Body: dto.PostCreateReq{}, Status: 201, Response: dto.PostRes{},
+	},
+	"PUT /api/posts/:id": {
+		Tag: "Posts", Summary: "Update a post",
+		Body: dto.PostUpdateReq{}, Response: dto.ResponseMessage{},
+	},
+	"PUT /api/posts/:id/status": {
+		Tag: "Posts", Summary: "Move a post along the status workflow", Roles: editors,
+		Body: dto.PostStatusReq{}, Response: dto.ResponseMessage{},
+	},
+	"DELETE /api/posts/:id": {
+		Tag: "Posts", Summary: "Delete a post",
+		Response: dto.ResponseMessage{},
+	},
+	"POST /api/posts/bulk": {
+		Tag: "Posts", Summary: "Create many posts, 207 when only some are",
+		Body: dto.PostBulkCreateReq{}, Status: 201, Response: dto.PostBulkRes{},
+	},
+	"POST /api/posts/bulk/update": {
+		Tag: "Posts", Summary: "Update many posts, 207 when only some are",
+		Body: dto.PostBulkUpdateReq{}, Response: dto.PostBulkRes{},
+	},
+	"POST /api/posts/bulk/delete": {
+		Tag: "Posts", Summary: "Delete many posts, 207 when only some are",
+		Body: dto.PostBulkDeleteReq{}, Response: dto.PostBulkRes{},
+	},
+	"GET /api/posts/export": {
+		Tag: "Posts", Summary: "Export the posts as JSON lines or CSV", Roles: editors,
+		Query: dto.PostExportReq{}, ResponseType: "application/octet-stream", Response: binary,
+	},
+	"POST /api/posts/import": {
+		Tag: "Posts", Summary: "Import posts from JSON lines or CSV, 207 when only some are", Roles: editors,
+		Query: dto.PostImportReq{}, BodyType: "multipart/form-data", Body: files("file", false),
+		Response: dto.PostImportRes{},
+	},
+
+	// post revisions
+	"GET /api/posts/:id/revisions": {
+		Tag: "Revisions", Summary: "List the revisions of a post", Roles: editors,
+		Response: []dto.PostRevisionRes{},
+	},
+	"GET /api/posts/:id/revisions/diff": {
+		Tag: "Revisions", Summary: "Diff two revisions of a post", Roles: editors,
+		Query: dto.PostRevisionDiffReq{}, Response: dto.PostRevisionDiffRes{},
+	},
+	"GET /api/posts/:id/revisions/:rev": {
+		Tag: "Revisions", Summary: "Get a revision of a post", Roles: editors,
+		Response: dto.PostRevisionRes{},
+	},
+	"POST /api/posts/:id/revisions/:rev/restore": {
+		Tag: "Revisions", Summary: "Restore a post to a revision", Roles: editors,
+		Response: dto.ResponseMessage{},
+	},
+
+	// assets
+	"POST /api/assets": {
+		Tag: "Assets", Summary: "Upload assets, 207 when only some are", Roles: editors,
+		BodyType: "multipart/form-data", Body: files("file", true),
+		Status: 201, Response: dto.AssetUploadRes{},
+	},
+	"GET /api/assets": {
+		Tag: "Assets", Summary: "List the assets, by filters like width>=1024 or mime=image/*", Roles: editors,
+		Query: dto.AssetListReq{}, Response: []dto.AssetRes{},
+	},
+	"GET /api/assets/:id": {
+		Tag: "Assets", Summary: "Get an asset",
+		Response: dto.AssetRes{},
+	},
+	"GET /api/assets/:id/download": {
+		Tag: "Assets", Summary: "Download an asset",
+		ResponseType: "application/octet-stream", Response: binary,
+	},
+	"GET /api/assets/:id/thumbnails/:size": {
+		Tag: "Assets", Summary: "Download a thumbnail of an image asset",
+		ResponseType: "application/octet-stream", Response: binary,
+	},
+	"DELETE /api/assets/:id": {
+		Tag: "Assets", Summary: "Delete an asset", Roles: editors,
+		Response: dto.ResponseMessage{},
+	},
+	"GET /api/posts/:id/assets": {
+		Tag: "Assets", Summary: "List the assets of a post",
+		Response: []dto.AssetRes{},
+	},
+	"POST /api/posts/:id/assets": {
+		Tag: "Assets", Summary: "Attach an asset to a post", Roles: editors,
+		Body: dto.PostAssetReq{}, Response: dto.ResponseMessage{},
+	},
+	"DELETE /api/posts/:id/assets/:asset_id": {
+		Tag: "Assets", Summary: "Detach an asset from a post", Roles: editors,
+		Response: dto.ResponseMessage{},
+	},
+	"GET /api/admin/storage/report": {
+		Tag: "Storage", Summary: "Report the storage use and orphan files", Roles: admins,
+		Response: dto.StorageReportRes{},
+	},
+	"POST /api/admin/storage/gc": {
+		Tag: "Storage", Summary: "Delete the orphan files", Roles: admins,
+		Query: dto.StorageGCReq{}, Response: dto.StorageGCRes{},
+	},
+
+	// tags
+	"GET /api/tags/suggest": {
+		Tag: "Tags", Summary: "Suggest the most used tags starting with a prefix",
+		Query: dto.TagSuggestReq{}, Response: []dto.TagUsageRes{},
+	},
+	"GET /api/tags/popular": {
+		Tag: "Tags", Summary: "List the most used tags of a window like 7d",
+		Query: dto.TagPopularReq{}, Response: []dto.TagUsageRes{},
+	},
+	"GET /api/admin/tags": {
+		Tag: "Tags", Summary: "Get the tag tree", Roles: admins,
+		Response: []dto.TagRes{},
+	},
+	"PUT /api/admin/tags/:id/parent": {
+		Tag: "Tags", Summary: "Move a tag under another", Roles: admins,
+		Body: dto.TagParentReq{}, Response: dto.ResponseMessage{},
+	},
+	"POST /api/admin/tags/:id/synonyms": {
+		Tag: "Tags", Summary: "Add a synonym to a tag", Roles: admins,
+		Body: dto.TagSynonymReq{}, Status: 201, Response: dto.ResponseMessage{},
+	},
+	"DELETE /api/admin/tags/:id/synonyms/:label": {
+		Tag: "Tags", Summary: "Delete a synonym of a tag", Roles: admins,
+		Response: dto.ResponseMessage{},
+	},
+	"POST /api/admin/tags/:id/merge": {
+		Tag: "Tags", Summary: "Merge a tag into another", Roles: admins,
+		Body: dto.TagMergeReq{}, Response: dto.ResponseMessage{},
+	},
+
+	// reports, CSV with ?format=csv
+	"GET /api/admin/reports/tags/cooccurrence": {
+		Tag: "Reports", Summary: "Co-occurrence matrix of the top tags", Roles: admins,
+		Query: dto.TagCooccurrenceReq{}, Response: dto.TagCooccurrenceRes{},
+	},
+	"GET /api/admin/reports/tags/histogram": {
+		Tag: "Reports", Summary: "Histogram of the number of tags per post", Roles: admins,
+		Query: dto.ReportFormatReq{}, Response: dto.TagHistogramRes{},
+	},
+	"GET /api/admin/reports/tags/weekly": {
+		Tag: "Reports", Summary: "Weekly usage of the top tags", Roles: admins,
+		Query: dto.TagWeeklyReq{}, Response: dto.TagWeeklyRes{},
+	},
+	"POST /api/admin/reports/refresh": {
+		Tag: "Reports", Summary: "Recompute the reports", Roles: admins,
+		Response: dto.ReportRefreshRes{},
+	},
+
+	// webhooks
+	"GET /api/admin/webhooks": {
+		Tag: "Webhooks", Summary: "List the webhooks", Roles: admins,
+		Response: []dto.WebhookRes{},
+	},
+	"POST /api/admin/webhooks": {
+		Tag: "Webhooks", Summary: "Create a webhook", Roles: admins,
+		Body: dto.WebhookReq{}, Status: 201, Response: dto.WebhookRes{},
+	},
+	"GET /api/admin/webhooks/:id": {
+		Tag: "Webhooks", Summary: "Get a webhook", Roles: admins,
+		Response: dto.WebhookRes{},
+	},
+	"PUT /api/admin/webhooks/:id": {
+		Tag: "Webhooks", Summary: "Update a webhook", Roles: admins,
+		Body: dto.WebhookReq{}, Response: dto.WebhookRes{},
+	},
+	"DELETE /api/admin/webhooks/:id": {
+		Tag: "Webhooks", Summary: "Delete a webhook", Roles: admins,
+		Response: dto.ResponseMessage{},
+	},
+	"GET /api/admin/webhooks/:id/deliveries": {
+		Tag: "Webhooks", Summary: "List the deliveries of a webhook", Roles: admins,
+		Query: dto.WebhookDeliveryListReq{}, Response: []models.WebhookDelivery{},
+	},
+	"POST /api/admin/webhooks/:id/deliveries/:delivery_id/redeliver": {
+		Tag: "Webhooks", Summary: "Deliver a delivery again", Roles: admins,
+		Status: 202, Response: models.WebhookDelivery{},
+	},
+
+	"POST /graphql": {
+		Tag: "GraphQL", Summary: "Run a GraphQL query or mutation",
+		Body: graph.Request{},
+		Response: &openapi.Schema{Type: "object", Properties: map[string]*openapi.Schema{
+			"data":   {},
+			"errors": {Type: "array", Items: &openapi.Schema{Type: "object"}},
+		}},
+	},
+}
+
+// files is the form of an upload in the field name.
+func files(name string, isMany bool) *openapi.Schema {
+	field := binary
+	if isMany {
+		field = &openapi.Schema{Type: "array", Items: binary}
+	}
+	return &openapi.Schema{Type: "object", Properties: map[string]*openapi.Schema{name: field}}
+}
@@ -2,17 +2,40 @@ package router
 
 import (
 	"github.com/adamnasrudin03/go-asset-findr/app/controller"
+	"github.com/adamnasrudin03/go-asset-findr/app/middlewares"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/auth"
 	"github.com/gin-gonic/gin"
 )
 
 func (r routes) postRouter(rg *gin.RouterGroup, handler controller.PostController) {
-	post := rg.Group("/posts")
+	var (
+		post        = rg.Group("/posts")
+		conditional = controller.ConditionalGet(r.cfg.App.CacheControl)
+		editorOnly  = middlewares.AuthorizationMustBe(auth.RoleEditor, auth.RoleAdmin)
+	)
 	{
-		post.GET("/:id", handler.GetDetail)
+		post.GET("/:id", conditional, handler.GetDetail)
+		post.GET("/by-slug/:slug", conditional, handler.GetBySlug)
+		post.GET("/:id/related", handler.GetRelated)
 		post.DELETE("/:id", handler.Delete)
 		post.PUT("/:id", handler.Update)
-		post.GET("", handler.GetList)
+		post.PUT("/:id/status", editorOnly, handler.UpdateStatus)
+		post.GET("", conditional, handler.GetList)
+		post.GET("/stream", handler.Stream)
 		post.POST("", handler.Create)
+		post.POST("/bulk", editorOnly, handler.BulkCreate)
+		post.POST("/bulk/update", editorOnly, handler.BulkUpdate)
+		post.POST("/bulk/delete", editorOnly, handler.BulkDelete)
+		post.GET("/export", editorOnly, handler.Export)
+		post.POST("/import", editorOnly, handler.Import)
+	}
+
+	revision := post.Group("/:id/revisions", editorOnly)
+	{
+		revision.GET("", handler.GetRevisions)
+		revision.GET("/diff", handler.DiffRevisions)
+		revision.GET("/:rev", handler.GetRevision)
+		revision.POST("/:rev/restore", handler.RestoreRevision)
 	}
 
 }
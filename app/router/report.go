@@ -0,0 +1,18 @@
+package router
+
+import (
+	"github.com/adamnasrudin03/go-asset-findr/app/controller"
+	"github.com/adamnasrudin03/go-asset-findr/app/middlewares"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/auth"
+	"github.com/gin-gonic/gin"
+)
+
+func (r routes) reportRouter(rg *gin.RouterGroup, handler controller.ReportController) {
+	report := rg.Group("/admin/reports", middlewares.AuthorizationMustBe(auth.RoleAdmin))
+	{
+		report.GET("/tags/cooccurrence", handler.TagCooccurrence)
+		report.GET("/tags/histogram", handler.TagHistogram)
+		report.GET("/tags/weekly", handler.TagWeekly)
+		report.POST("/refresh", handler.Refresh)
+	}
+}
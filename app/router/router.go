@@ -3,7 +3,9 @@ package router
 import (
 	"net/http"
 
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
 	"github.com/adamnasrudin03/go-asset-findr/app/controller"
+	"github.com/adamnasrudin03/go-asset-findr/app/middlewares"
 	"github.com/adamnasrudin03/go-template/pkg/helpers"
 
 	"github.com/gin-contrib/cors"
@@ -12,17 +14,28 @@ import (
 
 type routes struct {
 	router *gin.Engine
+	cfg    *configs.Configs
 }
 
-func NewRoutes(h controller.Controllers) routes {
+func NewRoutes(h controller.Controllers, cfg *configs.Configs) routes {
 	var err error
 	r := routes{
 		router: gin.Default(),
+		cfg:    cfg,
 	}
+	// let handlers pass *gin.Context down as context.Context and still see
+	// values stored on the request context by middlewares
+	r.router.ContextWithFallback = true
 
 	r.router.Use(gin.Logger())
 	r.router.Use(gin.Recovery())
-	r.router.Use(cors.Default())
+	corsConfig := cors.DefaultConfig()
+	corsConfig.AllowAllOrigins = true
+	corsConfig.AddAllowHeaders("Authorization", "If-None-Match", "If-Modified-Since", readPrimaryHeader)
+	corsConfig.AddExposeHeaders("ETag", "Last-Modified")
+	r.router.Use(cors.New(corsConfig))
+	r.router.Use(readYourWrites(cfg.DB.ReadYourWritesTTL))
+	r.router.Use(middlewares.Authentication(cfg))
 
 	r.router.GET("/", func(c *gin.Context) {
 		helpers.RenderJSON(c.Writer, http.StatusOK, "welcome this server")
@@ -30,6 +43,12 @@ func NewRoutes(h controller.Controllers) routes {
 
 	v1 := r.router.Group("/api")
 	r.postRouter(v1, h.Post)
+	r.assetRouter(v1, h.Asset)
+	r.tagRouter(v1, h.Tag)
+	r.reportRouter(v1, h.Report)
+	r.webhookRouter(v1, h.Webhook)
+	r.graphqlRouter(&r.router.RouterGroup, h.GraphQL)
+	r.docsRouter(&r.router.RouterGroup)
 
 	r.router.NoRoute(func(c *gin.Context) {
 		err = helpers.ErrRouteNotFound()
@@ -38,6 +57,11 @@ func NewRoutes(h controller.Controllers) routes {
 	return r
 }
 
+// Handler is the router as a http.Handler, for tests.
+func (r routes) Handler() http.Handler {
+	return r.router
+}
+
 func (r routes) Run(addr string) error {
 	return r.router.Run(addr)
 }
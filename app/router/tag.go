@@ -0,0 +1,25 @@
+package router
+
+import (
+	"github.com/adamnasrudin03/go-asset-findr/app/controller"
+	"github.com/adamnasrudin03/go-asset-findr/app/middlewares"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/auth"
+	"github.com/gin-gonic/gin"
+)
+
+func (r routes) tagRouter(rg *gin.RouterGroup, handler controller.TagController) {
+	admin := rg.Group("/admin/tags", middlewares.AuthorizationMustBe(auth.RoleAdmin))
+	{
+		admin.GET("", handler.GetTree)
+		admin.PUT("/:id/parent", handler.SetParent)
+		admin.POST("/:id/synonyms", handler.AddSynonym)
+		admin.DELETE("/:id/synonyms/:label", handler.DeleteSynonym)
+		admin.POST("/:id/merge", handler.Merge)
+	}
+
+	public := rg.Group("/tags")
+	{
+		public.GET("/suggest", handler.Suggest)
+		public.GET("/popular", handler.Popular)
+	}
+}
@@ -0,0 +1,21 @@
+package router
+
+import (
+	"github.com/adamnasrudin03/go-asset-findr/app/controller"
+	"github.com/adamnasrudin03/go-asset-findr/app/middlewares"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/auth"
+	"github.com/gin-gonic/gin"
+)
+
+func (r routes) webhookRouter(rg *gin.RouterGroup, handler controller.WebhookController) {
+	webhook := rg.Group("/admin/webhooks", middlewares.AuthorizationMustBe(auth.RoleAdmin))
+	{
+		webhook.GET("", handler.GetList)
+		webhook.POST("", handler.Create)
+		webhook.GET("/:id", handler.GetDetail)
+		webhook.PUT("/:id", handler.Update)
+		webhook.DELETE("/:id", handler.Delete)
+		webhook.GET("/:id/deliveries", handler.GetDeliveries)
+		webhook.POST("/:id/deliveries/:delivery_id/redeliver", handler.Redeliver)
+	}
+}
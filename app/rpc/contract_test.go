@@ -0,0 +1,333 @@
+package rpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app"
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/app/repository"
+	"github.com/adamnasrudin03/go-asset-findr/app/repository/mocks"
+	"github.com/adamnasrudin03/go-asset-findr/app/router"
+	"github.com/adamnasrudin03/go-asset-findr/app/rpc"
+	"github.com/adamnasrudin03/go-asset-findr/app/service"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/auth"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/driver"
+	findrv1 "github.com/adamnasrudin03/go-asset-findr/proto/findr/v1"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// ContractTestSuite calls the REST and the gRPC API on the same services
+// and checks they answer alike, so one does not drift from the other.
+type ContractTestSuite struct {
+	suite.Suite
+	postRepo   *mocks.PostRepository
+	tagRepo    *mocks.TagRepository
+	outboxRepo *mocks.OutboxRepository
+	cfg        configs.Configs
+	rest       http.Handler
+	server     *grpc.Server
+	conn       *grpc.ClientConn
+	posts      findrv1.PostServiceClient
+	tags       findrv1.TagServiceClient
+}
+
+func (s *ContractTestSuite) SetupSuite() {
+	gin.SetMode(gin.TestMode)
+}
+
+func (s *ContractTestSuite) SetupTest() {
+	s.cfg = *configs.GetInstance()
+	s.cfg.App.SecretKey = "contract-test-secret"
+	logger := driver.Logger(&s.cfg)
+
+	s.postRepo = &mocks.PostRepository{}
+	s.tagRepo = &mocks.TagRepository{}
+	s.outboxRepo = &mocks.OutboxRepository{}
+	repos := &repository.Repositories{Post: s.postRepo, Tag: s.tagRepo, Outbox: s.outboxRepo}
+	services := &service.Services{
+		Post:       service.NewPostService(repos, &s.cfg, logger),
+		Tag:        service.NewTagService(repos, &s.cfg, logger),
+		PostStream: service.NewPostStreamService(repos, &s.cfg, logger),
+	}
+
+	s.rest = router.NewRoutes(*app.WiringController(services, &s.cfg, logger), &s.cfg).Handler()
+
+	lis := bufconn.Listen(1 << 20)
+	s.server = rpc.New(services, &s.cfg, logger)
+	go func() {
+		_ = s.server.Serve(lis)
+	}()
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	s.Require().NoError(err)
+	s.conn = conn
+	s.posts = findrv1.NewPostServiceClient(conn)
+	s.tags = findrv1.NewTagServiceClient(conn)
+}
+
+func (s *ContractTestSuite) TearDownTest() {
+	s.conn.Close()
+	s.server.Stop()
+}
+
+func TestContract(t *testing.T) {
+	suite.Run(t, new(ContractTestSuite))
+}
+
+func (s *ContractTestSuite) token(role string) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &helpers.JWTClaims{ID: 1, Role: role}).
+		SignedString([]byte(s.cfg.App.SecretKey))
+	s.Require().NoError(err)
+	return token
+}
+
+// get calls the REST API and returns the status and the decoded body.
+func (s *ContractTestSuite) get(path, token string) (int, interface{}) {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	s.rest.ServeHTTP(w, req)
+
+	var body interface{}
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &body))
+	return w.Code, body
+}
+
+func (s *ContractTestSuite) withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+// asJSON is msg as a REST client would read it, protojson with the field
+// names of the proto.
+func (s *ContractTestSuite) asJSON(msg proto.Message) map[string]interface{} {
+	data, err := protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}.Marshal(msg)
+	s.Require().NoError(err)
+
+	result := map[string]interface{}{}
+	s.Require().NoError(json.Unmarshal(data, &result))
+	return result
+}
+
+// assertSameError checks the gRPC error has the code of the REST status and
+// the messages of the REST body.
+func (s *ContractTestSuite) assertSameError(code codes.Code, restBody interface{}, err error) {
+	st, ok := status.FromError(err)
+	s.Require().True(ok)
+	s.Equal(code, st.Code())
+
+	message := restBody.(map[string]interface{})["message"].(map[string]interface{})
+	s.Equal(message["en"], st.Message())
+
+	locales := map[string]string{}
+	for _, v := range st.Details() {
+		if m, ok := v.(*errdetails.LocalizedMessage); ok {
+			locales[m.Locale] = m.Message
+		}
+	}
+	s.Equal(map[string]string{"id-ID": message["id"].(string), "en-US": message["en"].(string)}, locales)
+}
+
+func (s *ContractTestSuite) TestGetPost() {
+	var (
+		publishAt = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		post      = func(context.Context, dto.PostGetReq) *dto.PostRes {
+			return &dto.PostRes{
+				ID: 5, Title: "go generics", Slug: "go-generics", Content: "# Generics", ContentFormat: "markdown",
+				Tags: []string{"go"}, Status: dto.PostStatusPublished, PublishAt: &publishAt,
+				CreatedAt: publishAt, UpdatedAt: publishAt.Add(time.Hour),
+			}
+		}
+	)
+	s.postRepo.On("GetDetail", mock.Anything, dto.PostGetReq{ID: 5, Render: "html"}).Return(post, nil).Twice()
+
+	code, rest := s.get("/api/posts/5?render=html", "")
+	s.Equal(http.StatusOK, code)
+
+	res, err := s.posts.GetPost(context.Background(), &findrv1.GetPostRequest{Id: 5, Render: "html"})
+	s.Require().NoError(err)
+	s.Equal(normalize(rest), normalize(s.asJSON(res)))
+}
+
+func (s *ContractTestSuite) TestGetPost_NotFound() {
+	s.postRepo.On("GetDetail", mock.Anything, dto.PostGetReq{ID: 6}).Return(func(context.Context, dto.PostGetReq) *dto.PostRes {
+		return &dto.PostRes{ID: 6, Status: dto.PostStatusDraft}
+	}, nil).Twice()
+
+	code, rest := s.get("/api/posts/6", "")
+	s.Equal(http.StatusNotFound, code)
+
+	_, err := s.posts.GetPost(context.Background(), &findrv1.GetPostRequest{Id: 6})
+	s.assertSameError(codes.NotFound, rest, err)
+}
+
+func (s *ContractTestSuite) TestListPosts() {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	s.tagRepo.On("Resolve", mock.Anything, "go", true).Return([]models.Tag{{ID: 2, Label: "go"}, {ID: 3, Label: "generics"}}, nil).Twice()
+	s.postRepo.On("GetAll", mock.Anything).Return(func(context.Context) []dto.PostRes {
+		return []dto.PostRes{
+			{ID: 1, Title: "one", Content: "one", Tags: []string{"generics"}, Status: dto.PostStatusPublished, CreatedAt: createdAt},
+			{ID: 2, Title: "two", Content: "two", Tags: []string{"rust"}, Status: dto.PostStatusPublished, CreatedAt: createdAt},
+			{ID: 3, Title: "three", Content: "three", Tags: []string{"go"}, Status: dto.PostStatusDraft, CreatedAt: createdAt},
+		}
+	}, nil).Twice()
+
+	code, rest := s.get("/api/posts?tag=go&descendants=true", "")
+	s.Equal(http.StatusOK, code)
+	s.Len(rest, 1)
+
+	res, err := s.posts.ListPosts(context.Background(), &findrv1.ListPostsRequest{Tag: "go", Descendants: true})
+	s.Require().NoError(err)
+	s.Equal(normalize(rest), normalize(s.asJSON(res)["posts"]))
+}
+
+func (s *ContractTestSuite) TestGetTagTree() {
+	code, rest := s.get("/api/admin/tags", "")
+	s.Equal(http.StatusUnauthorized, code)
+	_, err := s.tags.GetTagTree(context.Background(), &findrv1.GetTagTreeRequest{})
+	s.assertSameError(codes.Unauthenticated, rest, err)
+
+	user := s.token(auth.RoleUser)
+	code, rest = s.get("/api/admin/tags", user)
+	s.Equal(http.StatusForbidden, code)
+	_, err = s.tags.GetTagTree(s.withToken(user), &findrv1.GetTagTreeRequest{})
+	s.assertSameError(codes.PermissionDenied, rest, err)
+
+	parentID := uint64(1)
+	s.tagRepo.On("GetAll", mock.Anything).Return([]models.Tag{{ID: 1, Label: "programming"}, {ID: 2, Label: "go", ParentID: &parentID}}, nil).Twice()
+	s.tagRepo.On("GetSynonyms", mock.Anything).Return([]models.TagSynonym{{TagID: 2, Label: "golang"}}, nil).Twice()
+
+	admin := s.token(auth.RoleAdmin)
+	code, rest = s.get("/api/admin/tags", admin)
+	s.Equal(http.StatusOK, code)
+	res, err := s.tags.GetTagTree(s.withToken(admin), &findrv1.GetTagTreeRequest{})
+	s.Require().NoError(err)
+	s.Equal(normalize(rest), normalize(s.asJSON(res)["tags"]))
+}
+
+func (s *ContractTestSuite) TestWatchPosts() {
+	var (
+		ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+		occurredAt  = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		published   = &dto.PostRes{ID: 1, Title: "Go", Status: dto.PostStatusPublished, Tags: []string{"go"}}
+		seq         = uint64(11)
+	)
+	defer cancel()
+
+	payload, err := json.Marshal(dto.PostEventPayload{After: published})
+	s.Require().NoError(err)
+	s.outboxRepo.On("GetPublished", mock.Anything, dto.AggregatePost, uint64(10), mock.Anything).Return([]models.OutboxEvent{
+		{ID: 4, AggregateType: dto.AggregatePost, AggregateID: 1, EventType: dto.PostEventCreated, Payload: models.JSON(payload), CreatedAt: occurredAt, PublishedSeq: &seq},
+	}, nil).Once()
+
+	stream, err := s.posts.WatchPosts(ctx, &findrv1.WatchPostsRequest{LastEventId: 10})
+	s.Require().NoError(err)
+
+	e, err := stream.Recv()
+	s.Require().NoError(err)
+	s.Equal(uint64(11), e.GetId())
+	s.Equal(dto.PostEventCreated, e.GetType())
+	s.Equal(occurredAt, e.GetOccurredAt().AsTime())
+	s.Nil(e.GetBefore())
+	s.Equal("Go", e.GetAfter().GetTitle())
+
+	stream, err = s.posts.WatchPosts(ctx, &findrv1.WatchPostsRequest{Descendants: true})
+	s.Require().NoError(err)
+	_, err = stream.Recv()
+	s.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func (s *ContractTestSuite) TestWatchPosts_HTML() {
+	var (
+		ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+		seq         = uint64(11)
+		post        = func() *dto.PostRes {
+			return &dto.PostRes{
+				ID: 7, Title: "html post", Content: `<p onclick="steal()">hello</p><script>alert(1)</script>`,
+				ContentFormat: "html", Status: dto.PostStatusPublished, Tags: []string{"go"},
+			}
+		}
+	)
+	defer cancel()
+
+	s.postRepo.On("GetDetail", mock.Anything, dto.PostGetReq{ID: 7}).Return(func(context.Context, dto.PostGetReq) *dto.PostRes {
+		return post()
+	}, nil).Once()
+	code, rest := s.get("/api/posts/7", "")
+	s.Require().Equal(http.StatusOK, code)
+
+	payload, err := json.Marshal(dto.PostEventPayload{After: post()})
+	s.Require().NoError(err)
+	s.outboxRepo.On("GetPublished", mock.Anything, dto.AggregatePost, uint64(10), mock.Anything).Return([]models.OutboxEvent{
+		{ID: 4, AggregateType: dto.AggregatePost, AggregateID: 7, EventType: dto.PostEventCreated, Payload: models.JSON(payload), PublishedSeq: &seq},
+	}, nil).Once()
+
+	stream, err := s.posts.WatchPosts(ctx, &findrv1.WatchPostsRequest{LastEventId: 10})
+	s.Require().NoError(err)
+	e, err := stream.Recv()
+	s.Require().NoError(err)
+
+	after := e.GetAfter()
+	s.NotContains(after.GetContent(), "<script")
+	s.NotContains(after.GetContent(), "onclick")
+	restPost := rest.(map[string]interface{})
+	s.Equal(restPost["content"], after.GetContent())
+	s.Equal(restPost["title"], after.GetTitle())
+	s.Equal(restPost["excerpt"], after.GetExcerpt())
+}
+
+// normalize evens out what JSON and protojson write differently for the
+// same value: protojson quotes 64 bit integers, writes an empty list where
+// encoding/json may write null and leaves out an unset optional field.
+func normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			if e = normalize(e); e != nil {
+				result[k] = e
+			}
+		}
+		return result
+	case []interface{}:
+		if len(v) == 0 {
+			return nil
+		}
+		result := make([]interface{}, len(v))
+		for i, e := range v {
+			result[i] = normalize(e)
+		}
+		return result
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return v
+}
@@ -0,0 +1,84 @@
+package rpc
+
+import (
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	findrv1 "github.com/adamnasrudin03/go-asset-findr/proto/findr/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// toPost converts a post as the services return it, already represented:
+// Content of an html post is its sanitized HTML, see dto.PostRes.Represent.
+func toPost(m *dto.PostRes) *findrv1.Post {
+	if m == nil {
+		return nil
+	}
+
+	return &findrv1.Post{
+		Id:            m.ID,
+		Title:         m.Title,
+		Slug:          m.Slug,
+		Content:       m.Content,
+		ContentFormat: m.ContentFormat,
+		Excerpt:       m.Excerpt,
+		ReadingTime:   int32(m.ReadingTime),
+		Tags:          m.Tags,
+		Status:        m.Status,
+		PublishAt:     toTimestamp(m.PublishAt),
+		CreatedAt:     timestamppb.New(m.CreatedAt),
+		UpdatedAt:     timestamppb.New(m.UpdatedAt),
+	}
+}
+
+func toPostEvent(m dto.PostStreamEvent) *findrv1.PostEvent {
+	return &findrv1.PostEvent{
+		Id:         m.ID,
+		Type:       m.Type,
+		PostId:     m.PostID,
+		OccurredAt: timestamppb.New(m.OccurredAt),
+		Before:     toPost(m.Before),
+		After:      toPost(m.After),
+	}
+}
+
+func toTags(tags []dto.TagRes) []*findrv1.Tag {
+	result := make([]*findrv1.Tag, 0, len(tags))
+	for _, v := range tags {
+		result = append(result, &findrv1.Tag{
+			Id:       v.ID,
+			Label:    v.Label,
+			ParentId: v.ParentID,
+			Synonyms: v.Synonyms,
+			Children: toTags(v.Children),
+		})
+	}
+	return result
+}
+
+func toTagUsages(tags []dto.TagUsageRes) []*findrv1.TagUsage {
+	result := make([]*findrv1.TagUsage, 0, len(tags))
+	for _, v := range tags {
+		result = append(result, &findrv1.TagUsage{
+			Id:    v.ID,
+			Label: v.Label,
+			Usage: v.Usage,
+		})
+	}
+	return result
+}
+
+func toTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func fromTimestamp(t *timestamppb.Timestamp) *time.Time {
+	if t == nil {
+		return nil
+	}
+	v := t.AsTime()
+	return &v
+}
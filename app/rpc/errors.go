@@ -0,0 +1,67 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// httpCodes maps the status the REST API answers an error with to the gRPC
+// code of the same meaning.
+var httpCodes = map[int]codes.Code{
+	http.StatusBadRequest:          codes.InvalidArgument,
+	http.StatusUnauthorized:        codes.Unauthenticated,
+	http.StatusForbidden:           codes.PermissionDenied,
+	http.StatusNotFound:            codes.NotFound,
+	http.StatusConflict:            codes.Aborted,
+	http.StatusUnprocessableEntity: codes.FailedPrecondition,
+}
+
+// toStatus turns err into a gRPC status. The code of a ResponseError is the
+// one of its REST status, its messages in both languages go in the
+// details. Other errors are internal and not shown to the caller.
+func toStatus(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	var respErr *helpers.ResponseError
+	if !errors.As(err, &respErr) {
+		return status.Error(codes.Internal, "internal error")
+	}
+
+	code, ok := httpCodes[helpers.StatusErrorMapping(respErr.Code)]
+	if !ok {
+		code = codes.Internal
+	}
+
+	st, errDetails := status.New(code, respErr.Message.EN).WithDetails(
+		&errdetails.LocalizedMessage{Locale: "id-ID", Message: respErr.Message.ID},
+		&errdetails.LocalizedMessage{Locale: "en-US", Message: respErr.Message.EN},
+	)
+	if errDetails != nil {
+		return status.Error(code, respErr.Message.EN)
+	}
+	return st.Err()
+}
+
+func errUnauthenticated() error {
+	return helpers.NewError(helpers.ErrUnauthorized, helpers.NewResponseMultiLang(
+		helpers.MultiLanguages{
+			ID: "Bearer token tidak ditemukan",
+			EN: "Bearer token not found",
+		},
+	))
+}
@@ -0,0 +1,72 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantCode    codes.Code
+		wantMessage string
+	}{
+		{
+			name:        "not found",
+			err:         helpers.ErrDataNotFound("Post", "Post"),
+			wantCode:    codes.NotFound,
+			wantMessage: helpers.ErrDataNotFound("Post", "Post").Message.EN,
+		},
+		{
+			name:        "validation",
+			err:         helpers.ErrIsRequired("judul", "title"),
+			wantCode:    codes.InvalidArgument,
+			wantMessage: helpers.ErrIsRequired("judul", "title").Message.EN,
+		},
+		{
+			name:        "forbidden",
+			err:         helpers.ErrCannotHaveAccessResources(),
+			wantCode:    codes.PermissionDenied,
+			wantMessage: helpers.ErrCannotHaveAccessResources().Message.EN,
+		},
+		{
+			name:        "wrapped",
+			err:         fmt.Errorf("save: %w", errUnauthenticated()),
+			wantCode:    codes.Unauthenticated,
+			wantMessage: "Bearer token not found",
+		},
+		{
+			name:        "status as is",
+			err:         status.Error(codes.ResourceExhausted, "slow down"),
+			wantCode:    codes.ResourceExhausted,
+			wantMessage: "slow down",
+		},
+		{
+			name:        "canceled",
+			err:         context.Canceled,
+			wantCode:    codes.Canceled,
+			wantMessage: context.Canceled.Error(),
+		},
+		{
+			name:        "unknown error is hidden",
+			err:         errors.New("pq: connection refused"),
+			wantCode:    codes.Internal,
+			wantMessage: "internal error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := status.Convert(toStatus(tt.err))
+			if st.Code() != tt.wantCode || st.Message() != tt.wantMessage {
+				t.Errorf("toStatus() = %v %q, want %v %q", st.Code(), st.Message(), tt.wantCode, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package rpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/middlewares"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/auth"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type interceptors struct {
+	Cfg    *configs.Configs
+	Logger *logrus.Logger
+}
+
+// logUnary logs every call with its code and duration, as gin.Logger does
+// for the REST API.
+func (i interceptors) logUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	i.log(info.FullMethod, start, err)
+	return resp, err
+}
+
+func (i interceptors) logStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	i.log(info.FullMethod, start, err)
+	return err
+}
+
+func (i interceptors) log(method string, start time.Time, err error) {
+	if err != nil {
+		i.Logger.Errorf("%v error: %v ", method, err)
+	}
+	i.Logger.Infof("[GRPC] %v | %v | %v", status.Code(err), time.Since(start), method)
+}
+
+// errorUnary turns the errors of the services into gRPC statuses.
+func (i interceptors) errorUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return resp, nil
+}
+
+func (i interceptors) errorStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	err := handler(srv, ss)
+	if err != nil {
+		return toStatus(err)
+	}
+	return nil
+}
+
+// authUnary reads the actor from the authorization metadata like
+// middlewares.Authentication does from the header, and checks it has the
+// roles of the method.
+func (i interceptors) authUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := i.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (i interceptors) authStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := i.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+}
+
+func (i interceptors) authenticate(ctx context.Context, method string) (context.Context, error) {
+	header := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			header = values[0]
+		}
+	}
+
+	if header != "" {
+		tokenString, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok {
+			return ctx, errUnauthenticated()
+		}
+
+		actor, err := middlewares.ParseToken(tokenString, i.Cfg.App.SecretKey)
+		if err != nil {
+			return ctx, err
+		}
+		ctx = auth.WithActor(ctx, actor)
+	}
+
+	if roles, ok := methodRoles[method]; ok {
+		err := middlewares.Authorize(auth.FromContext(ctx), roles...)
+		if err != nil {
+			return ctx, err
+		}
+	}
+
+	return ctx, nil
+}
+
+// serverStream is a stream whose handler sees ctx, the context with the
+// actor.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,145 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/service"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/database"
+	findrv1 "github.com/adamnasrudin03/go-asset-findr/proto/findr/v1"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+// PostServer serves findrv1.PostService with service.PostService, the same
+// rules as the REST API apply.
+type PostServer struct {
+	findrv1.UnimplementedPostServiceServer
+	Post       service.PostService
+	PostStream service.PostStreamService
+	Logger     *logrus.Logger
+}
+
+func (s *PostServer) GetPost(ctx context.Context, req *findrv1.GetPostRequest) (*findrv1.Post, error) {
+	res, err := s.Post.GetDetail(ctx, dto.PostGetReq{
+		ID:     req.GetId(),
+		Render: req.GetRender(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toPost(res), nil
+}
+
+func (s *PostServer) GetPostBySlug(ctx context.Context, req *findrv1.GetPostBySlugRequest) (*findrv1.Post, error) {
+	res, err := s.Post.GetBySlug(ctx, req.GetSlug())
+	if err != nil {
+		return nil, err
+	}
+	return toPost(res), nil
+}
+
+func (s *PostServer) ListPosts(ctx context.Context, req *findrv1.ListPostsRequest) (*findrv1.ListPostsResponse, error) {
+	res, err := s.Post.GetList(ctx, dto.PostListReq{
+		Tag:         req.GetTag(),
+		Descendants: req.GetDescendants(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	posts := make([]*findrv1.Post, 0, len(res))
+	for i := range res {
+		posts = append(posts, toPost(&res[i]))
+	}
+	return &findrv1.ListPostsResponse{Posts: posts}, nil
+}
+
+func (s *PostServer) CreatePost(ctx context.Context, req *findrv1.CreatePostRequest) (*findrv1.Post, error) {
+	res, err := s.Post.Create(ctx, dto.PostCreateReq{
+		Title:         req.GetTitle(),
+		Content:       req.GetContent(),
+		ContentFormat: req.GetContentFormat(),
+		Tags:          req.GetTags(),
+		Status:        req.GetStatus(),
+		PublishAt:     fromTimestamp(req.GetPublishAt()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toPost(res), nil
+}
+
+func (s *PostServer) UpdatePost(ctx context.Context, req *findrv1.UpdatePostRequest) (*findrv1.Post, error) {
+	err := s.Post.UpdateByID(ctx, dto.PostUpdateReq{
+		ID:            req.GetId(),
+		Title:         req.GetTitle(),
+		Content:       req.GetContent(),
+		ContentFormat: req.GetContentFormat(),
+		Tags:          req.GetTags(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.readAfterWrite(ctx, req.GetId())
+}
+
+func (s *PostServer) UpdatePostStatus(ctx context.Context, req *findrv1.UpdatePostStatusRequest) (*findrv1.Post, error) {
+	err := s.Post.UpdateStatus(ctx, dto.PostStatusReq{
+		ID:        req.GetId(),
+		Status:    req.GetStatus(),
+		PublishAt: fromTimestamp(req.GetPublishAt()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.readAfterWrite(ctx, req.GetId())
+}
+
+func (s *PostServer) DeletePost(ctx context.Context, req *findrv1.DeletePostRequest) (*findrv1.DeletePostResponse, error) {
+	err := s.Post.DeleteByID(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return &findrv1.DeletePostResponse{}, nil
+}
+
+// WatchPosts sends the post changes until the caller goes away. A caller
+// resumes with the id of the last event it received as last_event_id.
+func (s *PostServer) WatchPosts(req *findrv1.WatchPostsRequest, stream grpc.ServerStreamingServer[findrv1.PostEvent]) error {
+	ctx := stream.Context()
+	events, err := s.PostStream.Subscribe(ctx, dto.PostStreamReq{
+		Tag:         req.GetTag(),
+		Descendants: req.GetDescendants(),
+		LastEventID: req.GetLastEventId(),
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e, ok := <-events:
+			if !ok {
+				return nil
+			}
+
+			err = stream.Send(toPostEvent(e))
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// readAfterWrite returns the post as just written, from the primary so a
+// lagging replica does not hide the change.
+func (s *PostServer) readAfterWrite(ctx context.Context, postID uint64) (*findrv1.Post, error) {
+	res, err := s.Post.GetDetail(database.WithReadPrimary(ctx), dto.PostGetReq{ID: postID})
+	if err != nil {
+		return nil, err
+	}
+	return toPost(res), nil
+}
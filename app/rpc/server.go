@@ -0,0 +1,44 @@
+package rpc
+
+import (
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/service"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/auth"
+	findrv1 "github.com/adamnasrudin03/go-asset-findr/proto/findr/v1"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+// methodRoles are the roles a method is limited to, as the matching REST
+// route is with middlewares.AuthorizationMustBe. Methods not listed are open
+// to anonymous callers.
+var methodRoles = map[string][]string{
+	findrv1.PostService_UpdatePostStatus_FullMethodName: {auth.RoleEditor, auth.RoleAdmin},
+	findrv1.TagService_GetTagTree_FullMethodName:        {auth.RoleAdmin},
+}
+
+// New builds the gRPC server of the posts and tags on top of the services.
+// The callers authenticate with the bearer JWT of the REST API in the
+// authorization metadata.
+func New(
+	srv *service.Services,
+	cfg *configs.Configs,
+	logger *logrus.Logger,
+) *grpc.Server {
+	i := interceptors{Cfg: cfg, Logger: logger}
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(i.logUnary, i.errorUnary, i.authUnary),
+		grpc.ChainStreamInterceptor(i.logStream, i.errorStream, i.authStream),
+	)
+
+	findrv1.RegisterPostServiceServer(server, &PostServer{
+		Post:       srv.Post,
+		PostStream: srv.PostStream,
+		Logger:     logger,
+	})
+	findrv1.RegisterTagServiceServer(server, &TagServer{
+		Tag:    srv.Tag,
+		Logger: logger,
+	})
+	return server
+}
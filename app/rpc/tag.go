@@ -0,0 +1,47 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/service"
+	findrv1 "github.com/adamnasrudin03/go-asset-findr/proto/findr/v1"
+	"github.com/sirupsen/logrus"
+)
+
+// TagServer serves findrv1.TagService with service.TagService.
+type TagServer struct {
+	findrv1.UnimplementedTagServiceServer
+	Tag    service.TagService
+	Logger *logrus.Logger
+}
+
+func (s *TagServer) GetTagTree(ctx context.Context, req *findrv1.GetTagTreeRequest) (*findrv1.GetTagTreeResponse, error) {
+	res, err := s.Tag.GetTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &findrv1.GetTagTreeResponse{Tags: toTags(res)}, nil
+}
+
+func (s *TagServer) SuggestTags(ctx context.Context, req *findrv1.SuggestTagsRequest) (*findrv1.TagUsageResponse, error) {
+	res, err := s.Tag.Suggest(ctx, dto.TagSuggestReq{
+		Prefix: req.GetPrefix(),
+		Limit:  int(req.GetLimit()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &findrv1.TagUsageResponse{Tags: toTagUsages(res)}, nil
+}
+
+func (s *TagServer) PopularTags(ctx context.Context, req *findrv1.PopularTagsRequest) (*findrv1.TagUsageResponse, error) {
+	res, err := s.Tag.Popular(ctx, dto.TagPopularReq{
+		Window: req.GetWindow(),
+		Limit:  int(req.GetLimit()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &findrv1.TagUsageResponse{Tags: toTagUsages(res)}, nil
+}
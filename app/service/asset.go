@@ -0,0 +1,438 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/app/repository"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/auth"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/media"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/storage"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/worker"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// sniffLen is how many bytes http.DetectContentType looks at.
+	sniffLen = 512
+	// exifHeadLen holds the largest JPEG EXIF segment after the SOI and
+	// an APP0 segment, StripGPS needs all of it. More is read when the
+	// segments before it are larger.
+	exifHeadLen = 2 * (1 << 16)
+	// blobMaxAttempts bounds how often an upload stores its content again
+	// when the garbage collector removes the blob it was about to use.
+	blobMaxAttempts = 3
+)
+
+type AssetService interface {
+	Upload(ctx context.Context, req dto.AssetUploadReq) (*dto.AssetRes, error)
+	GetDetail(ctx context.Context, assetID uint64) (*dto.AssetRes, error)
+	GetList(ctx context.Context, req dto.AssetListReq) ([]dto.AssetRes, error)
+	Open(ctx context.Context, asset *dto.AssetRes) (io.ReadCloser, error)
+	OpenThumbnail(ctx context.Context, assetID uint64, size int) (*dto.AssetThumbnailRes, io.ReadCloser, error)
+	Delete(ctx context.Context, assetID uint64) error
+	GetByPost(ctx context.Context, postID uint64) ([]dto.AssetRes, error)
+	GetByPosts(ctx context.Context, postIDs []uint64) (map[uint64][]dto.AssetRes, error)
+	Attach(ctx context.Context, req dto.PostAssetReq) error
+	Detach(ctx context.Context, postID uint64, assetID uint64) error
+	StartWorkers(ctx context.Context)
+	EnqueuePending(ctx context.Context) (int, error)
+	ProcessMeta(ctx context.Context, assetID uint64) error
+	StorageReport(ctx context.Context) (*dto.StorageReportRes, error)
+	CollectGarbage(ctx context.Context, req dto.StorageGCReq) (*dto.StorageGCRes, error)
+}
+
+type AssetSrv struct {
+	Repos   *repository.Repositories
+	Repo    repository.AssetRepository
+	Storage storage.Storage
+	Cfg     *configs.Configs
+	Logger  *logrus.Logger
+	workers *worker.Pool[uint64]
+}
+
+// NewAssetService creates a new instance of AssetService.
+func NewAssetService(
+	repos *repository.Repositories,
+	store storage.Storage,
+	cfg *configs.Configs,
+	logger *logrus.Logger,
+) AssetService {
+	srv := &AssetSrv{
+		Repos:   repos,
+		Repo:    repos.Asset,
+		Storage: store,
+		Cfg:     cfg,
+		Logger:  logger,
+	}
+	srv.workers = worker.NewPool("asset-meta", cfg.Storage.Workers, cfg.Storage.QueueSize, logger, srv.ProcessMeta)
+	return srv
+}
+
+// Upload stores the file and its asset row, then queues it for the metadata
+// workers. The type is sniffed from the content, the client's Content-Type
+// and file extension are not trusted. A content uploaded before, found by
+// its SHA-256, is not stored again, the new asset shares its blob.
+func (srv *AssetSrv) Upload(ctx context.Context, req dto.AssetUploadReq) (*dto.AssetRes, error) {
+	var (
+		opName = "AssetService-Upload"
+		err    error
+	)
+
+	err = req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Size > srv.Cfg.Storage.MaxUploadSize {
+		return nil, dto.ErrAssetTooLarge(srv.Cfg.Storage.MaxUploadSize)
+	}
+
+	headLen := sniffLen
+	if req.StripGPS {
+		headLen = exifHeadLen
+	}
+	head := make([]byte, headLen)
+	n, err := io.ReadFull(req.File, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		srv.Logger.Errorf("%s failed read file: %v \n", opName, err)
+		return nil, helpers.ErrGetRequest()
+	}
+	head = head[:n]
+
+	mimeType := detectMimeType(head)
+	if !srv.isAllowedType(mimeType) {
+		return nil, dto.ErrAssetType(mimeType)
+	}
+
+	if req.StripGPS && mimeType == "image/jpeg" {
+		head, err = srv.stripGPS(req, head)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// spool to a temporary file, the checksum decides whether the content is
+	// stored at all and is only known once all of it is read
+	spool, err := os.CreateTemp("", "asset-*")
+	if err != nil {
+		srv.Logger.Errorf("%s failed create temp file: %v \n", opName, err)
+		return nil, dto.ErrAssetStorage()
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	hash := sha256.New()
+	written, err := io.Copy(io.MultiWriter(spool, hash), io.LimitReader(io.MultiReader(bytes.NewReader(head), req.File), req.Size))
+	if err != nil || written != req.Size {
+		srv.Logger.Errorf("%s failed read file, %d of %d bytes: %v \n", opName, written, req.Size, err)
+		return nil, helpers.ErrGetRequest()
+	}
+
+	asset := &models.Asset{
+		Filename:   req.Filename,
+		MimeType:   mimeType,
+		Size:       req.Size,
+		Checksum:   hex.EncodeToString(hash.Sum(nil)),
+		UploaderID: auth.FromContext(ctx).ID,
+	}
+	for attempt := 1; ; attempt++ {
+		blob, err := srv.storeBlob(ctx, asset, spool)
+		if err != nil {
+			return nil, err
+		}
+
+		asset.StorageKey = blob.StorageKey
+		err = srv.Repo.Create(ctx, asset)
+		if errors.Is(err, repository.ErrBlobGone) && attempt < blobMaxAttempts {
+			continue
+		}
+		if errors.Is(err, repository.ErrBlobGone) {
+			err = helpers.ErrCreatedDB()
+		}
+		if err != nil {
+			srv.Logger.Errorf("%s failed create data: %v \n", opName, err)
+			return nil, err
+		}
+		break
+	}
+
+	if !srv.workers.Submit(asset.ID) {
+		srv.Logger.Warnf("%s queue full, asset %d left for the next sweep \n", opName, asset.ID)
+	}
+
+	result := dto.NewAssetRes(*asset)
+	return &result, nil
+}
+
+// stripGPS removes the GPS location of the JPEG starting with head, reading
+// more of the file into head until it holds the whole EXIF segment. A file
+// ending before that is rejected rather than stored with its location.
+func (srv *AssetSrv) stripGPS(req dto.AssetUploadReq, head []byte) ([]byte, error) {
+	opName := "AssetService-stripGPS"
+
+	for {
+		isStripped, err := media.StripGPS(head)
+		if err == nil {
+			if isStripped {
+				srv.Logger.Infof("%s stripped gps of %s \n", opName, req.Filename)
+			}
+			return head, nil
+		}
+		if int64(len(head)) >= req.Size {
+			srv.Logger.Errorf("%s failed strip gps of %s: %v \n", opName, req.Filename, err)
+			return nil, dto.ErrAssetStripGPS()
+		}
+
+		more := make([]byte, min(int64(len(head)), req.Size-int64(len(head))))
+		n, err := io.ReadFull(req.File, more)
+		head = append(head, more[:n]...)
+		if err != nil {
+			srv.Logger.Errorf("%s failed read file, %d of %d bytes: %v \n", opName, len(head), req.Size, err)
+			return nil, helpers.ErrGetRequest()
+		}
+	}
+}
+
+// storeBlob returns the blob of the asset's checksum, storing content as a
+// new one when there is none yet.
+func (srv *AssetSrv) storeBlob(ctx context.Context, asset *models.Asset, content io.ReadSeeker) (*models.Blob, error) {
+	opName := "AssetService-storeBlob"
+
+	for {
+		blob, err := srv.Repos.Blob.Find(ctx, asset.Checksum)
+		if err != nil {
+			srv.Logger.Errorf("%s failed get data: %v \n", opName, err)
+			return nil, err
+		}
+		if blob != nil {
+			return blob, nil
+		}
+
+		blob = &models.Blob{
+			Checksum:   asset.Checksum,
+			StorageKey: newBlobKey(asset.Checksum),
+			Size:       asset.Size,
+			MimeType:   asset.MimeType,
+		}
+		_, err = content.Seek(0, io.SeekStart)
+		if err == nil {
+			err = srv.Storage.Put(ctx, blob.StorageKey, content, blob.Size, blob.MimeType)
+		}
+		if err != nil {
+			srv.Logger.Errorf("%s failed put file: %v \n", opName, err)
+			return nil, dto.ErrAssetStorage()
+		}
+
+		isCreated, err := srv.Repos.Blob.Create(ctx, blob)
+		if err != nil {
+			// an object without a blob row is never read, nor collected
+			srv.deleteObject(context.WithoutCancel(ctx), blob.StorageKey)
+			return nil, err
+		}
+		if isCreated {
+			return blob, nil
+		}
+
+		// the same content was uploaded at the same time, use that blob
+		srv.deleteObject(ctx, blob.StorageKey)
+	}
+}
+
+func (srv *AssetSrv) GetDetail(ctx context.Context, assetID uint64) (*dto.AssetRes, error) {
+	opName := "AssetService-GetDetail"
+
+	asset, err := srv.Repo.GetDetail(ctx, assetID)
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, err
+	}
+
+	// the assets of unpublished posts do not exist for the public
+	if !auth.FromContext(ctx).IsEditor() {
+		isPublic, err := srv.Repo.IsPublic(ctx, assetID)
+		if err != nil {
+			srv.Logger.Errorf("%s failed get data public: %v \n", opName, err)
+			return nil, err
+		}
+		if !isPublic {
+			return nil, helpers.ErrDataNotFound("aset", "asset")
+		}
+	}
+
+	result := dto.NewAssetRes(*asset)
+	return &result, nil
+}
+
+// Open returns the content of asset, the caller closes it.
+func (srv *AssetSrv) Open(ctx context.Context, asset *dto.AssetRes) (io.ReadCloser, error) {
+	opName := "AssetService-Open"
+
+	content, err := srv.Storage.Get(ctx, asset.StorageKey)
+	if err != nil {
+		srv.Logger.Errorf("%s failed get file %s: %v \n", opName, asset.StorageKey, err)
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, helpers.ErrDataNotFound("file aset", "asset file")
+		}
+		return nil, dto.ErrAssetStorage()
+	}
+
+	return content, nil
+}
+
+// Delete removes the asset from every post. Its blob is left to the garbage
+// collector, other assets may share it.
+func (srv *AssetSrv) Delete(ctx context.Context, assetID uint64) error {
+	opName := "AssetService-Delete"
+
+	asset, err := srv.Repo.Delete(ctx, assetID)
+	if err != nil {
+		srv.Logger.Errorf("%s failed delete data: %v \n", opName, err)
+		return err
+	}
+
+	repository.AfterCommit(ctx, func() {
+		srv.releaseObjects(context.WithoutCancel(ctx), asset)
+	})
+	return nil
+}
+
+func (srv *AssetSrv) GetByPost(ctx context.Context, postID uint64) ([]dto.AssetRes, error) {
+	opName := "AssetService-GetByPost"
+
+	post, err := srv.Repos.Post.GetDetail(ctx, dto.PostGetReq{ID: postID, ColumnCustom: "id, status, publish_at"})
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data post: %v \n", opName, err)
+		return nil, err
+	}
+
+	// unpublished posts do not exist for the public
+	if !auth.FromContext(ctx).IsEditor() && !post.IsPublished(time.Now()) {
+		return nil, helpers.ErrNotFound()
+	}
+
+	assets, err := srv.Repo.GetByPost(ctx, postID)
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, err
+	}
+
+	result := make([]dto.AssetRes, 0, len(assets))
+	for _, v := range assets {
+		result = append(result, dto.NewAssetRes(v))
+	}
+	return result, nil
+}
+
+// GetByPosts returns the assets of every post of postIDs at once, the
+// caller has checked the posts can be seen.
+func (srv *AssetSrv) GetByPosts(ctx context.Context, postIDs []uint64) (map[uint64][]dto.AssetRes, error) {
+	opName := "AssetService-GetByPosts"
+
+	assets, err := srv.Repo.GetByPosts(ctx, postIDs)
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, err
+	}
+
+	result := make(map[uint64][]dto.AssetRes, len(assets))
+	for postID, v := range assets {
+		for _, asset := range v {
+			result[postID] = append(result[postID], dto.NewAssetRes(asset))
+		}
+	}
+	return result, nil
+}
+
+func (srv *AssetSrv) Attach(ctx context.Context, req dto.PostAssetReq) error {
+	var (
+		opName = "AssetService-Attach"
+		err    error
+	)
+
+	err = req.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = srv.Repo.Attach(ctx, req.PostID, req.AssetIDs)
+	if err != nil {
+		srv.Logger.Errorf("%s failed create data: %v \n", opName, err)
+		return err
+	}
+
+	return nil
+}
+
+func (srv *AssetSrv) Detach(ctx context.Context, postID uint64, assetID uint64) error {
+	opName := "AssetService-Detach"
+
+	err := srv.Repo.Detach(ctx, postID, assetID)
+	if err != nil {
+		srv.Logger.Errorf("%s failed delete data: %v \n", opName, err)
+		return err
+	}
+
+	return nil
+}
+
+func (srv *AssetSrv) isAllowedType(mimeType string) bool {
+	for _, v := range srv.Cfg.Storage.AllowedTypes {
+		if strings.EqualFold(strings.TrimSpace(v), mimeType) {
+			return true
+		}
+	}
+	return false
+}
+
+func (srv *AssetSrv) deleteObject(ctx context.Context, key string) {
+	err := srv.Storage.Delete(ctx, key)
+	if err != nil {
+		srv.Logger.Errorf("AssetService-deleteObject failed delete file %s: %v \n", key, err)
+	}
+}
+
+// detectMimeType sniffs the media type of head without its parameters, e.g.
+// "text/plain" for "text/plain; charset=utf-8".
+func detectMimeType(head []byte) string {
+	mimeType, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil {
+		return "application/octet-stream"
+	}
+	return mimeType
+}
+
+// newBlobKey returns a key for the content of checksum, spread over two
+// levels of directories. The random suffix keeps a blob stored again after
+// the garbage collector removed it apart from the old object.
+func newBlobKey(checksum string) string {
+	random := make([]byte, 4)
+	rand.Read(random)
+
+	return fmt.Sprintf("blobs/%s/%s/%s-%s", checksum[0:2], checksum[2:4], checksum, hex.EncodeToString(random))
+}
+
+// thumbnailKey returns the key of the thumbnail of size made from the object
+// under storageKey, so assets sharing a blob share its thumbnails too.
+func thumbnailKey(storageKey string, size int, ext string) string {
+	return fmt.Sprintf("%s%d%s", thumbnailPrefix(storageKey), size, ext)
+}
+
+func thumbnailPrefix(storageKey string) string {
+	return "thumbnails/" + storageKey + "/"
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+)
+
+// gcBatchSize is how many assets or blobs one query of the garbage
+// collector returns.
+const gcBatchSize = 100
+
+// thumbnailExts are the extensions media.Encode may give a thumbnail.
+var thumbnailExts = []string{".jpg", ".png"}
+
+// StorageReport tells how much deduplication saves and what the next garbage
+// collection removes.
+func (srv *AssetSrv) StorageReport(ctx context.Context) (*dto.StorageReportRes, error) {
+	opName := "AssetService-StorageReport"
+
+	result, err := srv.Repos.Blob.Report(ctx, srv.assetBefore(), srv.blobBefore())
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, err
+	}
+
+	result.GraceHours = srv.Cfg.Storage.GCGrace
+	result.AssetExpiryHours = srv.Cfg.Storage.AssetExpiry
+	return result, nil
+}
+
+// CollectGarbage removes the blobs used by no asset once older than the
+// grace period, with their objects. Only when asset expiry is on, the assets
+// attached to no post since before it expired are removed first. A dry run
+// only reports what would be removed.
+func (srv *AssetSrv) CollectGarbage(ctx context.Context, req dto.StorageGCReq) (*dto.StorageGCRes, error) {
+	opName := "AssetService-CollectGarbage"
+
+	if req.DryRun {
+		report, err := srv.StorageReport(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.StorageGCRes{
+			DryRun:        true,
+			DeletedAssets: int(report.OrphanAssets),
+			DeletedBlobs:  int(report.OrphanBlobs),
+			FreedBytes:    report.OrphanBlobBytes,
+		}, nil
+	}
+
+	var (
+		assetBefore = srv.assetBefore()
+		blobBefore  = srv.blobBefore()
+		result      = &dto.StorageGCRes{}
+	)
+
+	// a batch removing nothing ends the loop, its orphans would only be
+	// returned again
+	for isDone := assetBefore.IsZero(); !isDone; {
+		ids, err := srv.Repo.GetOrphanIDs(ctx, assetBefore, gcBatchSize)
+		if err != nil {
+			srv.Logger.Errorf("%s failed get orphan assets: %v \n", opName, err)
+			return result, err
+		}
+		isDone = len(ids) < gcBatchSize
+
+		deleted := 0
+		for _, id := range ids {
+			asset, err := srv.Repo.DeleteOrphan(ctx, id, assetBefore)
+			if err != nil {
+				srv.Logger.Errorf("%s failed delete asset %d: %v \n", opName, id, err)
+				continue
+			}
+			if asset == nil {
+				continue
+			}
+
+			deleted++
+			srv.releaseObjects(ctx, asset)
+		}
+		result.DeletedAssets += deleted
+		isDone = isDone || deleted == 0
+	}
+
+	for isDone := false; !isDone; {
+		blobs, err := srv.Repos.Blob.GetOrphans(ctx, blobBefore, gcBatchSize)
+		if err != nil {
+			srv.Logger.Errorf("%s failed get orphan blobs: %v \n", opName, err)
+			return result, err
+		}
+		isDone = len(blobs) < gcBatchSize
+
+		deleted := 0
+		for _, blob := range blobs {
+			isDeleted, err := srv.Repos.Blob.Delete(ctx, blob)
+			if err != nil {
+				srv.Logger.Errorf("%s failed delete blob %s: %v \n", opName, blob.Checksum, err)
+				continue
+			}
+			if !isDeleted {
+				continue
+			}
+
+			deleted++
+			result.FreedBytes += blob.Size
+			srv.deleteStored(ctx, blob.StorageKey)
+		}
+		result.DeletedBlobs += deleted
+		isDone = isDone || deleted == 0
+	}
+
+	if result.DeletedAssets > 0 || result.DeletedBlobs > 0 {
+		srv.Logger.Infof("%s removed %d assets and %d blobs, freed %d bytes \n", opName, result.DeletedAssets, result.DeletedBlobs, result.FreedBytes)
+	}
+	return result, nil
+}
+
+// releaseObjects removes the objects only the removed asset used. Those of
+// its blob stay until the blob is collected, assets uploaded before blobs
+// existed may have a key and thumbnails of their own.
+func (srv *AssetSrv) releaseObjects(ctx context.Context, asset *models.Asset) {
+	opName := "AssetService-releaseObjects"
+
+	for _, v := range asset.Thumbnails {
+		if !strings.HasPrefix(v.StorageKey, thumbnailPrefix(asset.StorageKey)) {
+			srv.deleteObject(ctx, v.StorageKey)
+		}
+	}
+
+	isUsed, err := srv.Repos.Blob.IsKeyUsed(ctx, asset.StorageKey)
+	if err != nil {
+		srv.Logger.Errorf("%s failed check key %s: %v \n", opName, asset.StorageKey, err)
+		return
+	}
+	if !isUsed {
+		srv.deleteStored(ctx, asset.StorageKey)
+	}
+}
+
+// deleteStored removes the object under storageKey with every thumbnail that
+// can have been made from it.
+func (srv *AssetSrv) deleteStored(ctx context.Context, storageKey string) {
+	srv.deleteObject(ctx, storageKey)
+	for _, size := range srv.Cfg.Storage.ThumbnailSizes {
+		for _, ext := range thumbnailExts {
+			srv.deleteObject(ctx, thumbnailKey(storageKey, size, ext))
+		}
+	}
+}
+
+func (srv *AssetSrv) blobBefore() time.Time {
+	return time.Now().Add(-time.Duration(srv.Cfg.Storage.GCGrace) * time.Hour)
+}
+
+// assetBefore returns the zero time when asset expiry is off, no asset is
+// created before it.
+func (srv *AssetSrv) assetBefore() time.Time {
+	if srv.Cfg.Storage.AssetExpiry <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(-time.Duration(srv.Cfg.Storage.AssetExpiry) * time.Hour)
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/database"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/media"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+)
+
+// metaClaimTTL is how long a worker may take on one asset before another
+// one may claim it again, e.g. after a crash.
+const metaClaimTTL = 10 * time.Minute
+
+func (srv *AssetSrv) GetList(ctx context.Context, req dto.AssetListReq) ([]dto.AssetRes, error) {
+	var (
+		opName = "AssetService-GetList"
+		err    error
+	)
+
+	err = req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	assets, err := srv.Repo.GetList(ctx, req)
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, err
+	}
+
+	result := make([]dto.AssetRes, 0, len(assets))
+	for _, v := range assets {
+		result = append(result, dto.NewAssetRes(v))
+	}
+	return result, nil
+}
+
+// OpenThumbnail returns the thumbnail of the asset made for size with its
+// content, the caller closes it.
+func (srv *AssetSrv) OpenThumbnail(ctx context.Context, assetID uint64, size int) (*dto.AssetThumbnailRes, io.ReadCloser, error) {
+	opName := "AssetService-OpenThumbnail"
+
+	asset, err := srv.GetDetail(ctx, assetID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	thumbnail := asset.Thumbnail(size)
+	if thumbnail == nil {
+		return nil, nil, helpers.ErrDataNotFound("thumbnail", "thumbnail")
+	}
+
+	content, err := srv.Open(ctx, &dto.AssetRes{StorageKey: thumbnail.StorageKey})
+	if err != nil {
+		srv.Logger.Errorf("%s failed get file: %v \n", opName, err)
+		return nil, nil, err
+	}
+	return thumbnail, content, nil
+}
+
+// StartWorkers runs the metadata workers until ctx is done.
+func (srv *AssetSrv) StartWorkers(ctx context.Context) {
+	srv.workers.Start(ctx)
+}
+
+// EnqueuePending queues the assets still waiting for their metadata, e.g.
+// after a restart or a full queue, and returns how many were queued.
+func (srv *AssetSrv) EnqueuePending(ctx context.Context) (int, error) {
+	opName := "AssetService-EnqueuePending"
+
+	ids, err := srv.Repo.GetPendingMetaIDs(ctx, time.Now().Add(-metaClaimTTL), max(srv.Cfg.Storage.QueueSize, 1))
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return 0, err
+	}
+
+	total := 0
+	for _, id := range ids {
+		if !srv.workers.Submit(id) {
+			break
+		}
+		total++
+	}
+	return total, nil
+}
+
+// ProcessMeta reads the metadata of the asset and makes its thumbnails. An
+// asset already done or being processed by another worker is skipped.
+func (srv *AssetSrv) ProcessMeta(ctx context.Context, assetID uint64) error {
+	opName := "AssetService-ProcessMeta"
+	ctx = database.WithReadPrimary(ctx)
+
+	isClaimed, err := srv.Repo.ClaimMeta(ctx, assetID, time.Now().Add(-metaClaimTTL))
+	if err != nil || !isClaimed {
+		return err
+	}
+
+	asset, err := srv.Repo.GetDetail(ctx, assetID)
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return err
+	}
+
+	content, err := srv.Storage.Get(ctx, asset.StorageKey)
+	if err != nil {
+		srv.Logger.Errorf("%s failed get file %s: %v \n", opName, asset.StorageKey, err)
+		return srv.saveMeta(ctx, asset, dto.AssetMetaFailed)
+	}
+	data, err := io.ReadAll(io.LimitReader(content, asset.Size))
+	content.Close()
+	if err != nil {
+		srv.Logger.Errorf("%s failed read file %s: %v \n", opName, asset.StorageKey, err)
+		return srv.saveMeta(ctx, asset, dto.AssetMetaFailed)
+	}
+
+	meta, err := media.Extract(asset.MimeType, data)
+	if err != nil {
+		srv.Logger.Errorf("%s failed extract metadata of asset %d: %v \n", opName, assetID, err)
+		return srv.saveMeta(ctx, asset, dto.AssetMetaFailed)
+	}
+	asset.Width, asset.Height, asset.PageCount = meta.Width, meta.Height, meta.PageCount
+	asset.EXIF = meta.EXIF
+
+	asset.Thumbnails = nil
+	if strings.HasPrefix(asset.MimeType, "image/") && len(srv.Cfg.Storage.ThumbnailSizes) > 0 {
+		asset.Thumbnails, err = srv.makeThumbnails(ctx, asset, data)
+		if err != nil {
+			srv.Logger.Errorf("%s failed make thumbnails of asset %d: %v \n", opName, assetID, err)
+		}
+	}
+
+	return srv.saveMeta(ctx, asset, dto.AssetMetaDone)
+}
+
+func (srv *AssetSrv) saveMeta(ctx context.Context, asset *models.Asset, status string) error {
+	asset.MetaStatus = status
+	err := srv.Repo.SaveMeta(ctx, asset)
+	if err != nil {
+		srv.Logger.Errorf("AssetService-saveMeta failed update data: %v \n", err)
+	}
+	return err
+}
+
+// makeThumbnails stores one thumbnail for every configured size, keyed by
+// storage key and size so processing again overwrites them.
+func (srv *AssetSrv) makeThumbnails(ctx context.Context, asset *models.Asset, data []byte) ([]models.AssetThumbnail, error) {
+	img, format, err := media.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.AssetThumbnail{}
+	for _, size := range srv.Cfg.Storage.ThumbnailSizes {
+		thumb := media.Thumbnail(img, size)
+		encoded, mimeType, err := media.Encode(thumb, format)
+		if err != nil {
+			return result, err
+		}
+
+		ext := ".jpg"
+		if mimeType == "image/png" {
+			ext = ".png"
+		}
+		key := thumbnailKey(asset.StorageKey, size, ext)
+		err = srv.Storage.Put(ctx, key, bytes.NewReader(encoded), int64(len(encoded)), mimeType)
+		if err != nil {
+			return result, err
+		}
+
+		result = append(result, models.AssetThumbnail{
+			AssetID:    asset.ID,
+			Size:       size,
+			Width:      thumb.Bounds().Dx(),
+			Height:     thumb.Bounds().Dy(),
+			MimeType:   mimeType,
+			ByteSize:   int64(len(encoded)),
+			StorageKey: key,
+		})
+	}
+	return result, nil
+}
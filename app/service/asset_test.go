@@ -0,0 +1,440 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/app/repository"
+	"github.com/adamnasrudin03/go-asset-findr/app/repository/mocks"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/auth"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/driver"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/storage"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+var pngHeader = []byte("\x89PNG\x0D\x0A\x1A\x0A\x00\x00\x00\x0DIHDR")
+
+type AssetServiceTestSuite struct {
+	suite.Suite
+	repo     *mocks.AssetRepository
+	blobRepo *mocks.BlobRepository
+	postRepo *mocks.PostRepository
+	store    *storage.Local
+	ctx      context.Context
+	service  AssetService
+}
+
+func (srv *AssetServiceTestSuite) SetupTest() {
+	var (
+		cfg    = *configs.GetInstance()
+		logger = driver.Logger(&cfg)
+		err    error
+	)
+	cfg.Storage.MaxUploadSize = 1 << 10
+	cfg.Storage.AllowedTypes = []string{"image/png", "text/plain"}
+	cfg.Storage.ThumbnailSizes = []int{16, 64}
+
+	srv.repo = &mocks.AssetRepository{}
+	srv.blobRepo = &mocks.BlobRepository{}
+	srv.postRepo = &mocks.PostRepository{}
+	srv.store, err = storage.NewLocal(srv.T().TempDir())
+	srv.Require().NoError(err)
+	srv.ctx = context.Background()
+	srv.service = NewAssetService(&repository.Repositories{Asset: srv.repo, Blob: srv.blobRepo, Post: srv.postRepo}, srv.store, &cfg, logger)
+}
+
+func TestAssetService(t *testing.T) {
+	suite.Run(t, new(AssetServiceTestSuite))
+}
+
+func (srv *AssetServiceTestSuite) TestAssetSrv_Upload() {
+	var (
+		sum      = sha256.Sum256(pngHeader)
+		checksum = hex.EncodeToString(sum[:])
+		existing = &models.Blob{Checksum: checksum, StorageKey: "blobs/existing"}
+		created  = func(args mock.Arguments) { args.Get(1).(*models.Asset).ID = 1 }
+	)
+	tests := []struct {
+		name      string
+		req       dto.AssetUploadReq
+		mockFunc  func()
+		wantKey   string
+		wantFiles int
+		wantErr   bool
+	}{
+		{
+			name:    "too large",
+			req:     dto.AssetUploadReq{Filename: "big.txt", Size: 2 << 10, File: strings.NewReader("a")},
+			wantErr: true,
+		},
+		{
+			name:    "type not allowed",
+			req:     dto.AssetUploadReq{Filename: "doc.png", Size: 5, File: strings.NewReader("%PDF-")},
+			wantErr: true,
+		},
+		{
+			name:    "shorter than its size",
+			req:     dto.AssetUploadReq{Filename: "note.txt", Size: 10, File: strings.NewReader("hello")},
+			wantErr: true,
+		},
+		{
+			name: "failed create data keeps the blob",
+			req:  dto.AssetUploadReq{Filename: "image.png", Size: int64(len(pngHeader)), File: bytes.NewReader(pngHeader)},
+			mockFunc: func() {
+				srv.blobRepo.On("Find", mock.Anything, checksum).Return(nil, nil).Once()
+				srv.blobRepo.On("Create", mock.Anything, mock.Anything).Return(true, nil).Once()
+				srv.repo.On("Create", mock.Anything, mock.Anything).Return(helpers.ErrCreatedDB()).Once()
+			},
+			wantFiles: 1,
+			wantErr:   true,
+		},
+		{
+			name: "success new content",
+			req:  dto.AssetUploadReq{Filename: "image.txt", Size: int64(len(pngHeader)), File: bytes.NewReader(pngHeader), StripGPS: true},
+			mockFunc: func() {
+				srv.blobRepo.On("Find", mock.Anything, checksum).Return(nil, nil).Once()
+				srv.blobRepo.On("Create", mock.Anything, mock.MatchedBy(func(m *models.Blob) bool {
+					return m.Checksum == checksum && m.MimeType == "image/png" && m.Size == int64(len(pngHeader))
+				})).Return(true, nil).Once()
+				srv.repo.On("Create", mock.Anything, mock.Anything).Run(created).Return(nil).Once()
+			},
+			wantKey:   "blobs/" + checksum[0:2] + "/" + checksum[2:4] + "/" + checksum + "-",
+			wantFiles: 1,
+		},
+		{
+			name: "success same content reuses the blob",
+			req:  dto.AssetUploadReq{Filename: "copy.png", Size: int64(len(pngHeader)), File: bytes.NewReader(pngHeader)},
+			mockFunc: func() {
+				srv.blobRepo.On("Find", mock.Anything, checksum).Return(existing, nil).Once()
+				srv.repo.On("Create", mock.Anything, mock.Anything).Run(created).Return(nil).Once()
+			},
+			wantKey: existing.StorageKey,
+		},
+		{
+			name: "success same content uploaded at the same time",
+			req:  dto.AssetUploadReq{Filename: "copy.png", Size: int64(len(pngHeader)), File: bytes.NewReader(pngHeader)},
+			mockFunc: func() {
+				srv.blobRepo.On("Find", mock.Anything, checksum).Return(nil, nil).Once()
+				srv.blobRepo.On("Create", mock.Anything, mock.Anything).Return(false, nil).Once()
+				srv.blobRepo.On("Find", mock.Anything, checksum).Return(existing, nil).Once()
+				srv.repo.On("Create", mock.Anything, mock.Anything).Run(created).Return(nil).Once()
+			},
+			wantKey: existing.StorageKey,
+		},
+		{
+			name: "success blob collected in the meantime",
+			req:  dto.AssetUploadReq{Filename: "copy.png", Size: int64(len(pngHeader)), File: bytes.NewReader(pngHeader)},
+			mockFunc: func() {
+				srv.blobRepo.On("Find", mock.Anything, checksum).Return(existing, nil).Once()
+				srv.repo.On("Create", mock.Anything, mock.Anything).Return(repository.ErrBlobGone).Once()
+				srv.blobRepo.On("Find", mock.Anything, checksum).Return(nil, nil).Once()
+				srv.blobRepo.On("Create", mock.Anything, mock.Anything).Return(true, nil).Once()
+				srv.repo.On("Create", mock.Anything, mock.Anything).Run(created).Return(nil).Once()
+			},
+			wantKey:   "blobs/",
+			wantFiles: 1,
+		},
+	}
+	for _, tt := range tests {
+		srv.T().Run(tt.name, func(t *testing.T) {
+			var (
+				dir   = t.TempDir()
+				store = srv.store
+				err   error
+			)
+			srv.store, err = storage.NewLocal(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			srv.service.(*AssetSrv).Storage = srv.store
+			defer func() { srv.store = store }()
+			if tt.mockFunc != nil {
+				tt.mockFunc()
+			}
+
+			got, err := srv.service.Upload(srv.ctx, tt.req)
+			if files := countFiles(t, dir); files != tt.wantFiles {
+				t.Errorf("AssetSrv.Upload() stored %d files, want %d", files, tt.wantFiles)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AssetSrv.Upload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got.MimeType != "image/png" || got.Checksum != checksum || got.DownloadURL != "/api/assets/1/download" ||
+				!strings.HasPrefix(got.StorageKey, tt.wantKey) {
+				t.Errorf("AssetSrv.Upload() = %+v", got)
+			}
+		})
+	}
+
+	srv.repo.AssertExpectations(srv.T())
+	srv.blobRepo.AssertExpectations(srv.T())
+}
+
+func countFiles(t *testing.T, dir string) int {
+	total := 0
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			total++
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return total
+}
+
+func (srv *AssetServiceTestSuite) TestAssetSrv_UploadStripGPS() {
+	srv.service.(*AssetSrv).Cfg.Storage.MaxUploadSize = 1 << 20
+	srv.service.(*AssetSrv).Cfg.Storage.AllowedTypes = []string{"image/jpeg"}
+
+	segment := func(marker byte, payload []byte) []byte {
+		result := []byte{0xFF, marker, 0, 0}
+		binary.BigEndian.PutUint16(result[2:], uint16(len(payload)+2))
+		return append(result, payload...)
+	}
+	// the exif data comes after more than the head read at first
+	jpeg := []byte{0xFF, 0xD8}
+	for i := 0; i < 3; i++ {
+		jpeg = append(jpeg, segment(0xE2, make([]byte, 60000))...)
+	}
+	jpeg = append(jpeg, segment(0xE1, []byte("Exif\x00\x00II*\x00\x08\x00\x00\x00\x00\x00\x00\x00\x00\x00"))...)
+	jpeg = append(jpeg, 0xFF, 0xD9)
+
+	srv.blobRepo.On("Find", mock.Anything, mock.Anything).Return(nil, nil).Once()
+	srv.blobRepo.On("Create", mock.Anything, mock.Anything).Return(true, nil).Once()
+	srv.repo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+
+	got, err := srv.service.Upload(srv.ctx, dto.AssetUploadReq{Filename: "photo.jpg", Size: int64(len(jpeg)), File: bytes.NewReader(jpeg), StripGPS: true})
+	srv.Require().NoError(err)
+	srv.Equal(int64(len(jpeg)), got.Size)
+
+	// a file cut inside its exif segment can not be stripped
+	cut := jpeg[:len(jpeg)-10]
+	_, err = srv.service.Upload(srv.ctx, dto.AssetUploadReq{Filename: "cut.jpg", Size: int64(len(cut)), File: bytes.NewReader(cut), StripGPS: true})
+	srv.Equal(dto.ErrAssetStripGPS(), err)
+
+	srv.repo.AssertExpectations(srv.T())
+	srv.blobRepo.AssertExpectations(srv.T())
+}
+
+func (srv *AssetServiceTestSuite) TestAssetSrv_Open() {
+	srv.Require().NoError(srv.store.Put(srv.ctx, "assets/a.txt", strings.NewReader("hello"), 5, "text/plain"))
+
+	content, err := srv.service.Open(srv.ctx, &dto.AssetRes{ID: 1, StorageKey: "assets/a.txt"})
+	srv.Require().NoError(err)
+	got, _ := io.ReadAll(content)
+	content.Close()
+	srv.Equal("hello", string(got))
+
+	_, err = srv.service.Open(srv.ctx, &dto.AssetRes{ID: 2, StorageKey: "assets/missing.txt"})
+	srv.Error(err)
+}
+
+func (srv *AssetServiceTestSuite) TestAssetSrv_Delete() {
+	for _, key := range []string{"assets/a.txt", "thumbnails/1/16.png", "blobs/b", "thumbnails/blobs/b/16.png"} {
+		srv.Require().NoError(srv.store.Put(srv.ctx, key, strings.NewReader("hello"), 5, "text/plain"))
+	}
+
+	// uploaded before blobs, with a key and thumbnails of its own
+	srv.repo.On("Delete", mock.Anything, uint64(1)).Return(&models.Asset{
+		ID: 1, StorageKey: "assets/a.txt", Thumbnails: []models.AssetThumbnail{{Size: 16, StorageKey: "thumbnails/1/16.png"}},
+	}, nil).Once()
+	srv.blobRepo.On("IsKeyUsed", mock.Anything, "assets/a.txt").Return(false, nil).Once()
+	srv.repo.On("Delete", mock.Anything, uint64(2)).Return(&models.Asset{
+		ID: 2, StorageKey: "blobs/b", Thumbnails: []models.AssetThumbnail{{Size: 16, StorageKey: "thumbnails/blobs/b/16.png"}},
+	}, nil).Once()
+	srv.blobRepo.On("IsKeyUsed", mock.Anything, "blobs/b").Return(true, nil).Once()
+	srv.repo.On("Delete", mock.Anything, uint64(3)).Return(nil, helpers.ErrNotFound()).Once()
+
+	srv.Require().NoError(srv.service.Delete(srv.ctx, 1))
+	for _, key := range []string{"assets/a.txt", "thumbnails/1/16.png"} {
+		_, err := srv.store.Get(srv.ctx, key)
+		srv.True(errors.Is(err, storage.ErrNotFound), "file %s not removed: %v", key, err)
+	}
+
+	// the blob is left to the garbage collector
+	srv.Require().NoError(srv.service.Delete(srv.ctx, 2))
+	for _, key := range []string{"blobs/b", "thumbnails/blobs/b/16.png"} {
+		content, err := srv.store.Get(srv.ctx, key)
+		srv.Require().NoError(err, "file %s removed", key)
+		content.Close()
+	}
+
+	srv.Error(srv.service.Delete(srv.ctx, 3))
+	srv.blobRepo.AssertExpectations(srv.T())
+}
+
+func (srv *AssetServiceTestSuite) TestAssetSrv_Attach() {
+	srv.repo.On("Attach", mock.Anything, uint64(1), []uint64{2, 3}).Return(nil).Once()
+
+	srv.Error(srv.service.Attach(srv.ctx, dto.PostAssetReq{PostID: 1}))
+	srv.NoError(srv.service.Attach(srv.ctx, dto.PostAssetReq{PostID: 1, AssetIDs: []uint64{2, 3, 2}}))
+	srv.repo.AssertExpectations(srv.T())
+}
+
+func (srv *AssetServiceTestSuite) TestAssetSrv_GetByPost() {
+	var (
+		createdAt = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		editorCtx = auth.WithActor(srv.ctx, auth.Actor{ID: 1, Role: auth.RoleEditor})
+		column    = "id, status, publish_at"
+	)
+	srv.postRepo.On("GetDetail", mock.Anything, dto.PostGetReq{ID: 1, ColumnCustom: column}).Return(&dto.PostRes{ID: 1, Status: dto.PostStatusPublished}, nil).Once()
+	srv.postRepo.On("GetDetail", mock.Anything, dto.PostGetReq{ID: 2, ColumnCustom: column}).Return(nil, helpers.ErrNotFound()).Once()
+	srv.postRepo.On("GetDetail", mock.Anything, dto.PostGetReq{ID: 3, ColumnCustom: column}).Return(&dto.PostRes{ID: 3, Status: dto.PostStatusDraft}, nil).Twice()
+	srv.repo.On("GetByPost", mock.Anything, uint64(1)).Return([]models.Asset{{ID: 5, Filename: "a.png", CreatedAt: createdAt}}, nil).Once()
+	srv.repo.On("GetByPost", mock.Anything, uint64(3)).Return([]models.Asset{}, nil).Once()
+
+	got, err := srv.service.GetByPost(srv.ctx, 1)
+	srv.Require().NoError(err)
+	srv.Equal([]dto.AssetRes{{ID: 5, Filename: "a.png", DownloadURL: "/api/assets/5/download", Thumbnails: []dto.AssetThumbnailRes{}, CreatedAt: createdAt}}, got)
+
+	_, err = srv.service.GetByPost(srv.ctx, 2)
+	srv.Error(err)
+
+	// a draft is only seen by editors
+	_, err = srv.service.GetByPost(srv.ctx, 3)
+	srv.Equal(helpers.ErrNotFound(), err)
+
+	_, err = srv.service.GetByPost(editorCtx, 3)
+	srv.NoError(err)
+	srv.repo.AssertExpectations(srv.T())
+}
+
+func (srv *AssetServiceTestSuite) TestAssetSrv_GetDetail() {
+	editorCtx := auth.WithActor(srv.ctx, auth.Actor{ID: 1, Role: auth.RoleEditor})
+	srv.repo.On("GetDetail", mock.Anything, uint64(1)).Return(&models.Asset{ID: 1}, nil).Once()
+	srv.repo.On("GetDetail", mock.Anything, uint64(2)).Return(&models.Asset{ID: 2}, nil).Twice()
+	srv.repo.On("IsPublic", mock.Anything, uint64(1)).Return(true, nil).Once()
+	srv.repo.On("IsPublic", mock.Anything, uint64(2)).Return(false, nil).Once()
+
+	got, err := srv.service.GetDetail(srv.ctx, 1)
+	srv.Require().NoError(err)
+	srv.Equal(uint64(1), got.ID)
+
+	// the assets of drafts only are seen by editors
+	_, err = srv.service.GetDetail(srv.ctx, 2)
+	srv.Error(err)
+
+	got, err = srv.service.GetDetail(editorCtx, 2)
+	srv.Require().NoError(err)
+	srv.Equal(uint64(2), got.ID)
+	srv.repo.AssertExpectations(srv.T())
+}
+
+func (srv *AssetServiceTestSuite) TestAssetSrv_ProcessMeta() {
+	img := &bytes.Buffer{}
+	png.Encode(img, image.NewRGBA(image.Rect(0, 0, 40, 20)))
+	srv.Require().NoError(srv.store.Put(srv.ctx, "assets/a.png", bytes.NewReader(img.Bytes()), int64(img.Len()), "image/png"))
+
+	asset := &models.Asset{ID: 1, MimeType: "image/png", Size: int64(img.Len()), StorageKey: "assets/a.png"}
+	srv.repo.On("ClaimMeta", mock.Anything, uint64(1), mock.Anything).Return(true, nil).Once()
+	srv.repo.On("ClaimMeta", mock.Anything, uint64(2), mock.Anything).Return(false, nil).Once()
+	srv.repo.On("GetDetail", mock.Anything, uint64(1)).Return(asset, nil).Once()
+	srv.repo.On("SaveMeta", mock.Anything, mock.MatchedBy(func(m *models.Asset) bool {
+		return m.MetaStatus == dto.AssetMetaDone && m.Width == 40 && m.Height == 20 &&
+			len(m.Thumbnails) == 2 && m.Thumbnails[0].Width == 16 && m.Thumbnails[1].Width == 40
+	})).Return(nil).Once()
+
+	srv.Require().NoError(srv.service.ProcessMeta(srv.ctx, 1))
+	srv.Require().NoError(srv.service.ProcessMeta(srv.ctx, 2))
+	srv.repo.AssertExpectations(srv.T())
+
+	content, err := srv.store.Get(srv.ctx, "thumbnails/assets/a.png/16.png")
+	srv.Require().NoError(err)
+	content.Close()
+}
+
+func (srv *AssetServiceTestSuite) TestAssetSrv_ProcessMetaMissingFile() {
+	srv.repo.On("ClaimMeta", mock.Anything, uint64(1), mock.Anything).Return(true, nil).Once()
+	srv.repo.On("GetDetail", mock.Anything, uint64(1)).Return(&models.Asset{ID: 1, MimeType: "image/png", StorageKey: "assets/missing.png"}, nil).Once()
+	srv.repo.On("SaveMeta", mock.Anything, mock.MatchedBy(func(m *models.Asset) bool {
+		return m.MetaStatus == dto.AssetMetaFailed
+	})).Return(nil).Once()
+
+	srv.NoError(srv.service.ProcessMeta(srv.ctx, 1))
+	srv.repo.AssertExpectations(srv.T())
+}
+
+func (srv *AssetServiceTestSuite) TestAssetSrv_GetList() {
+	srv.repo.On("GetList", mock.Anything, mock.MatchedBy(func(req dto.AssetListReq) bool {
+		return len(req.Parsed) == 1 && req.Parsed[0].Column == "width"
+	})).Return([]models.Asset{{ID: 1, Width: 2048}}, nil).Once()
+
+	_, err := srv.service.GetList(srv.ctx, dto.AssetListReq{Filters: []string{"owner=me"}})
+	srv.Error(err)
+
+	got, err := srv.service.GetList(srv.ctx, dto.AssetListReq{Filters: []string{"width>=1024"}})
+	srv.Require().NoError(err)
+	srv.Len(got, 1)
+}
+
+func (srv *AssetServiceTestSuite) TestAssetSrv_CollectGarbage() {
+	for _, key := range []string{"blobs/a", "thumbnails/blobs/a/16.jpg", "blobs/b"} {
+		srv.Require().NoError(srv.store.Put(srv.ctx, key, strings.NewReader("hello"), 5, "text/plain"))
+	}
+
+	srv.blobRepo.On("Report", mock.Anything, mock.Anything, mock.Anything).Return(&dto.StorageReportRes{OrphanAssets: 2, OrphanBlobs: 1, OrphanBlobBytes: 5}, nil).Once()
+	got, err := srv.service.CollectGarbage(srv.ctx, dto.StorageGCReq{DryRun: true})
+	srv.Require().NoError(err)
+	srv.Equal(&dto.StorageGCRes{DryRun: true, DeletedAssets: 2, DeletedBlobs: 1, FreedBytes: 5}, got)
+
+	// assets only expire when asked to, asset 2 was attached in the
+	// meantime, blob b is used again
+	srv.service.(*AssetSrv).Cfg.Storage.AssetExpiry = 24
+	srv.repo.On("GetOrphanIDs", mock.Anything, mock.Anything, gcBatchSize).Return([]uint64{1, 2}, nil).Once()
+	srv.repo.On("DeleteOrphan", mock.Anything, uint64(1), mock.Anything).Return(&models.Asset{ID: 1, StorageKey: "blobs/a"}, nil).Once()
+	srv.repo.On("DeleteOrphan", mock.Anything, uint64(2), mock.Anything).Return(nil, nil).Once()
+	srv.blobRepo.On("IsKeyUsed", mock.Anything, "blobs/a").Return(true, nil).Once()
+	srv.blobRepo.On("GetOrphans", mock.Anything, mock.Anything, gcBatchSize).Return([]models.Blob{
+		{Checksum: "a", StorageKey: "blobs/a", Size: 5},
+		{Checksum: "b", StorageKey: "blobs/b", Size: 5},
+	}, nil).Once()
+	srv.blobRepo.On("Delete", mock.Anything, models.Blob{Checksum: "a", StorageKey: "blobs/a", Size: 5}).Return(true, nil).Once()
+	srv.blobRepo.On("Delete", mock.Anything, models.Blob{Checksum: "b", StorageKey: "blobs/b", Size: 5}).Return(false, nil).Once()
+
+	got, err = srv.service.CollectGarbage(srv.ctx, dto.StorageGCReq{})
+	srv.Require().NoError(err)
+	srv.Equal(&dto.StorageGCRes{DeletedAssets: 1, DeletedBlobs: 1, FreedBytes: 5}, got)
+	srv.repo.AssertExpectations(srv.T())
+	srv.blobRepo.AssertExpectations(srv.T())
+
+	for _, key := range []string{"blobs/a", "thumbnails/blobs/a/16.jpg"} {
+		_, err := srv.store.Get(srv.ctx, key)
+		srv.True(errors.Is(err, storage.ErrNotFound), "file %s not removed: %v", key, err)
+	}
+	content, err := srv.store.Get(srv.ctx, "blobs/b")
+	srv.Require().NoError(err)
+	content.Close()
+
+	// without asset expiry only the blobs are collected
+	srv.service.(*AssetSrv).Cfg.Storage.AssetExpiry = 0
+	srv.blobRepo.On("GetOrphans", mock.Anything, mock.Anything, gcBatchSize).Return([]models.Blob{}, nil).Once()
+
+	got, err = srv.service.CollectGarbage(srv.ctx, dto.StorageGCReq{})
+	srv.Require().NoError(err)
+	srv.Equal(&dto.StorageGCRes{}, got)
+	srv.repo.AssertExpectations(srv.T())
+	srv.blobRepo.AssertExpectations(srv.T())
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/app/repository"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/event"
+	"github.com/sirupsen/logrus"
+)
+
+// OutboxService relays the events of the outbox to the publisher.
+type OutboxService interface {
+	Relay(ctx context.Context) (int, error)
+	Prune(ctx context.Context) (int64, error)
+}
+
+type OutboxSrv struct {
+	Repos     *repository.Repositories
+	Repo      repository.OutboxRepository
+	Publisher event.Publisher
+	Cfg       *configs.Configs
+	Logger    *logrus.Logger
+}
+
+// NewOutboxService creates a new instance of OutboxService.
+func NewOutboxService(
+	repos *repository.Repositories,
+	publisher event.Publisher,
+	cfg *configs.Configs,
+	logger *logrus.Logger,
+) OutboxService {
+	return &OutboxSrv{
+		Repos:     repos,
+		Repo:      repos.Outbox,
+		Publisher: publisher,
+		Cfg:       cfg,
+		Logger:    logger,
+	}
+}
+
+// Relay publishes the due events batch by batch until none is left and
+// returns how many were published. An event is marked published in the
+// transaction that locked it, only after the publisher accepted it, so a
+// crash in between publishes it again. A failed event is retried later with
+// a backoff, the next events of its post wait for it.
+func (srv *OutboxSrv) Relay(ctx context.Context) (int, error) {
+	var (
+		opName    = "OutboxService-Relay"
+		batchSize = srv.batchSize()
+		total     = 0
+	)
+
+	for {
+		var (
+			now       = time.Now()
+			published = []uint64{}
+			due       = 0
+		)
+		err := srv.Repos.UnitOfWork(ctx, func(ctx context.Context, repos *repository.Repositories) error {
+			events, err := repos.Outbox.GetDue(ctx, now, batchSize)
+			if err != nil {
+				return err
+			}
+			due = len(events)
+
+			for _, v := range events {
+				err = srv.Publisher.Publish(ctx, newEvent(v))
+				if err == nil {
+					published = append(published, v.ID)
+					continue
+				}
+
+				srv.Logger.Warnf("%s failed publish event %d %s of %s %d, attempt %d: %v \n", opName, v.ID, v.EventType, v.AggregateType, v.AggregateID, v.Attempts+1, err)
+				err = repos.Outbox.MarkFailed(ctx, v.ID, err.Error(), now.Add(srv.backoff(v.Attempts+1)))
+				if err != nil {
+					return err
+				}
+			}
+
+			return repos.Outbox.MarkPublished(ctx, published, now)
+		})
+		if err != nil {
+			srv.Logger.Errorf("%s failed relay events: %v \n", opName, err)
+			return total, err
+		}
+
+		total += len(published)
+		if due < batchSize || len(published) == 0 {
+			return total, nil
+		}
+	}
+}
+
+// Prune removes the events published longer ago than the retention.
+func (srv *OutboxSrv) Prune(ctx context.Context) (int64, error) {
+	opName := "OutboxService-Prune"
+
+	before := time.Now().Add(-time.Duration(srv.Cfg.Event.Retention) * time.Hour)
+	total, err := srv.Repo.DeletePublished(ctx, before)
+	if err != nil {
+		srv.Logger.Errorf("%s failed delete data: %v \n", opName, err)
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func (srv *OutboxSrv) batchSize() int {
+	if srv.Cfg.Event.BatchSize <= 0 {
+		return 100
+	}
+	return srv.Cfg.Event.BatchSize
+}
+
+func (srv *OutboxSrv) backoff(attempts int) time.Duration {
+	return backoff(srv.Cfg.Event.RetryBase, srv.Cfg.Event.RetryMax, attempts)
+}
+
+// backoff is the delay before the attempt after attempts failed ones, base
+// seconds doubled on every attempt up to max seconds.
+func backoff(base, max, attempts int) time.Duration {
+	var (
+		delay    = time.Duration(base) * time.Second
+		maxDelay = time.Duration(max) * time.Second
+	)
+	for i := 1; i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
+
+func newEvent(m models.OutboxEvent) event.Event {
+	result := event.Event{
+		ID:            m.ID,
+		Type:          m.EventType,
+		AggregateType: m.AggregateType,
+		AggregateID:   m.AggregateID,
+		Payload:       json.RawMessage(m.Payload),
+		OccurredAt:    m.CreatedAt,
+	}
+	if m.PublishedSeq != nil {
+		result.Seq = *m.PublishedSeq
+	}
+	return result
+}
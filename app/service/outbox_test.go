@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/app/repository"
+	"github.com/adamnasrudin03/go-asset-findr/app/repository/mocks"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/driver"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/event"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type OutboxServiceTestSuite struct {
+	suite.Suite
+	repo      *mocks.OutboxRepository
+	publisher *event.Memory
+	cfg       *configs.Configs
+	ctx       context.Context
+	service   *OutboxSrv
+}
+
+func (srv *OutboxServiceTestSuite) SetupTest() {
+	srv.cfg = configs.GetInstance()
+	srv.cfg.Event.BatchSize = 2
+	srv.cfg.Event.RetryBase = 5
+	srv.cfg.Event.RetryMax = 60
+
+	srv.repo = &mocks.OutboxRepository{}
+	srv.publisher = event.NewMemory()
+	srv.ctx = context.Background()
+	srv.service = NewOutboxService(&repository.Repositories{Outbox: srv.repo}, srv.publisher, srv.cfg, driver.Logger(srv.cfg)).(*OutboxSrv)
+}
+
+func TestOutboxService(t *testing.T) {
+	suite.Run(t, new(OutboxServiceTestSuite))
+}
+
+func (srv *OutboxServiceTestSuite) TestOutboxSrv_Relay() {
+	var (
+		got    = []uint64{}
+		events = []models.OutboxEvent{
+			{ID: 1, AggregateType: dto.AggregatePost, AggregateID: 10, EventType: dto.PostEventCreated, Payload: models.JSON(`{"before":null}`)},
+			{ID: 2, AggregateType: dto.AggregatePost, AggregateID: 11, EventType: dto.PostEventUpdated, Attempts: 2},
+		}
+	)
+	srv.publisher.Subscribe(func(ctx context.Context, e event.Event) error {
+		if e.AggregateID == 11 {
+			return errors.New("unavailable")
+		}
+		got = append(got, e.ID)
+		return nil
+	})
+
+	srv.repo.On("GetDue", mock.Anything, mock.Anything, 2).Return(events, nil).Once()
+	srv.repo.On("MarkFailed", mock.Anything, uint64(2), "unavailable", mock.MatchedBy(func(next time.Time) bool {
+		// third attempt: 5s doubled twice
+		delay := time.Until(next)
+		return delay > 19*time.Second && delay <= 20*time.Second
+	})).Return(nil).Once()
+	srv.repo.On("MarkPublished", mock.Anything, []uint64{1}, mock.Anything).Return(nil).Once()
+	srv.repo.On("GetDue", mock.Anything, mock.Anything, 2).Return([]models.OutboxEvent{events[0]}, nil).Once()
+	srv.repo.On("MarkPublished", mock.Anything, []uint64{1}, mock.Anything).Return(nil).Once()
+
+	total, err := srv.service.Relay(srv.ctx)
+	srv.Require().NoError(err)
+	srv.Equal(2, total)
+	srv.Equal([]uint64{1, 1}, got)
+	srv.repo.AssertExpectations(srv.T())
+}
+
+func (srv *OutboxServiceTestSuite) TestOutboxSrv_Relay_Failed() {
+	srv.repo.On("GetDue", mock.Anything, mock.Anything, 2).Return(nil, helpers.ErrDB()).Once()
+
+	total, err := srv.service.Relay(srv.ctx)
+	srv.Error(err)
+	srv.Equal(0, total)
+	srv.repo.AssertExpectations(srv.T())
+}
+
+func (srv *OutboxServiceTestSuite) TestOutboxSrv_Prune() {
+	srv.repo.On("DeletePublished", mock.Anything, mock.Anything).Return(int64(3), nil).Once()
+
+	total, err := srv.service.Prune(srv.ctx)
+	srv.NoError(err)
+	srv.Equal(int64(3), total)
+	srv.repo.AssertExpectations(srv.T())
+}
+
+func TestOutboxSrv_backoff(t *testing.T) {
+	srv := &OutboxSrv{Cfg: &configs.Configs{Event: configs.EventConfig{RetryBase: 5, RetryMax: 60}}}
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: 5 * time.Second},
+		{attempts: 2, want: 10 * time.Second},
+		{attempts: 4, want: 40 * time.Second},
+		{attempts: 5, want: 60 * time.Second},
+		{attempts: 100, want: 60 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := srv.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
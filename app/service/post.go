@@ -2,23 +2,44 @@ package service
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/adamnasrudin03/go-asset-findr/app/configs"
 	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
 	"github.com/adamnasrudin03/go-asset-findr/app/repository"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/auth"
 	"github.com/adamnasrudin03/go-template/pkg/helpers"
 	"github.com/sirupsen/logrus"
 )
 
 type PostService interface {
-	GetList(ctx context.Context) ([]dto.PostRes, error)
+	GetList(ctx context.Context, req dto.PostListReq) ([]dto.PostRes, error)
 	GetDetail(ctx context.Context, req dto.PostGetReq) (*dto.PostRes, error)
+	GetBySlug(ctx context.Context, slug string) (*dto.PostRes, error)
 	Create(ctx context.Context, req dto.PostCreateReq) (*dto.PostRes, error)
 	DeleteByID(ctx context.Context, postID uint64) error
 	UpdateByID(ctx context.Context, req dto.PostUpdateReq) error
+	UpdateStatus(ctx context.Context, req dto.PostStatusReq) error
+	PublishDue(ctx context.Context) (int, error)
+	GetRevisions(ctx context.Context, postID uint64) ([]dto.PostRevisionRes, error)
+	GetRevision(ctx context.Context, req dto.PostRevisionGetReq) (*dto.PostRevisionRes, error)
+	DiffRevisions(ctx context.Context, req dto.PostRevisionDiffReq) (*dto.PostRevisionDiffRes, error)
+	RestoreRevision(ctx context.Context, req dto.PostRevisionGetReq) error
+	BulkCreate(ctx context.Context, req dto.PostBulkCreateReq) (*dto.PostBulkRes, error)
+	BulkUpdate(ctx context.Context, req dto.PostBulkUpdateReq) (*dto.PostBulkRes, error)
+	BulkDelete(ctx context.Context, req dto.PostBulkDeleteReq) (*dto.PostBulkRes, error)
+	Export(ctx context.Context, req dto.PostExportReq, w io.Writer) error
+	Import(ctx context.Context, req dto.PostImportReq, r io.Reader) (*dto.PostImportRes, error)
+	GetRelated(ctx context.Context, req dto.PostRelatedReq) ([]dto.PostRelatedRes, error)
+	GetPage(ctx context.Context, req dto.PostPageReq) (*dto.PostPageRes, error)
+	GetTagsByPosts(ctx context.Context, postIDs []uint64) (map[uint64][]dto.TagRes, error)
+	GetAuthorsByPosts(ctx context.Context, postIDs []uint64) (map[uint64][]dto.PostAuthorRes, error)
 }
 
 type PostSrv struct {
+	Repos  *repository.Repositories
 	Repo   repository.PostRepository
 	Cfg    *configs.Configs
 	Logger *logrus.Logger
@@ -26,23 +47,48 @@ type PostSrv struct {
 
 // NewPostService creates a new instance of PostService.
 func NewPostService(
-	postRepo repository.PostRepository,
+	repos *repository.Repositories,
 	cfg *configs.Configs,
 	logger *logrus.Logger,
 ) PostService {
 	return &PostSrv{
-		Repo:   postRepo,
+		Repos:  repos,
+		Repo:   repos.Post,
 		Cfg:    cfg,
 		Logger: logger,
 	}
 }
 
-func (srv *PostSrv) GetList(ctx context.Context) ([]dto.PostRes, error) {
+// GetList returns the posts, only those tagged with req.Tag when given. The
+// tag may be given by a synonym and can include its descendants.
+func (srv *PostSrv) GetList(ctx context.Context, req dto.PostListReq) ([]dto.PostRes, error) {
 	var (
 		opName = "PostService-GetList"
 		err    error
 	)
 
+	err = req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	var labels map[string]bool
+	if req.Tag != "" {
+		tags, err := srv.Repos.Tag.Resolve(ctx, req.Tag, req.Descendants)
+		if err != nil {
+			srv.Logger.Errorf("%s failed get data tags: %v \n", opName, err)
+			return []dto.PostRes{}, err
+		}
+		if len(tags) == 0 {
+			return []dto.PostRes{}, nil
+		}
+
+		labels = make(map[string]bool, len(tags))
+		for _, v := range tags {
+			labels[v.Label] = true
+		}
+	}
+
 	res, err := srv.Repo.GetAll(ctx)
 	if err != nil {
 		srv.Logger.Errorf("%s failed get data: %v \n", opName, err)
@@ -52,11 +98,36 @@ func (srv *PostSrv) GetList(ctx context.Context) ([]dto.PostRes, error) {
 		return []dto.PostRes{}, nil
 	}
 
+	var (
+		isEditor = auth.FromContext(ctx).IsEditor()
+		now      = time.Now()
+		result   = make([]dto.PostRes, 0, len(res))
+	)
 	for i := range res {
+		if !isEditor && !res[i].IsPublished(now) {
+			continue
+		}
+		if labels != nil && !hasAnyTag(res[i].Tags, labels) {
+			continue
+		}
+
 		res[i].CheckResp()
+		res[i].Represent("")
+		result = append(result, res[i])
 	}
 
-	return res, nil
+	return result, nil
+}
+
+// hasAnyTag reports whether one of tags, as rendered in PostRes, is one of
+// labels.
+func hasAnyTag(tags []string, labels map[string]bool) bool {
+	for _, v := range tags {
+		if labels[helpers.ToLower(v)] {
+			return true
+		}
+	}
+	return false
 }
 
 func (srv *PostSrv) GetDetail(ctx context.Context, req dto.PostGetReq) (*dto.PostRes, error) {
@@ -76,7 +147,13 @@ func (srv *PostSrv) GetDetail(ctx context.Context, req dto.PostGetReq) (*dto.Pos
 		return nil, err
 	}
 
+	// unpublished posts do not exist for the public
+	if !auth.FromContext(ctx).IsEditor() && !res.IsPublished(time.Now()) {
+		return nil, helpers.ErrNotFound()
+	}
+
 	res.CheckResp()
+	res.Represent(req.Render)
 	return res, nil
 }
 
@@ -91,12 +168,18 @@ func (srv *PostSrv) Create(ctx context.Context, req dto.PostCreateReq) (*dto.Pos
 		return nil, err
 	}
 
+	err = checkCanCreateWithStatus(ctx, req.Status)
+	if err != nil {
+		return nil, err
+	}
+
 	result, err := srv.Repo.Create(ctx, req)
 	if err != nil {
 		srv.Logger.Errorf("%s failed create data: %v \n", opName, err)
 		return nil, helpers.ErrCreatedDB()
 	}
 	result.CheckResp()
+	result.Represent("")
 	return result, nil
 }
 
@@ -125,7 +208,7 @@ func (srv *PostSrv) UpdateByID(ctx context.Context, req dto.PostUpdateReq) error
 		return err
 	}
 
-	err = srv.Repo.UpdateByID(ctx, req)
+	err = srv.Repos.UnitOfWork(ctx, srv.update(req))
 	if err != nil {
 		srv.Logger.Errorf("%s failed update data: %v \n", opName, err)
 		return err
@@ -133,3 +216,213 @@ func (srv *PostSrv) UpdateByID(ctx context.Context, req dto.PostUpdateReq) error
 
 	return nil
 }
+
+// update updates the post of a validated req, after saving the post as it
+// is now as a revision. It is run as a unit of work, the snapshot and the
+// update share one transaction.
+func (srv *PostSrv) update(req dto.PostUpdateReq) func(ctx context.Context, repos *repository.Repositories) error {
+	return func(ctx context.Context, repos *repository.Repositories) error {
+		current, err := repos.Post.GetDetail(ctx, dto.PostGetReq{ID: req.ID, IsLock: true})
+		if err != nil {
+			return err
+		}
+
+		actor := auth.FromContext(ctx)
+		err = repos.PostRevision.Create(ctx, &models.PostRevision{
+			PostID:        current.ID,
+			Title:         current.Title,
+			Content:       current.Content,
+			ContentFormat: current.ContentFormat,
+			Tags:          models.StringList(current.Tags),
+			AuthorID:      actor.ID,
+			Author:        actor.Username,
+		})
+		if err != nil {
+			return err
+		}
+
+		if req.ContentFormat == "" {
+			req.ContentFormat = current.ContentFormat
+		}
+		return repos.Post.UpdateByID(ctx, req)
+	}
+}
+
+func (srv *PostSrv) BulkCreate(ctx context.Context, req dto.PostBulkCreateReq) (*dto.PostBulkRes, error) {
+	var (
+		opName = "PostService-BulkCreate"
+		err    error
+	)
+
+	err = req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		result = &dto.PostBulkRes{
+			Mode:  req.Mode,
+			Items: make([]dto.PostBulkItemRes, len(req.Items)),
+		}
+		valid      = []dto.PostCreateReq{}
+		validIndex = []int{}
+	)
+	for i := range req.Items {
+		result.Items[i].Index = i
+		err = req.Items[i].Validate()
+		if err == nil {
+			err = checkCanCreateWithStatus(ctx, req.Items[i].Status)
+		}
+		if err != nil {
+			result.Items[i].SetError(err, helpers.ErrGetRequest())
+			continue
+		}
+
+		valid = append(valid, req.Items[i])
+		validIndex = append(validIndex, i)
+	}
+
+	isCanceled := req.IsAtomic() && len(valid) < len(req.Items)
+	if isCanceled || len(valid) == 0 {
+		for _, i := range validIndex {
+			result.Items[i].SetError(nil, dto.ErrBulkCanceled())
+		}
+		result.CountResult()
+		return result, nil
+	}
+
+	items, err := srv.Repo.CreateBulk(ctx, valid, req.IsAtomic())
+	if err != nil {
+		srv.Logger.Errorf("%s failed create data: %v \n", opName, err)
+	}
+	for i, v := range bulkItemsOrError(items, len(valid), err) {
+		v.Index = validIndex[i]
+		result.Items[v.Index] = v
+	}
+
+	result.CountResult()
+	return result, nil
+}
+
+// BulkUpdate updates the posts as UpdateByID does, each keeping a revision.
+// When atomic all items share one transaction and any failure rolls back
+// the others, otherwise each item has its own transaction.
+func (srv *PostSrv) BulkUpdate(ctx context.Context, req dto.PostBulkUpdateReq) (*dto.PostBulkRes, error) {
+	var (
+		opName = "PostService-BulkUpdate"
+		err    error
+	)
+
+	err = req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		result = &dto.PostBulkRes{
+			Mode:  req.Mode,
+			Items: make([]dto.PostBulkItemRes, len(req.Items)),
+		}
+		validIndex = []int{}
+	)
+	for i := range req.Items {
+		result.Items[i].Index = i
+		result.Items[i].ID = req.Items[i].ID
+		err = req.Items[i].Validate()
+		if err != nil {
+			result.Items[i].SetError(err, helpers.ErrGetRequest())
+			continue
+		}
+		validIndex = append(validIndex, i)
+	}
+
+	isCanceled := req.IsAtomic() && len(validIndex) < len(req.Items)
+	if isCanceled || len(validIndex) == 0 {
+		for _, i := range validIndex {
+			result.Items[i].SetError(nil, dto.ErrBulkCanceled())
+		}
+		result.CountResult()
+		return result, nil
+	}
+
+	if !req.IsAtomic() {
+		for _, i := range validIndex {
+			err = srv.Repos.UnitOfWork(ctx, srv.update(req.Items[i]))
+			if err != nil {
+				srv.Logger.Errorf("%s failed update item %d: %v \n", opName, i, err)
+				result.Items[i].SetError(err, helpers.ErrUpdatedDB())
+				continue
+			}
+			result.Items[i].Status = dto.BulkStatusSuccess
+		}
+		result.CountResult()
+		return result, nil
+	}
+
+	failed := -1
+	err = srv.Repos.UnitOfWork(ctx, func(ctx context.Context, repos *repository.Repositories) error {
+		for _, i := range validIndex {
+			err := srv.update(req.Items[i])(ctx, repos)
+			if err != nil {
+				failed = i
+				return err
+			}
+		}
+		return nil
+	})
+	for _, i := range validIndex {
+		switch {
+		case err == nil:
+			result.Items[i].Status = dto.BulkStatusSuccess
+		case i == failed:
+			result.Items[i].SetError(err, helpers.ErrUpdatedDB())
+		default:
+			result.Items[i].SetError(nil, dto.ErrBulkCanceled())
+		}
+	}
+	if err != nil {
+		srv.Logger.Errorf("%s failed update data: %v \n", opName, err)
+	}
+
+	result.CountResult()
+	return result, nil
+}
+
+func (srv *PostSrv) BulkDelete(ctx context.Context, req dto.PostBulkDeleteReq) (*dto.PostBulkRes, error) {
+	var (
+		opName = "PostService-BulkDelete"
+		err    error
+	)
+
+	err = req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := srv.Repo.DeleteBulk(ctx, req.IDs, req.IsAtomic())
+	if err != nil {
+		srv.Logger.Errorf("%s failed delete data: %v \n", opName, err)
+	}
+
+	result := &dto.PostBulkRes{
+		Mode:  req.Mode,
+		Items: items,
+	}
+	result.CountResult()
+	return result, nil
+}
+
+// bulkItemsOrError returns the total items written by a bulk repository
+// call, or as many failed with err when it gave none back for them.
+func bulkItemsOrError(items []dto.PostBulkItemRes, total int, err error) []dto.PostBulkItemRes {
+	if len(items) == total {
+		return items
+	}
+
+	result := make([]dto.PostBulkItemRes, total)
+	for i := range result {
+		result[i].Index = i
+		result[i].SetError(err, helpers.ErrCreatedDB())
+	}
+	return result
+}
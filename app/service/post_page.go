@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/auth"
+)
+
+// GetPage returns a page of posts, newest first, the public only gets the
+// published ones. Their tags are not loaded, see GetTagsByPosts, and their
+// content is kept with its rendered forms for the caller to Represent.
+func (srv *PostSrv) GetPage(ctx context.Context, req dto.PostPageReq) (*dto.PostPageRes, error) {
+	opName := "PostService-GetPage"
+
+	err := req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.PostPageRes{Posts: []dto.PostRes{}}
+	req.TagIDs = nil
+	if req.Tag != "" {
+		tags, err := srv.Repos.Tag.Resolve(ctx, req.Tag, req.Descendants)
+		if err != nil {
+			srv.Logger.Errorf("%s failed get data tags: %v \n", opName, err)
+			return nil, err
+		}
+		if len(tags) == 0 {
+			return result, nil
+		}
+
+		req.TagIDs = make([]uint64, 0, len(tags))
+		for _, v := range tags {
+			req.TagIDs = append(req.TagIDs, v.ID)
+		}
+	}
+
+	req.PublishedAt = nil
+	if !auth.FromContext(ctx).IsEditor() {
+		now := time.Now()
+		req.PublishedAt = &now
+	}
+
+	posts, err := srv.Repo.GetPage(ctx, req)
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, err
+	}
+	if len(posts) > req.First {
+		posts, result.HasNextPage = posts[:req.First], true
+	}
+
+	for i := range posts {
+		posts[i].CheckResp()
+	}
+	if len(posts) > 0 {
+		result.Posts = posts
+		result.EndCursor = dto.EncodePostCursor(posts[len(posts)-1].ID)
+	}
+	return result, nil
+}
+
+// GetTagsByPosts returns the tags of every post of postIDs at once, the
+// caller has checked the posts can be seen.
+func (srv *PostSrv) GetTagsByPosts(ctx context.Context, postIDs []uint64) (map[uint64][]dto.TagRes, error) {
+	opName := "PostService-GetTagsByPosts"
+
+	tags, err := srv.Repo.GetTagsByPosts(ctx, postIDs)
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, err
+	}
+
+	result := make(map[uint64][]dto.TagRes, len(tags))
+	for postID, v := range tags {
+		for _, tag := range v {
+			result[postID] = append(result[postID], dto.TagRes{ID: tag.ID, Label: tag.Label, ParentID: tag.ParentID})
+		}
+	}
+	return result, nil
+}
+
+// GetAuthorsByPosts returns who edited every post of postIDs at once.
+func (srv *PostSrv) GetAuthorsByPosts(ctx context.Context, postIDs []uint64) (map[uint64][]dto.PostAuthorRes, error) {
+	opName := "PostService-GetAuthorsByPosts"
+
+	result, err := srv.Repos.PostRevision.GetAuthorsByPosts(ctx, postIDs)
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, err
+	}
+	return result, nil
+}
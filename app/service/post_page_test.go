@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/auth"
+	"github.com/stretchr/testify/mock"
+)
+
+func (srv *PostServiceTestSuite) TestPostSrv_GetPage() {
+	var (
+		editorCtx = auth.WithActor(context.Background(), auth.Actor{ID: 1, Role: auth.RoleEditor})
+		posts     = func() []dto.PostRes {
+			return []dto.PostRes{{ID: 9, Title: "nine"}, {ID: 7, Title: "seven"}, {ID: 4, Title: "four"}}
+		}
+		isPublic = func(req dto.PostPageReq) bool { return req.PublishedAt != nil }
+	)
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		req           dto.PostPageReq
+		mockFunc      func()
+		wantIDs       []uint64
+		wantNext      bool
+		wantEndCursor string
+		wantErr       bool
+	}{
+		{
+			name:    "invalid cursor",
+			req:     dto.PostPageReq{After: "nope"},
+			wantErr: true,
+		},
+		{
+			name: "unknown tag",
+			req:  dto.PostPageReq{Tag: "nothing"},
+			mockFunc: func() {
+				srv.tagRepo.On("Resolve", mock.Anything, "nothing", false).Return([]models.Tag{}, nil).Once()
+			},
+			wantIDs: []uint64{},
+		},
+		{
+			name: "failed query db",
+			req:  dto.PostPageReq{},
+			mockFunc: func() {
+				srv.repo.On("GetPage", mock.Anything, mock.Anything).Return(nil, errors.New("db error")).Once()
+			},
+			wantErr: true,
+		},
+		{
+			name: "public page with next page",
+			req:  dto.PostPageReq{First: 2, After: dto.EncodePostCursor(10), Tag: "go", Descendants: true},
+			mockFunc: func() {
+				srv.tagRepo.On("Resolve", mock.Anything, "go", true).Return([]models.Tag{{ID: 3}, {ID: 5}}, nil).Once()
+				srv.repo.On("GetPage", mock.Anything, mock.MatchedBy(func(req dto.PostPageReq) bool {
+					return isPublic(req) && req.AfterID == 10 && req.First == 2 && len(req.TagIDs) == 2
+				})).Return(posts(), nil).Once()
+			},
+			wantIDs:       []uint64{9, 7},
+			wantNext:      true,
+			wantEndCursor: dto.EncodePostCursor(7),
+		},
+		{
+			name: "editor last page",
+			ctx:  editorCtx,
+			req:  dto.PostPageReq{First: 5, Status: dto.PostStatusDraft},
+			mockFunc: func() {
+				srv.repo.On("GetPage", mock.Anything, mock.MatchedBy(func(req dto.PostPageReq) bool {
+					return !isPublic(req) && req.TagIDs == nil && req.Status == dto.PostStatusDraft
+				})).Return(posts(), nil).Once()
+			},
+			wantIDs:       []uint64{9, 7, 4},
+			wantEndCursor: dto.EncodePostCursor(4),
+		},
+	}
+	for _, tt := range tests {
+		srv.T().Run(tt.name, func(t *testing.T) {
+			if tt.mockFunc != nil {
+				tt.mockFunc()
+			}
+			ctx := srv.ctx
+			if tt.ctx != nil {
+				ctx = tt.ctx
+			}
+
+			got, err := srv.service.GetPage(ctx, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PostSrv.GetPage() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			ids := []uint64{}
+			for _, v := range got.Posts {
+				ids = append(ids, v.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) || got.HasNextPage != tt.wantNext || got.EndCursor != tt.wantEndCursor {
+				t.Errorf("PostSrv.GetPage() = %v %v %q, want %v %v %q", ids, got.HasNextPage, got.EndCursor, tt.wantIDs, tt.wantNext, tt.wantEndCursor)
+			}
+		})
+	}
+}
+
+func (srv *PostServiceTestSuite) TestPostSrv_GetTagsByPosts() {
+	parentID := uint64(1)
+	srv.repo.On("GetTagsByPosts", mock.Anything, []uint64{7, 9}).Return(map[uint64][]models.Tag{
+		7: {{ID: 2, Label: "go", ParentID: &parentID}, {ID: 3, Label: "sql"}},
+	}, nil).Once()
+
+	got, err := srv.service.GetTagsByPosts(srv.ctx, []uint64{7, 9})
+	srv.NoError(err)
+	srv.Equal(map[uint64][]dto.TagRes{
+		7: {{ID: 2, Label: "go", ParentID: &parentID}, {ID: 3, Label: "sql"}},
+	}, got)
+}
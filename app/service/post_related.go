@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/auth"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+)
+
+// GetRelated returns the posts sharing the most tags with the post, the
+// public only gets them for a published post and only published ones.
+func (srv *PostSrv) GetRelated(ctx context.Context, req dto.PostRelatedReq) ([]dto.PostRelatedRes, error) {
+	opName := "PostService-GetRelated"
+
+	err := req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	post, err := srv.Repo.GetDetail(ctx, dto.PostGetReq{ID: req.ID, ColumnCustom: "id, status, publish_at"})
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data post: %v \n", opName, err)
+		return nil, err
+	}
+
+	req.IsPublishedOnly = !auth.FromContext(ctx).IsEditor()
+	if req.IsPublishedOnly && !post.IsPublished(time.Now()) {
+		return nil, helpers.ErrNotFound()
+	}
+
+	result, err := srv.Repo.GetRelated(ctx, req)
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, err
+	}
+
+	for i := range result {
+		result[i].CheckResp()
+	}
+	return result, nil
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/auth"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/stretchr/testify/mock"
+)
+
+func (srv *PostServiceTestSuite) TestPostSrv_GetRelated() {
+	var (
+		detailReq = dto.PostGetReq{ID: 101, ColumnCustom: "id, status, publish_at"}
+		published = &dto.PostRes{ID: 101, Status: dto.PostStatusPublished}
+		draft     = &dto.PostRes{ID: 101, Status: dto.PostStatusDraft}
+		related   = []dto.PostRelatedRes{{ID: 102, Title: "other post", ContentText: "some text", Score: 0.5}}
+		editorCtx = auth.WithActor(context.Background(), auth.Actor{ID: 1, Role: auth.RoleEditor})
+	)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		req      dto.PostRelatedReq
+		mockFunc func()
+		want     []dto.PostRelatedRes
+		wantErr  bool
+	}{
+		{
+			name:    "limit too big",
+			req:     dto.PostRelatedReq{ID: 101, Limit: dto.PostRelatedMaxLimit + 1},
+			wantErr: true,
+		},
+		{
+			name: "post not found",
+			req:  dto.PostRelatedReq{ID: 101},
+			mockFunc: func() {
+				srv.repo.On("GetDetail", mock.Anything, detailReq).Return(nil, helpers.ErrNotFound()).Once()
+			},
+			wantErr: true,
+		},
+		{
+			name: "draft hidden from the public",
+			req:  dto.PostRelatedReq{ID: 101},
+			mockFunc: func() {
+				srv.repo.On("GetDetail", mock.Anything, detailReq).Return(draft, nil).Once()
+			},
+			wantErr: true,
+		},
+		{
+			name: "public gets published only",
+			req:  dto.PostRelatedReq{ID: 101},
+			mockFunc: func() {
+				srv.repo.On("GetDetail", mock.Anything, detailReq).Return(published, nil).Once()
+				srv.repo.On("GetRelated", mock.Anything, dto.PostRelatedReq{ID: 101, Limit: dto.PostRelatedDefaultLimit, IsPublishedOnly: true}).
+					Return(append([]dto.PostRelatedRes{}, related...), nil).Once()
+			},
+			want: []dto.PostRelatedRes{{ID: 102, Title: "Other Post", ContentText: "some text", Excerpt: "some text", Score: 0.5}},
+		},
+		{
+			name: "editor of a draft",
+			ctx:  editorCtx,
+			req:  dto.PostRelatedReq{ID: 101, Limit: 3},
+			mockFunc: func() {
+				srv.repo.On("GetDetail", mock.Anything, detailReq).Return(draft, nil).Once()
+				srv.repo.On("GetRelated", mock.Anything, dto.PostRelatedReq{ID: 101, Limit: 3}).Return([]dto.PostRelatedRes{}, nil).Once()
+			},
+			want: []dto.PostRelatedRes{},
+		},
+	}
+	for _, tt := range tests {
+		srv.T().Run(tt.name, func(t *testing.T) {
+			if tt.mockFunc != nil {
+				tt.mockFunc()
+			}
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = srv.ctx
+			}
+
+			got, err := srv.service.GetRelated(ctx, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PostSrv.GetRelated() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PostSrv.GetRelated() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	srv.repo.AssertExpectations(srv.T())
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/textdiff"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+)
+
+func (srv *PostSrv) GetRevisions(ctx context.Context, postID uint64) ([]dto.PostRevisionRes, error) {
+	var (
+		opName = "PostService-GetRevisions"
+		err    error
+	)
+
+	_, err = srv.Repo.GetDetail(ctx, dto.PostGetReq{ID: postID, ColumnCustom: "id"})
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data post: %v \n", opName, err)
+		return nil, err
+	}
+
+	revisions, err := srv.Repos.PostRevision.GetAll(ctx, postID)
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, err
+	}
+
+	result := make([]dto.PostRevisionRes, 0, len(revisions))
+	for _, v := range revisions {
+		result = append(result, dto.NewPostRevisionRes(v))
+	}
+	return result, nil
+}
+
+func (srv *PostSrv) GetRevision(ctx context.Context, req dto.PostRevisionGetReq) (*dto.PostRevisionRes, error) {
+	var (
+		opName = "PostService-GetRevision"
+		err    error
+	)
+
+	err = req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := srv.Repos.PostRevision.GetDetail(ctx, req)
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, err
+	}
+
+	result := dto.NewPostRevisionRes(*revision)
+	return &result, nil
+}
+
+// DiffRevisions compares revision From with revision To, or with the current
+// post when To is 0.
+func (srv *PostSrv) DiffRevisions(ctx context.Context, req dto.PostRevisionDiffReq) (*dto.PostRevisionDiffRes, error) {
+	var (
+		opName = "PostService-DiffRevisions"
+		err    error
+	)
+
+	err = req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := srv.GetRevision(ctx, dto.PostRevisionGetReq{PostID: req.PostID, Revision: req.From})
+	if err != nil {
+		return nil, err
+	}
+
+	to := &dto.PostRevisionRes{}
+	if req.To > 0 {
+		to, err = srv.GetRevision(ctx, dto.PostRevisionGetReq{PostID: req.PostID, Revision: req.To})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		post, err := srv.Repo.GetDetail(ctx, dto.PostGetReq{ID: req.PostID})
+		if err != nil {
+			srv.Logger.Errorf("%s failed get data post: %v \n", opName, err)
+			return nil, err
+		}
+		to.Title, to.Content, to.Tags = post.Title, post.Content, post.Tags
+	}
+
+	result := &dto.PostRevisionDiffRes{
+		PostID:    req.PostID,
+		From:      req.From,
+		To:        req.To,
+		TitleFrom: from.Title,
+		TitleTo:   to.Title,
+		Content:   textdiff.Lines(from.Content, to.Content),
+	}
+	result.TagsAdded, result.TagsRemoved = diffTags(from.Tags, to.Tags)
+	return result, nil
+}
+
+// RestoreRevision updates the post back to the revision, the replaced state
+// is kept as a new revision like any other update.
+func (srv *PostSrv) RestoreRevision(ctx context.Context, req dto.PostRevisionGetReq) error {
+	revision, err := srv.GetRevision(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return srv.UpdateByID(ctx, dto.PostUpdateReq{
+		ID:      req.PostID,
+		Title:   revision.Title,
+		Content: revision.Content,
+		Tags:    revision.Tags,
+
+		ContentFormat: revision.ContentFormat,
+	})
+}
+
+func diffTags(from, to []string) (added, removed []string) {
+	var (
+		isFrom = map[string]bool{}
+		isTo   = map[string]bool{}
+	)
+	for _, v := range from {
+		isFrom[helpers.ToLower(v)] = true
+	}
+	for _, v := range to {
+		isTo[helpers.ToLower(v)] = true
+	}
+
+	added, removed = []string{}, []string{}
+	for _, v := range to {
+		if !isFrom[helpers.ToLower(v)] {
+			added = append(added, v)
+		}
+	}
+	for _, v := range from {
+		if !isTo[helpers.ToLower(v)] {
+			removed = append(removed, v)
+		}
+	}
+	return added, removed
+}
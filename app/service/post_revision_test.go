@@ -0,0 +1,122 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/textdiff"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/stretchr/testify/mock"
+)
+
+func (srv *PostServiceTestSuite) TestPostSrv_GetRevisions() {
+	tests := []struct {
+		name     string
+		postID   uint64
+		mockFunc func()
+		want     []dto.PostRevisionRes
+		wantErr  bool
+	}{
+		{
+			name:   "post not found",
+			postID: 101,
+			mockFunc: func() {
+				srv.repo.On("GetDetail", mock.Anything, dto.PostGetReq{ID: 101, ColumnCustom: "id"}).Return(nil, helpers.ErrNotFound()).Once()
+			},
+			wantErr: true,
+		},
+		{
+			name:   "Success",
+			postID: 101,
+			mockFunc: func() {
+				srv.repo.On("GetDetail", mock.Anything, dto.PostGetReq{ID: 101, ColumnCustom: "id"}).Return(&dto.PostRes{ID: 101}, nil).Once()
+				srv.revRepo.On("GetAll", mock.Anything, uint64(101)).Return([]models.PostRevision{
+					{ID: 2, PostID: 101, Revision: 2, Title: "title 2", Tags: models.StringList{"go"}},
+					{ID: 1, PostID: 101, Revision: 1, Title: "title 1"},
+				}, nil).Once()
+			},
+			want: []dto.PostRevisionRes{
+				{PostID: 101, Revision: 2, Title: "title 2", Tags: []string{"go"}},
+				{PostID: 101, Revision: 1, Title: "title 1", Tags: []string{}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		srv.T().Run(tt.name, func(t *testing.T) {
+			if tt.mockFunc != nil {
+				tt.mockFunc()
+			}
+
+			got, err := srv.service.GetRevisions(srv.ctx, tt.postID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PostSrv.GetRevisions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PostSrv.GetRevisions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func (srv *PostServiceTestSuite) TestPostSrv_DiffRevisions() {
+	tests := []struct {
+		name     string
+		req      dto.PostRevisionDiffReq
+		mockFunc func()
+		want     *dto.PostRevisionDiffRes
+		wantErr  bool
+	}{
+		{
+			name:    "invalid request",
+			req:     dto.PostRevisionDiffReq{PostID: 101},
+			wantErr: true,
+		},
+		{
+			name: "diff with current post",
+			req:  dto.PostRevisionDiffReq{PostID: 101, From: 1},
+			mockFunc: func() {
+				srv.revRepo.On("GetDetail", mock.Anything, dto.PostRevisionGetReq{PostID: 101, Revision: 1}).Return(&models.PostRevision{
+					PostID:   101,
+					Revision: 1,
+					Title:    "old title",
+					Content:  "a\nb",
+					Tags:     models.StringList{"Go", "Sql"},
+				}, nil).Once()
+				srv.repo.On("GetDetail", mock.Anything, dto.PostGetReq{ID: 101}).Return(&dto.PostRes{
+					ID:      101,
+					Title:   "new title",
+					Content: "a\nc",
+					Tags:    []string{"Go", "Redis"},
+				}, nil).Once()
+			},
+			want: &dto.PostRevisionDiffRes{
+				PostID:      101,
+				From:        1,
+				TitleFrom:   "old title",
+				TitleTo:     "new title",
+				Content:     textdiff.Lines("a\nb", "a\nc"),
+				TagsAdded:   []string{"Redis"},
+				TagsRemoved: []string{"Sql"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		srv.T().Run(tt.name, func(t *testing.T) {
+			if tt.mockFunc != nil {
+				tt.mockFunc()
+			}
+
+			got, err := srv.service.DiffRevisions(srv.ctx, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PostSrv.DiffRevisions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PostSrv.DiffRevisions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
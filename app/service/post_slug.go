@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+)
+
+// GetBySlug returns the post having or having had slug. The caller tells a
+// historical slug apart by comparing it with the Slug of the result.
+func (srv *PostSrv) GetBySlug(ctx context.Context, slug string) (*dto.PostRes, error) {
+	opName := "PostService-GetBySlug"
+
+	slug = strings.TrimSpace(slug)
+	if slug == "" {
+		return nil, helpers.ErrIsRequired("slug", "slug")
+	}
+
+	postID, err := srv.Repo.FindIDBySlug(ctx, helpers.ToLower(slug))
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, err
+	}
+
+	return srv.GetDetail(ctx, dto.PostGetReq{ID: postID})
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/stretchr/testify/mock"
+)
+
+func (srv *PostServiceTestSuite) TestPostSrv_GetBySlug() {
+	resp := &dto.PostRes{
+		ID:     101,
+		Title:  "Title Test",
+		Slug:   "title-test",
+		Tags:   []string{},
+		Status: dto.PostStatusPublished,
+	}
+
+	tests := []struct {
+		name     string
+		slug     string
+		mockFunc func()
+		want     *dto.PostRes
+		wantErr  bool
+	}{
+		{
+			name:    "slug required",
+			slug:    " ",
+			wantErr: true,
+		},
+		{
+			name: "not found",
+			slug: "unknown",
+			mockFunc: func() {
+				srv.repo.On("FindIDBySlug", mock.Anything, "unknown").Return(uint64(0), helpers.ErrNotFound()).Once()
+			},
+			wantErr: true,
+		},
+		{
+			name: "historical slug",
+			slug: "Old-Title",
+			mockFunc: func() {
+				srv.repo.On("FindIDBySlug", mock.Anything, "old-title").Return(uint64(101), nil).Once()
+				srv.repo.On("GetDetail", mock.Anything, dto.PostGetReq{ID: 101}).Return(resp, nil).Once()
+			},
+			want: resp,
+		},
+	}
+	for _, tt := range tests {
+		srv.T().Run(tt.name, func(t *testing.T) {
+			if tt.mockFunc != nil {
+				tt.mockFunc()
+			}
+
+			got, err := srv.service.GetBySlug(srv.ctx, tt.slug)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PostSrv.GetBySlug() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PostSrv.GetBySlug() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/repository"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/auth"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+)
+
+// UpdateStatus moves the post to req.Status when the workflow allows it.
+func (srv *PostSrv) UpdateStatus(ctx context.Context, req dto.PostStatusReq) error {
+	var (
+		opName = "PostService-UpdateStatus"
+		err    error
+	)
+
+	err = req.Validate()
+	if err != nil {
+		return err
+	}
+
+	switch req.Status {
+	case dto.PostStatusPublished:
+		now := time.Now()
+		req.PublishAt = &now
+	case dto.PostStatusScheduled:
+	default:
+		req.PublishAt = nil
+	}
+
+	// lock the post so two concurrent changes can not both pass the check
+	err = srv.Repos.UnitOfWork(ctx, func(ctx context.Context, repos *repository.Repositories) error {
+		current, err := repos.Post.GetDetail(ctx, dto.PostGetReq{ID: req.ID, ColumnCustom: "id, status", IsLock: true})
+		if err != nil {
+			return err
+		}
+
+		if !dto.CanTransitPostStatus(current.Status, req.Status) {
+			return dto.ErrPostStatusTransition(current.Status, req.Status)
+		}
+
+		return repos.Post.UpdateStatus(ctx, req)
+	})
+	if err != nil {
+		srv.Logger.Errorf("%s failed update status: %v \n", opName, err)
+		return err
+	}
+
+	return nil
+}
+
+// PublishDue publishes the scheduled posts that are due and returns how many
+// it published, run periodically by the scheduler.
+func (srv *PostSrv) PublishDue(ctx context.Context) (int, error) {
+	opName := "PostService-PublishDue"
+
+	ids, err := srv.Repo.PublishDue(ctx, time.Now())
+	if err != nil {
+		srv.Logger.Errorf("%s failed publish posts: %v \n", opName, err)
+		return 0, err
+	}
+
+	if len(ids) > 0 {
+		srv.Logger.Infof("%s published posts %v \n", opName, ids)
+	}
+	return len(ids), nil
+}
+
+// checkCanCreateWithStatus lets only editors create a post past draft, as
+// only they may move a post along the workflow.
+func checkCanCreateWithStatus(ctx context.Context, status string) error {
+	if status != dto.PostStatusDraft && !auth.FromContext(ctx).IsEditor() {
+		return helpers.ErrCannotHaveAccessResources()
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/stretchr/testify/mock"
+)
+
+func (srv *PostServiceTestSuite) TestPostSrv_UpdateStatus() {
+	var (
+		getReq    = dto.PostGetReq{ID: 101, ColumnCustom: "id, status", IsLock: true}
+		publishAt = time.Now().Add(time.Hour)
+	)
+
+	tests := []struct {
+		name     string
+		req      dto.PostStatusReq
+		mockFunc func()
+		wantErr  bool
+	}{
+		{
+			name:    "invalid status",
+			req:     dto.PostStatusReq{ID: 101, Status: "deleted"},
+			wantErr: true,
+		},
+		{
+			name:    "scheduled without publish_at",
+			req:     dto.PostStatusReq{ID: 101, Status: dto.PostStatusScheduled},
+			wantErr: true,
+		},
+		{
+			name: "post not found",
+			req:  dto.PostStatusReq{ID: 101, Status: dto.PostStatusPublished},
+			mockFunc: func() {
+				srv.repo.On("GetDetail", mock.Anything, getReq).Return(nil, helpers.ErrNotFound()).Once()
+			},
+			wantErr: true,
+		},
+		{
+			name: "transition not allowed",
+			req:  dto.PostStatusReq{ID: 101, Status: dto.PostStatusPublished},
+			mockFunc: func() {
+				srv.repo.On("GetDetail", mock.Anything, getReq).Return(&dto.PostRes{ID: 101, Status: dto.PostStatusArchived}, nil).Once()
+			},
+			wantErr: true,
+		},
+		{
+			name: "Success",
+			req:  dto.PostStatusReq{ID: 101, Status: dto.PostStatusScheduled, PublishAt: &publishAt},
+			mockFunc: func() {
+				srv.repo.On("GetDetail", mock.Anything, getReq).Return(&dto.PostRes{ID: 101, Status: dto.PostStatusInReview}, nil).Once()
+				srv.repo.On("UpdateStatus", mock.Anything, dto.PostStatusReq{ID: 101, Status: dto.PostStatusScheduled, PublishAt: &publishAt}).Return(nil).Once()
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		srv.T().Run(tt.name, func(t *testing.T) {
+			if tt.mockFunc != nil {
+				tt.mockFunc()
+			}
+
+			if err := srv.service.UpdateStatus(srv.ctx, tt.req); (err != nil) != tt.wantErr {
+				t.Errorf("PostSrv.UpdateStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func (srv *PostServiceTestSuite) TestPostSrv_PublishDue() {
+	srv.repo.On("PublishDue", mock.Anything, mock.AnythingOfType("time.Time")).Return([]uint64{1, 2}, nil).Once()
+
+	got, err := srv.service.PublishDue(srv.ctx)
+	if err != nil || got != 2 {
+		srv.T().Errorf("PostSrv.PublishDue() = %v, %v, want 2, nil", got, err)
+	}
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/repository"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/auth"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/event"
+	"github.com/sirupsen/logrus"
+)
+
+// postStreamBuffer is how many events a stream may lag behind before it is
+// dropped and has to resume.
+const postStreamBuffer = 64
+
+// PostStreamService streams the post events to clients of every instance.
+// The relay notifies the ID of each post event through Postgres, the
+// listener of each instance loads it and broadcasts it to the streams of
+// that instance.
+type PostStreamService interface {
+	Notify(ctx context.Context, e event.Event) error
+	Receive(ctx context.Context, payload string)
+	Reset()
+	Subscribe(ctx context.Context, req dto.PostStreamReq) (<-chan dto.PostStreamEvent, error)
+}
+
+type PostStreamSrv struct {
+	Repos  *repository.Repositories
+	Repo   repository.OutboxRepository
+	Hub    *event.Hub
+	Cfg    *configs.Configs
+	Logger *logrus.Logger
+}
+
+// NewPostStreamService creates a new instance of PostStreamService.
+func NewPostStreamService(
+	repos *repository.Repositories,
+	cfg *configs.Configs,
+	logger *logrus.Logger,
+) PostStreamService {
+	return &PostStreamSrv{
+		Repos:  repos,
+		Repo:   repos.Outbox,
+		Hub:    event.NewHub(postStreamBuffer),
+		Cfg:    cfg,
+		Logger: logger,
+	}
+}
+
+// Notify sends the ID of a post event to the listeners. It is a handler of
+// the in process publisher, so the notification goes with the commit of the
+// relay.
+func (srv *PostStreamSrv) Notify(ctx context.Context, e event.Event) error {
+	opName := "PostStreamService-Notify"
+	if e.AggregateType != dto.AggregatePost {
+		return nil
+	}
+
+	err := srv.Repo.Notify(ctx, dto.PostStreamChannel, e.ID)
+	if err != nil {
+		srv.Logger.Errorf("%s failed notify event %d: %v \n", opName, e.ID, err)
+		return err
+	}
+
+	return nil
+}
+
+// Receive broadcasts the event of a notification to the streams of this
+// instance, loaded once committed so it has its published sequence.
+func (srv *PostStreamSrv) Receive(ctx context.Context, payload string) {
+	opName := "PostStreamService-Receive"
+
+	id, err := strconv.ParseUint(payload, 10, 64)
+	if err != nil {
+		srv.Logger.Warnf("%s invalid payload %q \n", opName, payload)
+		return
+	}
+
+	events, err := srv.Repo.GetByIDs(ctx, []uint64{id})
+	if err != nil {
+		srv.Logger.Errorf("%s failed get event %d: %v \n", opName, id, err)
+		return
+	}
+	for _, v := range events {
+		srv.Hub.Broadcast(newEvent(v))
+	}
+}
+
+// Reset ends every stream, their clients resume from their last event. The
+// listener calls it whenever it starts listening, notifications sent while
+// it was not are lost.
+func (srv *PostStreamSrv) Reset() {
+	srv.Hub.CloseAll()
+}
+
+// Subscribe returns the stream of the post events matching req, first those
+// published after req.LastEventID, then the new ones as they come, until ctx is done.
+// The stream is closed early when it lags behind or its events cannot be
+// loaded, the client is to resume. Readers that are not editors only see
+// published posts, a post leaving or entering the published ones is deleted
+// or created for them.
+func (srv *PostStreamSrv) Subscribe(ctx context.Context, req dto.PostStreamReq) (<-chan dto.PostStreamEvent, error) {
+	opName := "PostStreamService-Subscribe"
+
+	err := req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	var labels map[string]bool
+	if req.Tag != "" {
+		tags, err := srv.Repos.Tag.Resolve(ctx, req.Tag, req.Descendants)
+		if err != nil {
+			srv.Logger.Errorf("%s failed get data tags: %v \n", opName, err)
+			return nil, err
+		}
+
+		// an unknown tag matches nothing, the stream stays open for when
+		// it is created
+		labels = make(map[string]bool, len(tags))
+		for _, v := range tags {
+			labels[v.Label] = true
+		}
+	}
+
+	var (
+		isEditor     = auth.FromContext(ctx).IsEditor()
+		live, cancel = srv.Hub.Subscribe()
+		result       = make(chan dto.PostStreamEvent, postStreamBuffer)
+	)
+	send := func(e event.Event) bool {
+		res, ok := newPostStreamEvent(e, time.Now(), isEditor, labels)
+		if !ok {
+			return true
+		}
+		select {
+		case result <- res:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	go func() {
+		defer close(result)
+		defer cancel()
+
+		// live events already replayed are skipped
+		resumedUntil := req.LastEventID
+		for isDone, total := req.LastEventID == 0, 0; !isDone; {
+			missed, err := srv.Repo.GetPublished(ctx, dto.AggregatePost, resumedUntil, postStreamBuffer)
+			if err != nil {
+				srv.Logger.Errorf("%s failed get missed events: %v \n", opName, err)
+				return
+			}
+			for _, v := range missed {
+				e := newEvent(v)
+				if !send(e) {
+					return
+				}
+				resumedUntil = e.Seq
+			}
+
+			total += len(missed)
+			isDone = len(missed) < postStreamBuffer || total >= dto.PostStreamMaxResume
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-live:
+				if !ok {
+					return
+				}
+				if e.Seq <= resumedUntil {
+					continue
+				}
+				if !send(e) {
+					return
+				}
+			}
+		}
+	}()
+
+	return result, nil
+}
+
+// newPostStreamEvent turns e into the event of a stream, it tells if the
+// stream gets it at all.
+func newPostStreamEvent(e event.Event, now time.Time, isEditor bool, labels map[string]bool) (dto.PostStreamEvent, bool) {
+	payload := dto.PostEventPayload{}
+	if err := json.Unmarshal(e.Payload, &payload); err != nil {
+		return dto.PostStreamEvent{}, false
+	}
+	// the snapshots hold the source as written, unsanitized for html posts,
+	// they are sent as the REST API represents posts
+	for _, v := range []*dto.PostRes{payload.Before, payload.After} {
+		if v != nil {
+			v.CheckResp()
+			v.Represent("")
+		}
+	}
+
+	result := dto.PostStreamEvent{
+		ID:         e.Seq,
+		Type:       e.Type,
+		PostID:     e.AggregateID,
+		OccurredAt: e.OccurredAt,
+		Before:     payload.Before,
+		After:      payload.After,
+	}
+	if !isEditor {
+		if result.Before != nil && !result.Before.IsPublished(now) {
+			result.Before = nil
+		}
+		if result.After != nil && !result.After.IsPublished(now) {
+			result.After = nil
+		}
+
+		switch {
+		case result.Before == nil && result.After == nil:
+			return result, false
+		case result.Before == nil:
+			result.Type = dto.PostEventCreated
+		case result.After == nil:
+			result.Type = dto.PostEventDeleted
+		}
+	}
+
+	if labels != nil {
+		isMatch := false
+		for _, v := range []*dto.PostRes{result.Before, result.After} {
+			if v != nil && hasAnyTag(v.Tags, labels) {
+				isMatch = true
+			}
+		}
+		if !isMatch {
+			return result, false
+		}
+	}
+
+	return result, true
+}
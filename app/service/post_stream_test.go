@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/app/repository"
+	"github.com/adamnasrudin03/go-asset-findr/app/repository/mocks"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/auth"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/driver"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/render"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type PostStreamServiceTestSuite struct {
+	suite.Suite
+	repo    *mocks.OutboxRepository
+	tagRepo *mocks.TagRepository
+	cfg     *configs.Configs
+	ctx     context.Context
+	cancel  context.CancelFunc
+	service *PostStreamSrv
+}
+
+func (srv *PostStreamServiceTestSuite) SetupTest() {
+	srv.cfg = configs.GetInstance()
+	srv.repo = &mocks.OutboxRepository{}
+	srv.tagRepo = &mocks.TagRepository{}
+	srv.ctx, srv.cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	srv.service = NewPostStreamService(&repository.Repositories{Outbox: srv.repo, Tag: srv.tagRepo}, srv.cfg, driver.Logger(srv.cfg)).(*PostStreamSrv)
+}
+
+func (srv *PostStreamServiceTestSuite) TearDownTest() {
+	srv.cancel()
+}
+
+func TestPostStreamService(t *testing.T) {
+	suite.Run(t, new(PostStreamServiceTestSuite))
+}
+
+func postOutboxEvent(id, seq uint64, eventType string, before, after *dto.PostRes) models.OutboxEvent {
+	payload, _ := json.Marshal(dto.PostEventPayload{Before: before, After: after})
+	return models.OutboxEvent{ID: id, AggregateType: dto.AggregatePost, AggregateID: 1, EventType: eventType, Payload: models.JSON(payload), PublishedSeq: &seq}
+}
+
+// waitSubscribed waits until the streams have subscribed to the hub.
+func (srv *PostStreamServiceTestSuite) waitSubscribed(total int) {
+	srv.Require().Eventually(func() bool { return srv.service.Hub.Len() == total }, time.Second, time.Millisecond)
+}
+
+func (srv *PostStreamServiceTestSuite) next(events <-chan dto.PostStreamEvent) dto.PostStreamEvent {
+	select {
+	case e := <-events:
+		return e
+	case <-time.After(time.Second):
+		srv.FailNow("no event")
+		return dto.PostStreamEvent{}
+	}
+}
+
+func (srv *PostStreamServiceTestSuite) TestPostStreamSrv_Subscribe_Resume() {
+	var (
+		draft     = &dto.PostRes{ID: 1, Title: "draft", Status: dto.PostStatusDraft, Tags: []string{"go"}}
+		published = &dto.PostRes{ID: 1, Title: "published", Status: dto.PostStatusPublished, Tags: []string{"go"}}
+		other     = &dto.PostRes{ID: 1, Title: "other", Status: dto.PostStatusPublished, Tags: []string{"sql"}}
+	)
+	srv.tagRepo.On("Resolve", mock.Anything, "go", false).Return([]models.Tag{{ID: 1, Label: "go"}}, nil).Once()
+	srv.repo.On("GetPublished", mock.Anything, dto.AggregatePost, uint64(10), postStreamBuffer).Return([]models.OutboxEvent{
+		postOutboxEvent(4, 11, dto.PostEventCreated, nil, draft),
+		postOutboxEvent(6, 12, dto.PostEventUpdated, draft, published),
+		postOutboxEvent(5, 13, dto.PostEventCreated, nil, other),
+	}, nil).Once()
+
+	events, err := srv.service.Subscribe(srv.ctx, dto.PostStreamReq{Tag: " GO ", LastEventID: 10})
+	srv.Require().NoError(err)
+
+	// the draft is hidden from the public, its publication creates it, the
+	// id is the published sequence rather than the outbox ID
+	e := srv.next(events)
+	srv.Equal(uint64(12), e.ID)
+	srv.Equal(dto.PostEventCreated, e.Type)
+	srv.Nil(e.Before)
+	srv.Equal("Published", e.After.Title)
+
+	// live events replayed already are skipped, one published later with a
+	// smaller outbox ID is not
+	srv.waitSubscribed(1)
+	srv.service.Hub.Broadcast(newEvent(postOutboxEvent(6, 12, dto.PostEventUpdated, draft, published)))
+	srv.service.Hub.Broadcast(newEvent(postOutboxEvent(3, 14, dto.PostEventDeleted, published, nil)))
+	e = srv.next(events)
+	srv.Equal(uint64(14), e.ID)
+	srv.Equal(dto.PostEventDeleted, e.Type)
+
+	srv.cancel()
+	for range events {
+	}
+	srv.repo.AssertExpectations(srv.T())
+	srv.tagRepo.AssertExpectations(srv.T())
+}
+
+func (srv *PostStreamServiceTestSuite) TestPostStreamSrv_Subscribe_Editor() {
+	var (
+		ctx   = auth.WithActor(srv.ctx, auth.Actor{ID: 1, Role: auth.RoleEditor})
+		draft = &dto.PostRes{ID: 1, Title: "draft", Status: dto.PostStatusDraft}
+	)
+
+	events, err := srv.service.Subscribe(ctx, dto.PostStreamReq{})
+	srv.Require().NoError(err)
+	srv.waitSubscribed(1)
+
+	srv.service.Receive(ctx, "not a number")
+	srv.repo.On("GetByIDs", mock.Anything, []uint64{5}).Return([]models.OutboxEvent{postOutboxEvent(5, 9, dto.PostEventCreated, nil, draft)}, nil).Once()
+	srv.service.Receive(ctx, "5")
+
+	e := srv.next(events)
+	srv.Equal(uint64(9), e.ID)
+	srv.Equal("Draft", e.After.Title)
+
+	// a listener starting again ends the streams, their clients resume
+	srv.service.Reset()
+	_, ok := <-events
+	srv.False(ok)
+	srv.repo.AssertExpectations(srv.T())
+}
+
+func (srv *PostStreamServiceTestSuite) TestPostStreamSrv_Subscribe_Sanitized() {
+	post := &dto.PostRes{
+		ID:            1,
+		Title:         "html post",
+		Content:       `<p onclick="steal()">hello</p><script>alert(1)</script>`,
+		ContentFormat: render.FormatHTML,
+		Status:        dto.PostStatusPublished,
+		Tags:          []string{"go"},
+	}
+
+	events, err := srv.service.Subscribe(srv.ctx, dto.PostStreamReq{})
+	srv.Require().NoError(err)
+	srv.waitSubscribed(1)
+	srv.service.Hub.Broadcast(newEvent(postOutboxEvent(5, 9, dto.PostEventUpdated, post, post)))
+
+	e := srv.next(events)
+	for _, v := range []*dto.PostRes{e.Before, e.After} {
+		srv.Require().NotNil(v)
+		srv.NotContains(v.Content, "<script")
+		srv.NotContains(v.Content, "onclick")
+		srv.Contains(v.Content, "hello")
+		srv.Equal("Html Post", v.Title)
+		srv.NotEmpty(v.Excerpt)
+		srv.Empty(v.ContentHTML)
+	}
+}
+
+func (srv *PostStreamServiceTestSuite) TestPostStreamSrv_Subscribe_Failed() {
+	_, err := srv.service.Subscribe(srv.ctx, dto.PostStreamReq{Descendants: true})
+	srv.Error(err)
+
+	srv.tagRepo.On("Resolve", mock.Anything, "go", true).Return(nil, helpers.ErrDB()).Once()
+	_, err = srv.service.Subscribe(srv.ctx, dto.PostStreamReq{Tag: "go", Descendants: true})
+	srv.Error(err)
+
+	srv.repo.On("GetPublished", mock.Anything, dto.AggregatePost, uint64(3), postStreamBuffer).Return(nil, helpers.ErrDB()).Once()
+	events, err := srv.service.Subscribe(srv.ctx, dto.PostStreamReq{LastEventID: 3})
+	srv.Require().NoError(err)
+	_, ok := <-events
+	srv.False(ok)
+	srv.tagRepo.AssertExpectations(srv.T())
+	srv.repo.AssertExpectations(srv.T())
+}
+
+func (srv *PostStreamServiceTestSuite) TestPostStreamSrv_Notify() {
+	srv.repo.On("Notify", mock.Anything, dto.PostStreamChannel, uint64(7)).Return(nil).Once()
+
+	srv.NoError(srv.service.Notify(srv.ctx, newEvent(models.OutboxEvent{ID: 7, AggregateType: dto.AggregatePost})))
+	srv.NoError(srv.service.Notify(srv.ctx, newEvent(models.OutboxEvent{ID: 8, AggregateType: dto.AggregateTag})))
+	srv.repo.AssertExpectations(srv.T())
+}
@@ -5,11 +5,16 @@ import (
 	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/adamnasrudin03/go-asset-findr/app/configs"
 	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/app/repository"
 	"github.com/adamnasrudin03/go-asset-findr/app/repository/mocks"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/auth"
 	"github.com/adamnasrudin03/go-asset-findr/pkg/driver"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/render"
 	"github.com/adamnasrudin03/go-template/pkg/helpers"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -18,6 +23,8 @@ import (
 type PostServiceTestSuite struct {
 	suite.Suite
 	repo    *mocks.PostRepository
+	revRepo *mocks.PostRevisionRepository
+	tagRepo *mocks.TagRepository
 	ctx     context.Context
 	service PostService
 }
@@ -29,8 +36,10 @@ func (srv *PostServiceTestSuite) SetupTest() {
 	)
 
 	srv.repo = &mocks.PostRepository{}
+	srv.revRepo = &mocks.PostRevisionRepository{}
+	srv.tagRepo = &mocks.TagRepository{}
 	srv.ctx = context.Background()
-	srv.service = NewPostService(srv.repo, cfg, logger)
+	srv.service = NewPostService(&repository.Repositories{Post: srv.repo, PostRevision: srv.revRepo, Tag: srv.tagRepo}, cfg, logger)
 }
 
 func TestPostService(t *testing.T) {
@@ -38,15 +47,27 @@ func TestPostService(t *testing.T) {
 }
 
 func (srv *PostServiceTestSuite) TestPostSrv_GetDetail() {
-	resp := &dto.PostRes{
-		ID:      101,
-		Title:   "Title Test",
-		Content: "test content",
-		Tags:    []string{"tag1", "tag2"},
-	}
+	var (
+		resp = &dto.PostRes{
+			ID:      101,
+			Title:   "Title Test",
+			Content: "test content",
+			Tags:    []string{"tag1", "tag2"},
+			Status:  dto.PostStatusPublished,
+		}
+		draft = &dto.PostRes{
+			ID:      102,
+			Title:   "Title Draft",
+			Content: "draft content",
+			Tags:    []string{},
+			Status:  dto.PostStatusDraft,
+		}
+		editorCtx = auth.WithActor(context.Background(), auth.Actor{ID: 1, Role: auth.RoleEditor})
+	)
 
 	tests := []struct {
 		name     string
+		ctx      context.Context
 		req      dto.PostGetReq
 		mockFunc func(input dto.PostGetReq)
 		want     *dto.PostRes
@@ -84,6 +105,29 @@ func (srv *PostServiceTestSuite) TestPostSrv_GetDetail() {
 			want:    resp,
 			wantErr: false,
 		},
+		{
+			name: "draft hidden from public",
+			req: dto.PostGetReq{
+				ID: 102,
+			},
+			mockFunc: func(input dto.PostGetReq) {
+				srv.repo.On("GetDetail", mock.Anything, input).Return(draft, nil).Once()
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "draft visible to editor",
+			ctx:  editorCtx,
+			req: dto.PostGetReq{
+				ID: 102,
+			},
+			mockFunc: func(input dto.PostGetReq) {
+				srv.repo.On("GetDetail", mock.Anything, input).Return(draft, nil).Once()
+			},
+			want:    draft,
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -91,7 +135,11 @@ func (srv *PostServiceTestSuite) TestPostSrv_GetDetail() {
 			if tt.mockFunc != nil {
 				tt.mockFunc(tt.req)
 			}
-			got, err := srv.service.GetDetail(srv.ctx, tt.req)
+			ctx := srv.ctx
+			if tt.ctx != nil {
+				ctx = tt.ctx
+			}
+			got, err := srv.service.GetDetail(ctx, tt.req)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("PostSrv.GetDetail() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -104,27 +152,73 @@ func (srv *PostServiceTestSuite) TestPostSrv_GetDetail() {
 }
 
 func (srv *PostServiceTestSuite) TestPostSrv_GetList() {
-	resp := []dto.PostRes{
-		{
-			ID:      101,
-			Title:   "Test Title",
-			Content: "test content",
-			Tags:    []string{"tag1", "tag2"},
-		},
-		{
-			ID:      102,
-			Title:   "Test Title 102",
-			Content: "test content 102",
-			Tags:    []string{},
-		},
-	}
+	var (
+		past = time.Now().Add(-time.Minute)
+		resp = []dto.PostRes{
+			{
+				ID:          101,
+				Title:       "Test Title",
+				Content:     "test content",
+				Excerpt:     "test content",
+				ReadingTime: 1,
+				Tags:        []string{"tag1", "tag2"},
+				Status:      dto.PostStatusPublished,
+			},
+			{
+				ID:          102,
+				Title:       "Test Title 102",
+				Content:     "test content 102",
+				Excerpt:     "test content 102",
+				ReadingTime: 1,
+				Tags:        []string{},
+				Status:      dto.PostStatusScheduled,
+				PublishAt:   &past,
+			},
+		}
+		draft = dto.PostRes{
+			ID:          103,
+			Title:       "Test Title 103",
+			Content:     "test content 103",
+			Excerpt:     "test content 103",
+			ReadingTime: 1,
+			Tags:        []string{},
+			Status:      dto.PostStatusDraft,
+		}
+		editorCtx = auth.WithActor(context.Background(), auth.Actor{ID: 1, Role: auth.RoleEditor})
+	)
 
 	tests := []struct {
 		name     string
+		ctx      context.Context
+		req      dto.PostListReq
 		mockFunc func()
 		want     []dto.PostRes
 		wantErr  bool
 	}{
+		{
+			name:    "descendants without tag",
+			req:     dto.PostListReq{Descendants: true},
+			wantErr: true,
+		},
+		{
+			name: "unknown tag",
+			req:  dto.PostListReq{Tag: "Nope"},
+			mockFunc: func() {
+				srv.tagRepo.On("Resolve", mock.Anything, "nope", false).Return([]models.Tag{}, nil).Once()
+			},
+			want:    []dto.PostRes{},
+			wantErr: false,
+		},
+		{
+			name: "filter by tag with descendants",
+			req:  dto.PostListReq{Tag: "Parent", Descendants: true},
+			mockFunc: func() {
+				srv.tagRepo.On("Resolve", mock.Anything, "parent", true).Return([]models.Tag{{ID: 1, Label: "parent"}, {ID: 2, Label: "tag2"}}, nil).Once()
+				srv.repo.On("GetAll", mock.Anything).Return(resp, nil).Once()
+			},
+			want:    resp[:1],
+			wantErr: false,
+		},
 		{
 			name: "error query db",
 			mockFunc: func() {
@@ -149,13 +243,34 @@ func (srv *PostServiceTestSuite) TestPostSrv_GetList() {
 			want:    resp,
 			wantErr: false,
 		},
+		{
+			name: "draft hidden from public",
+			mockFunc: func() {
+				srv.repo.On("GetAll", mock.Anything).Return([]dto.PostRes{resp[0], draft}, nil).Once()
+			},
+			want:    resp[:1],
+			wantErr: false,
+		},
+		{
+			name: "draft visible to editor",
+			ctx:  editorCtx,
+			mockFunc: func() {
+				srv.repo.On("GetAll", mock.Anything).Return([]dto.PostRes{resp[0], draft}, nil).Once()
+			},
+			want:    []dto.PostRes{resp[0], draft},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		srv.T().Run(tt.name, func(t *testing.T) {
 			if tt.mockFunc != nil {
 				tt.mockFunc()
 			}
-			got, err := srv.service.GetList(srv.ctx)
+			ctx := srv.ctx
+			if tt.ctx != nil {
+				ctx = tt.ctx
+			}
+			got, err := srv.service.GetList(ctx, tt.req)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("PostSrv.GetList() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -175,9 +290,11 @@ func (srv *PostServiceTestSuite) TestPostSrv_Create() {
 		Tags:    []string{"tags1", "tags2", "tags1"},
 	}
 	resp.CheckResp()
+	editorCtx := auth.WithActor(context.Background(), auth.Actor{ID: 1, Role: auth.RoleEditor})
 
 	tests := []struct {
 		name     string
+		ctx      context.Context
 		req      dto.PostCreateReq
 		mockFunc func(input dto.PostCreateReq)
 		want     *dto.PostRes
@@ -223,6 +340,32 @@ func (srv *PostServiceTestSuite) TestPostSrv_Create() {
 			want:    resp,
 			wantErr: false,
 		},
+		{
+			name: "published by a non editor",
+			req: dto.PostCreateReq{
+				Title:   "title 1",
+				Content: "content 2",
+				Status:  dto.PostStatusPublished,
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "published by an editor",
+			ctx:  editorCtx,
+			req: dto.PostCreateReq{
+				Title:   "title 1",
+				Content: "content 2",
+				Tags:    []string{"tags1", "tags2", "tags1"},
+				Status:  dto.PostStatusPublished,
+			},
+			mockFunc: func(input dto.PostCreateReq) {
+				input.Validate()
+				srv.repo.On("Create", mock.Anything, input).Return(resp, nil).Once()
+			},
+			want:    resp,
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -230,8 +373,12 @@ func (srv *PostServiceTestSuite) TestPostSrv_Create() {
 			if tt.mockFunc != nil {
 				tt.mockFunc(tt.req)
 			}
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = srv.ctx
+			}
 
-			got, err := srv.service.Create(srv.ctx, tt.req)
+			got, err := srv.service.Create(ctx, tt.req)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("PostSrv.Create() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -311,7 +458,38 @@ func (srv *PostServiceTestSuite) TestPostSrv_UpdateByID() {
 			},
 			mockFunc: func(input dto.PostUpdateReq) {
 				input.Validate()
-				srv.repo.On("UpdateByID", mock.Anything, input).Return(helpers.ErrNotFound()).Once()
+				srv.repo.On("GetDetail", mock.Anything, dto.PostGetReq{ID: input.ID, IsLock: true}).Return(nil, helpers.ErrNotFound()).Once()
+			},
+			wantErr: true,
+		},
+		{
+			name: "err create revision",
+			req: dto.PostUpdateReq{
+				ID:      101,
+				Title:   "title test 101",
+				Content: "content test 101",
+				Tags:    []string{"tags1", "tags2"},
+			},
+			mockFunc: func(input dto.PostUpdateReq) {
+				input.Validate()
+				srv.repo.On("GetDetail", mock.Anything, dto.PostGetReq{ID: input.ID, IsLock: true}).Return(&dto.PostRes{ID: input.ID}, nil).Once()
+				srv.revRepo.On("Create", mock.Anything, mock.Anything).Return(helpers.ErrCreatedDB()).Once()
+			},
+			wantErr: true,
+		},
+		{
+			name: "err update",
+			req: dto.PostUpdateReq{
+				ID:      101,
+				Title:   "title test 101",
+				Content: "content test 101",
+				Tags:    []string{"tags1", "tags2"},
+			},
+			mockFunc: func(input dto.PostUpdateReq) {
+				input.Validate()
+				srv.repo.On("GetDetail", mock.Anything, dto.PostGetReq{ID: input.ID, IsLock: true}).Return(&dto.PostRes{ID: input.ID}, nil).Once()
+				srv.revRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+				srv.repo.On("UpdateByID", mock.Anything, input).Return(helpers.ErrUpdatedDB()).Once()
 			},
 			wantErr: true,
 		},
@@ -325,6 +503,18 @@ func (srv *PostServiceTestSuite) TestPostSrv_UpdateByID() {
 			},
 			mockFunc: func(input dto.PostUpdateReq) {
 				input.Validate()
+				srv.repo.On("GetDetail", mock.Anything, dto.PostGetReq{ID: input.ID, IsLock: true}).Return(&dto.PostRes{
+					ID:      input.ID,
+					Title:   "old title",
+					Content: "old content",
+					Tags:    []string{"Tags1"},
+				}, nil).Once()
+				srv.revRepo.On("Create", mock.Anything, &models.PostRevision{
+					PostID:  input.ID,
+					Title:   "old title",
+					Content: "old content",
+					Tags:    models.StringList{"Tags1"},
+				}).Return(nil).Once()
 				srv.repo.On("UpdateByID", mock.Anything, input).Return(nil).Once()
 			},
 			wantErr: false,
@@ -342,3 +532,250 @@ func (srv *PostServiceTestSuite) TestPostSrv_UpdateByID() {
 		})
 	}
 }
+
+func (srv *PostServiceTestSuite) TestPostSrv_BulkCreate() {
+	var (
+		valid   = dto.PostCreateReq{Title: "title", Content: "content", Tags: []string{"go"}}
+		invalid = dto.PostCreateReq{Title: "title"}
+		// the suite runs anonymous
+		published = dto.PostCreateReq{Title: "title", Content: "content", Status: dto.PostStatusPublished}
+	)
+	validated := valid
+	validated.Status = dto.PostStatusDraft
+	validated.ContentFormat = render.FormatPlain
+
+	tests := []struct {
+		name        string
+		req         dto.PostBulkCreateReq
+		mockFunc    func()
+		wantSuccess int
+		wantFailed  int
+		wantErr     bool
+	}{
+		{
+			name:    "invalid request",
+			req:     dto.PostBulkCreateReq{Mode: "some"},
+			wantErr: true,
+		},
+		{
+			name: "atomic canceled by invalid item",
+			req: dto.PostBulkCreateReq{
+				Mode:  dto.BulkModeAtomic,
+				Items: []dto.PostCreateReq{valid, invalid},
+			},
+			wantFailed: 2,
+		},
+		{
+			name: "partial writes valid items",
+			req: dto.PostBulkCreateReq{
+				Mode:  dto.BulkModePartial,
+				Items: []dto.PostCreateReq{invalid, valid},
+			},
+			mockFunc: func() {
+				srv.repo.On("CreateBulk", mock.Anything, []dto.PostCreateReq{validated}, false).
+					Return([]dto.PostBulkItemRes{{Index: 0, ID: 7, Status: dto.BulkStatusSuccess}}, nil).Once()
+			},
+			wantSuccess: 1,
+			wantFailed:  1,
+		},
+		{
+			name: "published by a non editor",
+			req: dto.PostBulkCreateReq{
+				Mode:  dto.BulkModePartial,
+				Items: []dto.PostCreateReq{published, valid},
+			},
+			mockFunc: func() {
+				srv.repo.On("CreateBulk", mock.Anything, []dto.PostCreateReq{validated}, false).
+					Return([]dto.PostBulkItemRes{{Index: 0, ID: 8, Status: dto.BulkStatusSuccess}}, nil).Once()
+			},
+			wantSuccess: 1,
+			wantFailed:  1,
+		},
+	}
+	for _, tt := range tests {
+		srv.T().Run(tt.name, func(t *testing.T) {
+			if tt.mockFunc != nil {
+				tt.mockFunc()
+			}
+
+			got, err := srv.service.BulkCreate(srv.ctx, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PostSrv.BulkCreate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got.Success != tt.wantSuccess || got.Failed != tt.wantFailed {
+				t.Errorf("PostSrv.BulkCreate() success = %v failed = %v, want %v and %v", got.Success, got.Failed, tt.wantSuccess, tt.wantFailed)
+			}
+			for i, v := range got.Items {
+				if v.Index != i {
+					t.Errorf("PostSrv.BulkCreate() item %d has index %d", i, v.Index)
+				}
+			}
+		})
+	}
+}
+
+func (srv *PostServiceTestSuite) TestPostSrv_BulkUpdate() {
+	var (
+		first   = dto.PostUpdateReq{ID: 1, Title: "first", Content: "first", Tags: []string{"go"}}
+		second  = dto.PostUpdateReq{ID: 2, Title: "second", Content: "second"}
+		invalid = dto.PostUpdateReq{ID: 3, Title: "third"}
+	)
+
+	tests := []struct {
+		name        string
+		req         dto.PostBulkUpdateReq
+		mockFunc    func()
+		wantSuccess int
+		wantFailed  int
+		wantErr     bool
+	}{
+		{
+			name:    "invalid request",
+			req:     dto.PostBulkUpdateReq{Mode: dto.BulkModeAtomic},
+			wantErr: true,
+		},
+		{
+			name: "atomic canceled by invalid item",
+			req: dto.PostBulkUpdateReq{
+				Mode:  dto.BulkModeAtomic,
+				Items: []dto.PostUpdateReq{first, invalid},
+			},
+			wantFailed: 2,
+		},
+		{
+			name: "atomic canceled by failed item",
+			req: dto.PostBulkUpdateReq{
+				Mode:  dto.BulkModeAtomic,
+				Items: []dto.PostUpdateReq{first, second},
+			},
+			mockFunc: func() {
+				srv.repo.On("GetDetail", mock.Anything, dto.PostGetReq{ID: 1, IsLock: true}).Return(&dto.PostRes{ID: 1, ContentFormat: render.FormatPlain}, nil).Once()
+				srv.revRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+				srv.repo.On("UpdateByID", mock.Anything, mock.Anything).Return(nil).Once()
+				srv.repo.On("GetDetail", mock.Anything, dto.PostGetReq{ID: 2, IsLock: true}).Return(nil, helpers.ErrNotFound()).Once()
+			},
+			wantFailed: 2,
+		},
+		{
+			name: "partial writes valid items",
+			req: dto.PostBulkUpdateReq{
+				Mode:  dto.BulkModePartial,
+				Items: []dto.PostUpdateReq{invalid, first, second},
+			},
+			mockFunc: func() {
+				srv.repo.On("GetDetail", mock.Anything, dto.PostGetReq{ID: 1, IsLock: true}).Return(&dto.PostRes{ID: 1, ContentFormat: render.FormatPlain}, nil).Once()
+				srv.repo.On("GetDetail", mock.Anything, dto.PostGetReq{ID: 2, IsLock: true}).Return(&dto.PostRes{ID: 2, ContentFormat: render.FormatMarkdown}, nil).Once()
+				srv.revRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Twice()
+				srv.repo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(req dto.PostUpdateReq) bool {
+					return req.ID == 1 && req.ContentFormat == render.FormatPlain
+				})).Return(nil).Once()
+				srv.repo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(req dto.PostUpdateReq) bool {
+					return req.ID == 2 && req.ContentFormat == render.FormatMarkdown
+				})).Return(helpers.ErrUpdatedDB()).Once()
+			},
+			wantSuccess: 1,
+			wantFailed:  2,
+		},
+	}
+	for _, tt := range tests {
+		srv.T().Run(tt.name, func(t *testing.T) {
+			if tt.mockFunc != nil {
+				tt.mockFunc()
+			}
+
+			got, err := srv.service.BulkUpdate(srv.ctx, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PostSrv.BulkUpdate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got.Success != tt.wantSuccess || got.Failed != tt.wantFailed {
+				t.Errorf("PostSrv.BulkUpdate() success = %v failed = %v, want %v and %v", got.Success, got.Failed, tt.wantSuccess, tt.wantFailed)
+			}
+			for i, v := range got.Items {
+				if v.Index != i || v.ID != tt.req.Items[i].ID {
+					t.Errorf("PostSrv.BulkUpdate() item %d = %+v", i, v)
+				}
+				if v.Status == dto.BulkStatusFailed && v.Error == nil {
+					t.Errorf("PostSrv.BulkUpdate() item %d failed without an error", i)
+				}
+			}
+		})
+	}
+	srv.repo.AssertExpectations(srv.T())
+}
+
+func (srv *PostServiceTestSuite) TestPostSrv_BulkDelete() {
+	tests := []struct {
+		name        string
+		req         dto.PostBulkDeleteReq
+		mockFunc    func()
+		wantSuccess int
+		wantFailed  int
+		wantErr     bool
+	}{
+		{
+			name:    "invalid request",
+			req:     dto.PostBulkDeleteReq{IDs: []uint64{0}},
+			wantErr: true,
+		},
+		{
+			name: "atomic without duplicates",
+			req:  dto.PostBulkDeleteReq{IDs: []uint64{1, 2, 1}},
+			mockFunc: func() {
+				srv.repo.On("DeleteBulk", mock.Anything, []uint64{1, 2}, true).Return([]dto.PostBulkItemRes{
+					{Index: 0, ID: 1, Status: dto.BulkStatusSuccess},
+					{Index: 1, ID: 2, Status: dto.BulkStatusSuccess},
+				}, nil).Once()
+			},
+			wantSuccess: 2,
+		},
+		{
+			name: "partial with a missing post",
+			req:  dto.PostBulkDeleteReq{Mode: dto.BulkModePartial, IDs: []uint64{3, 4}},
+			mockFunc: func() {
+				srv.repo.On("DeleteBulk", mock.Anything, []uint64{3, 4}, false).Return([]dto.PostBulkItemRes{
+					{Index: 0, ID: 3, Status: dto.BulkStatusSuccess},
+					{Index: 1, ID: 4, Status: dto.BulkStatusFailed, Error: helpers.ErrNotFound()},
+				}, nil).Once()
+			},
+			wantSuccess: 1,
+			wantFailed:  1,
+		},
+		{
+			name: "err db",
+			req:  dto.PostBulkDeleteReq{IDs: []uint64{5}},
+			mockFunc: func() {
+				srv.repo.On("DeleteBulk", mock.Anything, []uint64{5}, true).Return([]dto.PostBulkItemRes{
+					{Index: 0, ID: 5, Status: dto.BulkStatusFailed, Error: helpers.ErrDB()},
+				}, helpers.ErrDB()).Once()
+			},
+			wantFailed: 1,
+		},
+	}
+	for _, tt := range tests {
+		srv.T().Run(tt.name, func(t *testing.T) {
+			if tt.mockFunc != nil {
+				tt.mockFunc()
+			}
+
+			got, err := srv.service.BulkDelete(srv.ctx, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PostSrv.BulkDelete() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if got.Mode == "" || got.Success != tt.wantSuccess || got.Failed != tt.wantFailed {
+				t.Errorf("PostSrv.BulkDelete() = %+v, want %v success and %v failed", got, tt.wantSuccess, tt.wantFailed)
+			}
+		})
+	}
+}
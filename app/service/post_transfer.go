@@ -0,0 +1,348 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/slug"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+)
+
+const (
+	// exportFlushEvery is how many rows are written between flushes, so
+	// clients see the export progress.
+	exportFlushEvery = 100
+	// importMaxLineSize is the longest JSON line an import accepts.
+	importMaxLineSize = 10 << 20
+)
+
+// Export writes every post to w in req.Format.
+func (srv *PostSrv) Export(ctx context.Context, req dto.PostExportReq, w io.Writer) error {
+	var (
+		opName = "PostService-Export"
+		err    error
+	)
+
+	err = req.Validate()
+	if err != nil {
+		return err
+	}
+
+	var (
+		flusher, canFlush = w.(http.Flusher)
+		csvWriter         *csv.Writer
+		jsonEncoder       = json.NewEncoder(w)
+		total             = 0
+	)
+	if req.Format == dto.TransferFormatCSV {
+		csvWriter = csv.NewWriter(w)
+		err = csvWriter.Write(dto.PostCSVHeader)
+		if err != nil {
+			return err
+		}
+	}
+
+	flush := func() error {
+		if csvWriter != nil {
+			csvWriter.Flush()
+			if err := csvWriter.Error(); err != nil {
+				return err
+			}
+		}
+		if canFlush {
+			flusher.Flush()
+		}
+		return nil
+	}
+
+	err = srv.Repo.Export(ctx, func(row dto.PostTransferRow) error {
+		var err error
+		if csvWriter != nil {
+			err = csvWriter.Write(row.CSVRecord())
+		} else {
+			err = jsonEncoder.Encode(row)
+		}
+		if err != nil {
+			return err
+		}
+
+		total++
+		if total%exportFlushEvery == 0 {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		srv.Logger.Errorf("%s failed export data: %v \n", opName, err)
+		return err
+	}
+
+	return flush()
+}
+
+// importRow is a row read from an import file, err is set when the row
+// itself is malformed and the rest of the file can still be read.
+type importRow struct {
+	line int
+	row  dto.PostTransferRow
+	err  error
+}
+
+// Import creates posts from the rows of r, in chunks of the configured batch
+// size so large files are never held in memory. Every row is validated like
+// a create request, invalid and duplicate rows are reported and skipped.
+func (srv *PostSrv) Import(ctx context.Context, req dto.PostImportReq, r io.Reader) (*dto.PostImportRes, error) {
+	var (
+		opName = "PostService-Import"
+		err    error
+	)
+
+	err = req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	next := newJSONLRowReader(r)
+	if req.Format == dto.TransferFormatCSV {
+		next, err = newCSVRowReader(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		result = &dto.PostImportRes{Format: req.Format, Dedupe: req.Dedupe, Rows: []dto.PostImportRowRes{}}
+		seen   = newImportSeen()
+		chunk  = []importRow{}
+		size   = srv.Cfg.DB.BatchSize
+	)
+	if size <= 0 {
+		size = 100
+	}
+
+	for {
+		row, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			srv.Logger.Errorf("%s failed read data: %v \n", opName, err)
+			return nil, helpers.ErrGetRequest()
+		}
+
+		chunk = append(chunk, row)
+		if len(chunk) < size {
+			continue
+		}
+
+		err = srv.importChunk(ctx, req.Dedupe, chunk, seen, result)
+		if err != nil {
+			return nil, err
+		}
+		chunk = chunk[:0]
+	}
+
+	err = srv.importChunk(ctx, req.Dedupe, chunk, seen, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// importSeen remembers the keys of the rows already imported, so duplicates
+// inside the file are caught too.
+type importSeen struct {
+	ids   map[uint64]bool
+	slugs map[string]bool
+}
+
+func newImportSeen() *importSeen {
+	return &importSeen{ids: map[uint64]bool{}, slugs: map[string]bool{}}
+}
+
+func (srv *PostSrv) importChunk(ctx context.Context, dedupe string, chunk []importRow, seen *importSeen, result *dto.PostImportRes) error {
+	opName := "PostService-importChunk"
+	if len(chunk) == 0 {
+		return nil
+	}
+
+	var (
+		reports = make([]dto.PostImportRowRes, len(chunk))
+		reqs    = make([]dto.PostCreateReq, len(chunk))
+		keys    = make([]string, len(chunk))
+		ids     = []uint64{}
+		slugs   = []string{}
+	)
+	for i, v := range chunk {
+		reports[i] = dto.PostImportRowRes{Line: v.line}
+		if v.err != nil {
+			setImportError(&reports[i], v.err)
+			continue
+		}
+
+		reqs[i] = v.row.CreateReq()
+		err := reqs[i].Validate()
+		if err != nil {
+			setImportError(&reports[i], err)
+			continue
+		}
+
+		switch dedupe {
+		case dto.ImportDedupeID:
+			if v.row.ID != 0 {
+				ids = append(ids, v.row.ID)
+			}
+		case dto.ImportDedupeSlug:
+			keys[i] = helpers.ToLower(v.row.Slug)
+			if keys[i] == "" {
+				keys[i] = slug.Make(reqs[i].Title)
+			}
+			slugs = append(slugs, keys[i])
+		}
+	}
+
+	existingIDs, err := srv.Repo.FindExistingIDs(ctx, ids)
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data posts: %v \n", opName, err)
+		return err
+	}
+	existingSlugs, err := srv.Repo.FindIDsBySlugs(ctx, slugs)
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data slugs: %v \n", opName, err)
+		return err
+	}
+
+	var (
+		valid      = []dto.PostCreateReq{}
+		validIndex = []int{}
+	)
+	for i, v := range chunk {
+		if reports[i].Status != "" {
+			continue
+		}
+
+		isDuplicate := false
+		switch dedupe {
+		case dto.ImportDedupeID:
+			id := v.row.ID
+			isDuplicate = id != 0 && (existingIDs[id] || seen.ids[id])
+			if id != 0 {
+				seen.ids[id] = true
+			}
+		case dto.ImportDedupeSlug:
+			_, isExist := existingSlugs[keys[i]]
+			isDuplicate = isExist || seen.slugs[keys[i]]
+			seen.slugs[keys[i]] = true
+		}
+		if isDuplicate {
+			reports[i].Status = dto.ImportStatusSkipped
+			reports[i].Error = dto.ErrImportDuplicate(dedupe)
+			continue
+		}
+
+		valid = append(valid, reqs[i])
+		validIndex = append(validIndex, i)
+	}
+
+	if len(valid) > 0 {
+		items, err := srv.Repo.CreateBulk(ctx, valid, false)
+		if err != nil {
+			srv.Logger.Errorf("%s failed create data: %v \n", opName, err)
+		}
+		for i, v := range bulkItemsOrError(items, len(valid), err) {
+			report := &reports[validIndex[i]]
+			if v.Status != dto.BulkStatusSuccess {
+				report.Status = dto.ImportStatusFailed
+				report.Error = v.Error
+				continue
+			}
+			report.ID = v.ID
+			report.Status = dto.ImportStatusCreated
+		}
+	}
+
+	for _, v := range reports {
+		result.Add(v)
+	}
+	return nil
+}
+
+func setImportError(report *dto.PostImportRowRes, err error) {
+	item := dto.PostBulkItemRes{}
+	item.SetError(err, dto.ErrImportRow())
+	report.Status = dto.ImportStatusFailed
+	report.Error = item.Error
+}
+
+// newJSONLRowReader reads one post per line, blank lines are ignored.
+func newJSONLRowReader(r io.Reader) func() (importRow, error) {
+	var (
+		scanner = bufio.NewScanner(r)
+		line    = 0
+	)
+	scanner.Buffer(make([]byte, 0, 64<<10), importMaxLineSize)
+
+	return func() (importRow, error) {
+		for scanner.Scan() {
+			line++
+			data := scanner.Bytes()
+			if len(bytes.TrimSpace(data)) == 0 {
+				continue
+			}
+
+			result := importRow{line: line}
+			result.err = json.Unmarshal(data, &result.row)
+			return result, nil
+		}
+
+		if err := scanner.Err(); err != nil {
+			return importRow{}, err
+		}
+		return importRow{}, io.EOF
+	}
+}
+
+// newCSVRowReader reads the header row first and then one post per record.
+func newCSVRowReader(r io.Reader) (func() (importRow, error), error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, helpers.ErrIsRequired("header csv", "csv header")
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, v := range header {
+		columns[helpers.ToLower(v)] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, helpers.ErrIsRequired("kolom title", "title column")
+	}
+
+	return func() (importRow, error) {
+		record, err := reader.Read()
+
+		// a malformed record fails alone, the reader goes on with the next
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return importRow{line: parseErr.StartLine, err: err}, nil
+		}
+		if err != nil {
+			return importRow{}, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		result := importRow{line: line}
+		result.err = result.row.ParseCSVRecord(columns, record)
+		return result, nil
+	}, nil
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/stretchr/testify/mock"
+)
+
+func (srv *PostServiceTestSuite) TestPostSrv_Export() {
+	rows := []dto.PostTransferRow{
+		{ID: 1, Slug: "first", Title: "First", Content: "a, \"quoted\"\nline", Tags: []string{"go", "sql"}, Status: dto.PostStatusPublished},
+		{ID: 2, Slug: "second", Title: "Second", Content: "b", Tags: []string{}, Status: dto.PostStatusDraft},
+	}
+	export := func(args mock.Arguments) {
+		fn := args.Get(1).(func(row dto.PostTransferRow) error)
+		for _, v := range rows {
+			if err := fn(v); err != nil {
+				return
+			}
+		}
+	}
+
+	tests := []struct {
+		name      string
+		format    string
+		wantLines int
+		wantErr   bool
+	}{
+		{name: "invalid format", format: "xml", wantErr: true},
+		{name: "jsonl", format: "", wantLines: 2},
+		{name: "csv", format: dto.TransferFormatCSV, wantLines: 4},
+	}
+	for _, tt := range tests {
+		srv.T().Run(tt.name, func(t *testing.T) {
+			if !tt.wantErr {
+				srv.repo.On("Export", mock.Anything, mock.Anything).Run(export).Return(nil).Once()
+			}
+
+			w := &bytes.Buffer{}
+			err := srv.service.Export(srv.ctx, dto.PostExportReq{Format: tt.format}, w)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PostSrv.Export() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			// the CSV header and the quoted new line add a line each
+			if got := strings.Count(w.String(), "\n"); got != tt.wantLines {
+				t.Errorf("PostSrv.Export() lines = %d, want %d\n%s", got, tt.wantLines, w.String())
+			}
+		})
+	}
+}
+
+func (srv *PostServiceTestSuite) TestPostSrv_Import() {
+	created := func(ctx context.Context, reqs []dto.PostCreateReq, atomic bool) []dto.PostBulkItemRes {
+		result := make([]dto.PostBulkItemRes, len(reqs))
+		for i := range reqs {
+			result[i] = dto.PostBulkItemRes{Index: i, ID: uint64(100 + i), Status: dto.BulkStatusSuccess}
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		req      dto.PostImportReq
+		body     string
+		mockFunc func()
+		want     dto.PostImportRes
+		wantErr  bool
+	}{
+		{
+			name:    "invalid dedupe",
+			req:     dto.PostImportReq{Dedupe: "title"},
+			wantErr: true,
+		},
+		{
+			name:    "csv without title column",
+			req:     dto.PostImportReq{Format: dto.TransferFormatCSV},
+			body:    "id,content\n1,a\n",
+			wantErr: true,
+		},
+		{
+			name: "jsonl with invalid rows",
+			req:  dto.PostImportReq{},
+			body: `{"title":"one","content":"a","tags":["go"]}` + "\n\n" +
+				`{"title":"","content":"b"}` + "\n" +
+				`{not json` + "\n" +
+				`{"title":"two","content":"c"}` + "\n",
+			mockFunc: func() {
+				srv.repo.On("FindExistingIDs", mock.Anything, []uint64{}).Return(map[uint64]bool{}, nil).Once()
+				srv.repo.On("FindIDsBySlugs", mock.Anything, []string{}).Return(map[string]uint64{}, nil).Once()
+				srv.repo.On("CreateBulk", mock.Anything, mock.MatchedBy(func(reqs []dto.PostCreateReq) bool {
+					return len(reqs) == 2 && reqs[0].Title == "one" && reqs[1].Title == "two"
+				}), false).Return(created, nil).Once()
+			},
+			want: dto.PostImportRes{Format: dto.TransferFormatJSONL, Dedupe: dto.ImportDedupeNone, Total: 4, Created: 2, Failed: 2},
+		},
+		{
+			name: "csv dedupe by slug",
+			req:  dto.PostImportReq{Format: dto.TransferFormatCSV, Dedupe: dto.ImportDedupeSlug},
+			body: "title,content,tags,slug\n" +
+				"Hello World,a,go|sql,\n" +
+				"Other,b,,taken\n" +
+				"Hello world!,c,,\n",
+			mockFunc: func() {
+				srv.repo.On("FindExistingIDs", mock.Anything, []uint64{}).Return(map[uint64]bool{}, nil).Once()
+				srv.repo.On("FindIDsBySlugs", mock.Anything, []string{"hello-world", "taken", "hello-world"}).
+					Return(map[string]uint64{"taken": 7}, nil).Once()
+				srv.repo.On("CreateBulk", mock.Anything, mock.MatchedBy(func(reqs []dto.PostCreateReq) bool {
+					return len(reqs) == 1 && reqs[0].Title == "Hello World" && len(reqs[0].Tags) == 2
+				}), false).Return(created, nil).Once()
+			},
+			want: dto.PostImportRes{Format: dto.TransferFormatCSV, Dedupe: dto.ImportDedupeSlug, Total: 3, Created: 1, Skipped: 2},
+		},
+		{
+			name: "create fails without items",
+			req:  dto.PostImportReq{},
+			body: `{"title":"one","content":"a"}` + "\n" + `{"title":"two","content":"b"}` + "\n",
+			mockFunc: func() {
+				srv.repo.On("FindExistingIDs", mock.Anything, []uint64{}).Return(map[uint64]bool{}, nil).Once()
+				srv.repo.On("FindIDsBySlugs", mock.Anything, []string{}).Return(map[string]uint64{}, nil).Once()
+				srv.repo.On("CreateBulk", mock.Anything, mock.Anything, false).Return(nil, helpers.ErrCreatedDB()).Once()
+			},
+			want: dto.PostImportRes{Format: dto.TransferFormatJSONL, Dedupe: dto.ImportDedupeNone, Total: 2, Failed: 2},
+		},
+	}
+	for _, tt := range tests {
+		srv.T().Run(tt.name, func(t *testing.T) {
+			if tt.mockFunc != nil {
+				tt.mockFunc()
+			}
+
+			got, err := srv.service.Import(context.Background(), tt.req, strings.NewReader(tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PostSrv.Import() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			for _, v := range got.Rows {
+				if v.Status == dto.ImportStatusFailed && v.Error == nil {
+					t.Errorf("PostSrv.Import() line %d failed without an error", v.Line)
+				}
+			}
+			got.Rows = nil
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("PostSrv.Import() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/repository"
+	"github.com/sirupsen/logrus"
+)
+
+type ReportService interface {
+	TagCooccurrence(ctx context.Context, req dto.TagCooccurrenceReq) (*dto.TagCooccurrenceRes, error)
+	TagHistogram(ctx context.Context) (*dto.TagHistogramRes, error)
+	TagWeekly(ctx context.Context, req dto.TagWeeklyReq) (*dto.TagWeeklyRes, error)
+	Refresh(ctx context.Context) (*dto.ReportRefreshRes, error)
+}
+
+type ReportSrv struct {
+	Repos  *repository.Repositories
+	Repo   repository.ReportRepository
+	Cfg    *configs.Configs
+	Logger *logrus.Logger
+}
+
+// NewReportService creates a new instance of ReportService.
+func NewReportService(
+	repos *repository.Repositories,
+	cfg *configs.Configs,
+	logger *logrus.Logger,
+) ReportService {
+	return &ReportSrv{
+		Repos:  repos,
+		Repo:   repos.Report,
+		Cfg:    cfg,
+		Logger: logger,
+	}
+}
+
+// TagCooccurrence tells how often the most used tags are given together.
+func (srv *ReportSrv) TagCooccurrence(ctx context.Context, req dto.TagCooccurrenceReq) (*dto.TagCooccurrenceRes, error) {
+	opName := "ReportService-TagCooccurrence"
+
+	err := req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := srv.Repo.GetTopTags(ctx, req.Top)
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data tags: %v \n", opName, err)
+		return nil, err
+	}
+
+	ids := make([]uint64, 0, len(tags))
+	for _, v := range tags {
+		ids = append(ids, v.ID)
+	}
+	pairs, err := srv.Repo.GetTagPairs(ctx, ids)
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data pairs: %v \n", opName, err)
+		return nil, err
+	}
+
+	return dto.NewTagCooccurrence(tags, pairs), nil
+}
+
+// TagHistogram tells how many tags are given to how many posts.
+func (srv *ReportSrv) TagHistogram(ctx context.Context) (*dto.TagHistogramRes, error) {
+	opName := "ReportService-TagHistogram"
+
+	counts, err := srv.Repo.GetPostCounts(ctx)
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, err
+	}
+
+	return dto.NewTagHistogram(counts), nil
+}
+
+// TagWeekly tells how many posts published each week have the tag, or the
+// most used tags of those weeks. The current week is the last one.
+func (srv *ReportSrv) TagWeekly(ctx context.Context, req dto.TagWeeklyReq) (*dto.TagWeeklyRes, error) {
+	opName := "ReportService-TagWeekly"
+
+	err := req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		until = dto.WeekStart(time.Now())
+		since = until.AddDate(0, 0, -7*(req.Weeks-1))
+	)
+	rows, err := srv.Repo.GetTagWeeks(ctx, since, req.Tag, req.Top)
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, err
+	}
+
+	return dto.NewTagWeekly(since, until, rows), nil
+}
+
+// Refresh recomputes the reports from the posts and their tags.
+func (srv *ReportSrv) Refresh(ctx context.Context) (*dto.ReportRefreshRes, error) {
+	opName := "ReportService-Refresh"
+
+	start := time.Now()
+	err := srv.Repo.Refresh(ctx)
+	if err != nil {
+		srv.Logger.Errorf("%s failed refresh: %v \n", opName, err)
+		return nil, err
+	}
+
+	return &dto.ReportRefreshRes{
+		RefreshedAt: time.Now(),
+		DurationMs:  time.Since(start).Milliseconds(),
+	}, nil
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/repository"
+	"github.com/adamnasrudin03/go-asset-findr/app/repository/mocks"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/driver"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ReportServiceTestSuite struct {
+	suite.Suite
+	repo    *mocks.ReportRepository
+	ctx     context.Context
+	service ReportService
+}
+
+func (srv *ReportServiceTestSuite) SetupTest() {
+	var (
+		cfg    = configs.GetInstance()
+		logger = driver.Logger(cfg)
+	)
+
+	srv.repo = &mocks.ReportRepository{}
+	srv.ctx = context.Background()
+	srv.service = NewReportService(&repository.Repositories{Report: srv.repo}, cfg, logger)
+}
+
+func TestReportService(t *testing.T) {
+	suite.Run(t, new(ReportServiceTestSuite))
+}
+
+func (srv *ReportServiceTestSuite) TestReportSrv_TagCooccurrence() {
+	tags := []dto.TagUsageRes{{ID: 1, Label: "go", Usage: 3}, {ID: 2, Label: "sql", Usage: 2}}
+	srv.repo.On("GetTopTags", mock.Anything, 2).Return(tags, nil).Once()
+	srv.repo.On("GetTagPairs", mock.Anything, []uint64{1, 2}).Return([]dto.TagPairRow{{TagID: 1, OtherID: 2, Posts: 1}}, nil).Once()
+	srv.repo.On("GetTopTags", mock.Anything, dto.TagCooccurrenceDefaultTop).Return(nil, helpers.ErrDB()).Once()
+
+	_, err := srv.service.TagCooccurrence(srv.ctx, dto.TagCooccurrenceReq{Format: "xml"})
+	srv.Error(err)
+
+	got, err := srv.service.TagCooccurrence(srv.ctx, dto.TagCooccurrenceReq{Top: 2})
+	srv.Require().NoError(err)
+	srv.Equal([][]int64{{3, 1}, {1, 2}}, got.Matrix)
+
+	_, err = srv.service.TagCooccurrence(srv.ctx, dto.TagCooccurrenceReq{})
+	srv.Error(err)
+	srv.repo.AssertExpectations(srv.T())
+}
+
+func (srv *ReportServiceTestSuite) TestReportSrv_TagHistogram() {
+	srv.repo.On("GetPostCounts", mock.Anything).Return([]dto.TagPostCountRow{{Posts: 1, Tags: 4}, {Posts: 2, Tags: 1}}, nil).Once()
+	srv.repo.On("GetPostCounts", mock.Anything).Return(nil, helpers.ErrDB()).Once()
+
+	got, err := srv.service.TagHistogram(srv.ctx)
+	srv.Require().NoError(err)
+	srv.Equal([]dto.TagHistogramBucket{{Min: 0, Max: 0}, {Min: 1, Max: 1, Tags: 4}, {Min: 2, Max: 3, Tags: 1}}, got.Buckets)
+
+	_, err = srv.service.TagHistogram(srv.ctx)
+	srv.Error(err)
+	srv.repo.AssertExpectations(srv.T())
+}
+
+func (srv *ReportServiceTestSuite) TestReportSrv_TagWeekly() {
+	var (
+		until = dto.WeekStart(time.Now())
+		since = until.AddDate(0, 0, -7)
+		rows  = []dto.TagWeekRow{{Week: until, TagID: 1, Label: "go", Posts: 2}}
+	)
+	srv.repo.On("GetTagWeeks", mock.Anything, since, "go", dto.TagWeeklyDefaultTop).Return(rows, nil).Once()
+
+	_, err := srv.service.TagWeekly(srv.ctx, dto.TagWeeklyReq{Weeks: dto.TagWeeklyMaxWeeks + 1})
+	srv.Error(err)
+
+	got, err := srv.service.TagWeekly(srv.ctx, dto.TagWeeklyReq{Weeks: 2, Tag: "Go"})
+	srv.Require().NoError(err)
+	srv.Len(got.Weeks, 2)
+	srv.Equal([]dto.TagWeekSeriesRes{{Label: "go", Posts: []int64{0, 2}}}, got.Tags)
+	srv.repo.AssertExpectations(srv.T())
+}
+
+func (srv *ReportServiceTestSuite) TestReportSrv_Refresh() {
+	srv.repo.On("Refresh", mock.Anything).Return(nil).Once()
+	srv.repo.On("Refresh", mock.Anything).Return(helpers.ErrDB()).Once()
+
+	got, err := srv.service.Refresh(srv.ctx)
+	srv.Require().NoError(err)
+	srv.False(got.RefreshedAt.IsZero())
+
+	_, err = srv.service.Refresh(srv.ctx)
+	srv.Error(err)
+	srv.repo.AssertExpectations(srv.T())
+}
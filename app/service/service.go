@@ -2,5 +2,11 @@ package service
 
 // Services all service object injected here
 type Services struct {
-	Post PostService
+	Post       PostService
+	Asset      AssetService
+	Tag        TagService
+	Report     ReportService
+	Outbox     OutboxService
+	Webhook    WebhookService
+	PostStream PostStreamService
 }
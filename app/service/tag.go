@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/repository"
+	"github.com/sirupsen/logrus"
+)
+
+type TagService interface {
+	GetTree(ctx context.Context) ([]dto.TagRes, error)
+	SetParent(ctx context.Context, req dto.TagParentReq) error
+	AddSynonym(ctx context.Context, req dto.TagSynonymReq) error
+	DeleteSynonym(ctx context.Context, req dto.TagSynonymReq) error
+	Merge(ctx context.Context, req dto.TagMergeReq) error
+	Suggest(ctx context.Context, req dto.TagSuggestReq) ([]dto.TagUsageRes, error)
+	Popular(ctx context.Context, req dto.TagPopularReq) ([]dto.TagUsageRes, error)
+}
+
+type TagSrv struct {
+	Repos  *repository.Repositories
+	Repo   repository.TagRepository
+	Cfg    *configs.Configs
+	Logger *logrus.Logger
+}
+
+// NewTagService creates a new instance of TagService.
+func NewTagService(
+	repos *repository.Repositories,
+	cfg *configs.Configs,
+	logger *logrus.Logger,
+) TagService {
+	return &TagSrv{
+		Repos:  repos,
+		Repo:   repos.Tag,
+		Cfg:    cfg,
+		Logger: logger,
+	}
+}
+
+// GetTree returns every tag nested under its parent, with its synonyms.
+func (srv *TagSrv) GetTree(ctx context.Context) ([]dto.TagRes, error) {
+	opName := "TagService-GetTree"
+
+	tags, err := srv.Repo.GetAll(ctx)
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, err
+	}
+
+	synonyms, err := srv.Repo.GetSynonyms(ctx)
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data synonyms: %v \n", opName, err)
+		return nil, err
+	}
+
+	return dto.NewTagTree(tags, synonyms), nil
+}
+
+func (srv *TagSrv) SetParent(ctx context.Context, req dto.TagParentReq) error {
+	var (
+		opName = "TagService-SetParent"
+		err    error
+	)
+
+	err = req.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = srv.Repo.SetParent(ctx, req)
+	if err != nil {
+		srv.Logger.Errorf("%s failed update data: %v \n", opName, err)
+		return err
+	}
+
+	return nil
+}
+
+func (srv *TagSrv) AddSynonym(ctx context.Context, req dto.TagSynonymReq) error {
+	var (
+		opName = "TagService-AddSynonym"
+		err    error
+	)
+
+	err = req.Validate()
+	if err != nil {
+		return err
+	}
+
+	_, err = srv.Repo.AddSynonym(ctx, req)
+	if err != nil {
+		srv.Logger.Errorf("%s failed create data: %v \n", opName, err)
+		return err
+	}
+
+	return nil
+}
+
+func (srv *TagSrv) DeleteSynonym(ctx context.Context, req dto.TagSynonymReq) error {
+	var (
+		opName = "TagService-DeleteSynonym"
+		err    error
+	)
+
+	err = req.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = srv.Repo.DeleteSynonym(ctx, req)
+	if err != nil {
+		srv.Logger.Errorf("%s failed delete data: %v \n", opName, err)
+		return err
+	}
+
+	return nil
+}
+
+// Merge folds duplicate tags into one. The tags of many posts change at
+// once, so the cached posts are dropped.
+func (srv *TagSrv) Merge(ctx context.Context, req dto.TagMergeReq) error {
+	var (
+		opName = "TagService-Merge"
+		err    error
+	)
+
+	err = req.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = srv.Repo.Merge(ctx, req)
+	if err != nil {
+		srv.Logger.Errorf("%s failed merge data: %v \n", opName, err)
+		return err
+	}
+
+	if cache, ok := srv.Repos.Post.(repository.PostCachePurger); ok {
+		repository.AfterCommit(ctx, func() {
+			cache.Purge(context.WithoutCancel(ctx))
+		})
+	}
+	return nil
+}
+
+// Suggest completes the label being typed, the most used tags first.
+func (srv *TagSrv) Suggest(ctx context.Context, req dto.TagSuggestReq) ([]dto.TagUsageRes, error) {
+	opName := "TagService-Suggest"
+
+	err := req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := srv.Repo.Suggest(ctx, req)
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Popular returns the tags given most to published posts during the window.
+func (srv *TagSrv) Popular(ctx context.Context, req dto.TagPopularReq) ([]dto.TagUsageRes, error) {
+	opName := "TagService-Popular"
+
+	err := req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := srv.Repo.Popular(ctx, time.Now().Add(-req.Since), req.Limit)
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/app/repository"
+	"github.com/adamnasrudin03/go-asset-findr/app/repository/mocks"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/driver"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TagServiceTestSuite struct {
+	suite.Suite
+	repo    *mocks.TagRepository
+	ctx     context.Context
+	service TagService
+}
+
+func (srv *TagServiceTestSuite) SetupTest() {
+	var (
+		cfg    = configs.GetInstance()
+		logger = driver.Logger(cfg)
+	)
+
+	srv.repo = &mocks.TagRepository{}
+	srv.ctx = context.Background()
+	srv.service = NewTagService(&repository.Repositories{Tag: srv.repo, Post: &mocks.PostRepository{}}, cfg, logger)
+}
+
+func TestTagService(t *testing.T) {
+	suite.Run(t, new(TagServiceTestSuite))
+}
+
+func (srv *TagServiceTestSuite) TestTagSrv_GetTree() {
+	parent := uint64(1)
+	srv.repo.On("GetAll", mock.Anything).Return([]models.Tag{{ID: 1, Label: "language"}, {ID: 2, Label: "golang", ParentID: &parent}}, nil).Once()
+	srv.repo.On("GetSynonyms", mock.Anything).Return([]models.TagSynonym{{TagID: 2, Label: "go"}}, nil).Once()
+
+	got, err := srv.service.GetTree(srv.ctx)
+	srv.Require().NoError(err)
+	srv.Require().Len(got, 1)
+	srv.Equal([]string{"go"}, got[0].Children[0].Synonyms)
+
+	srv.repo.On("GetAll", mock.Anything).Return(nil, helpers.ErrDB()).Once()
+	_, err = srv.service.GetTree(srv.ctx)
+	srv.Error(err)
+	srv.repo.AssertExpectations(srv.T())
+}
+
+func (srv *TagServiceTestSuite) TestTagSrv_SetParent() {
+	parent := uint64(2)
+	srv.repo.On("SetParent", mock.Anything, dto.TagParentReq{ID: 1, ParentID: &parent}).Return(dto.ErrTagCycle()).Once()
+
+	srv.Error(srv.service.SetParent(srv.ctx, dto.TagParentReq{ID: 2, ParentID: &parent}))
+	srv.Error(srv.service.SetParent(srv.ctx, dto.TagParentReq{ID: 1, ParentID: &parent}))
+	srv.repo.AssertExpectations(srv.T())
+}
+
+func (srv *TagServiceTestSuite) TestTagSrv_Synonyms() {
+	srv.repo.On("AddSynonym", mock.Anything, dto.TagSynonymReq{TagID: 1, Label: "go"}).Return(&models.TagSynonym{ID: 1, TagID: 1, Label: "go"}, nil).Once()
+	srv.repo.On("DeleteSynonym", mock.Anything, dto.TagSynonymReq{TagID: 1, Label: "go"}).Return(helpers.ErrNotFound()).Once()
+
+	srv.Error(srv.service.AddSynonym(srv.ctx, dto.TagSynonymReq{TagID: 1}))
+	srv.NoError(srv.service.AddSynonym(srv.ctx, dto.TagSynonymReq{TagID: 1, Label: " Go "}))
+	srv.Error(srv.service.DeleteSynonym(srv.ctx, dto.TagSynonymReq{TagID: 1, Label: "GO"}))
+	srv.repo.AssertExpectations(srv.T())
+}
+
+func (srv *TagServiceTestSuite) TestTagSrv_Merge() {
+	srv.repo.On("Merge", mock.Anything, dto.TagMergeReq{TargetID: 1, SourceIDs: []uint64{2, 3}}).Return(nil).Once()
+	srv.repo.On("Merge", mock.Anything, dto.TagMergeReq{TargetID: 1, SourceIDs: []uint64{4}}).Return(helpers.ErrNotFound()).Once()
+
+	srv.Error(srv.service.Merge(srv.ctx, dto.TagMergeReq{TargetID: 1, SourceIDs: []uint64{1}}))
+	srv.NoError(srv.service.Merge(srv.ctx, dto.TagMergeReq{TargetID: 1, SourceIDs: []uint64{2, 3, 2}}))
+	srv.Error(srv.service.Merge(srv.ctx, dto.TagMergeReq{TargetID: 1, SourceIDs: []uint64{4}}))
+	srv.repo.AssertExpectations(srv.T())
+}
+
+func (srv *TagServiceTestSuite) TestTagSrv_Suggest() {
+	res := []dto.TagUsageRes{{ID: 1, Label: "golang", Usage: 3}}
+	srv.repo.On("Suggest", mock.Anything, dto.TagSuggestReq{Prefix: "go", Limit: dto.TagSuggestDefaultLimit}).Return(res, nil).Once()
+
+	_, err := srv.service.Suggest(srv.ctx, dto.TagSuggestReq{})
+	srv.Error(err)
+	got, err := srv.service.Suggest(srv.ctx, dto.TagSuggestReq{Prefix: " Go "})
+	srv.NoError(err)
+	srv.Equal(res, got)
+	srv.repo.AssertExpectations(srv.T())
+}
+
+func (srv *TagServiceTestSuite) TestTagSrv_Popular() {
+	var (
+		res   = []dto.TagUsageRes{{ID: 1, Label: "golang", Usage: 3}}
+		start = time.Now()
+	)
+	srv.repo.On("Popular", mock.Anything, mock.MatchedBy(func(since time.Time) bool {
+		want := start.Add(-7 * 24 * time.Hour)
+		return !since.Before(want) && since.Before(want.Add(time.Minute))
+	}), 5).Return(res, nil).Once()
+
+	_, err := srv.service.Popular(srv.ctx, dto.TagPopularReq{Window: "forever"})
+	srv.Error(err)
+	got, err := srv.service.Popular(srv.ctx, dto.TagPopularReq{Window: "1w", Limit: 5})
+	srv.NoError(err)
+	srv.Equal(res, got)
+	srv.repo.AssertExpectations(srv.T())
+}
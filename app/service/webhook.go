@@ -0,0 +1,350 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/app/repository"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/event"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/webhook"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/sirupsen/logrus"
+)
+
+// WebhookService manages the webhooks and sends them the events they
+// subscribed to.
+type WebhookService interface {
+	Create(ctx context.Context, req dto.WebhookReq) (*dto.WebhookRes, error)
+	GetList(ctx context.Context) ([]dto.WebhookRes, error)
+	GetDetail(ctx context.Context, id uint64) (*dto.WebhookRes, error)
+	Update(ctx context.Context, req dto.WebhookReq) (*dto.WebhookRes, error)
+	DeleteByID(ctx context.Context, id uint64) error
+	GetDeliveries(ctx context.Context, req dto.WebhookDeliveryListReq) ([]models.WebhookDelivery, error)
+	Redeliver(ctx context.Context, req dto.WebhookRedeliverReq) (*models.WebhookDelivery, error)
+	Enqueue(ctx context.Context, e event.Event) error
+	Deliver(ctx context.Context) (int, error)
+}
+
+type WebhookSrv struct {
+	Repos  *repository.Repositories
+	Repo   repository.WebhookRepository
+	Client *webhook.Client
+	Cfg    *configs.Configs
+	Logger *logrus.Logger
+}
+
+// NewWebhookService creates a new instance of WebhookService.
+func NewWebhookService(
+	repos *repository.Repositories,
+	cfg *configs.Configs,
+	logger *logrus.Logger,
+) WebhookService {
+	return &WebhookSrv{
+		Repos:  repos,
+		Repo:   repos.Webhook,
+		Client: webhook.NewClient(time.Duration(cfg.Webhook.Timeout) * time.Second),
+		Cfg:    cfg,
+		Logger: logger,
+	}
+}
+
+// Create adds the webhook, with a random secret when req has none. The
+// secret is only returned here.
+func (srv *WebhookSrv) Create(ctx context.Context, req dto.WebhookReq) (*dto.WebhookRes, error) {
+	var (
+		opName = "WebhookService-Create"
+		err    error
+	)
+
+	err = req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Secret == "" {
+		req.Secret, err = webhook.NewSecret()
+		if err != nil {
+			srv.Logger.Errorf("%s failed generate secret: %v \n", opName, err)
+			return nil, helpers.ErrCreatedDB()
+		}
+	}
+
+	created, err := srv.Repo.Create(ctx, req)
+	if err != nil {
+		srv.Logger.Errorf("%s failed create data: %v \n", opName, err)
+		return nil, err
+	}
+
+	res := dto.NewWebhookRes(*created)
+	res.Secret = created.Secret
+	return &res, nil
+}
+
+func (srv *WebhookSrv) GetList(ctx context.Context) ([]dto.WebhookRes, error) {
+	opName := "WebhookService-GetList"
+
+	webhooks, err := srv.Repo.GetList(ctx)
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, err
+	}
+
+	result := make([]dto.WebhookRes, 0, len(webhooks))
+	for _, v := range webhooks {
+		result = append(result, dto.NewWebhookRes(v))
+	}
+	return result, nil
+}
+
+func (srv *WebhookSrv) GetDetail(ctx context.Context, id uint64) (*dto.WebhookRes, error) {
+	opName := "WebhookService-GetDetail"
+
+	detail, err := srv.Repo.GetDetail(ctx, id)
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, err
+	}
+
+	res := dto.NewWebhookRes(*detail)
+	return &res, nil
+}
+
+// Update replaces the webhook, it keeps its secret unless req has one.
+// Deliveries already created are sent to the new url.
+func (srv *WebhookSrv) Update(ctx context.Context, req dto.WebhookReq) (*dto.WebhookRes, error) {
+	var (
+		opName = "WebhookService-Update"
+		err    error
+	)
+
+	if req.ID == 0 {
+		return nil, helpers.ErrIsRequired("id webhook", "webhook id")
+	}
+	err = req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := srv.Repo.UpdateByID(ctx, req)
+	if err != nil {
+		srv.Logger.Errorf("%s failed update data: %v \n", opName, err)
+		return nil, err
+	}
+
+	res := dto.NewWebhookRes(*updated)
+	return &res, nil
+}
+
+func (srv *WebhookSrv) DeleteByID(ctx context.Context, id uint64) error {
+	opName := "WebhookService-DeleteByID"
+
+	err := srv.Repo.DeleteByID(ctx, id)
+	if err != nil {
+		srv.Logger.Errorf("%s failed delete data: %v \n", opName, err)
+		return err
+	}
+
+	return nil
+}
+
+func (srv *WebhookSrv) GetDeliveries(ctx context.Context, req dto.WebhookDeliveryListReq) ([]models.WebhookDelivery, error) {
+	opName := "WebhookService-GetDeliveries"
+
+	err := req.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = srv.Repo.GetDetail(ctx, req.WebhookID)
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data webhook: %v \n", opName, err)
+		return nil, err
+	}
+
+	result, err := srv.Repo.GetDeliveries(ctx, req)
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Redeliver sends the delivery again with all its attempts, a dead one
+// included, as soon as the next deliveries are sent.
+func (srv *WebhookSrv) Redeliver(ctx context.Context, req dto.WebhookRedeliverReq) (*models.WebhookDelivery, error) {
+	opName := "WebhookService-Redeliver"
+
+	result, err := srv.Repo.Redeliver(ctx, req)
+	if err != nil {
+		srv.Logger.Errorf("%s failed update data: %v \n", opName, err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Enqueue creates the deliveries of e to the active webhooks subscribed to
+// it. It is a handler of the in process publisher, so it runs in the
+// transaction of the relay and the deliveries are stored with the event
+// marked published.
+func (srv *WebhookSrv) Enqueue(ctx context.Context, e event.Event) error {
+	opName := "WebhookService-Enqueue"
+
+	webhooks, err := srv.Repo.GetActive(ctx)
+	if err != nil {
+		srv.Logger.Errorf("%s failed get data: %v \n", opName, err)
+		return err
+	}
+
+	deliveries := []models.WebhookDelivery{}
+	for _, v := range webhooks {
+		if !dto.WebhookMatch(v.Events, e.Type) {
+			continue
+		}
+		if len(deliveries) == 0 {
+			// the body is the same for every webhook
+			body, err := json.Marshal(dto.WebhookPayload{
+				ID:            e.ID,
+				Type:          e.Type,
+				AggregateType: e.AggregateType,
+				AggregateID:   e.AggregateID,
+				OccurredAt:    e.OccurredAt,
+				Data:          models.JSON(e.Payload),
+			})
+			if err != nil {
+				srv.Logger.Errorf("%s failed marshal event %d: %v \n", opName, e.ID, err)
+				return err
+			}
+			deliveries = append(deliveries, models.WebhookDelivery{Payload: models.JSON(body)})
+		} else {
+			deliveries = append(deliveries, models.WebhookDelivery{Payload: deliveries[0].Payload})
+		}
+
+		delivery := &deliveries[len(deliveries)-1]
+		delivery.WebhookID = v.ID
+		delivery.EventID = e.ID
+		delivery.EventType = e.Type
+		delivery.Status = dto.WebhookStatusPending
+		delivery.NextAttemptAt = time.Now()
+	}
+
+	err = srv.Repo.CreateDeliveries(ctx, deliveries)
+	if err != nil {
+		srv.Logger.Errorf("%s failed create data: %v \n", opName, err)
+		return err
+	}
+
+	return nil
+}
+
+// Deliver sends the due deliveries batch by batch, those of a batch
+// concurrently, until none is left and returns how many succeeded. A failed
+// delivery is retried later with a backoff until it runs out of attempts
+// and is dead. Receivers get the delivery ID in a header to drop the ones
+// they already got, e.g. when a send timed out after they handled it.
+func (srv *WebhookSrv) Deliver(ctx context.Context) (int, error) {
+	var (
+		opName    = "WebhookService-Deliver"
+		batchSize = srv.batchSize()
+		total     = 0
+	)
+
+	for {
+		var (
+			now = time.Now()
+			// a claimed delivery is sent again if we are not done with it
+			// by then, e.g. after a crash
+			leaseUntil = now.Add(time.Duration(srv.Cfg.Webhook.Timeout)*time.Second + time.Minute)
+		)
+		deliveries, err := srv.Repo.ClaimDeliveries(ctx, now, leaseUntil, batchSize)
+		if err != nil {
+			srv.Logger.Errorf("%s failed claim deliveries: %v \n", opName, err)
+			return total, err
+		}
+
+		var (
+			wg        sync.WaitGroup
+			mu        sync.Mutex
+			succeeded = 0
+		)
+		for i := range deliveries {
+			wg.Add(1)
+			go func(delivery *models.WebhookDelivery) {
+				defer wg.Done()
+				if srv.attempt(ctx, delivery) {
+					mu.Lock()
+					succeeded++
+					mu.Unlock()
+				}
+			}(&deliveries[i])
+		}
+		wg.Wait()
+
+		total += succeeded
+		if len(deliveries) < batchSize {
+			return total, nil
+		}
+	}
+}
+
+// attempt sends the delivery once and stores the outcome, it tells if the
+// receiver accepted it.
+func (srv *WebhookSrv) attempt(ctx context.Context, delivery *models.WebhookDelivery) bool {
+	opName := "WebhookService-attempt"
+	if delivery.Webhook == nil {
+		return false
+	}
+
+	res, err := srv.Client.Send(ctx, webhook.Request{
+		URL:    delivery.Webhook.URL,
+		Secret: delivery.Webhook.Secret,
+		ID:     strconv.FormatUint(delivery.ID, 10),
+		Event:  delivery.EventType,
+		Body:   delivery.Payload,
+	})
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastStatusCode, delivery.LastResponse = 0, ""
+	if res != nil {
+		delivery.LastStatusCode, delivery.LastResponse = res.StatusCode, res.Body
+	}
+
+	switch {
+	case err == nil:
+		delivery.Status = dto.WebhookStatusSucceeded
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+	case delivery.Attempts >= srv.Cfg.Webhook.MaxAttempts:
+		srv.Logger.Warnf("%s delivery %d to webhook %d is dead after %d attempts: %v \n", opName, delivery.ID, delivery.WebhookID, delivery.Attempts, err)
+		delivery.Status = dto.WebhookStatusDead
+		delivery.LastError = err.Error()
+	default:
+		srv.Logger.Warnf("%s failed delivery %d to webhook %d, attempt %d: %v \n", opName, delivery.ID, delivery.WebhookID, delivery.Attempts, err)
+		delivery.Status = dto.WebhookStatusRetrying
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = now.Add(backoff(srv.Cfg.Webhook.RetryBase, srv.Cfg.Webhook.RetryMax, delivery.Attempts))
+	}
+
+	// the outcome is stored even when ctx is done, or the delivery would be
+	// sent again after its lease
+	saveErr := srv.Repo.SaveAttempt(context.WithoutCancel(ctx), delivery)
+	if saveErr != nil {
+		srv.Logger.Errorf("%s failed save attempt of delivery %d: %v \n", opName, delivery.ID, saveErr)
+	}
+	return err == nil
+}
+
+func (srv *WebhookSrv) batchSize() int {
+	if srv.Cfg.Webhook.BatchSize <= 0 {
+		return 50
+	}
+	return srv.Cfg.Webhook.BatchSize
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/models"
+	"github.com/adamnasrudin03/go-asset-findr/app/repository"
+	"github.com/adamnasrudin03/go-asset-findr/app/repository/mocks"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/driver"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/event"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/webhook"
+	"github.com/adamnasrudin03/go-template/pkg/helpers"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type WebhookServiceTestSuite struct {
+	suite.Suite
+	repo    *mocks.WebhookRepository
+	cfg     *configs.Configs
+	ctx     context.Context
+	service *WebhookSrv
+
+	server   *httptest.Server
+	mu       sync.Mutex
+	status   int
+	received []*http.Request
+}
+
+func (srv *WebhookServiceTestSuite) SetupTest() {
+	srv.cfg = configs.GetInstance()
+	srv.cfg.Webhook.BatchSize = 10
+	srv.cfg.Webhook.Timeout = 1
+	srv.cfg.Webhook.RetryBase = 10
+	srv.cfg.Webhook.RetryMax = 60
+	srv.cfg.Webhook.MaxAttempts = 3
+
+	srv.status = http.StatusOK
+	srv.received = nil
+	srv.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := webhook.Verify("secret-of-the-hook", r.Header, body, time.Minute, time.Now()); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		srv.received = append(srv.received, r)
+		w.WriteHeader(srv.status)
+		w.Write([]byte("ok"))
+	}))
+
+	srv.repo = &mocks.WebhookRepository{}
+	srv.ctx = context.Background()
+	srv.service = NewWebhookService(&repository.Repositories{Webhook: srv.repo}, srv.cfg, driver.Logger(srv.cfg)).(*WebhookSrv)
+}
+
+func (srv *WebhookServiceTestSuite) TearDownTest() {
+	srv.server.Close()
+}
+
+func TestWebhookService(t *testing.T) {
+	suite.Run(t, new(WebhookServiceTestSuite))
+}
+
+func (srv *WebhookServiceTestSuite) delivery(id uint64) models.WebhookDelivery {
+	return models.WebhookDelivery{
+		ID:        id,
+		WebhookID: 1,
+		EventID:   100 + id,
+		EventType: dto.PostEventCreated,
+		Payload:   models.JSON(`{"id":1}`),
+		Status:    dto.WebhookStatusPending,
+		Webhook:   &models.Webhook{ID: 1, URL: srv.server.URL, Secret: "secret-of-the-hook", IsActive: true},
+	}
+}
+
+func (srv *WebhookServiceTestSuite) TestWebhookSrv_Create() {
+	srv.repo.On("Create", mock.Anything, mock.MatchedBy(func(req dto.WebhookReq) bool {
+		return len(req.Secret) == 32 && req.URL == "https://example.com/hook"
+	})).Return(func(ctx context.Context, req dto.WebhookReq) (*models.Webhook, error) {
+		return &models.Webhook{ID: 1, URL: req.URL, Events: req.Events, Secret: req.Secret, IsActive: *req.IsActive}, nil
+	}).Once()
+
+	res, err := srv.service.Create(srv.ctx, dto.WebhookReq{URL: "https://example.com/hook", Events: []string{"post.*"}})
+	srv.Require().NoError(err)
+	srv.Len(res.Secret, 32)
+	srv.True(res.IsActive)
+	srv.Equal([]string{"post.*"}, res.Events)
+	srv.repo.AssertExpectations(srv.T())
+
+	_, err = srv.service.Create(srv.ctx, dto.WebhookReq{URL: "https://example.com/hook", Events: []string{"asset.created"}})
+	srv.Error(err)
+}
+
+func (srv *WebhookServiceTestSuite) TestWebhookSrv_Update() {
+	srv.repo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(req dto.WebhookReq) bool {
+		return req.ID == 1 && req.Secret == ""
+	})).Return(&models.Webhook{ID: 1, URL: "https://example.com/hook", Secret: "kept", IsActive: true}, nil).Once()
+
+	res, err := srv.service.Update(srv.ctx, dto.WebhookReq{ID: 1, URL: "https://example.com/hook", Events: []string{"*"}})
+	srv.Require().NoError(err)
+	srv.Empty(res.Secret)
+	srv.repo.AssertExpectations(srv.T())
+}
+
+func (srv *WebhookServiceTestSuite) TestWebhookSrv_GetDeliveries_NotFound() {
+	srv.repo.On("GetDetail", mock.Anything, uint64(9)).Return(nil, helpers.ErrDataNotFound("webhook", "webhook")).Once()
+
+	_, err := srv.service.GetDeliveries(srv.ctx, dto.WebhookDeliveryListReq{WebhookID: 9})
+	srv.Error(err)
+	srv.repo.AssertExpectations(srv.T())
+}
+
+func (srv *WebhookServiceTestSuite) TestWebhookSrv_Enqueue() {
+	var (
+		occurredAt = time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+		e          = event.Event{ID: 7, Type: dto.TagEventMerged, AggregateType: dto.AggregateTag, AggregateID: 3, Payload: []byte(`{"target":null}`), OccurredAt: occurredAt}
+	)
+	srv.repo.On("GetActive", mock.Anything).Return([]models.Webhook{
+		{ID: 1, Events: models.StringList{"post.*"}},
+		{ID: 2, Events: models.StringList{"tag.*"}},
+		{ID: 3, Events: models.StringList{"*"}},
+	}, nil).Once()
+	srv.repo.On("CreateDeliveries", mock.Anything, mock.MatchedBy(func(deliveries []models.WebhookDelivery) bool {
+		if len(deliveries) != 2 || deliveries[0].WebhookID != 2 || deliveries[1].WebhookID != 3 {
+			return false
+		}
+		payload := dto.WebhookPayload{}
+		err := json.Unmarshal(deliveries[1].Payload, &payload)
+		return err == nil && payload.ID == 7 && payload.Type == dto.TagEventMerged && string(payload.Data) == `{"target":null}` &&
+			deliveries[1].EventID == 7 && deliveries[1].Status == dto.WebhookStatusPending
+	})).Return(nil).Once()
+
+	srv.NoError(srv.service.Enqueue(srv.ctx, e))
+	srv.repo.AssertExpectations(srv.T())
+}
+
+func (srv *WebhookServiceTestSuite) TestWebhookSrv_Deliver() {
+	srv.repo.On("ClaimDeliveries", mock.Anything, mock.Anything, mock.Anything, 10).Return([]models.WebhookDelivery{srv.delivery(1), srv.delivery(2)}, nil).Once()
+	srv.repo.On("SaveAttempt", mock.Anything, mock.MatchedBy(func(d *models.WebhookDelivery) bool {
+		return d.Status == dto.WebhookStatusSucceeded && d.Attempts == 1 && d.LastStatusCode == http.StatusOK && d.DeliveredAt != nil
+	})).Return(nil).Twice()
+
+	total, err := srv.service.Deliver(srv.ctx)
+	srv.Require().NoError(err)
+	srv.Equal(2, total)
+	srv.Len(srv.received, 2)
+
+	ids := map[string]bool{}
+	for _, v := range srv.received {
+		ids[v.Header.Get(webhook.HeaderID)] = true
+		srv.Equal(dto.PostEventCreated, v.Header.Get(webhook.HeaderEvent))
+	}
+	srv.Equal(map[string]bool{"1": true, "2": true}, ids)
+	srv.repo.AssertExpectations(srv.T())
+}
+
+func (srv *WebhookServiceTestSuite) TestWebhookSrv_Deliver_Retry() {
+	srv.status = http.StatusServiceUnavailable
+	delivery := srv.delivery(1)
+	delivery.Attempts = 1
+
+	srv.repo.On("ClaimDeliveries", mock.Anything, mock.Anything, mock.Anything, 10).Return([]models.WebhookDelivery{delivery}, nil).Once()
+	srv.repo.On("SaveAttempt", mock.Anything, mock.MatchedBy(func(d *models.WebhookDelivery) bool {
+		// second attempt: 10s doubled once
+		delay := time.Until(d.NextAttemptAt)
+		return d.Status == dto.WebhookStatusRetrying && d.Attempts == 2 && d.LastStatusCode == http.StatusServiceUnavailable &&
+			d.LastError != "" && delay > 19*time.Second && delay <= 20*time.Second
+	})).Return(nil).Once()
+
+	total, err := srv.service.Deliver(srv.ctx)
+	srv.Require().NoError(err)
+	srv.Equal(0, total)
+	srv.repo.AssertExpectations(srv.T())
+}
+
+func (srv *WebhookServiceTestSuite) TestWebhookSrv_Deliver_Dead() {
+	delivery := srv.delivery(1)
+	delivery.Attempts = 2
+	delivery.Webhook.Secret = "another-secret-value"
+
+	srv.repo.On("ClaimDeliveries", mock.Anything, mock.Anything, mock.Anything, 10).Return([]models.WebhookDelivery{delivery}, nil).Once()
+	srv.repo.On("SaveAttempt", mock.Anything, mock.MatchedBy(func(d *models.WebhookDelivery) bool {
+		return d.Status == dto.WebhookStatusDead && d.Attempts == 3 && d.LastStatusCode == http.StatusUnauthorized && d.DeliveredAt == nil
+	})).Return(nil).Once()
+
+	total, err := srv.service.Deliver(srv.ctx)
+	srv.Require().NoError(err)
+	srv.Equal(0, total)
+	srv.Empty(srv.received)
+	srv.repo.AssertExpectations(srv.T())
+}
+
+func (srv *WebhookServiceTestSuite) TestWebhookSrv_Deliver_Failed() {
+	srv.repo.On("ClaimDeliveries", mock.Anything, mock.Anything, mock.Anything, 10).Return(nil, helpers.ErrDB()).Once()
+
+	total, err := srv.service.Deliver(srv.ctx)
+	srv.Error(err)
+	srv.Equal(0, total)
+	srv.repo.AssertExpectations(srv.T())
+}
@@ -1,14 +1,25 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"net"
+	"os"
+	"time"
 
 	"github.com/adamnasrudin03/go-asset-findr/app"
 	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/adamnasrudin03/go-asset-findr/app/dto"
+	"github.com/adamnasrudin03/go-asset-findr/app/repository"
 	"github.com/adamnasrudin03/go-asset-findr/app/router"
+	"github.com/adamnasrudin03/go-asset-findr/app/rpc"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/cache"
 	"github.com/adamnasrudin03/go-asset-findr/pkg/database"
 	"github.com/adamnasrudin03/go-asset-findr/pkg/driver"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/event"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/scheduler"
+	"github.com/adamnasrudin03/go-asset-findr/pkg/storage"
 	"github.com/joho/godotenv"
 	"gorm.io/gorm"
 )
@@ -19,17 +30,100 @@ func main() {
 	}
 
 	var (
-		cfg                  = configs.GetInstance()
-		logger               = driver.Logger(cfg)
+		cfg    = configs.GetInstance()
+		logger = driver.Logger(cfg)
+	)
+	if cfg.App.SecretKey == "" {
+		logger.Fatalln("JWT_SECRET is not set")
+	}
+
+	var (
 		db          *gorm.DB = database.SetupDbConnection(cfg, logger)
-		repo                 = app.WiringRepository(db, cfg, logger)
-		services             = app.WiringService(repo, cfg, logger)
+		cacheStore           = cache.Setup(cfg, logger)
+		fileStore            = storage.Setup(cfg, logger)
+		publisher            = event.Setup(cfg, logger)
+		repo                 = app.WiringRepository(db, cacheStore, cfg, logger)
+		services             = app.WiringService(repo, fileStore, publisher, cfg, logger)
 		controllers          = app.WiringController(services, cfg, logger)
 	)
 
 	defer database.CloseDbConnection(db, logger)
 
-	r := router.NewRoutes(*controllers)
+	if cfg.DB.DbIsMigrate {
+		if err := repository.Migrate(db); err != nil {
+			logger.Panicf("Failed to migrate database, %v", err)
+		}
+		if total, err := repo.Post.BackfillSlugs(context.Background()); err == nil && total > 0 {
+			logger.Infof("assigned slugs to %d posts \n", total)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if len(os.Args) > 1 && os.Args[1] == "refresh-reports" {
+		res, err := services.Report.Refresh(ctx)
+		if err != nil {
+			logger.Errorf("failed refresh reports: %v \n", err)
+			return
+		}
+		logger.Infof("refreshed reports in %d ms \n", res.DurationMs)
+		return
+	}
+
+	go scheduler.Every(ctx, "publish-due-posts", time.Duration(cfg.App.PublishInterval)*time.Second, logger, func(ctx context.Context) error {
+		_, err := services.Post.PublishDue(ctx)
+		return err
+	})
+
+	services.Asset.StartWorkers(ctx)
+	go scheduler.Every(ctx, "enqueue-pending-assets", time.Duration(cfg.Storage.ProcessInterval)*time.Second, logger, func(ctx context.Context) error {
+		_, err := services.Asset.EnqueuePending(ctx)
+		return err
+	})
+	go scheduler.Every(ctx, "collect-storage-garbage", time.Duration(cfg.Storage.GCInterval)*time.Second, logger, func(ctx context.Context) error {
+		_, err := services.Asset.CollectGarbage(ctx, dto.StorageGCReq{})
+		return err
+	})
+
+	go scheduler.Every(ctx, "refresh-reports", time.Duration(cfg.App.ReportRefreshInterval)*time.Second, logger, func(ctx context.Context) error {
+		_, err := services.Report.Refresh(ctx)
+		return err
+	})
+
+	go scheduler.Every(ctx, "relay-outbox", time.Duration(cfg.Event.RelayInterval)*time.Second, logger, func(ctx context.Context) error {
+		_, err := services.Outbox.Relay(ctx)
+		return err
+	})
+	go database.Listen(ctx, database.DSN(cfg), dto.PostStreamChannel, logger, services.PostStream.Reset, func(payload string) {
+		services.PostStream.Receive(ctx, payload)
+	})
+
+	go scheduler.Every(ctx, "deliver-webhooks", time.Duration(cfg.Webhook.DeliverInterval)*time.Second, logger, func(ctx context.Context) error {
+		_, err := services.Webhook.Deliver(ctx)
+		return err
+	})
+	go scheduler.Every(ctx, "prune-outbox", time.Hour, logger, func(ctx context.Context) error {
+		_, err := services.Outbox.Prune(ctx)
+		return err
+	})
+
+	if cfg.App.GRPCPort != "" {
+		lis, err := net.Listen("tcp", fmt.Sprintf(":%v", cfg.App.GRPCPort))
+		if err != nil {
+			logger.Fatalf("failed listen grpc: %v \n", err)
+		}
+
+		server := rpc.New(services, cfg, logger)
+		defer server.GracefulStop()
+		go func() {
+			if err := server.Serve(lis); err != nil {
+				logger.Errorf("failed serve grpc: %v \n", err)
+			}
+		}()
+	}
+
+	r := router.NewRoutes(*controllers, cfg)
 
 	listen := fmt.Sprintf(":%v", cfg.App.Port)
 	r.Run(listen)
@@ -0,0 +1,38 @@
+package auth
+
+import "context"
+
+const (
+	RoleAdmin  = "admin"
+	RoleEditor = "editor"
+	RoleUser   = "user"
+)
+
+// Actor is the caller of a request, the zero value is an anonymous caller.
+type Actor struct {
+	ID       uint64 `json:"id"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+func (a Actor) IsAnonymous() bool {
+	return a.ID == 0
+}
+
+// IsEditor reports whether the actor may manage content of other users.
+func (a Actor) IsEditor() bool {
+	return a.Role == RoleEditor || a.Role == RoleAdmin
+}
+
+type actorKey struct{}
+
+// WithActor returns ctx carrying actor.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// FromContext returns the actor carried by ctx, anonymous when there is none.
+func FromContext(ctx context.Context) Actor {
+	actor, _ := ctx.Value(actorKey{}).(Actor)
+	return actor
+}
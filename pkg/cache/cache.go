@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrMiss is returned by Get when the key is absent or expired.
+var ErrMiss = errors.New("cache: miss")
+
+// Cache stores raw bytes by key, implementations must be safe for
+// concurrent use.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// Setup creates the cache chosen by CACHE_DRIVER, it returns nil when caching
+// is disabled.
+func Setup(cfg *configs.Configs, logger *logrus.Logger) Cache {
+	switch cfg.Cache.Driver {
+	case "memory":
+		logger.Info("Cache in memory enabled")
+		return NewMemory(cfg.Cache.Size)
+	case "redis":
+		c, err := NewRedis(cfg.Cache.RedisAddr, cfg.Cache.RedisPassword, cfg.Cache.RedisDB)
+		if err != nil {
+			logger.Panicf("Failed to create a connection to redis , %v", err)
+			return nil
+		}
+		logger.Info("Connection Redis Success!")
+		return c
+	default:
+		return nil
+	}
+}
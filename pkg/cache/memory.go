@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+type memoryItem struct {
+	key       string
+	value     []byte
+	expiredAt time.Time
+}
+
+// Memory is an in process LRU cache, each item also expires after its ttl.
+type Memory struct {
+	mu    sync.Mutex
+	size  int
+	items map[string]*list.Element
+	order *list.List // front is the most recently used
+	now   func() time.Time
+}
+
+// NewMemory creates a LRU holding at most size items, size <= 0 means 1000.
+func NewMemory(size int) *Memory {
+	if size <= 0 {
+		size = 1000
+	}
+	return &Memory{
+		size:  size,
+		items: map[string]*list.Element{},
+		order: list.New(),
+		now:   time.Now,
+	}
+}
+
+func (m *Memory) Get(ctx context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.items[key]
+	if !ok {
+		return nil, ErrMiss
+	}
+
+	item := el.Value.(*memoryItem)
+	if !item.expiredAt.IsZero() && !m.now().Before(item.expiredAt) {
+		m.remove(el)
+		return nil, ErrMiss
+	}
+
+	m.order.MoveToFront(el)
+	return item.value, nil
+}
+
+func (m *Memory) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item := &memoryItem{key: key, value: value}
+	if ttl > 0 {
+		item.expiredAt = m.now().Add(ttl)
+	}
+
+	if el, ok := m.items[key]; ok {
+		el.Value = item
+		m.order.MoveToFront(el)
+		return nil
+	}
+
+	m.items[key] = m.order.PushFront(item)
+	for m.order.Len() > m.size {
+		m.remove(m.order.Back())
+	}
+	return nil
+}
+
+func (m *Memory) Delete(ctx context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		if el, ok := m.items[key]; ok {
+			m.remove(el)
+		}
+	}
+	return nil
+}
+
+func (m *Memory) DeletePrefix(ctx context.Context, prefix string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, el := range m.items {
+		if strings.HasPrefix(key, prefix) {
+			m.remove(el)
+		}
+	}
+	return nil
+}
+
+func (m *Memory) remove(el *list.Element) {
+	m.order.Remove(el)
+	delete(m.items, el.Value.(*memoryItem).key)
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemory_LRU(t *testing.T) {
+	var (
+		ctx = context.Background()
+		m   = NewMemory(2)
+	)
+
+	m.Set(ctx, "a", []byte("1"), 0)
+	m.Set(ctx, "b", []byte("2"), 0)
+	m.Get(ctx, "a") // a is now the most recently used
+	m.Set(ctx, "c", []byte("3"), 0)
+
+	if _, err := m.Get(ctx, "b"); !errors.Is(err, ErrMiss) {
+		t.Errorf("Get(b) error = %v, want ErrMiss", err)
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, err := m.Get(ctx, key); err != nil {
+			t.Errorf("Get(%s) error = %v", key, err)
+		}
+	}
+}
+
+func TestMemory_TTL(t *testing.T) {
+	var (
+		ctx = context.Background()
+		m   = NewMemory(10)
+		now = time.Now()
+	)
+	m.now = func() time.Time { return now }
+
+	m.Set(ctx, "a", []byte("1"), time.Minute)
+	if _, err := m.Get(ctx, "a"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := m.Get(ctx, "a"); !errors.Is(err, ErrMiss) {
+		t.Errorf("Get() after ttl error = %v, want ErrMiss", err)
+	}
+}
+
+func TestMemory_DeletePrefix(t *testing.T) {
+	var (
+		ctx = context.Background()
+		m   = NewMemory(10)
+	)
+
+	m.Set(ctx, "post:1", []byte("1"), 0)
+	m.Set(ctx, "post:2", []byte("2"), 0)
+	m.Set(ctx, "tag:1", []byte("3"), 0)
+	m.DeletePrefix(ctx, "post:")
+
+	if _, err := m.Get(ctx, "post:1"); !errors.Is(err, ErrMiss) {
+		t.Errorf("Get(post:1) error = %v, want ErrMiss", err)
+	}
+	if _, err := m.Get(ctx, "tag:1"); err != nil {
+		t.Errorf("Get(tag:1) error = %v", err)
+	}
+}
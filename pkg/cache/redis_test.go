@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestRedis(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		server = miniredis.RunT(t)
+	)
+
+	r, err := NewRedis(server.Addr(), "", 0)
+	if err != nil {
+		t.Fatalf("NewRedis() error = %v", err)
+	}
+
+	if _, err := r.Get(ctx, "post:1"); !errors.Is(err, ErrMiss) {
+		t.Errorf("Get() error = %v, want ErrMiss", err)
+	}
+
+	r.Set(ctx, "post:1", []byte("1"), time.Minute)
+	r.Set(ctx, "post:2", []byte("2"), 0)
+	r.Set(ctx, "tag:1", []byte("3"), 0)
+
+	got, err := r.Get(ctx, "post:1")
+	if err != nil || string(got) != "1" {
+		t.Errorf("Get() = %s, %v, want 1", got, err)
+	}
+
+	server.FastForward(time.Minute)
+	if _, err := r.Get(ctx, "post:1"); !errors.Is(err, ErrMiss) {
+		t.Errorf("Get() after ttl error = %v, want ErrMiss", err)
+	}
+
+	if err := r.DeletePrefix(ctx, "post:"); err != nil {
+		t.Fatalf("DeletePrefix() error = %v", err)
+	}
+	if server.Exists("post:1") || !server.Exists("tag:1") {
+		t.Errorf("DeletePrefix() keys left = %v", server.Keys())
+	}
+}
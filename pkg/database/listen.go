@@ -0,0 +1,54 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sirupsen/logrus"
+)
+
+// listenRetry is how long Listen waits before connecting again.
+const listenRetry = 5 * time.Second
+
+// Listen receives the notifications of channel on a connection of its own,
+// outside of the pool, and hands their payload to handle until ctx is done.
+// A lost connection is opened again, onListen is called every time it
+// listens so the caller can deal with the notifications sent in between.
+func Listen(ctx context.Context, dsn, channel string, logger *logrus.Logger, onListen func(), handle func(payload string)) {
+	for {
+		err := listen(ctx, dsn, channel, onListen, handle)
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Errorf("listen %s failed, retry in %v: %v \n", channel, listenRetry, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetry):
+		}
+	}
+}
+
+func listen(ctx context.Context, dsn, channel string, onListen func(), handle func(payload string)) error {
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.WithoutCancel(ctx))
+
+	_, err = conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize())
+	if err != nil {
+		return err
+	}
+	onListen()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		handle(notification.Payload)
+	}
+}
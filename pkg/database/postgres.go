@@ -8,7 +8,6 @@ import (
 	"time"
 
 	"github.com/adamnasrudin03/go-asset-findr/app/configs"
-	"github.com/adamnasrudin03/go-asset-findr/app/models"
 	"github.com/sirupsen/logrus"
 
 	"gorm.io/driver/postgres"
@@ -22,6 +21,16 @@ var (
 	mu  = &sync.Mutex{}
 )
 
+// DSN returns the connection string of the primary database.
+func DSN(cfg *configs.Configs) string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
+		cfg.DB.Host,
+		cfg.DB.Username,
+		cfg.DB.Password,
+		cfg.DB.DbName,
+		cfg.DB.Port)
+}
+
 // SetupDbConnection is creating a new connection to our database
 func SetupDbConnection(cfg *configs.Configs, logger *logrus.Logger) *gorm.DB {
 	mu.Lock()
@@ -46,14 +55,7 @@ func SetupDbConnection(cfg *configs.Configs, logger *logrus.Logger) *gorm.DB {
 		Logger:                 dbLogger,
 	}
 
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
-		cfg.DB.Host,
-		cfg.DB.Username,
-		cfg.DB.Password,
-		cfg.DB.DbName,
-		cfg.DB.Port)
-
-	db, err = gorm.Open(postgres.Open(dsn), gormConfig)
+	db, err = gorm.Open(postgres.Open(DSN(cfg)), gormConfig)
 	if err != nil {
 		logger.Panicf("Failed to create a connection to database , %v", err)
 		return nil
@@ -69,13 +71,13 @@ func SetupDbConnection(cfg *configs.Configs, logger *logrus.Logger) *gorm.DB {
 		return nil
 	}
 
-	if cfg.DB.DbIsMigrate {
-		//auto migration entity db
-		db.AutoMigrate(
-			&models.Post{},
-			&models.Tag{},
-			&models.PostTag{},
-		)
+	if err := registerReplicas(db, cfg); err != nil {
+		logger.Panicf("Failed to register database replicas , %v", err)
+		return nil
+	}
+	if err := registerWriteTracker(db); err != nil {
+		logger.Panicf("Failed to register database write tracker , %v", err)
+		return nil
 	}
 
 	logger.Info("Connection Database Success!")
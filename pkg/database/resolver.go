@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+type (
+	readPrimaryKey  struct{}
+	writeTrackerKey struct{}
+)
+
+// registerReplicas routes plain SELECT queries to the configured replicas,
+// writes and transactions keep using the primary connection.
+func registerReplicas(db *gorm.DB, cfg *configs.Configs) error {
+	if len(cfg.DB.ReplicaDSNs) == 0 {
+		return nil
+	}
+
+	replicas := make([]gorm.Dialector, 0, len(cfg.DB.ReplicaDSNs))
+	for _, dsn := range cfg.DB.ReplicaDSNs {
+		replicas = append(replicas, postgres.Open(dsn))
+	}
+
+	return db.Use(dbresolver.Register(dbresolver.Config{
+		Replicas:          replicas,
+		Policy:            dbresolver.RandomPolicy{},
+		TraceResolverMode: cfg.App.Env == "dev",
+	}))
+}
+
+// WithReadPrimary marks ctx so every read made with it goes to the primary,
+// used to read your own writes right after a mutation.
+func WithReadPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readPrimaryKey{}, true)
+}
+
+// IsReadPrimary reports whether ctx was marked by WithReadPrimary.
+func IsReadPrimary(ctx context.Context) bool {
+	val, _ := ctx.Value(readPrimaryKey{}).(bool)
+	return val
+}
+
+// Reader returns a query for ctx, bound to a replica unless ctx asks to read
+// from the primary. Without replicas configured it is always the primary.
+func Reader(ctx context.Context, db *gorm.DB) *gorm.DB {
+	query := db.WithContext(ctx)
+	if IsReadPrimary(ctx) {
+		query = query.Clauses(dbresolver.Write)
+	}
+	return query
+}
+
+// WithWriteTracker returns ctx recording whether a query made with it has
+// written to the database, see HasWritten.
+func WithWriteTracker(ctx context.Context) context.Context {
+	return context.WithValue(ctx, writeTrackerKey{}, new(atomic.Bool))
+}
+
+// HasWritten reports whether a create, update, delete or exec made with ctx
+// succeeded. It is false when ctx was not made by WithWriteTracker.
+func HasWritten(ctx context.Context) bool {
+	written, ok := ctx.Value(writeTrackerKey{}).(*atomic.Bool)
+	return ok && written.Load()
+}
+
+// MarkWritten records on the tracker of ctx that it has written, for writes
+// made outside of gorm. It does nothing without a tracker.
+func MarkWritten(ctx context.Context) {
+	if written, ok := ctx.Value(writeTrackerKey{}).(*atomic.Bool); ok {
+		written.Store(true)
+	}
+}
+
+// registerWriteTracker marks the tracker of the statement context after each
+// successful write, a failed one leaves it as is.
+func registerWriteTracker(db *gorm.DB) error {
+	track := func(tx *gorm.DB) {
+		if tx.Error == nil && tx.Statement.Context != nil {
+			MarkWritten(tx.Statement.Context)
+		}
+	}
+
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().After("gorm:create").Register("findr:track_write", track),
+		callbacks.Update().After("gorm:update").Register("findr:track_write", track),
+		callbacks.Delete().After("gorm:delete").Register("findr:track_write", track),
+		callbacks.Raw().After("gorm:raw").Register("findr:track_write", track),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
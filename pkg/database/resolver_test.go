@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type trackedRow struct {
+	ID   uint64
+	Name string
+}
+
+func TestWriteTracker(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 port=1"}), &gorm.Config{
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open() error = %v", err)
+	}
+	if err := registerWriteTracker(db); err != nil {
+		t.Fatalf("registerWriteTracker() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		query func(db *gorm.DB) error
+		want  bool
+	}{
+		{
+			name:  "select",
+			query: func(db *gorm.DB) error { return db.Find(&[]trackedRow{}).Error },
+			want:  false,
+		},
+		{
+			name:  "create",
+			query: func(db *gorm.DB) error { return db.Create(&trackedRow{Name: "a"}).Error },
+			want:  true,
+		},
+		{
+			name:  "update",
+			query: func(db *gorm.DB) error { return db.Model(&trackedRow{ID: 1}).Update("name", "b").Error },
+			want:  true,
+		},
+		{
+			name:  "delete",
+			query: func(db *gorm.DB) error { return db.Delete(&trackedRow{ID: 1}).Error },
+			want:  true,
+		},
+		{
+			name:  "exec",
+			query: func(db *gorm.DB) error { return db.Exec("UPDATE tracked_row SET name = ?", "c").Error },
+			want:  true,
+		},
+		{
+			name: "failed",
+			query: func(db *gorm.DB) error {
+				tx := db.Session(&gorm.Session{})
+				tx.AddError(errors.New("failed"))
+				tx.Create(&trackedRow{Name: "d"})
+				return nil
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := WithWriteTracker(context.Background())
+			if err := tt.query(db.WithContext(ctx)); err != nil {
+				t.Fatalf("query error = %v", err)
+			}
+			if got := HasWritten(ctx); got != tt.want {
+				t.Errorf("HasWritten() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if HasWritten(context.Background()) {
+		t.Error("HasWritten() without tracker = true, want false")
+	}
+}
@@ -0,0 +1,83 @@
+package dataloader
+
+import (
+	"context"
+	"sync"
+)
+
+// Fetch returns the values of keys at once, a key missing from the result
+// has the zero value.
+type Fetch[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Thunk returns the value of a key, fetching it with every key loaded
+// before it the first time one of them is asked.
+type Thunk[V any] func() (V, error)
+
+// Loader batches and caches the loads of one request. Keys loaded while a
+// level of a query is resolved are fetched with one call of Fetch when the
+// first of their thunks runs, instead of one call per key.
+type Loader[K comparable, V any] struct {
+	fetch   Fetch[K, V]
+	mu      sync.Mutex
+	fetchMu sync.Mutex
+	pending []K
+	queued  map[K]bool
+	done    map[K]result[V]
+}
+
+type result[V any] struct {
+	value V
+	err   error
+}
+
+func New[K comparable, V any](fetch Fetch[K, V]) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:  fetch,
+		queued: map[K]bool{},
+		done:   map[K]result[V]{},
+	}
+}
+
+// Load queues key and returns its thunk, a key already fetched is not
+// fetched again.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) Thunk[V] {
+	l.mu.Lock()
+	if _, ok := l.done[key]; !ok && !l.queued[key] {
+		l.queued[key] = true
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (V, error) {
+		l.dispatch(ctx)
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		res := l.done[key]
+		return res.value, res.err
+	}
+}
+
+// dispatch fetches the pending keys. Only one fetch runs at a time, so a
+// thunk waits for the batch holding its key.
+func (l *Loader[K, V]) dispatch(ctx context.Context) {
+	l.fetchMu.Lock()
+	defer l.fetchMu.Unlock()
+
+	l.mu.Lock()
+	keys := l.pending
+	l.pending = nil
+	l.mu.Unlock()
+	if len(keys) == 0 {
+		return
+	}
+
+	values, err := l.fetch(ctx, keys)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, k := range keys {
+		delete(l.queued, k)
+		l.done[k] = result[V]{value: values[k], err: err}
+	}
+}
@@ -0,0 +1,70 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestLoader_Batch(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		batches = [][]int{}
+		loader  = New(func(ctx context.Context, keys []int) (map[int]string, error) {
+			batches = append(batches, keys)
+			result := map[int]string{}
+			for _, k := range keys {
+				if k != 3 {
+					result[k] = string(rune('a' + k))
+				}
+			}
+			return result, nil
+		})
+	)
+
+	thunks := []Thunk[string]{loader.Load(ctx, 1), loader.Load(ctx, 2), loader.Load(ctx, 1), loader.Load(ctx, 3)}
+	got := []string{}
+	for _, v := range thunks {
+		value, err := v()
+		if err != nil {
+			t.Fatalf("thunk error = %v", err)
+		}
+		got = append(got, value)
+	}
+	if !reflect.DeepEqual(got, []string{"b", "c", "b", ""}) {
+		t.Errorf("values = %q", got)
+	}
+
+	// cached keys are not fetched again, new ones make a new batch
+	value, _ := loader.Load(ctx, 2)()
+	if value != "c" {
+		t.Errorf("cached value = %q, want c", value)
+	}
+	value, _ = loader.Load(ctx, 4)()
+	if value != "e" {
+		t.Errorf("value = %q, want e", value)
+	}
+
+	if want := [][]int{{1, 2, 3}, {4}}; !reflect.DeepEqual(batches, want) {
+		t.Errorf("batches = %v, want %v", batches, want)
+	}
+}
+
+func TestLoader_Error(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		errDB  = errors.New("db error")
+		loader = New(func(ctx context.Context, keys []int) (map[int]int, error) {
+			return nil, errDB
+		})
+	)
+
+	a, b := loader.Load(ctx, 1), loader.Load(ctx, 2)
+	if _, err := a(); !errors.Is(err, errDB) {
+		t.Errorf("a error = %v, want %v", err, errDB)
+	}
+	if _, err := b(); !errors.Is(err, errDB) {
+		t.Errorf("b error = %v, want %v", err, errDB)
+	}
+}
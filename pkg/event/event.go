@@ -0,0 +1,56 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/sirupsen/logrus"
+)
+
+// Event is a change of an aggregate, e.g. post.updated of a post, as relayed
+// from the outbox. ID is unique and grows with the changes of an aggregate,
+// consumers use it to drop the events they have already seen. Seq is the
+// order it was published in, only set on events read back once published.
+type Event struct {
+	ID            uint64          `json:"id"`
+	Seq           uint64          `json:"seq,omitempty"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   uint64          `json:"aggregate_id"`
+	Payload       json.RawMessage `json:"payload"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+}
+
+// Publisher delivers events to other systems. An event whose Publish failed
+// is published again later, and so may one that succeeded, so delivery is
+// at least once. Implementations must be safe for concurrent use.
+type Publisher interface {
+	Publish(ctx context.Context, e Event) error
+}
+
+// Handler consumes the events published in process.
+type Handler func(ctx context.Context, e Event) error
+
+// Subscriber hands the events published to handlers in this process too,
+// whatever other systems they are delivered to.
+type Subscriber interface {
+	Subscribe(handler Handler)
+}
+
+// Broker is a Publisher the webhooks and post streams of this process can
+// subscribe to, every publisher must be one.
+type Broker interface {
+	Publisher
+	Subscriber
+}
+
+// Setup creates the publisher chosen by EVENT_PUBLISHER.
+func Setup(cfg *configs.Configs, logger *logrus.Logger) Broker {
+	switch cfg.Event.Publisher {
+	default:
+		logger.Info("Event publisher in memory enabled")
+		return NewMemory()
+	}
+}
@@ -0,0 +1,75 @@
+package event
+
+import "sync"
+
+// Hub fans the events broadcast in this process out to its subscribers,
+// e.g. the clients of a stream. A subscriber too slow to keep up with buffer
+// events is dropped, its channel is closed so it can resume from its last
+// event.
+type Hub struct {
+	mu     sync.Mutex
+	buffer int
+	subs   map[chan Event]struct{}
+}
+
+func NewHub(buffer int) *Hub {
+	if buffer <= 0 {
+		buffer = 1
+	}
+	return &Hub{buffer: buffer, subs: map[chan Event]struct{}{}}
+}
+
+// Subscribe returns the channel of the events broadcast from now on and the
+// function ending the subscription.
+func (h *Hub) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, h.buffer)
+
+	h.mu.Lock()
+	h.subs[ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.drop(ch)
+	}
+}
+
+// Broadcast hands e to every subscriber without waiting for any of them.
+func (h *Hub) Broadcast(e Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subs {
+		select {
+		case ch <- e:
+		default:
+			h.drop(ch)
+		}
+	}
+}
+
+// CloseAll ends every subscription, e.g. when events may have been missed.
+func (h *Hub) CloseAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subs {
+		h.drop(ch)
+	}
+}
+
+// Len returns how many subscribers there are.
+func (h *Hub) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.subs)
+}
+
+func (h *Hub) drop(ch chan Event) {
+	if _, ok := h.subs[ch]; ok {
+		delete(h.subs, ch)
+		close(ch)
+	}
+}
@@ -0,0 +1,46 @@
+package event
+
+import "testing"
+
+func TestHub(t *testing.T) {
+	hub := NewHub(2)
+
+	fast, cancelFast := hub.Subscribe()
+	slow, _ := hub.Subscribe()
+	if hub.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", hub.Len())
+	}
+
+	for i := uint64(1); i <= 3; i++ {
+		hub.Broadcast(Event{ID: i})
+		if i < 3 {
+			if got := <-fast; got.ID != i {
+				t.Errorf("fast got event %d, want %d", got.ID, i)
+			}
+		}
+	}
+
+	// the slow one got the first 2 and was dropped on the third
+	got := []uint64{}
+	for e := range slow {
+		got = append(got, e.ID)
+	}
+	if len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Errorf("slow got %v, want [1 2] then closed", got)
+	}
+	if e := <-fast; e.ID != 3 {
+		t.Errorf("fast got event %d, want 3", e.ID)
+	}
+
+	cancelFast()
+	cancelFast()
+	if _, ok := <-fast; ok {
+		t.Errorf("fast is open after cancel")
+	}
+
+	again, _ := hub.Subscribe()
+	hub.CloseAll()
+	if _, ok := <-again; ok || hub.Len() != 0 {
+		t.Errorf("CloseAll() left %d subscribers", hub.Len())
+	}
+}
@@ -0,0 +1,41 @@
+package event
+
+import (
+	"context"
+	"sync"
+)
+
+// Memory publishes events to the handlers subscribed in this process, in
+// the order they subscribed. An event nobody subscribed to is dropped.
+type Memory struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+// Subscribe adds handler to every event published from now on.
+func (m *Memory) Subscribe(handler Handler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.handlers = append(m.handlers, handler)
+}
+
+// Publish hands e to every handler and fails with the first handler failing,
+// the handlers after it do not get e until it is published again.
+func (m *Memory) Publish(ctx context.Context, e Event) error {
+	m.mu.RLock()
+	handlers := m.handlers
+	m.mu.RUnlock()
+
+	for _, handler := range handlers {
+		err := handler(ctx, e)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMemory_Publish(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		m     = NewMemory()
+		got   = []string{}
+		isBad = true
+	)
+
+	if err := m.Publish(ctx, Event{ID: 1}); err != nil {
+		t.Fatalf("Publish() without handler error = %v", err)
+	}
+
+	m.Subscribe(func(ctx context.Context, e Event) error {
+		got = append(got, "first:"+e.Type)
+		return nil
+	})
+	m.Subscribe(func(ctx context.Context, e Event) error {
+		if isBad {
+			return errors.New("unavailable")
+		}
+		got = append(got, "second:"+e.Type)
+		return nil
+	})
+
+	if err := m.Publish(ctx, Event{ID: 2, Type: "post.created"}); err == nil {
+		t.Errorf("Publish() error = nil, want the handler error")
+	}
+	isBad = false
+	if err := m.Publish(ctx, Event{ID: 2, Type: "post.created"}); err != nil {
+		t.Errorf("Publish() error = %v", err)
+	}
+
+	want := []string{"first:post.created", "first:post.created", "second:post.created"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("handled = %v, want %v", got, want)
+	}
+}
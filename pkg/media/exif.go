@@ -0,0 +1,135 @@
+package media
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+const tagGPSInfo = 0x8825
+
+// ErrTruncated is returned by StripGPS when data ends before the EXIF
+// segment does, or before the image data when there is none.
+var ErrTruncated = errors.New("media: jpeg ends before its exif data")
+
+// StripGPS blanks the GPS block of the EXIF data of a JPEG in place. The
+// size of data does not change, so it works on the head of a stream, a head
+// too short to hold the whole EXIF segment returns ErrTruncated. It reports
+// whether GPS data was found.
+func StripGPS(data []byte) (bool, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return false, nil
+	}
+
+	for i := 2; ; {
+		if i+4 > len(data) {
+			return false, ErrTruncated
+		}
+		if data[i] != 0xFF {
+			return false, nil
+		}
+		marker := data[i+1]
+		if marker == 0xFF { // fill byte
+			i++
+			continue
+		}
+		if marker == 0xDA || marker == 0xD9 { // start of scan, end of image
+			return false, nil
+		}
+
+		end := i + 2 + int(binary.BigEndian.Uint16(data[i+2:]))
+		if end < i+4 {
+			return false, nil
+		}
+		if end > len(data) {
+			return false, ErrTruncated
+		}
+		segment := data[i+4 : end]
+		if marker == 0xE1 && len(segment) >= 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return stripTIFFGPS(segment[6:]), nil
+		}
+		i = end
+	}
+}
+
+// stripTIFFGPS empties the GPS IFD of a TIFF structure and zeroes the
+// values it pointed to.
+func stripTIFFGPS(data []byte) bool {
+	if len(data) < 8 {
+		return false
+	}
+
+	var order binary.ByteOrder
+	switch string(data[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return false
+	}
+
+	gps, ok := findIFDTag(data, order, int(order.Uint32(data[4:])), tagGPSInfo)
+	if !ok {
+		return false
+	}
+
+	total, ok := ifdLen(data, order, gps)
+	if !ok || total == 0 {
+		return false
+	}
+	for k := 0; k < total; k++ {
+		entry := data[gps+2+k*12:]
+		size := int64(tiffTypeSize(order.Uint16(entry[2:]))) * int64(order.Uint32(entry[4:]))
+		if size <= 4 {
+			continue
+		}
+		offset := int64(order.Uint32(entry[8:]))
+		if offset+size <= int64(len(data)) {
+			clear(data[offset : offset+size])
+		}
+	}
+	// no entries and no next IFD
+	clear(data[gps : gps+2+total*12+4])
+	return true
+}
+
+func findIFDTag(data []byte, order binary.ByteOrder, ifd int, tag uint16) (int, bool) {
+	total, ok := ifdLen(data, order, ifd)
+	if !ok {
+		return 0, false
+	}
+
+	for k := 0; k < total; k++ {
+		entry := data[ifd+2+k*12:]
+		if order.Uint16(entry) == tag {
+			return int(order.Uint32(entry[8:])), true
+		}
+	}
+	return 0, false
+}
+
+// ifdLen returns the entry count of the IFD at offset, if the IFD with its
+// next IFD pointer fits in data.
+func ifdLen(data []byte, order binary.ByteOrder, offset int) (int, bool) {
+	if offset < 8 || offset+2 > len(data) {
+		return 0, false
+	}
+
+	total := int(order.Uint16(data[offset:]))
+	return total, offset+2+total*12+4 <= len(data)
+}
+
+func tiffTypeSize(typ uint16) int {
+	switch typ {
+	case 1, 2, 6, 7: // byte, ascii, sbyte, undefined
+		return 1
+	case 3, 8: // short, sshort
+		return 2
+	case 4, 9, 11: // long, slong, float
+		return 4
+	case 5, 10, 12: // rational, srational, double
+		return 8
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,157 @@
+// Package media reads metadata of uploaded files and makes image
+// thumbnails.
+package media
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	_ "image/gif" // registers the gif decoder
+	"image/jpeg"
+	"image/png"
+	"regexp"
+	"strings"
+
+	"github.com/rwcarlsen/goexif/exif"
+	"github.com/rwcarlsen/goexif/tiff"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // registers the webp decoder
+)
+
+const (
+	// MaxPixels is the largest image Decode accepts, bigger ones are
+	// likely decompression bombs.
+	MaxPixels = 50_000_000
+
+	maxExifValue = 256
+)
+
+// ErrTooLarge is returned by Decode for images over MaxPixels.
+var ErrTooLarge = errors.New("media: image too large")
+
+type Metadata struct {
+	Width     int               `json:"width,omitempty"`
+	Height    int               `json:"height,omitempty"`
+	PageCount int               `json:"page_count,omitempty"`
+	EXIF      map[string]string `json:"exif,omitempty"`
+}
+
+// Extract reads the metadata of data by its sniffed mimeType. Unknown types
+// give empty metadata. A PDF page count of 0 means it could not be counted.
+func Extract(mimeType string, data []byte) (Metadata, error) {
+	result := Metadata{}
+
+	switch {
+	case strings.HasPrefix(mimeType, "image/"):
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return result, err
+		}
+		result.Width, result.Height = cfg.Width, cfg.Height
+
+		if mimeType == "image/jpeg" {
+			result.EXIF = readEXIF(data)
+		}
+	case mimeType == "application/pdf":
+		result.PageCount = countPDFPages(data)
+	}
+
+	return result, nil
+}
+
+type exifWalker map[string]string
+
+func (w exifWalker) Walk(name exif.FieldName, tag *tiff.Tag) error {
+	value := ""
+	if tag.Format() == tiff.StringVal {
+		value, _ = tag.StringVal()
+	} else {
+		value = tag.String()
+	}
+
+	value = strings.TrimSpace(strings.Trim(value, "\x00"))
+	if value == "" || len(value) > maxExifValue || strings.HasSuffix(string(name), "IFDPointer") {
+		return nil
+	}
+	w[string(name)] = value
+	return nil
+}
+
+// readEXIF returns the EXIF fields of a JPEG, nil when it has none.
+func readEXIF(data []byte) map[string]string {
+	x, err := exif.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil
+	}
+
+	result := exifWalker{}
+	x.Walk(result)
+	// a vendor specific binary blob
+	delete(result, string(exif.MakerNote))
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// pdfPageRe matches page objects, not the /Pages tree nodes.
+var pdfPageRe = regexp.MustCompile(`/Type\s*/Page(?:[^s]|$)`)
+
+// countPDFPages counts the uncompressed page objects, pages kept in
+// compressed object streams are not seen.
+func countPDFPages(data []byte) int {
+	return len(pdfPageRe.FindAllIndex(data, -1))
+}
+
+// Decode decodes an image, refusing images over MaxPixels before their
+// pixels are allocated.
+func Decode(data []byte) (image.Image, string, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+	if cfg.Width*cfg.Height > MaxPixels {
+		return nil, "", ErrTooLarge
+	}
+
+	return image.Decode(bytes.NewReader(data))
+}
+
+// Thumbnail scales img down to fit in a size x size square, keeping its
+// aspect ratio. Smaller images are returned as is.
+func Thumbnail(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= size && height <= size {
+		return img
+	}
+
+	if width >= height {
+		width, height = size, max(1, height*size/width)
+	} else {
+		width, height = max(1, width*size/height), size
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+	return dst
+}
+
+// Encode writes a thumbnail of an image decoded from format as PNG when the
+// source may be transparent, or as JPEG otherwise. It returns the MIME type
+// used.
+func Encode(img image.Image, format string) ([]byte, string, error) {
+	var (
+		buf bytes.Buffer
+		err error
+	)
+
+	switch format {
+	case "png", "gif", "webp":
+		err = png.Encode(&buf, img)
+		return buf.Bytes(), "image/png", err
+	default:
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85})
+		return buf.Bytes(), "image/jpeg", err
+	}
+}
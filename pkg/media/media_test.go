@@ -0,0 +1,161 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// testTIFF builds little endian EXIF data with a Make tag and a GPS IFD.
+func testTIFF() []byte {
+	var (
+		buf = &bytes.Buffer{}
+		le  = binary.LittleEndian
+	)
+	write := func(v ...any) {
+		for _, x := range v {
+			binary.Write(buf, le, x)
+		}
+	}
+
+	write([]byte("II"), uint16(42), uint32(8))
+	// IFD0: Make and the GPS IFD pointer
+	write(uint16(2))
+	write(uint16(0x010F), uint16(2), uint32(5), uint32(38))
+	write(uint16(tagGPSInfo), uint16(4), uint32(1), uint32(44))
+	write(uint32(0))
+	write([]byte("Test\x00\x00"))
+	// GPS IFD: latitude ref inline and latitude out of line
+	write(uint16(2))
+	write(uint16(0x0001), uint16(2), uint32(2), []byte("N\x00\x00\x00"))
+	write(uint16(0x0002), uint16(5), uint32(3), uint32(74))
+	write(uint32(0))
+	write(uint32(1), uint32(1), uint32(2), uint32(1), uint32(3), uint32(1))
+	return buf.Bytes()
+}
+
+func testJPEG(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+
+	buf := &bytes.Buffer{}
+	if err := jpeg.Encode(buf, img, nil); err != nil {
+		t.Fatalf("jpeg.Encode() error = %v", err)
+	}
+
+	app1 := append([]byte("Exif\x00\x00"), testTIFF()...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(app1)+2))
+
+	data := buf.Bytes()
+	result := append([]byte{}, data[:2]...)
+	result = append(result, segment...)
+	result = append(result, app1...)
+	return append(result, data[2:]...)
+}
+
+func TestExtract(t *testing.T) {
+	pngData := &bytes.Buffer{}
+	png.Encode(pngData, image.NewRGBA(image.Rect(0, 0, 30, 20)))
+
+	pdf := "%PDF-1.4\n1 0 obj << /Type /Pages /Count 2 >> endobj\n" +
+		"2 0 obj << /Type /Page >> endobj\n3 0 obj <</Type/Page/Parent 1 0 R>> endobj"
+
+	tests := []struct {
+		name     string
+		mimeType string
+		data     []byte
+		want     Metadata
+		wantExif []string
+	}{
+		{name: "jpeg with exif", mimeType: "image/jpeg", data: testJPEG(t, 40, 10), want: Metadata{Width: 40, Height: 10}, wantExif: []string{"Make", "GPSLatitudeRef", "GPSLatitude"}},
+		{name: "png", mimeType: "image/png", data: pngData.Bytes(), want: Metadata{Width: 30, Height: 20}},
+		{name: "pdf", mimeType: "application/pdf", data: []byte(pdf), want: Metadata{PageCount: 2}},
+		{name: "text", mimeType: "text/plain", data: []byte("hello")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Extract(tt.mimeType, tt.data)
+			if err != nil {
+				t.Fatalf("Extract() error = %v", err)
+			}
+			if got.Width != tt.want.Width || got.Height != tt.want.Height || got.PageCount != tt.want.PageCount {
+				t.Errorf("Extract() = %+v, want %+v", got, tt.want)
+			}
+			if len(got.EXIF) != len(tt.wantExif) {
+				t.Errorf("Extract() exif = %v, want %v", got.EXIF, tt.wantExif)
+			}
+			for _, name := range tt.wantExif {
+				if _, ok := got.EXIF[name]; !ok {
+					t.Errorf("Extract() exif = %v, want %s", got.EXIF, name)
+				}
+			}
+		})
+	}
+}
+
+func TestStripGPS(t *testing.T) {
+	data := testJPEG(t, 8, 8)
+	size := len(data)
+
+	// the head of the stream misses the end of the exif segment
+	if _, err := StripGPS(append([]byte{}, data[:40]...)); !errors.Is(err, ErrTruncated) {
+		t.Fatalf("StripGPS() on a short head error = %v, want ErrTruncated", err)
+	}
+
+	if isStripped, err := StripGPS(data); !isStripped || err != nil {
+		t.Fatalf("StripGPS() = %v, %v, want GPS found", isStripped, err)
+	}
+	if len(data) != size {
+		t.Errorf("StripGPS() changed size %d to %d", size, len(data))
+	}
+
+	got, err := Extract("image/jpeg", data)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	for name := range got.EXIF {
+		if strings.HasPrefix(name, "GPS") {
+			t.Errorf("Extract() after StripGPS exif = %v, want no GPS", got.EXIF)
+		}
+	}
+	if got.EXIF["Make"] != "Test" {
+		t.Errorf("Extract() after StripGPS exif = %v, want Make kept", got.EXIF)
+	}
+	if _, _, err := Decode(data); err != nil {
+		t.Errorf("Decode() after StripGPS error = %v", err)
+	}
+
+	if isStripped, _ := StripGPS(data); isStripped {
+		t.Errorf("StripGPS() twice = true, want false")
+	}
+	if isStripped, err := StripGPS([]byte("not a jpeg")); isStripped || err != nil {
+		t.Errorf("StripGPS() on text = %v, %v, want false", isStripped, err)
+	}
+}
+
+func TestThumbnail(t *testing.T) {
+	img, format, err := Decode(testJPEG(t, 400, 100))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	thumb := Thumbnail(img, 160)
+	if b := thumb.Bounds(); b.Dx() != 160 || b.Dy() != 40 {
+		t.Errorf("Thumbnail() size = %dx%d, want 160x40", b.Dx(), b.Dy())
+	}
+	if thumb = Thumbnail(img, 1000); thumb != img {
+		t.Errorf("Thumbnail() of a smaller image is scaled")
+	}
+
+	data, mimeType, err := Encode(Thumbnail(img, 160), format)
+	if err != nil || mimeType != "image/jpeg" || len(data) == 0 {
+		t.Errorf("Encode() = %d bytes %v, %v", len(data), mimeType, err)
+	}
+}
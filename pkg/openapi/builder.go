@@ -0,0 +1,291 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BearerAuth is the security scheme of the routes needing a signed in user.
+const BearerAuth = "bearerAuth"
+
+const contentTypeJSON = "application/json"
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// Route describes an operation by the Go values of its request and
+// response, Builder.Add turns it into the operation of the document. A
+// *Schema given instead of a Go value is used as is.
+type Route struct {
+	ID      string
+	Tag     string
+	Summary string
+	// Auth limits the route to signed in users, Roles to those having one
+	// of them.
+	Auth  bool
+	Roles []string
+	// Query is a struct, its fields having a form tag are the query
+	// parameters.
+	Query interface{}
+	Body  interface{}
+	// BodyType is the content type of Body, JSON when empty.
+	BodyType string
+	// Status is the status of a success, 200 when 0.
+	Status       int
+	Response     interface{}
+	ResponseType string
+}
+
+// Builder builds a document. The named struct types of the routes go in the
+// schemas of the components, the operations refer to them.
+type Builder struct {
+	doc   Document
+	names map[reflect.Type]string
+	err   *Schema
+	// PathParam is the schema of the path parameter name, a string when nil.
+	PathParam func(name string) *Schema
+}
+
+// NewBuilder starts a document whose error responses have the body errBody.
+func NewBuilder(info Info, errBody interface{}) *Builder {
+	b := &Builder{
+		doc: Document{
+			OpenAPI: Version,
+			Info:    info,
+			Paths:   map[string]PathItem{},
+			Components: Components{
+				Schemas: map[string]*Schema{},
+				SecuritySchemes: map[string]SecurityScheme{
+					BearerAuth: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+				},
+			},
+		},
+		names: map[reflect.Type]string{},
+	}
+	b.err = b.Schema(errBody)
+	return b
+}
+
+// Add adds the operation of route at method and path, a path of gin like
+// /posts/:id.
+func (b *Builder) Add(method, ginPath string, route Route) {
+	op := &Operation{
+		Summary:     route.Summary,
+		OperationID: route.ID,
+		Responses:   map[string]Response{},
+	}
+	if route.Tag != "" {
+		op.Tags = []string{route.Tag}
+	}
+	if route.Auth || len(route.Roles) > 0 {
+		op.Security = []map[string][]string{{BearerAuth: {}}}
+	}
+	if len(route.Roles) > 0 {
+		op.Description = "Only for " + strings.Join(route.Roles, ", ") + "."
+	}
+
+	segments := strings.Split(ginPath, "/")
+	for i, v := range segments {
+		if v == "" || (v[0] != ':' && v[0] != '*') {
+			continue
+		}
+
+		name := v[1:]
+		segments[i] = "{" + name + "}"
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   b.pathParam(name),
+		})
+	}
+	if route.Query != nil {
+		op.Parameters = append(op.Parameters, b.queryParams(reflect.TypeOf(route.Query))...)
+	}
+
+	if route.Body != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{orDefault(route.BodyType, contentTypeJSON): {Schema: b.Schema(route.Body)}},
+		}
+	}
+
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := Response{Description: http.StatusText(status)}
+	if route.Response != nil {
+		success.Content = map[string]MediaType{orDefault(route.ResponseType, contentTypeJSON): {Schema: b.Schema(route.Response)}}
+	}
+	op.Responses[strconv.Itoa(status)] = success
+	op.Responses["default"] = Response{
+		Description: "Error",
+		Content:     map[string]MediaType{contentTypeJSON: {Schema: b.err}},
+	}
+
+	p := strings.Join(segments, "/")
+	if b.doc.Paths[p] == nil {
+		b.doc.Paths[p] = PathItem{}
+	}
+	b.doc.Paths[p][strings.ToLower(method)] = op
+}
+
+// Document returns the document built so far.
+func (b *Builder) Document() Document {
+	return b.doc
+}
+
+// Schema returns the schema of the Go value v, a reference for a named
+// struct.
+func (b *Builder) Schema(v interface{}) *Schema {
+	if s, ok := v.(*Schema); ok {
+		return s
+	}
+	return b.schemaOf(reflect.TypeOf(v))
+}
+
+func (b *Builder) schemaOf(t reflect.Type) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		// json.RawMessage and the like are any JSON
+		if t.Implements(marshalerType) {
+			return &Schema{}
+		}
+		return &Schema{Type: "string", Format: "byte"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := b.schemaOf(t.Elem())
+		if s.Ref == "" {
+			s.Nullable = true
+		}
+		return s
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: b.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.schemaOf(t.Elem())}
+	case reflect.Struct:
+		return b.structSchema(t)
+	}
+	return &Schema{}
+}
+
+func (b *Builder) structSchema(t reflect.Type) *Schema {
+	if t.Name() == "" {
+		s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		b.properties(t, s.Properties)
+		return s
+	}
+
+	name, ok := b.names[t]
+	if !ok {
+		name = t.Name()
+		if _, isTaken := b.doc.Components.Schemas[name]; isTaken {
+			name = path.Base(t.PkgPath()) + "." + name
+		}
+		b.names[t] = name
+
+		// registered before its fields so a type may hold itself
+		s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		b.doc.Components.Schemas[name] = s
+		b.properties(t, s.Properties)
+	}
+
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// properties adds the fields of struct t as encoding/json writes them.
+func (b *Builder) properties(t reflect.Type, props map[string]*Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				b.properties(ft, props)
+				continue
+			}
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		if strings.Contains(opts, "string") {
+			props[name] = &Schema{Type: "string"}
+			continue
+		}
+		props[name] = b.schemaOf(f.Type)
+	}
+}
+
+// queryParams are the fields of struct t having a form tag.
+func (b *Builder) queryParams(t reflect.Type) []Parameter {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var result []Parameter
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("form"), ",")
+		if name == "" || name == "-" || !f.IsExported() {
+			continue
+		}
+
+		result = append(result, Parameter{
+			Name:   name,
+			In:     "query",
+			Schema: b.schemaOf(f.Type),
+		})
+	}
+	return result
+}
+
+func (b *Builder) pathParam(name string) *Schema {
+	if b.PathParam != nil {
+		return b.PathParam(name)
+	}
+	return &Schema{Type: "string"}
+}
+
+func orDefault(v, defaultValue string) string {
+	if v == "" {
+		return defaultValue
+	}
+	return v
+}
@@ -0,0 +1,113 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type Base struct {
+	ID        uint64    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Node struct {
+	Base
+	Label    string          `json:"label,omitempty"`
+	ParentID *uint64         `json:"parent_id"`
+	Children []Node          `json:"children"`
+	Meta     json.RawMessage `json:"meta"`
+	Count    int64           `json:"count,string"`
+	Secret   string          `json:"-"`
+	internal string
+}
+
+type URL struct {
+	Link string `json:"link"`
+}
+
+type listReq struct {
+	Tag    string   `form:"tag"`
+	Filter []string `form:"filter"`
+	ID     uint64   `form:"-"`
+}
+
+func TestBuilder_Schema(t *testing.T) {
+	b := NewBuilder(Info{Title: "test", Version: "1"}, struct {
+		Message string `json:"message"`
+	}{})
+
+	if got := b.Schema([]Node{}); !reflect.DeepEqual(got, &Schema{Type: "array", Items: &Schema{Ref: "#/components/schemas/Node"}}) {
+		t.Errorf("Schema() = %+v", got)
+	}
+
+	want := &Schema{Type: "object", Properties: map[string]*Schema{
+		"id":         {Type: "integer", Format: "int64"},
+		"created_at": {Type: "string", Format: "date-time"},
+		"label":      {Type: "string"},
+		"parent_id":  {Type: "integer", Format: "int64", Nullable: true},
+		"children":   {Type: "array", Items: &Schema{Ref: "#/components/schemas/Node"}},
+		"meta":       {},
+		"count":      {Type: "string"},
+	}}
+	if got := b.Document().Components.Schemas["Node"]; !reflect.DeepEqual(got, want) {
+		gotJSON, _ := json.Marshal(got)
+		t.Errorf("schema Node = %s", gotJSON)
+	}
+
+	// a second type of the same name is told apart by its package
+	b.Schema(URL{})
+	if got := b.Schema(url.URL{}); got.Ref != "#/components/schemas/url.URL" {
+		t.Errorf("Schema(url.URL) = %+v", got)
+	}
+}
+
+func TestBuilder_Add(t *testing.T) {
+	b := NewBuilder(Info{Title: "test", Version: "1"}, struct{}{})
+	b.PathParam = func(name string) *Schema {
+		if name == "id" {
+			return &Schema{Type: "integer"}
+		}
+		return &Schema{Type: "string"}
+	}
+	b.Add("GET", "/nodes/:id/links/:label", Route{
+		ID:       "Node.GetLinks",
+		Tag:      "Nodes",
+		Roles:    []string{"admin"},
+		Query:    listReq{},
+		Response: []URL{},
+	})
+	b.Add("POST", "/nodes", Route{Body: Node{}, Status: 201, Response: Node{}})
+
+	doc := b.Document()
+	op := doc.Paths["/nodes/{id}/links/{label}"]["get"]
+	if op == nil {
+		t.Fatalf("Paths = %+v", doc.Paths)
+	}
+
+	wantParams := []Parameter{
+		{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "integer"}},
+		{Name: "label", In: "path", Required: true, Schema: &Schema{Type: "string"}},
+		{Name: "tag", In: "query", Schema: &Schema{Type: "string"}},
+		{Name: "filter", In: "query", Schema: &Schema{Type: "array", Items: &Schema{Type: "string"}}},
+	}
+	if !reflect.DeepEqual(op.Parameters, wantParams) {
+		t.Errorf("Parameters = %+v", op.Parameters)
+	}
+	if len(op.Security) != 1 || op.Description != "Only for admin." || op.OperationID != "Node.GetLinks" {
+		t.Errorf("Operation = %+v", op)
+	}
+	if _, ok := op.Responses["200"]; !ok {
+		t.Errorf("Responses = %+v, want 200", op.Responses)
+	}
+
+	op = doc.Paths["/nodes"]["post"]
+	if op == nil || op.RequestBody.Content["application/json"].Schema.Ref != "#/components/schemas/Node" {
+		t.Fatalf("POST /nodes = %+v", op)
+	}
+	if _, ok := op.Responses["201"]; !ok || op.Security != nil {
+		t.Errorf("POST /nodes = %+v", op)
+	}
+}
@@ -0,0 +1,154 @@
+package render
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	xhtml "golang.org/x/net/html"
+)
+
+const (
+	FormatPlain    = "plain"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+
+	// WordsPerMinute is the reading speed used by ReadingTime.
+	WordsPerMinute = 200
+)
+
+var (
+	markdown  = goldmark.New(goldmark.WithExtensions(extension.GFM))
+	policy    = newPolicy()
+	blankLine = regexp.MustCompile(`\n\s*\n`)
+)
+
+// newPolicy allows the usual user content markup: formatting, links,
+// images, lists, tables and code with its language class. Scripts, styles,
+// event handlers and javascript: links are removed.
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w-]+$`)).OnElements("code")
+	p.RequireNoFollowOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	return p
+}
+
+func IsValidFormat(format string) bool {
+	return format == FormatPlain || format == FormatMarkdown || format == FormatHTML
+}
+
+// Result is the content rendered to display.
+type Result struct {
+	HTML string // sanitized, safe to put in a page as is
+	Text string // plain text without markup
+}
+
+// Content renders source written in format. Markdown is converted to HTML,
+// HTML is kept, plain text is escaped with blank lines as paragraphs, and
+// the HTML of every format is sanitized.
+func Content(format, source string) (Result, error) {
+	var raw string
+	switch format {
+	case FormatMarkdown:
+		buf := bytes.Buffer{}
+		if err := markdown.Convert([]byte(source), &buf); err != nil {
+			return Result{}, err
+		}
+		raw = buf.String()
+	case FormatHTML:
+		raw = source
+	default:
+		raw = plainToHTML(source)
+	}
+
+	safe := policy.Sanitize(raw)
+	return Result{HTML: safe, Text: Text(safe)}, nil
+}
+
+func plainToHTML(source string) string {
+	var b strings.Builder
+	for _, paragraph := range blankLine.Split(strings.TrimSpace(source), -1) {
+		if paragraph == "" {
+			continue
+		}
+		b.WriteString("<p>")
+		b.WriteString(strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>\n"))
+		b.WriteString("</p>\n")
+	}
+	return b.String()
+}
+
+// blockTags end a line of text when converting HTML to text.
+var blockTags = map[string]bool{
+	"p": true, "div": true, "br": true, "li": true, "tr": true, "pre": true,
+	"blockquote": true, "h1": true, "h2": true, "h3": true, "h4": true,
+	"h5": true, "h6": true, "hr": true, "table": true, "ul": true, "ol": true,
+}
+
+// Text returns the text of an HTML fragment, one line per block element.
+func Text(fragment string) string {
+	var (
+		tokenizer = xhtml.NewTokenizer(strings.NewReader(fragment))
+		lines     = []string{}
+		line      strings.Builder
+	)
+	endLine := func() {
+		if s := strings.Join(strings.Fields(line.String()), " "); s != "" {
+			lines = append(lines, s)
+		}
+		line.Reset()
+	}
+
+	for {
+		switch tokenizer.Next() {
+		case xhtml.ErrorToken:
+			endLine()
+			return strings.Join(lines, "\n")
+		case xhtml.TextToken:
+			// Text is unescaped already
+			line.Write(tokenizer.Text())
+		case xhtml.StartTagToken, xhtml.EndTagToken, xhtml.SelfClosingTagToken:
+			name, _ := tokenizer.TagName()
+			switch string(name) {
+			case "td", "th", "img":
+				line.WriteByte(' ')
+			default:
+				if blockTags[string(name)] {
+					endLine()
+				}
+			}
+		}
+	}
+}
+
+// Excerpt returns the first words of text up to max runes, cut on a word
+// boundary and ending with an ellipsis when text is longer.
+func Excerpt(text string, max int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= max {
+		return text
+	}
+
+	runes := []rune(text)
+	cut := string(runes[:max])
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:") + "…"
+}
+
+// ReadingTime returns the minutes needed to read text, at least 1.
+func ReadingTime(text string) int {
+	words := len(strings.Fields(text))
+	minutes := (words + WordsPerMinute - 1) / WordsPerMinute
+	if minutes < 1 {
+		return 1
+	}
+	return minutes
+}
@@ -0,0 +1,113 @@
+package render
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		source   string
+		wantHTML []string
+		notHTML  []string
+		wantText string
+	}{
+		{
+			name:     "markdown",
+			format:   FormatMarkdown,
+			source:   "# Title\n\nSome **bold** and [link](https://example.com).\n\n```go\nfmt.Println(1)\n```",
+			wantHTML: []string{"<h1>Title</h1>", "<strong>bold</strong>", "nofollow", `class="language-go"`},
+			wantText: "Title\nSome bold and link.\nfmt.Println(1)",
+		},
+		{
+			name:     "markdown raw html is dropped",
+			format:   FormatMarkdown,
+			source:   "hi <script>alert(1)</script>",
+			notHTML:  []string{"<script"},
+			wantText: "hi alert(1)",
+		},
+		{
+			name:     "html is sanitized",
+			format:   FormatHTML,
+			source:   `<p onclick="x()">Hi <a href="javascript:alert(1)">there</a></p><script>alert(1)</script><img src=x onerror=alert(1)>`,
+			wantHTML: []string{"<p>Hi there</p>"},
+			notHTML:  []string{"onclick", "javascript:", "<script", "onerror"},
+			wantText: "Hi there",
+		},
+		{
+			name:     "plain is escaped",
+			format:   FormatPlain,
+			source:   "a < b\nline two\n\n<b>para</b>",
+			wantHTML: []string{"<p>a &lt; b<br>\nline two</p>", "<p>&lt;b&gt;para&lt;/b&gt;</p>"},
+			wantText: "a < b\nline two\n<b>para</b>",
+		},
+		{
+			name:     "entities are unescaped once",
+			format:   FormatHTML,
+			source:   "<p>a &amp;lt; b</p>",
+			wantText: "a &lt; b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Content(tt.format, tt.source)
+			if err != nil {
+				t.Fatalf("Content() error = %v", err)
+			}
+			for _, v := range tt.wantHTML {
+				if !strings.Contains(got.HTML, v) {
+					t.Errorf("Content() HTML = %q, want it to contain %q", got.HTML, v)
+				}
+			}
+			for _, v := range tt.notHTML {
+				if strings.Contains(got.HTML, v) {
+					t.Errorf("Content() HTML = %q, want it without %q", got.HTML, v)
+				}
+			}
+			if got.Text != tt.wantText {
+				t.Errorf("Content() Text = %q, want %q", got.Text, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		max  int
+		want string
+	}{
+		{name: "short", text: "short  text\n", max: 20, want: "short text"},
+		{name: "word boundary", text: "one two three four", max: 10, want: "one two…"},
+		{name: "multibyte", text: "satu dua tiga empat", max: 12, want: "satu dua…"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Excerpt(tt.text, tt.max); got != tt.want {
+				t.Errorf("Excerpt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadingTime(t *testing.T) {
+	tests := []struct {
+		name  string
+		words int
+		want  int
+	}{
+		{name: "empty", words: 0, want: 1},
+		{name: "one minute", words: WordsPerMinute, want: 1},
+		{name: "rounds up", words: WordsPerMinute + 1, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ReadingTime(strings.Repeat("word ", tt.words)); got != tt.want {
+				t.Errorf("ReadingTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
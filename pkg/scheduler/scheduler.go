@@ -0,0 +1,35 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Job is a task run periodically by Every.
+type Job func(ctx context.Context) error
+
+// Every runs job every interval until ctx is done, a failed run is logged
+// and retried on the next tick. Runs never overlap, a tick that comes while
+// job is still running is dropped.
+func Every(ctx context.Context, name string, interval time.Duration, logger *logrus.Logger, job Job) {
+	if interval <= 0 {
+		logger.Infof("scheduler %s disabled \n", name)
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := job(ctx); err != nil {
+				logger.Errorf("scheduler %s failed: %v \n", name, err)
+			}
+		}
+	}
+}
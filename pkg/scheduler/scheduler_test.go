@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestEvery(t *testing.T) {
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		runs        int32
+		done        = make(chan struct{})
+	)
+
+	go func() {
+		Every(ctx, "test", time.Millisecond, logrus.New(), func(ctx context.Context) error {
+			if atomic.AddInt32(&runs, 1) >= 3 {
+				cancel()
+			}
+			return errors.New("failed run is retried")
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Every() did not stop after ctx is done")
+	}
+
+	if got := atomic.LoadInt32(&runs); got < 3 {
+		t.Errorf("Every() runs = %d, want at least 3", got)
+	}
+}
+
+func TestEvery_Disabled(t *testing.T) {
+	called := false
+	Every(context.Background(), "test", 0, logrus.New(), func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	if called {
+		t.Error("Every() with interval 0 ran the job")
+	}
+}
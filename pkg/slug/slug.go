@@ -0,0 +1,78 @@
+package slug
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLength is the longest slug Make returns, collision suffixes come on top.
+const MaxLength = 80
+
+// Fallback is used when a title has nothing to transliterate.
+const Fallback = "post"
+
+// replacements covers letters that do not decompose into an ASCII base
+// letter plus accents, and symbols worth keeping as words.
+var replacements = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'Æ': "ae", 'œ': "oe", 'Œ': "oe",
+	'ø': "o", 'Ø': "o", 'đ': "d", 'Đ': "d", 'ð': "d", 'Ð': "d",
+	'ł': "l", 'Ł': "l", 'þ': "th", 'Þ': "th", 'ı': "i",
+	'&': " dan ", '@': " at ", '+': " plus ", '%': " persen ",
+}
+
+// Make turns title into a lowercase, URL safe slug of ASCII letters, digits
+// and single dashes, e.g. "Résumé & Café" becomes "resume-dan-cafe".
+// Accents are stripped after unicode decomposition, other scripts that have
+// no ASCII form are dropped.
+func Make(title string) string {
+	var (
+		b    strings.Builder
+		dash = false
+	)
+
+	for _, r := range norm.NFKD.String(title) {
+		if s, ok := replacements[r]; ok {
+			for _, c := range s {
+				dash = writeRune(&b, c, dash)
+			}
+			continue
+		}
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		dash = writeRune(&b, r, dash)
+	}
+
+	result := strings.Trim(b.String(), "-")
+	if len(result) > MaxLength {
+		result = result[:MaxLength]
+		// cut on a word boundary when there is one
+		if i := strings.LastIndexByte(result, '-'); i > MaxLength/2 {
+			result = result[:i]
+		}
+		result = strings.Trim(result, "-")
+	}
+
+	if result == "" {
+		return Fallback
+	}
+	return result
+}
+
+// writeRune writes r lowercased when it is an ASCII letter or digit, any
+// other rune becomes a single dash. It returns whether the last written
+// rune is a dash.
+func writeRune(b *strings.Builder, r rune, dash bool) bool {
+	r = unicode.ToLower(r)
+	if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+		b.WriteRune(r)
+		return false
+	}
+
+	if !dash && b.Len() > 0 {
+		b.WriteByte('-')
+	}
+	return true
+}
@@ -0,0 +1,37 @@
+package slug
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{name: "plain", title: "Hello World", want: "hello-world"},
+		{name: "punctuation", title: "  Go: tips, tricks & more!! ", want: "go-tips-tricks-dan-more"},
+		{name: "indonesian", title: "Cara Membuat Kue Lapis 100% Enak", want: "cara-membuat-kue-lapis-100-persen-enak"},
+		{name: "accents", title: "Résumé Café Über Ñandú", want: "resume-cafe-uber-nandu"},
+		{name: "special letters", title: "Straße Łódź Ærø", want: "strasse-lodz-aero"},
+		{name: "full width", title: "ＧＯ言語", want: "go"},
+		{name: "no ascii", title: "日本語", want: Fallback},
+		{name: "empty", title: "", want: Fallback},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Make(tt.title); got != tt.want {
+				t.Errorf("Make() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMake_MaxLength(t *testing.T) {
+	got := Make(strings.Repeat("panjang ", 30))
+	if len(got) > MaxLength || strings.HasSuffix(got, "-") || strings.HasSuffix(got, "panj") {
+		t.Errorf("Make() = %v, want at most %d chars cut on a word", got, MaxLength)
+	}
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/adamnasrudin03/go-asset-findr/app/configs"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrNotFound is returned by Get when no object has the key.
+var ErrNotFound = errors.New("storage: object not found")
+
+// Storage keeps file contents by key, implementations must be safe for
+// concurrent use. Keys are slash separated relative paths, see CleanKey.
+type Storage interface {
+	// Put stores size bytes of r under key, replacing any previous object.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the object of key, the caller closes it.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object of key, a missing object is no error.
+	Delete(ctx context.Context, key string) error
+}
+
+// Setup creates the storage chosen by STORAGE_DRIVER.
+func Setup(cfg *configs.Configs, logger *logrus.Logger) Storage {
+	switch cfg.Storage.Driver {
+	case "s3":
+		s, err := NewS3(S3Options{
+			Endpoint:  cfg.Storage.S3Endpoint,
+			Region:    cfg.Storage.S3Region,
+			Bucket:    cfg.Storage.S3Bucket,
+			AccessKey: cfg.Storage.S3AccessKey,
+			SecretKey: cfg.Storage.S3SecretKey,
+			Timeout:   time.Duration(cfg.Storage.S3Timeout) * time.Second,
+		})
+		if err != nil {
+			logger.Panicf("Failed to setup s3 storage, %v", err)
+			return nil
+		}
+		logger.Info("Storage s3 enabled")
+		return s
+	default:
+		s, err := NewLocal(cfg.Storage.LocalDir)
+		if err != nil {
+			logger.Panicf("Failed to setup local storage, %v", err)
+			return nil
+		}
+		logger.Info("Storage local enabled")
+		return s
+	}
+}
+
+// CleanKey normalizes key and rejects keys escaping the storage root.
+func CleanKey(key string) (string, error) {
+	cleaned := path.Clean("/" + strings.ReplaceAll(key, "\\", "/"))
+	cleaned = strings.TrimPrefix(cleaned, "/")
+	if cleaned == "" || cleaned == "." || strings.Contains(key, "..") {
+		return "", fmt.Errorf("storage: invalid key %q", key)
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,184 @@
+package textdiff
+
+import "strings"
+
+const (
+	OpEqual  = "equal"
+	OpInsert = "insert"
+	OpDelete = "delete"
+)
+
+// MaxEdits caps the edits Lines searches for, bounding its time and memory
+// on large revisions. Past it the changed lines are shown as all deleted,
+// then all inserted.
+const MaxEdits = 1000
+
+type Line struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// Lines returns the line by line diff turning a into b, the shortest one as
+// found by the Myers algorithm.
+func Lines(a, b string) []Line {
+	var (
+		from = splitLines(a)
+		to   = splitLines(b)
+		n, m = len(from), len(to)
+	)
+
+	// the lines are compared by number, equal lines sharing one
+	numbers := map[string]int{}
+	x, y := lineNumbers(from, numbers), lineNumbers(to, numbers)
+
+	prefix := 0
+	for prefix < n && prefix < m && x[prefix] == y[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < n-prefix && suffix < m-prefix && x[n-1-suffix] == y[m-1-suffix] {
+		suffix++
+	}
+
+	result := make([]Line, 0, max(n, m))
+	for _, v := range from[:prefix] {
+		result = append(result, Line{Op: OpEqual, Text: v})
+	}
+
+	var (
+		fromMid = from[prefix : n-suffix]
+		toMid   = to[prefix : m-suffix]
+		ops     = shortestEdit(x[prefix:n-suffix], y[prefix:m-suffix])
+	)
+	if ops == nil {
+		for _, v := range fromMid {
+			result = append(result, Line{Op: OpDelete, Text: v})
+		}
+		for _, v := range toMid {
+			result = append(result, Line{Op: OpInsert, Text: v})
+		}
+	}
+	i, j := 0, 0
+	for _, op := range ops {
+		switch op {
+		case OpEqual:
+			result = append(result, Line{Op: OpEqual, Text: fromMid[i]})
+			i++
+			j++
+		case OpDelete:
+			result = append(result, Line{Op: OpDelete, Text: fromMid[i]})
+			i++
+		default:
+			result = append(result, Line{Op: OpInsert, Text: toMid[j]})
+			j++
+		}
+	}
+
+	for _, v := range from[n-suffix:] {
+		result = append(result, Line{Op: OpEqual, Text: v})
+	}
+	return result
+}
+
+// shortestEdit returns the ops turning x into y, deletions before
+// insertions, or nil past MaxEdits.
+func shortestEdit(x, y []int) []string {
+	var (
+		n, m   = len(x), len(y)
+		maxD   = min(n+m, MaxEdits)
+		offset = maxD + 1
+		// v[offset+k] is the furthest x reached on diagonal k = x - y
+		v = make([]int, 2*maxD+3)
+		// trace[d] holds v on the diagonals -d to d before the round d
+		trace = [][]int{}
+	)
+
+	for d := 0; d <= maxD; d++ {
+		trace = append(trace, append([]int{}, v[offset-d:offset+d+1]...))
+
+		for k := -d; k <= d; k += 2 {
+			var i int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				i = v[offset+k+1] // down, an insertion
+			} else {
+				i = v[offset+k-1] + 1 // right, a deletion
+			}
+
+			j := i - k
+			for i < n && j < m && x[i] == y[j] {
+				i++
+				j++
+			}
+			v[offset+k] = i
+
+			if i >= n && j >= m {
+				return backtrack(trace, n, m)
+			}
+		}
+	}
+	return nil
+}
+
+// backtrack walks trace back from the end of both texts to their start.
+func backtrack(trace [][]int, n, m int) []string {
+	var (
+		result = make([]string, 0, n+m)
+		i, j   = n, m
+	)
+
+	for d := len(trace) - 1; d > 0; d-- {
+		var (
+			v     = trace[d]
+			k     = i - j
+			prevK int
+		)
+		if k == -d || (k != d && v[k-1+d] < v[k+1+d]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevI := v[prevK+d]
+		prevJ := prevI - prevK
+
+		for i > prevI && j > prevJ {
+			result = append(result, OpEqual)
+			i--
+			j--
+		}
+		if i == prevI {
+			result = append(result, OpInsert)
+		} else {
+			result = append(result, OpDelete)
+		}
+		i, j = prevI, prevJ
+	}
+	for ; i > 0; i-- {
+		result = append(result, OpEqual)
+	}
+
+	for l, r := 0, len(result)-1; l < r; l, r = l+1, r-1 {
+		result[l], result[r] = result[r], result[l]
+	}
+	return result
+}
+
+// lineNumbers numbers lines, adding the lines not seen yet to numbers.
+func lineNumbers(lines []string, numbers map[string]int) []int {
+	result := make([]int, len(lines))
+	for i, v := range lines {
+		number, ok := numbers[v]
+		if !ok {
+			number = len(numbers)
+			numbers[v] = number
+		}
+		result[i] = number
+	}
+	return result
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+	return strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+}
@@ -0,0 +1,132 @@
+package textdiff
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want []Line
+	}{
+		{
+			name: "empty",
+			a:    "",
+			b:    "",
+			want: []Line{},
+		},
+		{
+			name: "insert and delete",
+			a:    "a\nb\nc",
+			b:    "a\nc\nd",
+			want: []Line{
+				{Op: OpEqual, Text: "a"},
+				{Op: OpDelete, Text: "b"},
+				{Op: OpEqual, Text: "c"},
+				{Op: OpInsert, Text: "d"},
+			},
+		},
+		{
+			name: "replace all",
+			a:    "old",
+			b:    "new",
+			want: []Line{
+				{Op: OpDelete, Text: "old"},
+				{Op: OpInsert, Text: "new"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Lines(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// apply returns both texts of diff, checking the diff keeps the most lines
+// of a as lcs, their longest common subsequence, says it can.
+func apply(t *testing.T, diff []Line, lcs int) (a, b string) {
+	t.Helper()
+	var from, to []string
+	equal := 0
+	for _, v := range diff {
+		switch v.Op {
+		case OpEqual:
+			from, to = append(from, v.Text), append(to, v.Text)
+			equal++
+		case OpDelete:
+			from = append(from, v.Text)
+		case OpInsert:
+			to = append(to, v.Text)
+		}
+	}
+	if lcs >= 0 && equal != lcs {
+		t.Errorf("Lines() keeps %d lines, want %d", equal, lcs)
+	}
+	return strings.Join(from, "\n"), strings.Join(to, "\n")
+}
+
+func lcsLen(a, b []string) int {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	return lcs[0][0]
+}
+
+func TestLines_Random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	text := func() []string {
+		result := make([]string, rnd.Intn(30)+1)
+		for i := range result {
+			result[i] = fmt.Sprint(rnd.Intn(5))
+		}
+		return result
+	}
+
+	for i := 0; i < 200; i++ {
+		from, to := text(), text()
+		a, b := strings.Join(from, "\n"), strings.Join(to, "\n")
+
+		gotA, gotB := apply(t, Lines(a, b), lcsLen(from, to))
+		if gotA != a || gotB != b {
+			t.Fatalf("Lines(%q, %q) turns %q into %q", a, b, gotA, gotB)
+		}
+	}
+}
+
+func TestLines_MaxEdits(t *testing.T) {
+	var from, to []string
+	for i := 0; i < 5*MaxEdits; i++ {
+		from = append(from, fmt.Sprint("old ", i))
+		to = append(to, fmt.Sprint("new ", i))
+	}
+	a := "title\n" + strings.Join(from, "\n") + "\nend"
+	b := "title\n" + strings.Join(to, "\n") + "\nend"
+
+	got := Lines(a, b)
+	gotA, gotB := apply(t, got, 2)
+	if gotA != a || gotB != b {
+		t.Fatalf("Lines() does not turn a into b")
+	}
+	if got[1].Op != OpDelete || got[len(from)+1].Op != OpInsert {
+		t.Errorf("Lines() past MaxEdits = %v ..., want the deletions then the insertions", got[:3])
+	}
+}
@@ -0,0 +1,143 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Headers of every request sent to a webhook. The signature is
+// "sha256=" followed by the hex HMAC-SHA256, keyed with the secret of the
+// webhook, of the timestamp, a dot and the body.
+const (
+	HeaderID        = "X-Webhook-ID"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+
+	signaturePrefix = "sha256="
+)
+
+// MaxResponse is how much of the response body Send returns.
+const MaxResponse = 1024
+
+var (
+	ErrSignature = errors.New("webhook signature mismatch")
+	ErrExpired   = errors.New("webhook timestamp outside tolerance")
+)
+
+// Sign returns the signature header of body sent at timestamp (unix
+// seconds).
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of a received request, as a receiver would.
+// The timestamp must be within tolerance of now so a captured request cannot
+// be replayed later, a tolerance of 0 skips that check.
+func Verify(secret string, header http.Header, body []byte, tolerance time.Duration, now time.Time) error {
+	timestamp, err := strconv.ParseInt(header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		return ErrSignature
+	}
+
+	if tolerance > 0 {
+		sentAt := time.Unix(timestamp, 0)
+		if sentAt.Before(now.Add(-tolerance)) || sentAt.After(now.Add(tolerance)) {
+			return ErrExpired
+		}
+	}
+
+	if !hmac.Equal([]byte(header.Get(HeaderSignature)), []byte(Sign(secret, timestamp, body))) {
+		return ErrSignature
+	}
+	return nil
+}
+
+// NewSecret returns a random secret of 32 hex characters.
+func NewSecret() (string, error) {
+	data := make([]byte, 16)
+	_, err := rand.Read(data)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(data), nil
+}
+
+// Request is one delivery to send, ID identifies it to the receiver, which
+// gets the same ID again when it is retried.
+type Request struct {
+	URL    string
+	Secret string
+	ID     string
+	Event  string
+	Body   []byte
+}
+
+// Response is what the receiver answered, Body is cut at MaxResponse.
+type Response struct {
+	StatusCode int
+	Body       string
+}
+
+// Client posts signed requests to webhooks.
+type Client struct {
+	HTTP *http.Client
+	// Now gives the timestamp of the signature, time.Now when nil.
+	Now func() time.Time
+}
+
+// NewClient creates a client giving up on a receiver after timeout.
+func NewClient(timeout time.Duration) *Client {
+	return &Client{HTTP: &http.Client{Timeout: timeout}}
+}
+
+// Send posts req. It fails when the receiver cannot be reached or does not
+// answer with a 2xx status, the response is returned whenever there is one.
+func (c *Client) Send(ctx context.Context, req Request) (*Response, error) {
+	now := time.Now
+	if c.Now != nil {
+		now = c.Now
+	}
+	timestamp := now().Unix()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", "go-asset-findr-webhook")
+	httpReq.Header.Set(HeaderID, req.ID)
+	httpReq.Header.Set(HeaderEvent, req.Event)
+	httpReq.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	httpReq.Header.Set(HeaderSignature, Sign(req.Secret, timestamp, req.Body))
+
+	httpRes, err := c.HTTP.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpRes.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(httpRes.Body, MaxResponse))
+	// drain the rest so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(httpRes.Body, 64*MaxResponse))
+
+	res := &Response{StatusCode: httpRes.StatusCode, Body: string(body)}
+	if httpRes.StatusCode < 200 || httpRes.StatusCode > 299 {
+		return res, fmt.Errorf("unexpected status %d", httpRes.StatusCode)
+	}
+	return res, nil
+}
@@ -0,0 +1,91 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	var (
+		now    = time.Unix(1700000000, 0)
+		body   = []byte(`{"id":1}`)
+		header = http.Header{}
+	)
+	header.Set(HeaderTimestamp, "1700000000")
+	header.Set(HeaderSignature, Sign("secret", now.Unix(), body))
+
+	tests := []struct {
+		name      string
+		secret    string
+		body      []byte
+		tolerance time.Duration
+		now       time.Time
+		wantErr   error
+	}{
+		{name: "valid", secret: "secret", body: body, tolerance: time.Minute, now: now},
+		{name: "other secret", secret: "other", body: body, tolerance: time.Minute, now: now, wantErr: ErrSignature},
+		{name: "changed body", secret: "secret", body: []byte(`{"id":2}`), tolerance: time.Minute, now: now, wantErr: ErrSignature},
+		{name: "expired", secret: "secret", body: body, tolerance: time.Minute, now: now.Add(2 * time.Minute), wantErr: ErrExpired},
+		{name: "no tolerance", secret: "secret", body: body, now: now.Add(time.Hour)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Verify(tt.secret, header, tt.body, tt.tolerance, tt.now); err != tt.wantErr {
+				t.Errorf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_Send(t *testing.T) {
+	var (
+		now      = time.Unix(1700000000, 0)
+		status   = http.StatusNoContent
+		received http.Header
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = r.Header.Clone()
+		if err := Verify("secret", r.Header, body, time.Minute, now); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(strings.Repeat("x", 2*MaxResponse)))
+	}))
+	defer server.Close()
+
+	client := NewClient(time.Second)
+	client.Now = func() time.Time { return now }
+	req := Request{URL: server.URL, Secret: "secret", ID: "7", Event: "post.created", Body: []byte(`{"id":7}`)}
+
+	res, err := client.Send(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if res.StatusCode != http.StatusNoContent || received.Get(HeaderID) != "7" || received.Get(HeaderEvent) != "post.created" {
+		t.Errorf("Send() = %+v, headers %v", res, received)
+	}
+
+	status = http.StatusOK
+	res, err = client.Send(context.Background(), req)
+	if err != nil || len(res.Body) != MaxResponse {
+		t.Errorf("Send() body of %d bytes, error = %v, want %d bytes", len(res.Body), err, MaxResponse)
+	}
+
+	req.Secret = "wrong"
+	res, err = client.Send(context.Background(), req)
+	if err == nil || res == nil || res.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Send() with a wrong secret = %+v, %v, want an error with 401", res, err)
+	}
+
+	server.Close()
+	if _, err = client.Send(context.Background(), req); err == nil {
+		t.Errorf("Send() to a closed server error = nil")
+	}
+}
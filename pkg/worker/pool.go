@@ -0,0 +1,86 @@
+package worker
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Handler processes one job of a Pool.
+type Handler[T any] func(ctx context.Context, job T) error
+
+// Pool runs jobs on a fixed number of goroutines fed by a bounded queue.
+// Jobs are kept in memory only, callers that can not lose a job should keep
+// its state elsewhere and submit it again, e.g. from a scheduler.
+type Pool[T any] struct {
+	name    string
+	workers int
+	queue   chan T
+	handle  Handler[T]
+	logger  *logrus.Logger
+	wg      sync.WaitGroup
+}
+
+func NewPool[T any](name string, workers, queueSize int, logger *logrus.Logger, handle Handler[T]) *Pool[T] {
+	if workers <= 0 {
+		workers = 1
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+
+	return &Pool[T]{
+		name:    name,
+		workers: workers,
+		queue:   make(chan T, queueSize),
+		handle:  handle,
+		logger:  logger,
+	}
+}
+
+// Start runs the workers until ctx is done, jobs still queued then are
+// dropped.
+func (p *Pool[T]) Start(ctx context.Context) {
+	for i := 0; i < p.workers; i++ {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case job := <-p.queue:
+					p.run(ctx, job)
+				}
+			}
+		}()
+	}
+}
+
+func (p *Pool[T]) run(ctx context.Context, job T) {
+	defer func() {
+		if r := recover(); r != nil {
+			p.logger.Errorf("worker %s panic on job %v: %v \n", p.name, job, r)
+		}
+	}()
+
+	if err := p.handle(ctx, job); err != nil {
+		p.logger.Errorf("worker %s failed job %v: %v \n", p.name, job, err)
+	}
+}
+
+// Submit queues job without blocking, false means the queue is full.
+func (p *Pool[T]) Submit(job T) bool {
+	select {
+	case p.queue <- job:
+		return true
+	default:
+		return false
+	}
+}
+
+// Wait blocks until the workers stopped, after ctx given to Start is done.
+func (p *Pool[T]) Wait() {
+	p.wg.Wait()
+}
@@ -0,0 +1,58 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestPool(t *testing.T) {
+	var (
+		logger = logrus.New()
+		mu     sync.Mutex
+		done   = map[int]bool{}
+		wg     sync.WaitGroup
+	)
+	logger.SetOutput(io.Discard)
+
+	pool := NewPool("test", 3, 10, logger, func(ctx context.Context, job int) error {
+		defer wg.Done()
+		if job == 2 {
+			panic("boom")
+		}
+		if job == 3 {
+			return errors.New("failed")
+		}
+
+		mu.Lock()
+		done[job] = true
+		mu.Unlock()
+		return nil
+	})
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		if !pool.Submit(i) {
+			t.Fatalf("Submit(%d) = false, want queued", i)
+		}
+	}
+	wg.Add(1)
+	if pool.Submit(10) {
+		t.Errorf("Submit() on a full queue = true, want false")
+	}
+	wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pool.Start(ctx)
+	wg.Wait()
+	cancel()
+	pool.Wait()
+
+	if len(done) != 8 || done[2] || done[3] {
+		t.Errorf("done jobs = %v, want all but the failed ones", done)
+	}
+}
@@ -0,0 +1,616 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: findr/v1/tag.proto
+
+package findrv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Tag struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id       uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Label    string   `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	ParentId *uint64  `protobuf:"varint,3,opt,name=parent_id,json=parentId,proto3,oneof" json:"parent_id,omitempty"`
+	Synonyms []string `protobuf:"bytes,4,rep,name=synonyms,proto3" json:"synonyms,omitempty"`
+	Children []*Tag   `protobuf:"bytes,5,rep,name=children,proto3" json:"children,omitempty"`
+}
+
+func (x *Tag) Reset() {
+	*x = Tag{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_findr_v1_tag_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Tag) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tag) ProtoMessage() {}
+
+func (x *Tag) ProtoReflect() protoreflect.Message {
+	mi := &file_findr_v1_tag_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tag.ProtoReflect.Descriptor instead.
+func (*Tag) Descriptor() ([]byte, []int) {
+	return file_findr_v1_tag_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Tag) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Tag) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *Tag) GetParentId() uint64 {
+	if x != nil && x.ParentId != nil {
+		return *x.ParentId
+	}
+	return 0
+}
+
+func (x *Tag) GetSynonyms() []string {
+	if x != nil {
+		return x.Synonyms
+	}
+	return nil
+}
+
+func (x *Tag) GetChildren() []*Tag {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+type GetTagTreeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetTagTreeRequest) Reset() {
+	*x = GetTagTreeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_findr_v1_tag_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetTagTreeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTagTreeRequest) ProtoMessage() {}
+
+func (x *GetTagTreeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_findr_v1_tag_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTagTreeRequest.ProtoReflect.Descriptor instead.
+func (*GetTagTreeRequest) Descriptor() ([]byte, []int) {
+	return file_findr_v1_tag_proto_rawDescGZIP(), []int{1}
+}
+
+type GetTagTreeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tags []*Tag `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`
+}
+
+func (x *GetTagTreeResponse) Reset() {
+	*x = GetTagTreeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_findr_v1_tag_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetTagTreeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTagTreeResponse) ProtoMessage() {}
+
+func (x *GetTagTreeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_findr_v1_tag_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTagTreeResponse.ProtoReflect.Descriptor instead.
+func (*GetTagTreeResponse) Descriptor() ([]byte, []int) {
+	return file_findr_v1_tag_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetTagTreeResponse) GetTags() []*Tag {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type SuggestTagsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Prefix string `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Limit  int32  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *SuggestTagsRequest) Reset() {
+	*x = SuggestTagsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_findr_v1_tag_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SuggestTagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SuggestTagsRequest) ProtoMessage() {}
+
+func (x *SuggestTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_findr_v1_tag_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SuggestTagsRequest.ProtoReflect.Descriptor instead.
+func (*SuggestTagsRequest) Descriptor() ([]byte, []int) {
+	return file_findr_v1_tag_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SuggestTagsRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *SuggestTagsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type PopularTagsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// e.g. 7d or 24h
+	Window string `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"`
+	Limit  int32  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *PopularTagsRequest) Reset() {
+	*x = PopularTagsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_findr_v1_tag_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PopularTagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PopularTagsRequest) ProtoMessage() {}
+
+func (x *PopularTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_findr_v1_tag_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PopularTagsRequest.ProtoReflect.Descriptor instead.
+func (*PopularTagsRequest) Descriptor() ([]byte, []int) {
+	return file_findr_v1_tag_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PopularTagsRequest) GetWindow() string {
+	if x != nil {
+		return x.Window
+	}
+	return ""
+}
+
+func (x *PopularTagsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type TagUsage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id    uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Label string `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	Usage int64  `protobuf:"varint,3,opt,name=usage,proto3" json:"usage,omitempty"`
+}
+
+func (x *TagUsage) Reset() {
+	*x = TagUsage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_findr_v1_tag_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TagUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TagUsage) ProtoMessage() {}
+
+func (x *TagUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_findr_v1_tag_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TagUsage.ProtoReflect.Descriptor instead.
+func (*TagUsage) Descriptor() ([]byte, []int) {
+	return file_findr_v1_tag_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TagUsage) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *TagUsage) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *TagUsage) GetUsage() int64 {
+	if x != nil {
+		return x.Usage
+	}
+	return 0
+}
+
+type TagUsageResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tags []*TagUsage `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`
+}
+
+func (x *TagUsageResponse) Reset() {
+	*x = TagUsageResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_findr_v1_tag_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TagUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TagUsageResponse) ProtoMessage() {}
+
+func (x *TagUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_findr_v1_tag_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TagUsageResponse.ProtoReflect.Descriptor instead.
+func (*TagUsageResponse) Descriptor() ([]byte, []int) {
+	return file_findr_v1_tag_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TagUsageResponse) GetTags() []*TagUsage {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+var File_findr_v1_tag_proto protoreflect.FileDescriptor
+
+var file_findr_v1_tag_proto_rawDesc = []byte{
+	0x0a, 0x12, 0x66, 0x69, 0x6e, 0x64, 0x72, 0x2f, 0x76, 0x31, 0x2f, 0x74, 0x61, 0x67, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x66, 0x69, 0x6e, 0x64, 0x72, 0x2e, 0x76, 0x31, 0x22, 0xa2,
+	0x01, 0x0a, 0x03, 0x54, 0x61, 0x67, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x20, 0x0a, 0x09,
+	0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x48,
+	0x00, 0x52, 0x08, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x88, 0x01, 0x01, 0x12, 0x1a,
+	0x0a, 0x08, 0x73, 0x79, 0x6e, 0x6f, 0x6e, 0x79, 0x6d, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x08, 0x73, 0x79, 0x6e, 0x6f, 0x6e, 0x79, 0x6d, 0x73, 0x12, 0x29, 0x0a, 0x08, 0x63, 0x68,
+	0x69, 0x6c, 0x64, 0x72, 0x65, 0x6e, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x66,
+	0x69, 0x6e, 0x64, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x67, 0x52, 0x08, 0x63, 0x68, 0x69,
+	0x6c, 0x64, 0x72, 0x65, 0x6e, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74,
+	0x5f, 0x69, 0x64, 0x22, 0x13, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x54, 0x61, 0x67, 0x54, 0x72, 0x65,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x37, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x54,
+	0x61, 0x67, 0x54, 0x72, 0x65, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21,
+	0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x66,
+	0x69, 0x6e, 0x64, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x67, 0x52, 0x04, 0x74, 0x61, 0x67,
+	0x73, 0x22, 0x42, 0x0a, 0x12, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x54, 0x61, 0x67, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69,
+	0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12,
+	0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05,
+	0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x42, 0x0a, 0x12, 0x50, 0x6f, 0x70, 0x75, 0x6c, 0x61, 0x72,
+	0x54, 0x61, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x77,
+	0x69, 0x6e, 0x64, 0x6f, 0x77, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x77, 0x69, 0x6e,
+	0x64, 0x6f, 0x77, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x46, 0x0a, 0x08, 0x54, 0x61, 0x67,
+	0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x02, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x75,
+	0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x75, 0x73, 0x61, 0x67,
+	0x65, 0x22, 0x3a, 0x0a, 0x10, 0x54, 0x61, 0x67, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x26, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x66, 0x69, 0x6e, 0x64, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x54,
+	0x61, 0x67, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x32, 0xe7, 0x01,
+	0x0a, 0x0a, 0x54, 0x61, 0x67, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x47, 0x0a, 0x0a,
+	0x47, 0x65, 0x74, 0x54, 0x61, 0x67, 0x54, 0x72, 0x65, 0x65, 0x12, 0x1b, 0x2e, 0x66, 0x69, 0x6e,
+	0x64, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x61, 0x67, 0x54, 0x72, 0x65, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x66, 0x69, 0x6e, 0x64, 0x72, 0x2e,
+	0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x61, 0x67, 0x54, 0x72, 0x65, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x47, 0x0a, 0x0b, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74,
+	0x54, 0x61, 0x67, 0x73, 0x12, 0x1c, 0x2e, 0x66, 0x69, 0x6e, 0x64, 0x72, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x54, 0x61, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x66, 0x69, 0x6e, 0x64, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61,
+	0x67, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x47,
+	0x0a, 0x0b, 0x50, 0x6f, 0x70, 0x75, 0x6c, 0x61, 0x72, 0x54, 0x61, 0x67, 0x73, 0x12, 0x1c, 0x2e,
+	0x66, 0x69, 0x6e, 0x64, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x70, 0x75, 0x6c, 0x61, 0x72,
+	0x54, 0x61, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x66, 0x69,
+	0x6e, 0x64, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x67, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x41, 0x5a, 0x3f, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x64, 0x61, 0x6d, 0x6e, 0x61, 0x73, 0x72, 0x75, 0x64,
+	0x69, 0x6e, 0x30, 0x33, 0x2f, 0x67, 0x6f, 0x2d, 0x61, 0x73, 0x73, 0x65, 0x74, 0x2d, 0x66, 0x69,
+	0x6e, 0x64, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x66, 0x69, 0x6e, 0x64, 0x72, 0x2f,
+	0x76, 0x31, 0x3b, 0x66, 0x69, 0x6e, 0x64, 0x72, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_findr_v1_tag_proto_rawDescOnce sync.Once
+	file_findr_v1_tag_proto_rawDescData = file_findr_v1_tag_proto_rawDesc
+)
+
+func file_findr_v1_tag_proto_rawDescGZIP() []byte {
+	file_findr_v1_tag_proto_rawDescOnce.Do(func() {
+		file_findr_v1_tag_proto_rawDescData = protoimpl.X.CompressGZIP(file_findr_v1_tag_proto_rawDescData)
+	})
+	return file_findr_v1_tag_proto_rawDescData
+}
+
+var file_findr_v1_tag_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_findr_v1_tag_proto_goTypes = []any{
+	(*Tag)(nil),                // 0: findr.v1.Tag
+	(*GetTagTreeRequest)(nil),  // 1: findr.v1.GetTagTreeRequest
+	(*GetTagTreeResponse)(nil), // 2: findr.v1.GetTagTreeResponse
+	(*SuggestTagsRequest)(nil), // 3: findr.v1.SuggestTagsRequest
+	(*PopularTagsRequest)(nil), // 4: findr.v1.PopularTagsRequest
+	(*TagUsage)(nil),           // 5: findr.v1.TagUsage
+	(*TagUsageResponse)(nil),   // 6: findr.v1.TagUsageResponse
+}
+var file_findr_v1_tag_proto_depIdxs = []int32{
+	0, // 0: findr.v1.Tag.children:type_name -> findr.v1.Tag
+	0, // 1: findr.v1.GetTagTreeResponse.tags:type_name -> findr.v1.Tag
+	5, // 2: findr.v1.TagUsageResponse.tags:type_name -> findr.v1.TagUsage
+	1, // 3: findr.v1.TagService.GetTagTree:input_type -> findr.v1.GetTagTreeRequest
+	3, // 4: findr.v1.TagService.SuggestTags:input_type -> findr.v1.SuggestTagsRequest
+	4, // 5: findr.v1.TagService.PopularTags:input_type -> findr.v1.PopularTagsRequest
+	2, // 6: findr.v1.TagService.GetTagTree:output_type -> findr.v1.GetTagTreeResponse
+	6, // 7: findr.v1.TagService.SuggestTags:output_type -> findr.v1.TagUsageResponse
+	6, // 8: findr.v1.TagService.PopularTags:output_type -> findr.v1.TagUsageResponse
+	6, // [6:9] is the sub-list for method output_type
+	3, // [3:6] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_findr_v1_tag_proto_init() }
+func file_findr_v1_tag_proto_init() {
+	if File_findr_v1_tag_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_findr_v1_tag_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*Tag); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_findr_v1_tag_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*GetTagTreeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_findr_v1_tag_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*GetTagTreeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_findr_v1_tag_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*SuggestTagsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_findr_v1_tag_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*PopularTagsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_findr_v1_tag_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*TagUsage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_findr_v1_tag_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*TagUsageResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_findr_v1_tag_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_findr_v1_tag_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_findr_v1_tag_proto_goTypes,
+		DependencyIndexes: file_findr_v1_tag_proto_depIdxs,
+		MessageInfos:      file_findr_v1_tag_proto_msgTypes,
+	}.Build()
+	File_findr_v1_tag_proto = out.File
+	file_findr_v1_tag_proto_rawDesc = nil
+	file_findr_v1_tag_proto_goTypes = nil
+	file_findr_v1_tag_proto_depIdxs = nil
+}
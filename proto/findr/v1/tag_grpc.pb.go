@@ -0,0 +1,205 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: findr/v1/tag.proto
+
+package findrv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TagService_GetTagTree_FullMethodName  = "/findr.v1.TagService/GetTagTree"
+	TagService_SuggestTags_FullMethodName = "/findr.v1.TagService/SuggestTags"
+	TagService_PopularTags_FullMethodName = "/findr.v1.TagService/PopularTags"
+)
+
+// TagServiceClient is the client API for TagService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// TagService serves the tags to internal services, as the REST API under
+// /api/tags and /api/admin/tags does.
+type TagServiceClient interface {
+	// GetTagTree returns the tags nested under their parents, for admins.
+	GetTagTree(ctx context.Context, in *GetTagTreeRequest, opts ...grpc.CallOption) (*GetTagTreeResponse, error)
+	SuggestTags(ctx context.Context, in *SuggestTagsRequest, opts ...grpc.CallOption) (*TagUsageResponse, error)
+	PopularTags(ctx context.Context, in *PopularTagsRequest, opts ...grpc.CallOption) (*TagUsageResponse, error)
+}
+
+type tagServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTagServiceClient(cc grpc.ClientConnInterface) TagServiceClient {
+	return &tagServiceClient{cc}
+}
+
+func (c *tagServiceClient) GetTagTree(ctx context.Context, in *GetTagTreeRequest, opts ...grpc.CallOption) (*GetTagTreeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTagTreeResponse)
+	err := c.cc.Invoke(ctx, TagService_GetTagTree_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tagServiceClient) SuggestTags(ctx context.Context, in *SuggestTagsRequest, opts ...grpc.CallOption) (*TagUsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TagUsageResponse)
+	err := c.cc.Invoke(ctx, TagService_SuggestTags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tagServiceClient) PopularTags(ctx context.Context, in *PopularTagsRequest, opts ...grpc.CallOption) (*TagUsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TagUsageResponse)
+	err := c.cc.Invoke(ctx, TagService_PopularTags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TagServiceServer is the server API for TagService service.
+// All implementations must embed UnimplementedTagServiceServer
+// for forward compatibility.
+//
+// TagService serves the tags to internal services, as the REST API under
+// /api/tags and /api/admin/tags does.
+type TagServiceServer interface {
+	// GetTagTree returns the tags nested under their parents, for admins.
+	GetTagTree(context.Context, *GetTagTreeRequest) (*GetTagTreeResponse, error)
+	SuggestTags(context.Context, *SuggestTagsRequest) (*TagUsageResponse, error)
+	PopularTags(context.Context, *PopularTagsRequest) (*TagUsageResponse, error)
+	mustEmbedUnimplementedTagServiceServer()
+}
+
+// UnimplementedTagServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTagServiceServer struct{}
+
+func (UnimplementedTagServiceServer) GetTagTree(context.Context, *GetTagTreeRequest) (*GetTagTreeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTagTree not implemented")
+}
+func (UnimplementedTagServiceServer) SuggestTags(context.Context, *SuggestTagsRequest) (*TagUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SuggestTags not implemented")
+}
+func (UnimplementedTagServiceServer) PopularTags(context.Context, *PopularTagsRequest) (*TagUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PopularTags not implemented")
+}
+func (UnimplementedTagServiceServer) mustEmbedUnimplementedTagServiceServer() {}
+func (UnimplementedTagServiceServer) testEmbeddedByValue()                    {}
+
+// UnsafeTagServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TagServiceServer will
+// result in compilation errors.
+type UnsafeTagServiceServer interface {
+	mustEmbedUnimplementedTagServiceServer()
+}
+
+func RegisterTagServiceServer(s grpc.ServiceRegistrar, srv TagServiceServer) {
+	// If the following call pancis, it indicates UnimplementedTagServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TagService_ServiceDesc, srv)
+}
+
+func _TagService_GetTagTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTagTreeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TagServiceServer).GetTagTree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TagService_GetTagTree_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TagServiceServer).GetTagTree(ctx, req.(*GetTagTreeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TagService_SuggestTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TagServiceServer).SuggestTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TagService_SuggestTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TagServiceServer).SuggestTags(ctx, req.(*SuggestTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TagService_PopularTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PopularTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TagServiceServer).PopularTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TagService_PopularTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TagServiceServer).PopularTags(ctx, req.(*PopularTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TagService_ServiceDesc is the grpc.ServiceDesc for TagService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TagService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "findr.v1.TagService",
+	HandlerType: (*TagServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetTagTree",
+			Handler:    _TagService_GetTagTree_Handler,
+		},
+		{
+			MethodName: "SuggestTags",
+			Handler:    _TagService_SuggestTags_Handler,
+		},
+		{
+			MethodName: "PopularTags",
+			Handler:    _TagService_PopularTags_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "findr/v1/tag.proto",
+}